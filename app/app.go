@@ -7,6 +7,8 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/bootstrap"
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/snapshot"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/config"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/db/database"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/db/database/ldb"
@@ -21,8 +23,7 @@ import (
 )
 
 const (
-	leveldbCacheSizeMiB = 256
-	defaultDataDirname  = "datadir2"
+	defaultDataDirname = "datadir2"
 )
 
 var desiredLimits = &limits.DesiredLimits{
@@ -102,6 +103,14 @@ func (app *karlsendApp) main(startedChan chan<- struct{}) error {
 		}
 	}
 
+	if app.cfg.RestoreSnapshot != "" {
+		err := snapshot.Restore(app.cfg.RestoreSnapshot, app.cfg.ActiveNetParams, databasePath(app.cfg))
+		if err != nil {
+			log.Errorf("Restoring the snapshot failed: %+v", err)
+			return err
+		}
+	}
+
 	// Open the database
 	databaseContext, err := openDB(app.cfg)
 	if err != nil {
@@ -122,6 +131,14 @@ func (app *karlsendApp) main(startedChan chan<- struct{}) error {
 		return nil
 	}
 
+	if app.cfg.VerifyDatabase {
+		return verifyDatabase(app.cfg, databaseContext)
+	}
+
+	if app.cfg.ExportBootstrap != "" {
+		return exportBootstrap(app.cfg, databaseContext)
+	}
+
 	// Create componentManager and start it.
 	componentManager, err := NewComponentManager(app.cfg, databaseContext, interrupt)
 	if err != nil {
@@ -148,6 +165,14 @@ func (app *karlsendApp) main(startedChan chan<- struct{}) error {
 		log.Infof("Karlsend shutdown complete")
 	}()
 
+	if app.cfg.ImportBootstrap != "" {
+		err := componentManager.importBootstrap(app.cfg.ImportBootstrap)
+		if err != nil {
+			log.Errorf("Importing the bootstrap file failed: %+v", err)
+			return err
+		}
+	}
+
 	componentManager.Start()
 
 	if startedChan != nil {
@@ -161,6 +186,20 @@ func (app *karlsendApp) main(startedChan chan<- struct{}) error {
 	return nil
 }
 
+func exportBootstrap(cfg *config.Config, db database.Database) error {
+	domain, err := newDomain(cfg, db)
+	if err != nil {
+		log.Errorf("Unable to create the domain: %+v", err)
+		return err
+	}
+	err = bootstrap.Export(domain.Consensus(), cfg.ActiveNetParams, cfg.ExportBootstrap)
+	if err != nil {
+		log.Errorf("Exporting the bootstrap file failed: %+v", err)
+		return err
+	}
+	return nil
+}
+
 // dbPath returns the path to the block database given a database type.
 func databasePath(cfg *config.Config) string {
 	return filepath.Join(cfg.AppDir, defaultDataDirname)
@@ -179,8 +218,9 @@ func openDB(cfg *config.Config) (database.Database, error) {
 		return nil, err
 	}
 
-	log.Infof("Loading database from '%s'", dbPath)
-	db, err := ldb.NewLevelDB(dbPath, leveldbCacheSizeMiB)
+	dbConfig := cfg.DBConfig()
+	log.Infof("Loading database from '%s' (%s)", dbPath, dbConfig)
+	db, err := ldb.NewLevelDBWithConfig(dbPath, dbConfig)
 	if err != nil {
 		return nil, err
 	}
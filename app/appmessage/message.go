@@ -73,6 +73,10 @@ const (
 	CmdRequestIBDChainBlockLocator
 	CmdIBDChainBlockLocator
 	CmdRequestAnticone
+	CmdRequestCompactBlock
+	CmdCompactBlock
+	CmdRequestBlockTransactions
+	CmdBlockTransactions
 
 	// rpc
 	CmdGetCurrentNetworkRequestMessage
@@ -163,6 +167,33 @@ const (
 	CmdGetMempoolEntriesByAddressesResponseMessage
 	CmdGetCoinSupplyRequestMessage
 	CmdGetCoinSupplyResponseMessage
+	CmdDisconnectPeerRequestMessage
+	CmdDisconnectPeerResponseMessage
+	CmdRemovePeerRequestMessage
+	CmdRemovePeerResponseMessage
+	CmdGetBannedPeersRequestMessage
+	CmdGetBannedPeersResponseMessage
+	CmdGetConnectionRequestsRequestMessage
+	CmdGetConnectionRequestsResponseMessage
+	CmdInvalidateBlockRequestMessage
+	CmdInvalidateBlockResponseMessage
+	CmdReconsiderBlockRequestMessage
+	CmdReconsiderBlockResponseMessage
+	CmdCreateSnapshotRequestMessage
+	CmdCreateSnapshotResponseMessage
+	CmdGetDAGRegionRequestMessage
+	CmdGetDAGRegionResponseMessage
+	CmdNotifyReorgRequestMessage
+	CmdNotifyReorgResponseMessage
+	CmdReorgNotificationMessage
+	CmdGetMiningInfoRequestMessage
+	CmdGetMiningInfoResponseMessage
+	CmdPrioritiseTransactionRequestMessage
+	CmdPrioritiseTransactionResponseMessage
+	CmdGenerateBlocksRequestMessage
+	CmdGenerateBlocksResponseMessage
+	CmdSetMockTimeRequestMessage
+	CmdSetMockTimeResponseMessage
 )
 
 // ProtocolMessageCommandToString maps all MessageCommands to their string representation
@@ -210,6 +241,10 @@ var ProtocolMessageCommandToString = map[MessageCommand]string{
 	CmdRequestIBDChainBlockLocator:                 "RequestIBDChainBlockLocator",
 	CmdIBDChainBlockLocator:                        "IBDChainBlockLocator",
 	CmdRequestAnticone:                             "RequestAnticone",
+	CmdRequestCompactBlock:                         "RequestCompactBlock",
+	CmdCompactBlock:                                "CompactBlock",
+	CmdRequestBlockTransactions:                    "RequestBlockTransactions",
+	CmdBlockTransactions:                           "BlockTransactions",
 }
 
 // RPCMessageCommandToString maps all MessageCommands to their string representation
@@ -300,6 +335,33 @@ var RPCMessageCommandToString = map[MessageCommand]string{
 	CmdGetMempoolEntriesByAddressesResponseMessage:                "GetMempoolEntriesByAddressesResponse",
 	CmdGetCoinSupplyRequestMessage:                                "GetCoinSupplyRequest",
 	CmdGetCoinSupplyResponseMessage:                               "GetCoinSupplyResponse",
+	CmdDisconnectPeerRequestMessage:                               "DisconnectPeerRequest",
+	CmdDisconnectPeerResponseMessage:                              "DisconnectPeerResponse",
+	CmdRemovePeerRequestMessage:                                   "RemovePeerRequest",
+	CmdRemovePeerResponseMessage:                                  "RemovePeerResponse",
+	CmdGetBannedPeersRequestMessage:                               "GetBannedPeersRequest",
+	CmdGetBannedPeersResponseMessage:                              "GetBannedPeersResponse",
+	CmdGetConnectionRequestsRequestMessage:                        "GetConnectionRequestsRequest",
+	CmdGetConnectionRequestsResponseMessage:                       "GetConnectionRequestsResponse",
+	CmdInvalidateBlockRequestMessage:                              "InvalidateBlockRequest",
+	CmdInvalidateBlockResponseMessage:                             "InvalidateBlockResponse",
+	CmdReconsiderBlockRequestMessage:                              "ReconsiderBlockRequest",
+	CmdReconsiderBlockResponseMessage:                             "ReconsiderBlockResponse",
+	CmdCreateSnapshotRequestMessage:                               "CreateSnapshotRequest",
+	CmdCreateSnapshotResponseMessage:                              "CreateSnapshotResponse",
+	CmdGetDAGRegionRequestMessage:                                 "GetDAGRegionRequest",
+	CmdGetDAGRegionResponseMessage:                                "GetDAGRegionResponse",
+	CmdNotifyReorgRequestMessage:                                  "NotifyReorgRequest",
+	CmdNotifyReorgResponseMessage:                                 "NotifyReorgResponse",
+	CmdReorgNotificationMessage:                                   "ReorgNotification",
+	CmdGetMiningInfoRequestMessage:                                "GetMiningInfoRequest",
+	CmdGetMiningInfoResponseMessage:                               "GetMiningInfoResponse",
+	CmdPrioritiseTransactionRequestMessage:                        "PrioritiseTransactionRequest",
+	CmdPrioritiseTransactionResponseMessage:                       "PrioritiseTransactionResponse",
+	CmdGenerateBlocksRequestMessage:                               "GenerateBlocksRequest",
+	CmdGenerateBlocksResponseMessage:                              "GenerateBlocksResponse",
+	CmdSetMockTimeRequestMessage:                                  "SetMockTimeRequest",
+	CmdSetMockTimeResponseMessage:                                 "SetMockTimeResponse",
 }
 
 // Message is an interface that describes a kaspa message. A type that
@@ -0,0 +1,30 @@
+package appmessage
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+)
+
+// MsgBlockTransactions implements the Message interface and represents a kaspa
+// BlockTransactions message. It is sent in response to a MsgRequestBlockTransactions,
+// and contains the requested transactions in the order they were requested.
+type MsgBlockTransactions struct {
+	baseMessage
+	BlockHash    *externalapi.DomainHash
+	Transactions []*MsgTx
+}
+
+// Command returns the protocol command string for the message. This is part
+// of the Message interface implementation.
+func (msg *MsgBlockTransactions) Command() MessageCommand {
+	return CmdBlockTransactions
+}
+
+// NewMsgBlockTransactions returns a new kaspa BlockTransactions message that
+// conforms to the Message interface using the passed parameters.
+func NewMsgBlockTransactions(blockHash *externalapi.DomainHash, transactions []*MsgTx) *MsgBlockTransactions {
+	return &MsgBlockTransactions{
+		BlockHash:    blockHash,
+		Transactions: transactions,
+	}
+}
+
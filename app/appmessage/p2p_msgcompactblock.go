@@ -0,0 +1,47 @@
+package appmessage
+
+// MsgCompactBlock implements the Message interface and represents a kaspa
+// CompactBlock message. It contains a block header, along with a short ID
+// for every transaction that the receiving node is expected to already have
+// in its mempool, and the full transactions it isn't (such as the coinbase).
+type MsgCompactBlock struct {
+	baseMessage
+	Header *MsgBlockHeader
+
+	// ShortIDNonce is mixed, together with the block hash, into the key
+	// that's used to calculate the short IDs of this block's transactions
+	ShortIDNonce uint64
+
+	// ShortIDs holds the short IDs of all the block's transactions that
+	// aren't prefilled, in block order
+	ShortIDs []uint64
+
+	PrefilledTransactions []*PrefilledTransaction
+}
+
+// PrefilledTransaction is a transaction that's sent in full within a
+// MsgCompactBlock, along with its index within the block
+type PrefilledTransaction struct {
+	Index       uint32
+	Transaction *MsgTx
+}
+
+// Command returns the protocol command string for the message. This is part
+// of the Message interface implementation.
+func (msg *MsgCompactBlock) Command() MessageCommand {
+	return CmdCompactBlock
+}
+
+// NewMsgCompactBlock returns a new kaspa CompactBlock message that conforms to
+// the Message interface using the passed parameters.
+func NewMsgCompactBlock(header *MsgBlockHeader, shortIDNonce uint64, shortIDs []uint64,
+	prefilledTransactions []*PrefilledTransaction) *MsgCompactBlock {
+
+	return &MsgCompactBlock{
+		Header:                header,
+		ShortIDNonce:          shortIDNonce,
+		ShortIDs:              shortIDs,
+		PrefilledTransactions: prefilledTransactions,
+	}
+}
+
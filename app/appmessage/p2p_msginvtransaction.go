@@ -14,6 +14,11 @@ const MaxInvPerTxInvMsg = MaxInvPerMsg
 type MsgInvTransaction struct {
 	baseMessage
 	TxIDs []*externalapi.DomainTransactionID
+
+	// IsStem marks transactions that are in their Dandelion stem phase.
+	// Such transactions should be relayed to a single peer rather than
+	// broadcast to the whole network.
+	IsStem bool
 }
 
 // Command returns the protocol command string for the message. This is part
@@ -30,3 +35,12 @@ func NewMsgInvTransaction(ids []*externalapi.DomainTransactionID) *MsgInvTransac
 	}
 }
 
+// NewMsgInvStemTransaction returns a new kaspa TxInv message for transactions
+// in their Dandelion stem phase. See MsgInvTransaction for details.
+func NewMsgInvStemTransaction(ids []*externalapi.DomainTransactionID) *MsgInvTransaction {
+	return &MsgInvTransaction{
+		TxIDs:  ids,
+		IsStem: true,
+	}
+}
+
@@ -0,0 +1,32 @@
+package appmessage
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+)
+
+// MsgRequestBlockTransactions implements the Message interface and represents a
+// kaspa RequestBlockTransactions message. It is used to request the transactions
+// of a compact block that couldn't be found in the mempool.
+type MsgRequestBlockTransactions struct {
+	baseMessage
+	BlockHash *externalapi.DomainHash
+
+	// Indexes are the indexes of the requested transactions within the block
+	Indexes []uint32
+}
+
+// Command returns the protocol command string for the message. This is part
+// of the Message interface implementation.
+func (msg *MsgRequestBlockTransactions) Command() MessageCommand {
+	return CmdRequestBlockTransactions
+}
+
+// NewMsgRequestBlockTransactions returns a new kaspa RequestBlockTransactions
+// message that conforms to the Message interface using the passed parameters.
+func NewMsgRequestBlockTransactions(blockHash *externalapi.DomainHash, indexes []uint32) *MsgRequestBlockTransactions {
+	return &MsgRequestBlockTransactions{
+		BlockHash: blockHash,
+		Indexes:   indexes,
+	}
+}
+
@@ -0,0 +1,28 @@
+package appmessage
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+)
+
+// MsgRequestCompactBlock implements the Message interface and represents a kaspa
+// RequestCompactBlock message. It is used to request a relay block in its
+// compact form, to be reconstructed from the requesting node's mempool.
+type MsgRequestCompactBlock struct {
+	baseMessage
+	Hash *externalapi.DomainHash
+}
+
+// Command returns the protocol command string for the message. This is part
+// of the Message interface implementation.
+func (msg *MsgRequestCompactBlock) Command() MessageCommand {
+	return CmdRequestCompactBlock
+}
+
+// NewMsgRequestCompactBlock returns a new kaspa RequestCompactBlock message that
+// conforms to the Message interface using the passed parameters.
+func NewMsgRequestCompactBlock(hash *externalapi.DomainHash) *MsgRequestCompactBlock {
+	return &MsgRequestCompactBlock{
+		Hash: hash,
+	}
+}
+
@@ -41,6 +41,14 @@ const (
 	// SFNodeCF is a flag used to indicate a peer supports committed
 	// filters (CFs).
 	SFNodeCF
+
+	// SFNodeEncryptedTransport is a flag used to indicate a peer accepts
+	// encrypted P2P connections.
+	SFNodeEncryptedTransport
+
+	// SFNodeDandelion is a flag used to indicate a peer relays transactions
+	// in a Dandelion stem phase.
+	SFNodeDandelion
 )
 
 // Map of service flags back to their constant names for pretty printing.
@@ -51,6 +59,9 @@ var sfStrings = map[ServiceFlag]string{
 	SFNodeXthin:   "SFNodeXthin",
 	SFNodeBit5:    "SFNodeBit5",
 	SFNodeCF:      "SFNodeCF",
+
+	SFNodeEncryptedTransport: "SFNodeEncryptedTransport",
+	SFNodeDandelion:          "SFNodeDandelion",
 }
 
 // orderedSFStrings is an ordered list of service flags from highest to
@@ -62,6 +73,8 @@ var orderedSFStrings = []ServiceFlag{
 	SFNodeXthin,
 	SFNodeBit5,
 	SFNodeCF,
+	SFNodeEncryptedTransport,
+	SFNodeDandelion,
 }
 
 // String returns the ServiceFlag in human-readable form.
@@ -19,7 +19,9 @@ func TestServiceFlagStringer(t *testing.T) {
 		{SFNodeXthin, "SFNodeXthin"},
 		{SFNodeBit5, "SFNodeBit5"},
 		{SFNodeCF, "SFNodeCF"},
-		{0xffffffff, "SFNodeNetwork|SFNodeGetUTXO|SFNodeBloom|SFNodeXthin|SFNodeBit5|SFNodeCF|0xffffffc0"},
+		{SFNodeEncryptedTransport, "SFNodeEncryptedTransport"},
+		{SFNodeDandelion, "SFNodeDandelion"},
+		{0xffffffff, "SFNodeNetwork|SFNodeGetUTXO|SFNodeBloom|SFNodeXthin|SFNodeBit5|SFNodeCF|SFNodeEncryptedTransport|SFNodeDandelion|0xffffff00"},
 	}
 
 	t.Logf("Running %d tests", len(tests))
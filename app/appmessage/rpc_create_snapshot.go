@@ -0,0 +1,50 @@
+package appmessage
+
+// CreateSnapshotRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type CreateSnapshotRequestMessage struct {
+	baseMessage
+
+	TargetDirectory string
+}
+
+// Command returns the protocol command string for the message
+func (msg *CreateSnapshotRequestMessage) Command() MessageCommand {
+	return CmdCreateSnapshotRequestMessage
+}
+
+// NewCreateSnapshotRequestMessage returns an instance of the message
+func NewCreateSnapshotRequestMessage(targetDirectory string) *CreateSnapshotRequestMessage {
+	return &CreateSnapshotRequestMessage{
+		TargetDirectory: targetDirectory,
+	}
+}
+
+// CreateSnapshotResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type CreateSnapshotResponseMessage struct {
+	baseMessage
+
+	PruningPointHash          string
+	VirtualSelectedParentHash string
+	KeyCount                  uint64
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *CreateSnapshotResponseMessage) Command() MessageCommand {
+	return CmdCreateSnapshotResponseMessage
+}
+
+// NewCreateSnapshotResponseMessage returns a instance of the message
+func NewCreateSnapshotResponseMessage(pruningPointHash string, virtualSelectedParentHash string,
+	keyCount uint64) *CreateSnapshotResponseMessage {
+
+	return &CreateSnapshotResponseMessage{
+		PruningPointHash:          pruningPointHash,
+		VirtualSelectedParentHash: virtualSelectedParentHash,
+		KeyCount:                  keyCount,
+	}
+}
+
@@ -0,0 +1,40 @@
+package appmessage
+
+// DisconnectPeerRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type DisconnectPeerRequestMessage struct {
+	baseMessage
+
+	Address string
+}
+
+// Command returns the protocol command string for the message
+func (msg *DisconnectPeerRequestMessage) Command() MessageCommand {
+	return CmdDisconnectPeerRequestMessage
+}
+
+// NewDisconnectPeerRequestMessage returns an instance of the message
+func NewDisconnectPeerRequestMessage(address string) *DisconnectPeerRequestMessage {
+	return &DisconnectPeerRequestMessage{
+		Address: address,
+	}
+}
+
+// DisconnectPeerResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type DisconnectPeerResponseMessage struct {
+	baseMessage
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *DisconnectPeerResponseMessage) Command() MessageCommand {
+	return CmdDisconnectPeerResponseMessage
+}
+
+// NewDisconnectPeerResponseMessage returns a instance of the message
+func NewDisconnectPeerResponseMessage() *DisconnectPeerResponseMessage {
+	return &DisconnectPeerResponseMessage{}
+}
+
@@ -0,0 +1,47 @@
+package appmessage
+
+// GetBannedPeersRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetBannedPeersRequestMessage struct {
+	baseMessage
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetBannedPeersRequestMessage) Command() MessageCommand {
+	return CmdGetBannedPeersRequestMessage
+}
+
+// NewGetBannedPeersRequestMessage returns a instance of the message
+func NewGetBannedPeersRequestMessage() *GetBannedPeersRequestMessage {
+	return &GetBannedPeersRequestMessage{}
+}
+
+// GetBannedPeersResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetBannedPeersResponseMessage struct {
+	baseMessage
+	BannedPeers []*BannedPeerInfo
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetBannedPeersResponseMessage) Command() MessageCommand {
+	return CmdGetBannedPeersResponseMessage
+}
+
+// NewGetBannedPeersResponseMessage returns a instance of the message
+func NewGetBannedPeersResponseMessage(bannedPeers []*BannedPeerInfo) *GetBannedPeersResponseMessage {
+	return &GetBannedPeersResponseMessage{
+		BannedPeers: bannedPeers,
+	}
+}
+
+// BannedPeerInfo holds information about a banned address
+type BannedPeerInfo struct {
+	Address string
+
+	// BanExpiry is the UNIX time in milliseconds at which the ban expires
+	BanExpiry int64
+}
+
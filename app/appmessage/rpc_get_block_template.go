@@ -4,8 +4,10 @@ package appmessage
 // its respective RPC message
 type GetBlockTemplateRequestMessage struct {
 	baseMessage
-	PayAddress string
-	ExtraData  string
+	PayAddress         string
+	ExtraData          string
+	LongPollTemplateID string
+	HeaderOnly         bool
 }
 
 // Command returns the protocol command string for the message
@@ -14,10 +16,14 @@ func (msg *GetBlockTemplateRequestMessage) Command() MessageCommand {
 }
 
 // NewGetBlockTemplateRequestMessage returns a instance of the message
-func NewGetBlockTemplateRequestMessage(payAddress, extraData string) *GetBlockTemplateRequestMessage {
+func NewGetBlockTemplateRequestMessage(payAddress, extraData, longPollTemplateID string,
+	headerOnly bool) *GetBlockTemplateRequestMessage {
+
 	return &GetBlockTemplateRequestMessage{
-		PayAddress: payAddress,
-		ExtraData:  extraData,
+		PayAddress:         payAddress,
+		ExtraData:          extraData,
+		LongPollTemplateID: longPollTemplateID,
+		HeaderOnly:         headerOnly,
 	}
 }
 
@@ -25,8 +31,9 @@ func NewGetBlockTemplateRequestMessage(payAddress, extraData string) *GetBlockTe
 // its respective RPC message
 type GetBlockTemplateResponseMessage struct {
 	baseMessage
-	Block    *RPCBlock
-	IsSynced bool
+	Block      *RPCBlock
+	IsSynced   bool
+	TemplateID string
 
 	Error *RPCError
 }
@@ -37,10 +44,11 @@ func (msg *GetBlockTemplateResponseMessage) Command() MessageCommand {
 }
 
 // NewGetBlockTemplateResponseMessage returns a instance of the message
-func NewGetBlockTemplateResponseMessage(block *RPCBlock, isSynced bool) *GetBlockTemplateResponseMessage {
+func NewGetBlockTemplateResponseMessage(block *RPCBlock, isSynced bool, templateID string) *GetBlockTemplateResponseMessage {
 	return &GetBlockTemplateResponseMessage{
-		Block:    block,
-		IsSynced: isSynced,
+		Block:      block,
+		IsSynced:   isSynced,
+		TemplateID: templateID,
 	}
 }
 
@@ -47,5 +47,18 @@ type GetConnectedPeerInfoMessage struct {
 	AdvertisedProtocolVersion uint32
 	TimeConnected             int64
 	IsIBDPeer                 bool
+	IsEncrypted               bool
+	PeerPublicKey             string
+	BytesSent                 uint64
+	BytesReceived             uint64
+	BandwidthByMessageType    []*PeerMessageTypeBandwidth
+}
+
+// PeerMessageTypeBandwidth holds the amount of bytes sent to and
+// received from a peer for a single message type
+type PeerMessageTypeBandwidth struct {
+	MessageType   string
+	BytesSent     uint64
+	BytesReceived uint64
 }
 
@@ -0,0 +1,81 @@
+package appmessage
+
+// GetDAGRegionRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetDAGRegionRequestMessage struct {
+	baseMessage
+	LowDAAScore    uint64
+	HighDAAScore   uint64
+	AroundHash     string
+	DAAScoreRadius uint64
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetDAGRegionRequestMessage) Command() MessageCommand {
+	return CmdGetDAGRegionRequestMessage
+}
+
+// NewGetDAGRegionRequestMessage returns a instance of the message
+func NewGetDAGRegionRequestMessage(lowDAAScore uint64, highDAAScore uint64,
+	aroundHash string, daaScoreRadius uint64) *GetDAGRegionRequestMessage {
+
+	return &GetDAGRegionRequestMessage{
+		LowDAAScore:    lowDAAScore,
+		HighDAAScore:   highDAAScore,
+		AroundHash:     aroundHash,
+		DAAScoreRadius: daaScoreRadius,
+	}
+}
+
+// GetDAGRegionResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetDAGRegionResponseMessage struct {
+	baseMessage
+	LowDAAScore  uint64
+	HighDAAScore uint64
+	Blocks       []*DAGRegionBlock
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetDAGRegionResponseMessage) Command() MessageCommand {
+	return CmdGetDAGRegionResponseMessage
+}
+
+// NewGetDAGRegionResponseMessage returns a instance of the message
+func NewGetDAGRegionResponseMessage(lowDAAScore uint64, highDAAScore uint64,
+	blocks []*DAGRegionBlock) *GetDAGRegionResponseMessage {
+
+	return &GetDAGRegionResponseMessage{
+		LowDAAScore:  lowDAAScore,
+		HighDAAScore: highDAAScore,
+		Blocks:       blocks,
+	}
+}
+
+// The colors of a block in a DAGRegionBlock
+const (
+	DAGRegionBlockColorBlue = "blue"
+	DAGRegionBlockColorRed  = "red"
+
+	// DAGRegionBlockColorUnmerged is the color of blocks that are
+	// not merged by any block in the virtual selected parent chain
+	DAGRegionBlockColorUnmerged = "unmerged"
+)
+
+// DAGRegionBlock holds the GHOSTDAG information of a block in a DAG region
+type DAGRegionBlock struct {
+	Hash                string
+	ParentHashes        []string
+	SelectedParentHash  string
+	DAAScore            uint64
+	BlueScore           uint64
+	BlueWork            string
+	IsChainBlock        bool
+	Color               string
+	MergingBlockHash    string
+	MergeSetBluesHashes []string
+	MergeSetRedsHashes  []string
+}
+
@@ -0,0 +1,50 @@
+package appmessage
+
+// GetMiningInfoRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetMiningInfoRequestMessage struct {
+	baseMessage
+	PayAddress string
+	WindowSize uint64
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetMiningInfoRequestMessage) Command() MessageCommand {
+	return CmdGetMiningInfoRequestMessage
+}
+
+// NewGetMiningInfoRequestMessage returns a instance of the message
+func NewGetMiningInfoRequestMessage(payAddress string, windowSize uint64) *GetMiningInfoRequestMessage {
+	return &GetMiningInfoRequestMessage{
+		PayAddress: payAddress,
+		WindowSize: windowSize,
+	}
+}
+
+// GetMiningInfoResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetMiningInfoResponseMessage struct {
+	baseMessage
+	WindowStartDAAScore          uint64
+	AcceptedBlockCount           uint64
+	BlueBlockCount               uint64
+	RedBlockCount                uint64
+	PendingBlockCount            uint64
+	MaturedRewards               uint64
+	PendingRewards               uint64
+	NetworkHashesPerSecond       uint64
+	BlockTemplateAgeMilliseconds int64
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetMiningInfoResponseMessage) Command() MessageCommand {
+	return CmdGetMiningInfoResponseMessage
+}
+
+// NewGetMiningInfoResponseMessage returns a instance of the message
+func NewGetMiningInfoResponseMessage() *GetMiningInfoResponseMessage {
+	return &GetMiningInfoResponseMessage{}
+}
+
@@ -0,0 +1,64 @@
+package appmessage
+
+// NotifyReorgRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type NotifyReorgRequestMessage struct {
+	baseMessage
+}
+
+// Command returns the protocol command string for the message
+func (msg *NotifyReorgRequestMessage) Command() MessageCommand {
+	return CmdNotifyReorgRequestMessage
+}
+
+// NewNotifyReorgRequestMessage returns a instance of the message
+func NewNotifyReorgRequestMessage() *NotifyReorgRequestMessage {
+	return &NotifyReorgRequestMessage{}
+}
+
+// NotifyReorgResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type NotifyReorgResponseMessage struct {
+	baseMessage
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *NotifyReorgResponseMessage) Command() MessageCommand {
+	return CmdNotifyReorgResponseMessage
+}
+
+// NewNotifyReorgResponseMessage returns a instance of the message
+func NewNotifyReorgResponseMessage() *NotifyReorgResponseMessage {
+	return &NotifyReorgResponseMessage{}
+}
+
+// ReorgNotificationMessage is an appmessage corresponding to
+// its respective RPC message
+type ReorgNotificationMessage struct {
+	baseMessage
+	CommonAncestorHash      string
+	Depth                   uint64
+	RemovedChainBlockHashes []string
+	AddedChainBlockHashes   []string
+	RemovedTransactionIDs   []string
+}
+
+// Command returns the protocol command string for the message
+func (msg *ReorgNotificationMessage) Command() MessageCommand {
+	return CmdReorgNotificationMessage
+}
+
+// NewReorgNotificationMessage returns a instance of the message
+func NewReorgNotificationMessage(commonAncestorHash string, depth uint64, removedChainBlockHashes,
+	addedChainBlockHashes []string, removedTransactionIDs []string) *ReorgNotificationMessage {
+
+	return &ReorgNotificationMessage{
+		CommonAncestorHash:      commonAncestorHash,
+		Depth:                   depth,
+		RemovedChainBlockHashes: removedChainBlockHashes,
+		AddedChainBlockHashes:   addedChainBlockHashes,
+		RemovedTransactionIDs:   removedTransactionIDs,
+	}
+}
+
@@ -0,0 +1,44 @@
+package appmessage
+
+// PrioritiseTransactionRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type PrioritiseTransactionRequestMessage struct {
+	baseMessage
+	TransactionID string
+	FeeDelta      int64
+}
+
+// Command returns the protocol command string for the message
+func (msg *PrioritiseTransactionRequestMessage) Command() MessageCommand {
+	return CmdPrioritiseTransactionRequestMessage
+}
+
+// NewPrioritiseTransactionRequestMessage returns a instance of the message
+func NewPrioritiseTransactionRequestMessage(transactionID string, feeDelta int64) *PrioritiseTransactionRequestMessage {
+	return &PrioritiseTransactionRequestMessage{
+		TransactionID: transactionID,
+		FeeDelta:      feeDelta,
+	}
+}
+
+// PrioritiseTransactionResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type PrioritiseTransactionResponseMessage struct {
+	baseMessage
+	TotalFeeDelta int64
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *PrioritiseTransactionResponseMessage) Command() MessageCommand {
+	return CmdPrioritiseTransactionResponseMessage
+}
+
+// NewPrioritiseTransactionResponseMessage returns a instance of the message
+func NewPrioritiseTransactionResponseMessage(totalFeeDelta int64) *PrioritiseTransactionResponseMessage {
+	return &PrioritiseTransactionResponseMessage{
+		TotalFeeDelta: totalFeeDelta,
+	}
+}
+
@@ -0,0 +1,40 @@
+package appmessage
+
+// RemovePeerRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type RemovePeerRequestMessage struct {
+	baseMessage
+
+	Address string
+}
+
+// Command returns the protocol command string for the message
+func (msg *RemovePeerRequestMessage) Command() MessageCommand {
+	return CmdRemovePeerRequestMessage
+}
+
+// NewRemovePeerRequestMessage returns an instance of the message
+func NewRemovePeerRequestMessage(address string) *RemovePeerRequestMessage {
+	return &RemovePeerRequestMessage{
+		Address: address,
+	}
+}
+
+// RemovePeerResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type RemovePeerResponseMessage struct {
+	baseMessage
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *RemovePeerResponseMessage) Command() MessageCommand {
+	return CmdRemovePeerResponseMessage
+}
+
+// NewRemovePeerResponseMessage returns a instance of the message
+func NewRemovePeerResponseMessage() *RemovePeerResponseMessage {
+	return &RemovePeerResponseMessage{}
+}
+
@@ -102,5 +102,16 @@ type RPCBlockVerboseData struct {
 	MergeSetBluesHashes []string
 	MergeSetRedsHashes  []string
 	IsChainBlock        bool
+	CoinbaseData        *RPCCoinbaseData
+}
+
+// RPCCoinbaseData holds the data that is decoded from the coinbase
+// payload of a block, which attributes the block to its miner
+type RPCCoinbaseData struct {
+	ScriptPublicKey        *RPCScriptPublicKey
+	ScriptPublicKeyAddress string
+	ExtraData              string
+	Version                string
+	MinerTag               string
 }
 
@@ -0,0 +1,237 @@
+package bootstrap_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/bootstrap"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/dagconfig"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/miningmanager/blocktemplatebuilder"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/miningmanager/mempool"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/db/database/ldb"
+	"github.com/pkg/errors"
+)
+
+func TestExportAndImport(t *testing.T) {
+	prunedParams := dagconfig.SimnetParams
+	// This is done to make a pruning depth of 6 blocks, with block
+	// timestamps that are spaced far enough apart to avoid failing
+	// the timestamp threshold validation of the pruning point proof
+	prunedParams.TargetTimePerBlock = time.Minute
+	prunedParams.FinalityDuration = 2 * prunedParams.TargetTimePerBlock
+	prunedParams.K = 0
+	prunedParams.PruningProofM = 20
+
+	tests := []struct {
+		name                     string
+		params                   dagconfig.Params
+		numBlocks                int
+		expectPruningPointChange bool
+	}{
+		{
+			name:                     "genesis pruning point",
+			params:                   dagconfig.SimnetParams,
+			numBlocks:                10,
+			expectPruningPointChange: false,
+		},
+		{
+			name:                     "pruned",
+			params:                   prunedParams,
+			numBlocks:                50,
+			expectPruningPointChange: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			consensusConfig := &consensus.Config{Params: test.params}
+			consensusConfig.SkipProofOfWork = true
+
+			source, teardown := setupDomain(t, consensusConfig, "source")
+			defer teardown()
+			mineBlocks(t, source, test.numBlocks)
+
+			path := filepath.Join(t.TempDir(), "bootstrap.dat")
+			err := bootstrap.Export(source.Consensus(), &consensusConfig.Params, path)
+			if err != nil {
+				t.Fatalf("Export: %+v", err)
+			}
+
+			destination, teardown := setupDomain(t, consensusConfig, "destination")
+			defer teardown()
+
+			pruningPointUTXOSetOverrides := 0
+			onPruningPointUTXOSetOverride := func() error {
+				pruningPointUTXOSetOverrides++
+				return nil
+			}
+			err = bootstrap.Import(destination, &consensusConfig.Params, path, onPruningPointUTXOSetOverride)
+			if err != nil {
+				t.Fatalf("Import: %+v", err)
+			}
+			expectedPruningPointUTXOSetOverrides := 0
+			if test.expectPruningPointChange {
+				expectedPruningPointUTXOSetOverrides = 1
+			}
+			if pruningPointUTXOSetOverrides != expectedPruningPointUTXOSetOverrides {
+				t.Fatalf("Expected %d pruning point UTXO set overrides, but got %d",
+					expectedPruningPointUTXOSetOverrides, pruningPointUTXOSetOverrides)
+			}
+			verifySameState(t, source.Consensus(), destination.Consensus())
+
+			// Importing the same file again should leave the state as is
+			err = bootstrap.Import(destination, &consensusConfig.Params, path, onPruningPointUTXOSetOverride)
+			if err != nil {
+				t.Fatalf("Import: %+v", err)
+			}
+			if pruningPointUTXOSetOverrides != expectedPruningPointUTXOSetOverrides {
+				t.Fatalf("Expected the second import not to override the pruning point UTXO set")
+			}
+			verifySameState(t, source.Consensus(), destination.Consensus())
+
+			// The destination should be able to continue from where the source stopped
+			mineBlocks(t, destination, 1)
+		})
+	}
+}
+
+func TestImportCorruptedFile(t *testing.T) {
+	consensusConfig := &consensus.Config{Params: dagconfig.SimnetParams}
+	consensusConfig.SkipProofOfWork = true
+
+	source, teardown := setupDomain(t, consensusConfig, "source")
+	defer teardown()
+	mineBlocks(t, source, 10)
+
+	path := filepath.Join(t.TempDir(), "bootstrap.dat")
+	err := bootstrap.Export(source.Consensus(), &consensusConfig.Params, path)
+	if err != nil {
+		t.Fatalf("Export: %+v", err)
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %+v", err)
+	}
+	content[len(content)/2] ^= 0xff
+	err = ioutil.WriteFile(path, content, 0600)
+	if err != nil {
+		t.Fatalf("WriteFile: %+v", err)
+	}
+
+	destination, teardown := setupDomain(t, consensusConfig, "destination")
+	defer teardown()
+
+	err = bootstrap.Import(destination, &consensusConfig.Params, path, func() error { return nil })
+	if !errors.Is(err, bootstrap.ErrChecksumMismatch) {
+		t.Fatalf("Expected ErrChecksumMismatch, but got: %+v", err)
+	}
+
+	virtualSelectedParent, err := destination.Consensus().GetVirtualSelectedParent()
+	if err != nil {
+		t.Fatalf("GetVirtualSelectedParent: %+v", err)
+	}
+	if !virtualSelectedParent.Equal(consensusConfig.GenesisHash) {
+		t.Fatalf("Expected nothing to be imported from a corrupted file")
+	}
+}
+
+func setupDomain(t *testing.T, consensusConfig *consensus.Config, name string) (domain.Domain, func()) {
+	dataDir, err := ioutil.TempDir("", name)
+	if err != nil {
+		t.Fatalf("TempDir: %+v", err)
+	}
+
+	db, err := ldb.NewLevelDB(dataDir, 8)
+	if err != nil {
+		t.Fatalf("NewLevelDB: %+v", err)
+	}
+
+	domainInstance, err := domain.New(consensusConfig, mempool.DefaultConfig(&consensusConfig.Params), blocktemplatebuilder.DefaultPolicy(&consensusConfig.Params), db)
+	if err != nil {
+		t.Fatalf("New: %+v", err)
+	}
+
+	return domainInstance, func() {
+		db.Close()
+		os.RemoveAll(dataDir)
+	}
+}
+
+func mineBlocks(t *testing.T, domainInstance domain.Domain, numBlocks int) {
+	coinbaseData := &externalapi.DomainCoinbaseData{
+		ScriptPublicKey: &externalapi.ScriptPublicKey{},
+		ExtraData:       []byte{},
+	}
+
+	// Space the block timestamps 10 seconds apart, like in the IBD
+	// tests, so that the last block is mined at about the current time
+	const timestampInterval = 10_000
+	mockTimestamp := time.Now().UnixMilli() - int64(numBlocks)*timestampInterval
+
+	for i := 0; i < numBlocks; i++ {
+		block, err := domainInstance.Consensus().BuildBlock(coinbaseData, nil)
+		if err != nil {
+			t.Fatalf("BuildBlock: %+v", err)
+		}
+
+		mockTimestamp += timestampInterval
+		if mockTimestamp > block.Header.TimeInMilliseconds() {
+			mutableHeader := block.Header.ToMutable()
+			mutableHeader.SetTimeInMilliseconds(mockTimestamp)
+			block.Header = mutableHeader.ToImmutable()
+		}
+
+		err = domainInstance.Consensus().ValidateAndInsertBlock(block, true)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertBlock: %+v", err)
+		}
+	}
+}
+
+func verifySameState(t *testing.T, expected, actual externalapi.Consensus) {
+	expectedVirtualSelectedParent, err := expected.GetVirtualSelectedParent()
+	if err != nil {
+		t.Fatalf("GetVirtualSelectedParent: %+v", err)
+	}
+	actualVirtualSelectedParent, err := actual.GetVirtualSelectedParent()
+	if err != nil {
+		t.Fatalf("GetVirtualSelectedParent: %+v", err)
+	}
+	if !actualVirtualSelectedParent.Equal(expectedVirtualSelectedParent) {
+		t.Fatalf("Expected the virtual selected parent to be %s, but got %s",
+			expectedVirtualSelectedParent, actualVirtualSelectedParent)
+	}
+
+	expectedPruningPoint, err := expected.PruningPoint()
+	if err != nil {
+		t.Fatalf("PruningPoint: %+v", err)
+	}
+	actualPruningPoint, err := actual.PruningPoint()
+	if err != nil {
+		t.Fatalf("PruningPoint: %+v", err)
+	}
+	if !actualPruningPoint.Equal(expectedPruningPoint) {
+		t.Fatalf("Expected the pruning point to be %s, but got %s", expectedPruningPoint, actualPruningPoint)
+	}
+
+	expectedTips, err := expected.Tips()
+	if err != nil {
+		t.Fatalf("Tips: %+v", err)
+	}
+	actualTips, err := actual.Tips()
+	if err != nil {
+		t.Fatalf("Tips: %+v", err)
+	}
+	if !reflect.DeepEqual(expectedTips, actualTips) {
+		t.Fatalf("Expected the tips to be %s, but got %s", expectedTips, actualTips)
+	}
+}
+
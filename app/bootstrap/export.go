@@ -0,0 +1,311 @@
+package bootstrap
+
+import (
+	"os"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/dagconfig"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/logger"
+	"github.com/pkg/errors"
+)
+
+// maxBlocksPerChunk is the maximum amount of blocks that are requested from
+// consensus at once. It MUST be >= MergeSetSizeLimit + 1
+const maxBlocksPerChunk = 1 << 10
+
+// utxoSetChunkSize is the amount of UTXOs in each UTXO set chunk
+const utxoSetChunkSize = 1000
+
+// progressLogInterval is the amount of blocks between progress logs
+const progressLogInterval = 1000
+
+type exporter struct {
+	consensus externalapi.Consensus
+	params    *dagconfig.Params
+	file      *fileWriter
+}
+
+// Export writes the pruning point proof, the pruning point and its anticone, the pruning point
+// UTXO set and all the blocks between the pruning point and the virtual selected parent to a
+// bootstrap file in the given path. If the pruning point is the genesis, only the blocks are written.
+func Export(consensus externalapi.Consensus, params *dagconfig.Params, path string) error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "bootstrap.Export")
+	defer onEnd()
+
+	pruningPoint, err := consensus.PruningPoint()
+	if err != nil {
+		return err
+	}
+
+	file, err := createFile(path, &fileHeader{
+		version:      version,
+		networkName:  params.Name,
+		pruningPoint: pruningPoint,
+	})
+	if err != nil {
+		return err
+	}
+
+	e := &exporter{
+		consensus: consensus,
+		params:    params,
+		file:      file,
+	}
+	err = e.export(pruningPoint)
+	if err != nil {
+		file.file.Close()
+		os.Remove(path)
+		return err
+	}
+
+	err = file.close()
+	if err != nil {
+		return err
+	}
+	log.Infof("Exported the bootstrap file to %s", path)
+	return nil
+}
+
+func (e *exporter) export(pruningPoint *externalapi.DomainHash) error {
+	isGenesisPruningPoint := pruningPoint.Equal(e.params.GenesisHash)
+	if !isGenesisPruningPoint {
+		err := e.writePruningPointProof()
+		if err != nil {
+			return err
+		}
+		err = e.writePruningPointAndItsAnticone()
+		if err != nil {
+			return err
+		}
+	}
+
+	virtualSelectedParent, err := e.consensus.GetVirtualSelectedParent()
+	if err != nil {
+		return err
+	}
+	blockHashes, err := e.hashesBetween(pruningPoint, virtualSelectedParent)
+	if err != nil {
+		return err
+	}
+
+	err = e.writeHeaders(blockHashes)
+	if err != nil {
+		return err
+	}
+
+	if !isGenesisPruningPoint {
+		err = e.writePruningPointUTXOSet(pruningPoint)
+		if err != nil {
+			return err
+		}
+	}
+
+	return e.writeBlocks(blockHashes)
+}
+
+func (e *exporter) writePruningPointProof() error {
+	log.Infof("Exporting the pruning point proof")
+	pruningPointProof, err := e.consensus.BuildPruningPointProof()
+	if err != nil {
+		return err
+	}
+	return e.file.writeMessage(appmessage.DomainPruningPointProofToMsgPruningPointProof(pruningPointProof))
+}
+
+func (e *exporter) writePruningPointAndItsAnticone() error {
+	log.Infof("Exporting the past pruning points and the pruning point anticone")
+	pruningPointHeaders, err := e.consensus.PruningPointHeaders()
+	if err != nil {
+		return err
+	}
+	msgPruningPointHeaders := make([]*appmessage.MsgBlockHeader, len(pruningPointHeaders))
+	for i, header := range pruningPointHeaders {
+		msgPruningPointHeaders[i] = appmessage.DomainBlockHeaderToBlockHeader(header)
+	}
+	err = e.file.writeMessage(appmessage.NewMsgPruningPoints(msgPruningPointHeaders))
+	if err != nil {
+		return err
+	}
+
+	pointAndItsAnticone, err := e.consensus.PruningPointAndItsAnticone()
+	if err != nil {
+		return err
+	}
+
+	windowSize := e.params.DifficultyAdjustmentWindowSize
+	daaWindowBlocks := make([]*externalapi.TrustedDataDataDAAHeader, 0, windowSize)
+	daaWindowHashesToIndex := make(map[externalapi.DomainHash]int, windowSize)
+	trustedDataDAABlockIndexes := make(map[externalapi.DomainHash][]uint64)
+
+	ghostdagData := make([]*externalapi.BlockGHOSTDAGDataHashPair, 0)
+	ghostdagDataHashToIndex := make(map[externalapi.DomainHash]int)
+	trustedDataGHOSTDAGDataIndexes := make(map[externalapi.DomainHash][]uint64)
+	for _, blockHash := range pointAndItsAnticone {
+		blockDAAWindowHashes, err := e.consensus.BlockDAAWindowHashes(blockHash)
+		if err != nil {
+			return err
+		}
+
+		trustedDataDAABlockIndexes[*blockHash] = make([]uint64, 0, windowSize)
+		for i, daaBlockHash := range blockDAAWindowHashes {
+			index, exists := daaWindowHashesToIndex[*daaBlockHash]
+			if !exists {
+				trustedDataDataDAAHeader, err := e.consensus.TrustedDataDataDAAHeader(blockHash, daaBlockHash, uint64(i))
+				if err != nil {
+					return err
+				}
+				daaWindowBlocks = append(daaWindowBlocks, trustedDataDataDAAHeader)
+				index = len(daaWindowBlocks) - 1
+				daaWindowHashesToIndex[*daaBlockHash] = index
+			}
+
+			trustedDataDAABlockIndexes[*blockHash] = append(trustedDataDAABlockIndexes[*blockHash], uint64(index))
+		}
+
+		ghostdagDataBlockHashes, err := e.consensus.TrustedBlockAssociatedGHOSTDAGDataBlockHashes(blockHash)
+		if err != nil {
+			return err
+		}
+
+		trustedDataGHOSTDAGDataIndexes[*blockHash] = make([]uint64, 0, e.params.K)
+		for _, ghostdagDataBlockHash := range ghostdagDataBlockHashes {
+			index, exists := ghostdagDataHashToIndex[*ghostdagDataBlockHash]
+			if !exists {
+				data, err := e.consensus.TrustedGHOSTDAGData(ghostdagDataBlockHash)
+				if err != nil {
+					return err
+				}
+				ghostdagData = append(ghostdagData, &externalapi.BlockGHOSTDAGDataHashPair{
+					Hash:         ghostdagDataBlockHash,
+					GHOSTDAGData: data,
+				})
+				index = len(ghostdagData) - 1
+				ghostdagDataHashToIndex[*ghostdagDataBlockHash] = index
+			}
+
+			trustedDataGHOSTDAGDataIndexes[*blockHash] = append(trustedDataGHOSTDAGDataIndexes[*blockHash], uint64(index))
+		}
+	}
+
+	err = e.file.writeMessage(appmessage.DomainTrustedDataToTrustedData(daaWindowBlocks, ghostdagData))
+	if err != nil {
+		return err
+	}
+
+	for _, blockHash := range pointAndItsAnticone {
+		block, found, err := e.consensus.GetBlock(blockHash)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return errors.Errorf("pruning point anticone block %s not found", blockHash)
+		}
+
+		err = e.file.writeMessage(appmessage.DomainBlockWithTrustedDataToBlockWithTrustedDataV4(
+			block, trustedDataDAABlockIndexes[*blockHash], trustedDataGHOSTDAGDataIndexes[*blockHash]))
+		if err != nil {
+			return err
+		}
+	}
+
+	return e.file.writeMessage(appmessage.NewMsgDoneBlocksWithTrustedData())
+}
+
+// hashesBetween returns the hashes of the blocks in the past of highHash that
+// aren't in the past of lowHash, sorted so that every block comes after its parents
+func (e *exporter) hashesBetween(lowHash, highHash *externalapi.DomainHash) ([]*externalapi.DomainHash, error) {
+	var blockHashes []*externalapi.DomainHash
+	for !lowHash.Equal(highHash) {
+		chunk, _, err := e.consensus.GetHashesBetween(lowHash, highHash, maxBlocksPerChunk)
+		if err != nil {
+			return nil, err
+		}
+		blockHashes = append(blockHashes, chunk...)
+
+		// The next lowHash is the last element in chunk
+		lowHash = chunk[len(chunk)-1]
+	}
+	return blockHashes, nil
+}
+
+func (e *exporter) writeHeaders(blockHashes []*externalapi.DomainHash) error {
+	log.Infof("Exporting %d block headers", len(blockHashes))
+	for offset := 0; offset < len(blockHashes); offset += maxBlocksPerChunk {
+		end := offset + maxBlocksPerChunk
+		if end > len(blockHashes) {
+			end = len(blockHashes)
+		}
+
+		blockHeaders := make([]*appmessage.MsgBlockHeader, 0, end-offset)
+		for _, blockHash := range blockHashes[offset:end] {
+			blockHeader, err := e.consensus.GetBlockHeader(blockHash)
+			if err != nil {
+				return err
+			}
+			blockHeaders = append(blockHeaders, appmessage.DomainBlockHeaderToBlockHeader(blockHeader))
+		}
+
+		err := e.file.writeMessage(appmessage.NewBlockHeadersMessage(blockHeaders))
+		if err != nil {
+			return err
+		}
+	}
+
+	return e.file.writeMessage(appmessage.NewMsgDoneHeaders())
+}
+
+func (e *exporter) writePruningPointUTXOSet(pruningPoint *externalapi.DomainHash) error {
+	log.Infof("Exporting the pruning point UTXO set")
+	var fromOutpoint *externalapi.DomainOutpoint
+	utxoCount := 0
+	for {
+		pruningPointUTXOs, err := e.consensus.GetPruningPointUTXOs(pruningPoint, fromOutpoint, utxoSetChunkSize)
+		if err != nil {
+			return err
+		}
+
+		if len(pruningPointUTXOs) > 0 {
+			outpointAndUTXOEntryPairs :=
+				appmessage.DomainOutpointAndUTXOEntryPairsToOutpointAndUTXOEntryPairs(pruningPointUTXOs)
+			err = e.file.writeMessage(appmessage.NewMsgPruningPointUTXOSetChunk(outpointAndUTXOEntryPairs))
+			if err != nil {
+				return err
+			}
+			utxoCount += len(pruningPointUTXOs)
+			fromOutpoint = pruningPointUTXOs[len(pruningPointUTXOs)-1].Outpoint
+		}
+
+		if len(pruningPointUTXOs) < utxoSetChunkSize {
+			break
+		}
+	}
+	log.Infof("Exported %d UTXOs", utxoCount)
+
+	return e.file.writeMessage(appmessage.NewMsgDonePruningPointUTXOSetChunks())
+}
+
+func (e *exporter) writeBlocks(blockHashes []*externalapi.DomainHash) error {
+	log.Infof("Exporting %d blocks", len(blockHashes))
+	for i, blockHash := range blockHashes {
+		block, found, err := e.consensus.GetBlock(blockHash)
+		if err != nil {
+			return err
+		}
+		if !found {
+			// Blocks that only have a header are left for IBD
+			continue
+		}
+
+		err = e.file.writeMessage(appmessage.DomainBlockToMsgBlock(block))
+		if err != nil {
+			return err
+		}
+
+		if (i+1)%progressLogInterval == 0 {
+			log.Infof("Exported %d out of %d blocks", i+1, len(blockHashes))
+		}
+	}
+	return nil
+}
+
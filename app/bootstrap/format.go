@@ -0,0 +1,313 @@
+package bootstrap
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"io"
+	"os"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter/server/grpcserver/protowire"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// A bootstrap file is laid out as follows:
+//
+//	magic             16 bytes
+//	version           uint32
+//	network name      uint32 length followed by the name
+//	pruning point     32 bytes
+//	messages          a uint32 length followed by a serialized protowire.KarlsendMessage, for each message
+//	end marker        uint32 zero
+//	checksum          SHA-256 of everything that precedes it
+//
+// The messages are the same P2P messages that are sent during IBD with headers proof,
+// in the same order. All integers are little endian.
+
+var magic = []byte("karlsenbootstrap")
+
+// version is the version of the bootstrap file format. It should be bumped
+// whenever the layout of the file or the set of messages in it changes
+const version = 1
+
+// maxMessageSize is the maximum size of a single message in the
+// file, and matches the maximum size of a P2P message
+const maxMessageSize = 1024 * 1024 * 1024 // 1GB
+
+// ErrChecksumMismatch is returned when the checksum of a bootstrap file
+// doesn't match its content
+var ErrChecksumMismatch = errors.New("bootstrap file checksum mismatch")
+
+type fileHeader struct {
+	version      uint32
+	networkName  string
+	pruningPoint *externalapi.DomainHash
+}
+
+type fileWriter struct {
+	file     *os.File
+	buffered *bufio.Writer
+	writer   io.Writer
+	hasher   hash.Hash
+}
+
+func createFile(path string, header *fileHeader) (*fileWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	buffered := bufio.NewWriter(file)
+	hasher := sha256.New()
+	fw := &fileWriter{
+		file:     file,
+		buffered: buffered,
+		writer:   io.MultiWriter(buffered, hasher),
+		hasher:   hasher,
+	}
+
+	err = fw.writeHeader(header)
+	if err != nil {
+		fw.file.Close()
+		return nil, err
+	}
+	return fw, nil
+}
+
+func (fw *fileWriter) writeHeader(header *fileHeader) error {
+	_, err := fw.writer.Write(magic)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = fw.writeUint32(header.version)
+	if err != nil {
+		return err
+	}
+	err = fw.writeBytes([]byte(header.networkName))
+	if err != nil {
+		return err
+	}
+	_, err = fw.writer.Write(header.pruningPoint.ByteSlice())
+	return errors.WithStack(err)
+}
+
+func (fw *fileWriter) writeMessage(message appmessage.Message) error {
+	protoMessage, err := protowire.FromAppMessage(message)
+	if err != nil {
+		return err
+	}
+	messageBytes, err := proto.Marshal(protoMessage)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(messageBytes) == 0 || len(messageBytes) > maxMessageSize {
+		return errors.Errorf("unexpected size %d of message %s", len(messageBytes), message.Command())
+	}
+	return fw.writeBytes(messageBytes)
+}
+
+func (fw *fileWriter) writeBytes(data []byte) error {
+	err := fw.writeUint32(uint32(len(data)))
+	if err != nil {
+		return err
+	}
+	_, err = fw.writer.Write(data)
+	return errors.WithStack(err)
+}
+
+func (fw *fileWriter) writeUint32(value uint32) error {
+	var buffer [4]byte
+	binary.LittleEndian.PutUint32(buffer[:], value)
+	_, err := fw.writer.Write(buffer[:])
+	return errors.WithStack(err)
+}
+
+// close writes the end marker and the checksum, and closes the file
+func (fw *fileWriter) close() error {
+	err := fw.writeUint32(0)
+	if err != nil {
+		fw.file.Close()
+		return err
+	}
+	_, err = fw.buffered.Write(fw.hasher.Sum(nil))
+	if err != nil {
+		fw.file.Close()
+		return errors.WithStack(err)
+	}
+	err = fw.buffered.Flush()
+	if err != nil {
+		fw.file.Close()
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(fw.file.Close())
+}
+
+type fileReader struct {
+	file   *os.File
+	reader io.Reader
+	hasher hash.Hash
+	header *fileHeader
+}
+
+func openFile(path string) (*fileReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	hasher := sha256.New()
+	fr := &fileReader{
+		file:   file,
+		reader: io.TeeReader(bufio.NewReader(file), hasher),
+		hasher: hasher,
+	}
+
+	fr.header, err = fr.readHeader()
+	if err != nil {
+		fr.file.Close()
+		return nil, err
+	}
+	return fr, nil
+}
+
+func (fr *fileReader) readHeader() (*fileHeader, error) {
+	fileMagic := make([]byte, len(magic))
+	_, err := io.ReadFull(fr.reader, fileMagic)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed reading the bootstrap file header")
+	}
+	if !bytes.Equal(fileMagic, magic) {
+		return nil, errors.New("the file is not a bootstrap file")
+	}
+
+	fileVersion, err := fr.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	if fileVersion != version {
+		return nil, errors.Errorf("unsupported bootstrap file version %d, expected version %d", fileVersion, version)
+	}
+
+	networkName, err := fr.readBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	pruningPointBytes := make([]byte, externalapi.DomainHashSize)
+	_, err = io.ReadFull(fr.reader, pruningPointBytes)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	pruningPoint, err := externalapi.NewDomainHashFromByteSlice(pruningPointBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileHeader{
+		version:      fileVersion,
+		networkName:  string(networkName),
+		pruningPoint: pruningPoint,
+	}, nil
+}
+
+// readMessage returns the next message in the file, or
+// nil once the end marker is reached
+func (fr *fileReader) readMessage() (appmessage.Message, error) {
+	messageBytes, err := fr.readBytes()
+	if err != nil {
+		return nil, err
+	}
+	if len(messageBytes) == 0 {
+		return nil, nil
+	}
+
+	protoMessage := &protowire.KarlsendMessage{}
+	err = proto.Unmarshal(messageBytes, protoMessage)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed parsing a message in the bootstrap file")
+	}
+	return protoMessage.ToAppMessage()
+}
+
+func (fr *fileReader) readBytes() ([]byte, error) {
+	length, err := fr.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	if length > maxMessageSize {
+		return nil, errors.Errorf("unexpected message size %d in the bootstrap file", length)
+	}
+	data := make([]byte, length)
+	_, err = io.ReadFull(fr.reader, data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed reading the bootstrap file")
+	}
+	return data, nil
+}
+
+func (fr *fileReader) readUint32() (uint32, error) {
+	var buffer [4]byte
+	_, err := io.ReadFull(fr.reader, buffer[:])
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed reading the bootstrap file")
+	}
+	return binary.LittleEndian.Uint32(buffer[:]), nil
+}
+
+// verifyChecksum reads the checksum that follows the end marker and compares it
+// to the checksum of everything read so far. It must be called once the end
+// marker has been read
+func (fr *fileReader) verifyChecksum() error {
+	expectedChecksum := fr.hasher.Sum(nil)
+
+	checksum := make([]byte, sha256.Size)
+	_, err := io.ReadFull(fr.reader, checksum)
+	if err != nil {
+		return errors.Wrapf(err, "failed reading the bootstrap file checksum")
+	}
+	if !bytes.Equal(checksum, expectedChecksum) {
+		return errors.WithStack(ErrChecksumMismatch)
+	}
+
+	extra, err := io.ReadFull(fr.reader, make([]byte, 1))
+	if extra != 0 || err != io.EOF {
+		return errors.New("unexpected data after the bootstrap file checksum")
+	}
+	return nil
+}
+
+func (fr *fileReader) close() error {
+	return errors.WithStack(fr.file.Close())
+}
+
+// verifyFile reads the whole bootstrap file in the given path and makes
+// sure that it's well-formed and that its checksum matches its content
+func verifyFile(path string) (*fileHeader, error) {
+	fr, err := openFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fr.close()
+
+	for {
+		messageBytes, err := fr.readBytes()
+		if err != nil {
+			return nil, err
+		}
+		if len(messageBytes) == 0 {
+			break
+		}
+	}
+
+	err = fr.verifyChecksum()
+	if err != nil {
+		return nil, err
+	}
+	return fr.header, nil
+}
+
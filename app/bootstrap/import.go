@@ -0,0 +1,489 @@
+package bootstrap
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/ruleerrors"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/dagconfig"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/logger"
+	"github.com/pkg/errors"
+)
+
+type importer struct {
+	domain domain.Domain
+	params *dagconfig.Params
+	file   *fileReader
+}
+
+// Import validates and inserts the content of the bootstrap file in the given path into the
+// consensus of the given domain, through the same APIs that are used by IBD with headers proof.
+// The checksum of the file is verified before anything is inserted.
+// onPruningPointUTXOSetOverride is called if the import replaces the pruning point UTXO set.
+func Import(domain domain.Domain, params *dagconfig.Params, path string,
+	onPruningPointUTXOSetOverride func() error) error {
+
+	onEnd := logger.LogAndMeasureExecutionTime(log, "bootstrap.Import")
+	defer onEnd()
+
+	log.Infof("Verifying the bootstrap file %s", path)
+	header, err := verifyFile(path)
+	if err != nil {
+		return err
+	}
+	if header.networkName != params.Name {
+		return errors.Errorf("the bootstrap file is for network %s, but the node runs on %s",
+			header.networkName, params.Name)
+	}
+
+	file, err := openFile(path)
+	if err != nil {
+		return err
+	}
+	defer file.close()
+
+	im := &importer{
+		domain: domain,
+		params: params,
+		file:   file,
+	}
+
+	shouldApplyPruningPointProof, err := im.shouldApplyPruningPointProof(header.pruningPoint)
+	if err != nil {
+		return err
+	}
+	if shouldApplyPruningPointProof {
+		err = im.importWithPruningPointProof(header.pruningPoint)
+		if err != nil {
+			return err
+		}
+		err = onPruningPointUTXOSetOverride()
+		if err != nil {
+			return err
+		}
+	} else {
+		err = im.importHeadersOnly(header.pruningPoint)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = im.importBlocks()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Imported the bootstrap file %s", path)
+	return nil
+}
+
+// shouldApplyPruningPointProof returns whether the pruning point of the file should replace
+// the pruning point of the node. This is the case if the node doesn't know the pruning point
+// of the file, in which case it's required that the node has no blocks other than the genesis.
+func (im *importer) shouldApplyPruningPointProof(pruningPoint *externalapi.DomainHash) (bool, error) {
+	if pruningPoint.Equal(im.params.GenesisHash) {
+		return false, nil
+	}
+
+	pruningPointInfo, err := im.domain.Consensus().GetBlockInfo(pruningPoint)
+	if err != nil {
+		return false, err
+	}
+	if pruningPointInfo.Exists {
+		log.Infof("The pruning point %s of the bootstrap file is already known", pruningPoint)
+		return false, nil
+	}
+
+	virtualSelectedParent, err := im.domain.Consensus().GetVirtualSelectedParent()
+	if err != nil {
+		return false, err
+	}
+	if !virtualSelectedParent.Equal(im.params.GenesisHash) {
+		return false, errors.Errorf("the pruning point %s of the bootstrap file is unknown, and the "+
+			"node already has blocks. Use --reset-db to import the bootstrap file", pruningPoint)
+	}
+	return true, nil
+}
+
+func (im *importer) importWithPruningPointProof(pruningPoint *externalapi.DomainHash) error {
+	err := im.domain.InitStagingConsensusWithoutGenesis()
+	if err != nil {
+		return err
+	}
+
+	err = im.importPruningPointAndHeaders(pruningPoint)
+	if err != nil {
+		log.Infof("Importing the pruning point was unsuccessful. Deleting the staging consensus. (%s)", err)
+		deleteStagingConsensusErr := im.domain.DeleteStagingConsensus()
+		if deleteStagingConsensusErr != nil {
+			return deleteStagingConsensusErr
+		}
+		return err
+	}
+
+	log.Infof("Imported the pruning point and the block headers. " +
+		"Committing the staging consensus and deleting the previous obsolete one.")
+	return im.domain.CommitStagingConsensus()
+}
+
+func (im *importer) importPruningPointAndHeaders(pruningPoint *externalapi.DomainHash) error {
+	stagingConsensus := im.domain.StagingConsensus()
+
+	err := im.importPruningPointProof(pruningPoint)
+	if err != nil {
+		return err
+	}
+	err = im.importPruningPoints(pruningPoint)
+	if err != nil {
+		return err
+	}
+	err = im.importPruningPointAndItsAnticone(pruningPoint)
+	if err != nil {
+		return err
+	}
+	err = im.importHeaders(stagingConsensus)
+	if err != nil {
+		return err
+	}
+
+	isValid, err := stagingConsensus.IsValidPruningPoint(pruningPoint)
+	if err != nil {
+		return err
+	}
+	if !isValid {
+		return errors.Errorf("invalid pruning point %s", pruningPoint)
+	}
+
+	return im.importPruningPointUTXOSet(pruningPoint)
+}
+
+func (im *importer) importPruningPointProof(pruningPoint *externalapi.DomainHash) error {
+	log.Infof("Importing the pruning point proof")
+	message, err := im.readMessage(appmessage.CmdPruningPointProof)
+	if err != nil {
+		return err
+	}
+	pruningPointProof := appmessage.MsgPruningPointProofToDomainPruningPointProof(message.(*appmessage.MsgPruningPointProof))
+	if len(pruningPointProof.Headers) == 0 || len(pruningPointProof.Headers[0]) == 0 {
+		return errors.New("the pruning point proof is empty")
+	}
+
+	err = im.domain.Consensus().ValidatePruningPointProof(pruningPointProof)
+	if err != nil {
+		return errors.Wrapf(err, "pruning point proof validation failed")
+	}
+
+	proofPruningPoint := consensushashing.HeaderHash(pruningPointProof.Headers[0][len(pruningPointProof.Headers[0])-1])
+	if !proofPruningPoint.Equal(pruningPoint) {
+		return errors.Errorf("the proof pruning point %s is not the pruning point %s of the bootstrap file",
+			proofPruningPoint, pruningPoint)
+	}
+
+	return im.domain.StagingConsensus().ApplyPruningPointProof(pruningPointProof)
+}
+
+func (im *importer) importPruningPoints(pruningPoint *externalapi.DomainHash) error {
+	message, err := im.readMessage(appmessage.CmdPruningPoints)
+	if err != nil {
+		return err
+	}
+	msgPruningPoints := message.(*appmessage.MsgPruningPoints)
+	if len(msgPruningPoints.Headers) == 0 {
+		return errors.New("the bootstrap file has no pruning points")
+	}
+
+	headers := make([]externalapi.BlockHeader, len(msgPruningPoints.Headers))
+	for i, header := range msgPruningPoints.Headers {
+		headers[i] = appmessage.BlockHeaderToDomainBlockHeader(header)
+	}
+
+	arePruningPointsViolatingFinality, err := im.domain.Consensus().ArePruningPointsViolatingFinality(headers)
+	if err != nil {
+		return err
+	}
+	if arePruningPointsViolatingFinality {
+		return errors.New("the pruning points of the bootstrap file are violating finality")
+	}
+
+	lastPruningPoint := consensushashing.HeaderHash(headers[len(headers)-1])
+	if !lastPruningPoint.Equal(pruningPoint) {
+		return errors.Errorf("the last pruning point %s is not the pruning point %s of the bootstrap file",
+			lastPruningPoint, pruningPoint)
+	}
+
+	return im.domain.StagingConsensus().ImportPruningPoints(headers)
+}
+
+func (im *importer) importPruningPointAndItsAnticone(pruningPoint *externalapi.DomainHash) error {
+	log.Infof("Importing the pruning point and its anticone")
+	message, err := im.readMessage(appmessage.CmdTrustedData)
+	if err != nil {
+		return err
+	}
+	msgTrustedData := message.(*appmessage.MsgTrustedData)
+
+	blockCount := 0
+	for {
+		message, err := im.readMessage(appmessage.CmdBlockWithTrustedDataV4, appmessage.CmdDoneBlocksWithTrustedData)
+		if err != nil {
+			return err
+		}
+		msgBlockWithTrustedData, ok := message.(*appmessage.MsgBlockWithTrustedDataV4)
+		if !ok {
+			break
+		}
+
+		if blockCount == 0 && !consensushashing.BlockHash(
+			appmessage.MsgBlockToDomainBlock(msgBlockWithTrustedData.Block)).Equal(pruningPoint) {
+
+			return errors.New("the first block with trusted data is not the pruning point")
+		}
+
+		err = im.processBlockWithTrustedData(msgBlockWithTrustedData, msgTrustedData)
+		if err != nil {
+			return err
+		}
+		blockCount++
+	}
+	if blockCount == 0 {
+		return errors.New("the bootstrap file doesn't contain the pruning point")
+	}
+
+	log.Infof("Imported the pruning point and its anticone. Total blocks: %d", blockCount)
+	return nil
+}
+
+func (im *importer) processBlockWithTrustedData(
+	block *appmessage.MsgBlockWithTrustedDataV4, data *appmessage.MsgTrustedData) error {
+
+	blockWithTrustedData := &externalapi.BlockWithTrustedData{
+		Block:        appmessage.MsgBlockToDomainBlock(block.Block),
+		DAAWindow:    make([]*externalapi.TrustedDataDataDAAHeader, 0, len(block.DAAWindowIndices)),
+		GHOSTDAGData: make([]*externalapi.BlockGHOSTDAGDataHashPair, 0, len(block.GHOSTDAGDataIndices)),
+	}
+
+	for _, index := range block.DAAWindowIndices {
+		if index >= uint64(len(data.DAAWindow)) {
+			return errors.Errorf("DAA window index %d is out of range", index)
+		}
+		blockWithTrustedData.DAAWindow = append(blockWithTrustedData.DAAWindow,
+			appmessage.TrustedDataDataDAABlockV4ToTrustedDataDataDAAHeader(data.DAAWindow[index]))
+	}
+
+	for _, index := range block.GHOSTDAGDataIndices {
+		if index >= uint64(len(data.GHOSTDAGData)) {
+			return errors.Errorf("GHOSTDAG data index %d is out of range", index)
+		}
+		blockWithTrustedData.GHOSTDAGData = append(blockWithTrustedData.GHOSTDAGData,
+			appmessage.GHOSTDAGHashPairToDomainGHOSTDAGHashPair(data.GHOSTDAGData[index]))
+	}
+
+	err := im.domain.StagingConsensus().ValidateAndInsertBlockWithTrustedData(blockWithTrustedData, false)
+	if err != nil {
+		return errors.Wrapf(err, "failed validating block with trusted data %s",
+			consensushashing.BlockHash(blockWithTrustedData.Block))
+	}
+	return nil
+}
+
+// importHeadersOnly skips the pruning point data of the file, if it has any, and
+// inserts its block headers to the current consensus
+func (im *importer) importHeadersOnly(pruningPoint *externalapi.DomainHash) error {
+	hasPruningPointData := !pruningPoint.Equal(im.params.GenesisHash)
+	if hasPruningPointData {
+		err := im.skipUntil(appmessage.CmdDoneBlocksWithTrustedData)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := im.importHeaders(im.domain.Consensus())
+	if err != nil {
+		return err
+	}
+
+	if hasPruningPointData {
+		return im.skipUntil(appmessage.CmdDonePruningPointUTXOSetChunks)
+	}
+	return nil
+}
+
+func (im *importer) importHeaders(consensus externalapi.Consensus) error {
+	headerCount := 0
+	for {
+		message, err := im.readMessage(appmessage.CmdBlockHeaders, appmessage.CmdDoneHeaders)
+		if err != nil {
+			return err
+		}
+		blockHeadersMessage, ok := message.(*appmessage.BlockHeadersMessage)
+		if !ok {
+			break
+		}
+
+		for _, msgBlockHeader := range blockHeadersMessage.BlockHeaders {
+			err := im.processHeader(consensus, msgBlockHeader)
+			if err != nil {
+				return err
+			}
+		}
+		headerCount += len(blockHeadersMessage.BlockHeaders)
+		log.Infof("Imported %d block headers", headerCount)
+	}
+	return nil
+}
+
+func (im *importer) processHeader(consensus externalapi.Consensus, msgBlockHeader *appmessage.MsgBlockHeader) error {
+	block := &externalapi.DomainBlock{
+		Header:       appmessage.BlockHeaderToDomainBlockHeader(msgBlockHeader),
+		Transactions: nil,
+	}
+
+	blockHash := consensushashing.BlockHash(block)
+	blockInfo, err := consensus.GetBlockInfo(blockHash)
+	if err != nil {
+		return err
+	}
+	if blockInfo.Exists {
+		log.Debugf("Block header %s is already in the DAG. Skipping...", blockHash)
+		return nil
+	}
+
+	err = consensus.ValidateAndInsertBlock(block, false)
+	if err != nil && !errors.Is(err, ruleerrors.ErrDuplicateBlock) {
+		return errors.Wrapf(err, "failed to process header %s", blockHash)
+	}
+	return nil
+}
+
+func (im *importer) importPruningPointUTXOSet(pruningPoint *externalapi.DomainHash) (err error) {
+	log.Infof("Importing the pruning point UTXO set")
+	stagingConsensus := im.domain.StagingConsensus()
+	defer func() {
+		clearErr := stagingConsensus.ClearImportedPruningPointData()
+		if err == nil {
+			err = clearErr
+		}
+	}()
+
+	utxoCount := 0
+	for {
+		message, err := im.readMessage(appmessage.CmdPruningPointUTXOSetChunk, appmessage.CmdDonePruningPointUTXOSetChunks)
+		if err != nil {
+			return err
+		}
+		chunk, ok := message.(*appmessage.MsgPruningPointUTXOSetChunk)
+		if !ok {
+			break
+		}
+
+		err = stagingConsensus.AppendImportedPruningPointUTXOs(
+			appmessage.OutpointAndUTXOEntryPairsToDomainOutpointAndUTXOEntryPairs(chunk.OutpointAndUTXOEntryPairs))
+		if err != nil {
+			return err
+		}
+		utxoCount += len(chunk.OutpointAndUTXOEntryPairs)
+	}
+	log.Infof("Imported %d UTXOs", utxoCount)
+
+	err = stagingConsensus.ValidateAndInsertImportedPruningPoint(pruningPoint)
+	if err != nil {
+		return errors.Wrapf(err, "error with the pruning point UTXO set")
+	}
+	return nil
+}
+
+func (im *importer) importBlocks() error {
+	log.Infof("Importing blocks")
+	consensus := im.domain.Consensus()
+	blockCount := 0
+	var highestDAAScore uint64
+	for {
+		message, err := im.readMessageOrEnd(appmessage.CmdBlock)
+		if err != nil {
+			return err
+		}
+		if message == nil {
+			break
+		}
+
+		block := appmessage.MsgBlockToDomainBlock(message.(*appmessage.MsgBlock))
+		blockHash := consensushashing.BlockHash(block)
+		blockInfo, err := consensus.GetBlockInfo(blockHash)
+		if err != nil {
+			return err
+		}
+		if blockInfo.HasBody() {
+			continue
+		}
+
+		err = consensus.ValidateAndInsertBlock(block, false)
+		if err != nil {
+			if errors.Is(err, ruleerrors.ErrDuplicateBlock) {
+				continue
+			}
+			return errors.Wrapf(err, "invalid block %s", blockHash)
+		}
+
+		blockCount++
+		highestDAAScore = block.Header.DAAScore()
+		if blockCount%progressLogInterval == 0 {
+			log.Infof("Imported %d blocks (DAA score %d)", blockCount, highestDAAScore)
+		}
+	}
+	log.Infof("Imported %d blocks. Resolving the virtual", blockCount)
+
+	return consensus.ResolveVirtual(func(virtualDAAScoreStart uint64, virtualDAAScore uint64) {
+		log.Infof("Resolving virtual. Current DAA score: %d, target DAA score: %d", virtualDAAScore, highestDAAScore)
+	})
+}
+
+// readMessage reads the next message in the file and
+// makes sure it's of one of the expected types
+func (im *importer) readMessage(expectedCommands ...appmessage.MessageCommand) (appmessage.Message, error) {
+	message, err := im.readMessageOrEnd(expectedCommands...)
+	if err != nil {
+		return nil, err
+	}
+	if message == nil {
+		return nil, errors.Errorf("unexpected end of the bootstrap file. Expected: %s", expectedCommands)
+	}
+	return message, nil
+}
+
+// readMessageOrEnd is the same as readMessage, except that it
+// returns nil once the end of the file is reached
+func (im *importer) readMessageOrEnd(expectedCommands ...appmessage.MessageCommand) (appmessage.Message, error) {
+	message, err := im.file.readMessage()
+	if err != nil {
+		return nil, err
+	}
+	if message == nil {
+		return nil, nil
+	}
+	for _, expectedCommand := range expectedCommands {
+		if message.Command() == expectedCommand {
+			return message, nil
+		}
+	}
+	return nil, errors.Errorf("unexpected message in the bootstrap file. Expected: %s, got: %s",
+		expectedCommands, message.Command())
+}
+
+// skipUntil skips all the messages in the file up to and including
+// the first message of the given type
+func (im *importer) skipUntil(command appmessage.MessageCommand) error {
+	for {
+		message, err := im.file.readMessage()
+		if err != nil {
+			return err
+		}
+		if message == nil {
+			return errors.Errorf("unexpected end of the bootstrap file. Expected: %s", command)
+		}
+		if message.Command() == command {
+			return nil
+		}
+	}
+}
+
@@ -0,0 +1,8 @@
+package bootstrap
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/logger"
+)
+
+var log = logger.RegisterSubSystem("BOOT")
+
@@ -6,8 +6,10 @@ import (
 
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
 
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/miningmanager/blocktemplatebuilder"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/miningmanager/mempool"
 
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/bootstrap"
 	"github.com/karlsend/PYVERT/testfork/karlsend/app/protocol"
 	"github.com/karlsend/PYVERT/testfork/karlsend/app/rpc"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain"
@@ -79,16 +81,7 @@ func (a *ComponentManager) Stop() {
 func NewComponentManager(cfg *config.Config, db infrastructuredatabase.Database, interrupt chan<- struct{}) (
 	*ComponentManager, error) {
 
-	consensusConfig := consensus.Config{
-		Params:                          *cfg.ActiveNetParams,
-		IsArchival:                      cfg.IsArchivalNode,
-		EnableSanityCheckPruningUTXOSet: cfg.EnableSanityCheckPruningUTXOSet,
-	}
-	mempoolConfig := mempool.DefaultConfig(&consensusConfig.Params)
-	mempoolConfig.MaximumOrphanTransactionCount = cfg.MaxOrphanTxs
-	mempoolConfig.MinimumRelayTransactionFee = cfg.MinRelayTxFee
-
-	domain, err := domain.New(&consensusConfig, mempoolConfig, db)
+	domain, err := newDomain(cfg, db)
 	if err != nil {
 		return nil, err
 	}
@@ -121,7 +114,7 @@ func NewComponentManager(cfg *config.Config, db infrastructuredatabase.Database,
 	if err != nil {
 		return nil, err
 	}
-	rpcManager := setupRPC(cfg, domain, netAdapter, protocolManager, connectionManager, addressManager, utxoIndex, domain.ConsensusEventsChannel(), interrupt)
+	rpcManager := setupRPC(cfg, domain, db, netAdapter, protocolManager, connectionManager, addressManager, utxoIndex, domain.ConsensusEventsChannel(), interrupt)
 
 	return &ComponentManager{
 		cfg:               cfg,
@@ -134,9 +127,35 @@ func NewComponentManager(cfg *config.Config, db infrastructuredatabase.Database,
 
 }
 
+func newDomain(cfg *config.Config, db infrastructuredatabase.Database) (domain.Domain, error) {
+	consensusConfig := consensus.Config{
+		Params:                          *cfg.ActiveNetParams,
+		IsArchival:                      cfg.IsArchivalNode,
+		EnableSanityCheckPruningUTXOSet: cfg.EnableSanityCheckPruningUTXOSet,
+	}
+	mempoolConfig := mempool.DefaultConfig(&consensusConfig.Params)
+	mempoolConfig.MaximumOrphanTransactionCount = cfg.MaxOrphanTxs
+	mempoolConfig.MinimumRelayTransactionFee = cfg.MinRelayTxFee
+
+	blockTemplatePolicy := blocktemplatebuilder.DefaultPolicy(&consensusConfig.Params)
+	blockTemplatePolicy.HighFeeMassRatio = cfg.TemplateHighFeeRatio
+	blockTemplatePolicy.PriorityScriptPublicKeys = cfg.TemplatePriorityScriptPublicKeys
+	blockTemplatePolicy.ExcludedScriptClasses = cfg.TemplateExcludedScriptClasses
+
+	return domain.New(&consensusConfig, mempoolConfig, blockTemplatePolicy, db)
+}
+
+// importBootstrap imports the bootstrap file in the given path. It must
+// be called before Start, so that the node doesn't begin IBD meanwhile
+func (a *ComponentManager) importBootstrap(path string) error {
+	flowContext := a.protocolManager.Context()
+	return bootstrap.Import(flowContext.Domain(), a.cfg.ActiveNetParams, path, flowContext.OnPruningPointUTXOSetOverride)
+}
+
 func setupRPC(
 	cfg *config.Config,
 	domain domain.Domain,
+	db infrastructuredatabase.Database,
 	netAdapter *netadapter.NetAdapter,
 	protocolManager *protocol.Manager,
 	connectionManager *connmanager.ConnectionManager,
@@ -149,6 +168,7 @@ func setupRPC(
 	rpcManager := rpc.NewManager(
 		cfg,
 		domain,
+		db,
 		netAdapter,
 		protocolManager,
 		connectionManager,
@@ -80,7 +80,9 @@ func (f *FlowContext) broadcastTransactionsAfterBlockAdded(
 		if err != nil {
 			return err
 		}
-		txIDsToRebroadcast = consensushashing.TransactionIDs(txsToRebroadcast)
+		// Transactions that are still in their Dandelion stem phase are broadcast once their
+		// embargo expires, and broadcasting them earlier would reveal that they originated here
+		txIDsToRebroadcast = f.withoutDandelionEmbargoes(consensushashing.TransactionIDs(txsToRebroadcast))
 		f.lastRebroadcastTime = time.Now()
 	}
 
@@ -62,6 +62,11 @@ type FlowContext struct {
 	lastTransactionIDPropagationTime time.Time
 	transactionIDPropagationLock     sync.Mutex
 
+	stemPeer              *peerpkg.Peer
+	stemPeerSelectionTime time.Time
+	dandelionEmbargoes    map[externalapi.DomainTransactionID]*time.Timer
+	dandelionLock         sync.Mutex
+
 	shutdownChan chan struct{}
 }
 
@@ -82,6 +87,7 @@ func New(cfg *config.Config, domain domain.Domain, addressManager *addressmanage
 		timeStarted:                      mstime.Now().UnixMilliseconds(),
 		transactionIDsToPropagate:        []*externalapi.DomainTransactionID{},
 		lastTransactionIDPropagationTime: time.Now(),
+		dandelionEmbargoes:               make(map[externalapi.DomainTransactionID]*time.Timer),
 		shutdownChan:                     make(chan struct{}),
 	}
 }
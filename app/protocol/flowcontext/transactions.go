@@ -1,16 +1,35 @@
 package flowcontext
 
 import (
+	"math/rand"
 	"time"
 
 	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	peerpkg "github.com/karlsend/PYVERT/testfork/karlsend/app/protocol/peer"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter"
 )
 
 // TransactionIDPropagationInterval is the interval between transaction IDs propagations
 const TransactionIDPropagationInterval = 500 * time.Millisecond
 
+const (
+	// dandelionFluffProbability is the probability that a node which receives
+	// transactions in their stem phase broadcasts them instead of relaying
+	// them to its own stem peer
+	dandelionFluffProbability = 0.1
+
+	// dandelionStemPeerEpoch is how long the same stem peer is used before
+	// a new one is picked
+	dandelionStemPeerEpoch = 10 * time.Minute
+
+	// A node that relays a stem transaction broadcasts it itself if it isn't
+	// seen broadcast by the network within a random duration in this range
+	dandelionMinEmbargoDuration = 10 * time.Second
+	dandelionMaxEmbargoDuration = 30 * time.Second
+)
+
 // AddTransaction adds transaction to the mempool and propagates it.
 func (f *FlowContext) AddTransaction(tx *externalapi.DomainTransaction, allowOrphan bool) error {
 	acceptedTransactions, err := f.Domain().MiningManager().ValidateAndInsertTransaction(tx, true, allowOrphan)
@@ -19,6 +38,9 @@ func (f *FlowContext) AddTransaction(tx *externalapi.DomainTransaction, allowOrp
 	}
 
 	acceptedTransactionIDs := consensushashing.TransactionIDs(acceptedTransactions)
+	if f.Config().Dandelion {
+		return f.stemTransactions(acceptedTransactionIDs, nil)
+	}
 	return f.EnqueueTransactionIDsForPropagation(acceptedTransactionIDs)
 }
 
@@ -45,6 +67,8 @@ func (f *FlowContext) OnTransactionAddedToMempool() {
 // propagate. The IDs will be broadcast to all peers within a single transaction Inv message.
 // The broadcast itself may happen only during a subsequent call to this method
 func (f *FlowContext) EnqueueTransactionIDsForPropagation(transactionIDs []*externalapi.DomainTransactionID) error {
+	f.ClearDandelionEmbargoes(transactionIDs)
+
 	f.transactionIDPropagationLock.Lock()
 	defer f.transactionIDPropagationLock.Unlock()
 
@@ -80,3 +104,163 @@ func (f *FlowContext) maybePropagateTransactions() error {
 	return nil
 }
 
+// RelayStemTransactions relays transactions that were received from the given
+// peer in their Dandelion stem phase. The transactions are either relayed to a
+// single stem peer, or, with probability dandelionFluffProbability, broadcast
+// to all peers.
+func (f *FlowContext) RelayStemTransactions(transactionIDs []*externalapi.DomainTransactionID, source *peerpkg.Peer) error {
+	if !f.Config().Dandelion || rand.Float64() < dandelionFluffProbability {
+		return f.EnqueueTransactionIDsForPropagation(transactionIDs)
+	}
+	return f.stemTransactions(transactionIDs, source)
+}
+
+// stemTransactions sends the given transactions to the current stem peer, and
+// embargoes them until they are seen broadcast by the network. If there's no
+// stem peer available, the transactions are broadcast right away.
+func (f *FlowContext) stemTransactions(transactionIDs []*externalapi.DomainTransactionID, source *peerpkg.Peer) error {
+	if len(transactionIDs) == 0 {
+		return nil
+	}
+
+	stemPeer := f.selectStemPeer(source)
+	if stemPeer == nil {
+		log.Debugf("Dandelion: no stem peer is available. Broadcasting %d transactions", len(transactionIDs))
+		return f.EnqueueTransactionIDsForPropagation(transactionIDs)
+	}
+
+	f.embargoTransactions(transactionIDs)
+
+	log.Debugf("Dandelion: relaying %d stem transactions to %s", len(transactionIDs), stemPeer)
+	for len(transactionIDs) > 0 {
+		transactionIDsToSend := transactionIDs
+		if len(transactionIDsToSend) > appmessage.MaxInvPerTxInvMsg {
+			transactionIDsToSend = transactionIDs[:appmessage.MaxInvPerTxInvMsg]
+		}
+		inv := appmessage.NewMsgInvStemTransaction(transactionIDsToSend)
+		err := f.netAdapter.P2PBroadcast([]*netadapter.NetConnection{stemPeer.Connection()}, inv)
+		if err != nil {
+			return err
+		}
+		transactionIDs = transactionIDs[len(transactionIDsToSend):]
+	}
+	return nil
+}
+
+// selectStemPeer returns the peer stem transactions are relayed to. A new stem
+// peer is picked at random whenever the previous one disconnects or has been
+// used for longer than dandelionStemPeerEpoch. The source peer is never selected,
+// so that transactions aren't relayed back to where they came from.
+// Returns nil if no other peer supports Dandelion.
+func (f *FlowContext) selectStemPeer(source *peerpkg.Peer) *peerpkg.Peer {
+	f.dandelionLock.Lock()
+	defer f.dandelionLock.Unlock()
+
+	var candidates []*peerpkg.Peer
+	isStemPeerCandidate := false
+	for _, peer := range f.Peers() {
+		if peer == source || !peer.HasService(appmessage.SFNodeDandelion) {
+			continue
+		}
+		if peer == f.stemPeer {
+			isStemPeerCandidate = true
+		}
+		candidates = append(candidates, peer)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	if !isStemPeerCandidate || time.Since(f.stemPeerSelectionTime) > dandelionStemPeerEpoch {
+		f.stemPeer = candidates[rand.Intn(len(candidates))]
+		f.stemPeerSelectionTime = time.Now()
+		log.Debugf("Dandelion: selected %s as the stem peer", f.stemPeer)
+	}
+	return f.stemPeer
+}
+
+func (f *FlowContext) embargoTransactions(transactionIDs []*externalapi.DomainTransactionID) {
+	f.dandelionLock.Lock()
+	defer f.dandelionLock.Unlock()
+
+	for _, transactionID := range transactionIDs {
+		if _, ok := f.dandelionEmbargoes[*transactionID]; ok {
+			continue
+		}
+		transactionID := transactionID
+		embargoDuration := dandelionMinEmbargoDuration +
+			time.Duration(rand.Int63n(int64(dandelionMaxEmbargoDuration-dandelionMinEmbargoDuration)))
+		f.dandelionEmbargoes[*transactionID] = time.AfterFunc(embargoDuration, func() {
+			f.onDandelionEmbargoExpired(transactionID)
+		})
+	}
+}
+
+// onDandelionEmbargoExpired broadcasts a stem transaction that wasn't seen
+// broadcast by the network in time, in case the stem phase broke off somewhere
+// down the line.
+func (f *FlowContext) onDandelionEmbargoExpired(transactionID *externalapi.DomainTransactionID) {
+	f.dandelionLock.Lock()
+	_, ok := f.dandelionEmbargoes[*transactionID]
+	delete(f.dandelionEmbargoes, *transactionID)
+	f.dandelionLock.Unlock()
+	if !ok {
+		return
+	}
+
+	select {
+	case <-f.shutdownChan:
+		return
+	default:
+	}
+
+	if _, _, ok := f.Domain().MiningManager().GetTransaction(transactionID, true, false); !ok {
+		return
+	}
+	log.Debugf("Dandelion: embargo of transaction %s expired. Broadcasting it", transactionID)
+	err := f.EnqueueTransactionIDsForPropagation([]*externalapi.DomainTransactionID{transactionID})
+	if err != nil {
+		log.Warnf("Failed to broadcast transaction %s after its embargo expired: %s", transactionID, err)
+	}
+}
+
+// ClearDandelionEmbargoes stops waiting for the given stem transactions to be
+// broadcast. It should be called once they are seen broadcast by the network.
+func (f *FlowContext) ClearDandelionEmbargoes(transactionIDs []*externalapi.DomainTransactionID) {
+	f.dandelionLock.Lock()
+	defer f.dandelionLock.Unlock()
+
+	if len(f.dandelionEmbargoes) == 0 {
+		return
+	}
+	for _, transactionID := range transactionIDs {
+		timer, ok := f.dandelionEmbargoes[*transactionID]
+		if !ok {
+			continue
+		}
+		timer.Stop()
+		delete(f.dandelionEmbargoes, *transactionID)
+	}
+}
+
+// withoutDandelionEmbargoes returns the given transaction IDs, excluding
+// transactions that are still embargoed in their Dandelion stem phase
+func (f *FlowContext) withoutDandelionEmbargoes(
+	transactionIDs []*externalapi.DomainTransactionID) []*externalapi.DomainTransactionID {
+
+	f.dandelionLock.Lock()
+	defer f.dandelionLock.Unlock()
+
+	if len(f.dandelionEmbargoes) == 0 {
+		return transactionIDs
+	}
+	filtered := make([]*externalapi.DomainTransactionID, 0, len(transactionIDs))
+	for _, transactionID := range transactionIDs {
+		if _, ok := f.dandelionEmbargoes[*transactionID]; ok {
+			continue
+		}
+		filtered = append(filtered, transactionID)
+	}
+	return filtered
+}
+
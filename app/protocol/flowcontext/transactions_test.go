@@ -0,0 +1,42 @@
+package flowcontext
+
+import (
+	"testing"
+	"time"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+)
+
+func TestWithoutDandelionEmbargoes(t *testing.T) {
+	flowContext := &FlowContext{
+		dandelionEmbargoes: make(map[externalapi.DomainTransactionID]*time.Timer),
+	}
+	transactionIDs := []*externalapi.DomainTransactionID{
+		externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{1}),
+		externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{2}),
+		externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{3}),
+	}
+
+	filtered := flowContext.withoutDandelionEmbargoes(transactionIDs)
+	if len(filtered) != len(transactionIDs) {
+		t.Fatalf("Expected all %d transactions without embargoes, but got %d", len(transactionIDs), len(filtered))
+	}
+
+	// Embargo the second transaction, without letting its embargo expire during the test
+	flowContext.dandelionEmbargoes[*transactionIDs[1]] = time.NewTimer(time.Hour)
+	defer flowContext.dandelionEmbargoes[*transactionIDs[1]].Stop()
+
+	filtered = flowContext.withoutDandelionEmbargoes(transactionIDs)
+	if len(filtered) != 2 || !filtered[0].Equal(transactionIDs[0]) || !filtered[1].Equal(transactionIDs[2]) {
+		t.Fatalf("Expected the embargoed transaction %s to be excluded, but got %s", transactionIDs[1], filtered)
+	}
+
+	// Once the transaction is seen broadcast by the network it's no longer excluded
+	flowContext.ClearDandelionEmbargoes(transactionIDs[1:2])
+	filtered = flowContext.withoutDandelionEmbargoes(transactionIDs)
+	if len(filtered) != len(transactionIDs) {
+		t.Fatalf("Expected all %d transactions after clearing the embargo, but got %d",
+			len(transactionIDs), len(filtered))
+	}
+}
+
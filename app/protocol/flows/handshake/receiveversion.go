@@ -20,7 +20,7 @@ var (
 	// connected peer may support.
 	minAcceptableProtocolVersion = uint32(5)
 
-	maxAcceptableProtocolVersion = uint32(5)
+	maxAcceptableProtocolVersion = uint32(6)
 )
 
 type receiveVersionFlow struct {
@@ -100,6 +100,17 @@ func (flow *receiveVersionFlow) start() (*appmessage.NetAddress, error) {
 		return nil, protocolerrors.New(false, "incompatible subnetworks")
 	}
 
+	// Disconnect peers that fell back to a plaintext connection while we
+	// require encryption. The transport normally refuses these before the
+	// handshake, so this only guards against misconfiguration.
+	isEncrypted := flow.peer.Connection().IsEncrypted()
+	if flow.Config().P2PEncryptionRequired && !isEncrypted {
+		return nil, protocolerrors.New(false, "unencrypted connections are not allowed")
+	}
+	if !isEncrypted && flow.Config().P2PEncryption && msgVersion.HasService(appmessage.SFNodeEncryptedTransport) {
+		log.Debugf("Peer %s supports encryption but is connected over plaintext", flow.peer)
+	}
+
 	if flow.Config().ProtocolVersion > maxAcceptableProtocolVersion {
 		return nil, errors.Errorf("%d is a non existing protocol version", flow.Config().ProtocolVersion)
 	}
@@ -66,6 +66,12 @@ func (flow *sendVersionFlow) start() error {
 
 	// Advertise the services flag
 	msg.Services = defaultServices
+	if flow.Config().P2PEncryption {
+		msg.AddService(appmessage.SFNodeEncryptedTransport)
+	}
+	if flow.Config().Dandelion && !flow.Config().BlocksOnly {
+		msg.AddService(appmessage.SFNodeDandelion)
+	}
 
 	// Advertise our max supported protocol version.
 	msg.ProtocolVersion = flow.Config().ProtocolVersion
@@ -0,0 +1,155 @@
+package blockrelay
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/rand"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/protocol/protocolerrors"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+)
+
+// compactBlocksProtocolVersion is the first protocol version in which
+// relay blocks are requested in their compact form
+const compactBlocksProtocolVersion = 6
+
+// shortIDLength is the amount of bytes of a transaction ID that are used
+// as its short ID within a compact block
+const shortIDLength = 6
+
+// shortIDHasher calculates the short IDs of a block's transactions. Short IDs
+// are keyed by the block hash and a per-message nonce, so that collisions
+// can't be crafted in advance and don't repeat between blocks.
+type shortIDHasher struct {
+	hasher hash.Hash
+}
+
+func newShortIDHasher(blockHash *externalapi.DomainHash, nonce uint64) *shortIDHasher {
+	key := make([]byte, externalapi.DomainHashSize+8)
+	copy(key, blockHash.ByteSlice())
+	binary.LittleEndian.PutUint64(key[externalapi.DomainHashSize:], nonce)
+
+	hasher, err := blake2b.New256(key)
+	if err != nil {
+		panic(errors.Wrapf(err, "key of length %d is invalid for blake2b", len(key)))
+	}
+	return &shortIDHasher{hasher: hasher}
+}
+
+func (h *shortIDHasher) shortID(transactionID *externalapi.DomainTransactionID) uint64 {
+	h.hasher.Reset()
+	h.hasher.Write(transactionID.ByteSlice())
+	var shortIDBytes [8]byte
+	copy(shortIDBytes[:shortIDLength], h.hasher.Sum(nil))
+	return binary.LittleEndian.Uint64(shortIDBytes[:])
+}
+
+// domainBlockToCompactBlock converts the given block to a MsgCompactBlock.
+// Only the coinbase transaction is prefilled, since all other transactions
+// have most likely already been relayed to the receiving peer.
+func domainBlockToCompactBlock(block *externalapi.DomainBlock) *appmessage.MsgCompactBlock {
+	blockHash := consensushashing.BlockHash(block)
+	nonce := rand.Uint64()
+	hasher := newShortIDHasher(blockHash, nonce)
+
+	prefilledTransactions := []*appmessage.PrefilledTransaction{{
+		Index:       0,
+		Transaction: appmessage.DomainTransactionToMsgTx(block.Transactions[0]),
+	}}
+	shortIDs := make([]uint64, len(block.Transactions)-1)
+	for i, transaction := range block.Transactions[1:] {
+		shortIDs[i] = hasher.shortID(consensushashing.TransactionID(transaction))
+	}
+
+	header := appmessage.DomainBlockHeaderToBlockHeader(block.Header)
+	return appmessage.NewMsgCompactBlock(header, nonce, shortIDs, prefilledTransactions)
+}
+
+// findMempoolTransactionsFunc returns the mempool transactions whose IDs match isMatch
+type findMempoolTransactionsFunc func(isMatch func(transactionID *externalapi.DomainTransactionID) bool) []*externalapi.DomainTransaction
+
+// reconstructBlockTransactions fills in the transactions of the given compact
+// block from its prefilled transactions and the mempool transactions found by
+// findMempoolTransactions. Only mempool transactions whose short ID appears in
+// the compact block are looked up, so the mempool isn't copied as a whole.
+// The indexes of transactions that couldn't be found are returned in
+// missingIndexes, and their place in the returned transactions is left nil.
+//
+// Note that a short ID might match the wrong transaction, so the result must
+// be verified against the block's merkle root.
+func reconstructBlockTransactions(blockHash *externalapi.DomainHash, compactBlock *appmessage.MsgCompactBlock,
+	findMempoolTransactions findMempoolTransactionsFunc) (
+	transactions []*externalapi.DomainTransaction, missingIndexes []uint32, err error) {
+
+	transactionCount := len(compactBlock.ShortIDs) + len(compactBlock.PrefilledTransactions)
+	if len(compactBlock.PrefilledTransactions) == 0 {
+		return nil, nil, protocolerrors.Errorf(true, "compact block %s has no prefilled coinbase", blockHash)
+	}
+	transactions = make([]*externalapi.DomainTransaction, transactionCount)
+	for i, prefilledTransaction := range compactBlock.PrefilledTransactions {
+		index := int(prefilledTransaction.Index)
+		if index >= transactionCount {
+			return nil, nil, protocolerrors.Errorf(true, "prefilled transaction index %d of compact block "+
+				"%s is out of range", index, blockHash)
+		}
+		if i > 0 && prefilledTransaction.Index <= compactBlock.PrefilledTransactions[i-1].Index {
+			return nil, nil, protocolerrors.Errorf(true, "prefilled transactions of compact block %s "+
+				"are not sorted by index", blockHash)
+		}
+		transactions[index] = appmessage.MsgTxToDomainTransaction(prefilledTransaction.Transaction)
+	}
+	if transactions[0] == nil {
+		return nil, nil, protocolerrors.Errorf(true, "compact block %s has no prefilled coinbase", blockHash)
+	}
+
+	blockShortIDs := make(map[uint64]struct{}, len(compactBlock.ShortIDs))
+	for _, shortID := range compactBlock.ShortIDs {
+		blockShortIDs[shortID] = struct{}{}
+	}
+	hasher := newShortIDHasher(blockHash, compactBlock.ShortIDNonce)
+	matchingTransactionIDs := make(map[externalapi.DomainTransactionID]uint64)
+	matchingTransactions := findMempoolTransactions(func(transactionID *externalapi.DomainTransactionID) bool {
+		shortID := hasher.shortID(transactionID)
+		if _, ok := blockShortIDs[shortID]; !ok {
+			return false
+		}
+		matchingTransactionIDs[*transactionID] = shortID
+		return true
+	})
+
+	mempoolTransactionsByShortID := make(map[uint64]*externalapi.DomainTransaction, len(matchingTransactions))
+	collidingShortIDs := make(map[uint64]struct{})
+	for _, transaction := range matchingTransactions {
+		shortID, ok := matchingTransactionIDs[*consensushashing.TransactionID(transaction)]
+		if !ok {
+			continue
+		}
+		if _, ok := mempoolTransactionsByShortID[shortID]; ok {
+			collidingShortIDs[shortID] = struct{}{}
+			continue
+		}
+		mempoolTransactionsByShortID[shortID] = transaction
+	}
+
+	shortIDIndex := 0
+	for i := range transactions {
+		if transactions[i] != nil {
+			continue
+		}
+		shortID := compactBlock.ShortIDs[shortIDIndex]
+		shortIDIndex++
+
+		transaction, ok := mempoolTransactionsByShortID[shortID]
+		if _, isColliding := collidingShortIDs[shortID]; !ok || isColliding {
+			missingIndexes = append(missingIndexes, uint32(i))
+			continue
+		}
+		transactions[i] = transaction
+	}
+	return transactions, missingIndexes, nil
+}
+
@@ -0,0 +1,131 @@
+package blockrelay
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/blockheader"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/merkle"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/subnetworks"
+)
+
+func newTestTransaction(subnetworkID externalapi.DomainSubnetworkID, payload byte) *externalapi.DomainTransaction {
+	return &externalapi.DomainTransaction{
+		Inputs:       []*externalapi.DomainTransactionInput{},
+		Outputs:      []*externalapi.DomainTransactionOutput{},
+		SubnetworkID: subnetworkID,
+		Payload:      []byte{payload},
+	}
+}
+
+func newTestBlock(transactions []*externalapi.DomainTransaction) *externalapi.DomainBlock {
+	header := blockheader.NewImmutableBlockHeader(0, nil, merkle.CalculateHashMerkleRoot(transactions),
+		&externalapi.DomainHash{}, &externalapi.DomainHash{}, 0, 0, 0, 0, 0, big.NewInt(0), &externalapi.DomainHash{})
+	return &externalapi.DomainBlock{Header: header, Transactions: transactions}
+}
+
+// newTestMempool returns a findMempoolTransactionsFunc over the given transactions, along
+// with a counter of the transactions it has returned
+func newTestMempool(mempoolTransactions []*externalapi.DomainTransaction) (findMempoolTransactionsFunc, *int) {
+	foundCount := 0
+	return func(isMatch func(transactionID *externalapi.DomainTransactionID) bool) []*externalapi.DomainTransaction {
+		var found []*externalapi.DomainTransaction
+		for _, transaction := range mempoolTransactions {
+			if isMatch(consensushashing.TransactionID(transaction)) {
+				found = append(found, transaction.Clone())
+			}
+		}
+		foundCount += len(found)
+		return found
+	}, &foundCount
+}
+
+func TestCompactBlockReconstruction(t *testing.T) {
+	transactions := []*externalapi.DomainTransaction{newTestTransaction(subnetworks.SubnetworkIDCoinbase, 0)}
+	for i := byte(1); i <= 4; i++ {
+		transactions = append(transactions, newTestTransaction(subnetworks.SubnetworkIDNative, i))
+	}
+	block := newTestBlock(transactions)
+	blockHash := consensushashing.BlockHash(block)
+
+	compactBlock := domainBlockToCompactBlock(block)
+	if len(compactBlock.ShortIDs) != len(transactions)-1 {
+		t.Fatalf("expected %d short IDs but got %d", len(transactions)-1, len(compactBlock.ShortIDs))
+	}
+
+	// The mempool is missing the block's third transaction, and holds an unrelated one
+	mempoolTransactions := []*externalapi.DomainTransaction{
+		transactions[4], transactions[1], newTestTransaction(subnetworks.SubnetworkIDNative, 5), transactions[2],
+	}
+	findMempoolTransactions, foundCount := newTestMempool(mempoolTransactions)
+	reconstructedTransactions, missingIndexes, err := reconstructBlockTransactions(blockHash, compactBlock, findMempoolTransactions)
+	if err != nil {
+		t.Fatalf("reconstructBlockTransactions: %+v", err)
+	}
+	if *foundCount != 3 {
+		t.Fatalf("expected only the 3 mempool transactions of the block to be found, but %d were found", *foundCount)
+	}
+	if !reflect.DeepEqual(missingIndexes, []uint32{3}) {
+		t.Fatalf("expected missing indexes [3] but got %v", missingIndexes)
+	}
+	for i, transaction := range reconstructedTransactions {
+		if i == 3 {
+			if transaction != nil {
+				t.Fatalf("expected the missing transaction to be nil")
+			}
+			continue
+		}
+		if !consensushashing.TransactionID(transaction).Equal(consensushashing.TransactionID(transactions[i])) {
+			t.Fatalf("transaction %d was reconstructed incorrectly", i)
+		}
+	}
+
+	reconstructedTransactions[3] = transactions[3]
+	if !merkle.CalculateHashMerkleRoot(reconstructedTransactions).Equal(block.Header.HashMerkleRoot()) {
+		t.Fatalf("the reconstructed block does not match its merkle root")
+	}
+}
+
+func TestCompactBlockShortIDsAreKeyed(t *testing.T) {
+	transactionID := consensushashing.TransactionID(newTestTransaction(subnetworks.SubnetworkIDNative, 1))
+	blockHash := &externalapi.DomainHash{}
+
+	shortID := newShortIDHasher(blockHash, 0).shortID(transactionID)
+	if shortID >= 1<<(8*shortIDLength) {
+		t.Fatalf("short ID %x is longer than %d bytes", shortID, shortIDLength)
+	}
+	if shortID == newShortIDHasher(blockHash, 1).shortID(transactionID) {
+		t.Fatalf("expected short IDs with different nonces to differ")
+	}
+	otherBlockHash := externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{1})
+	if shortID == newShortIDHasher(otherBlockHash, 0).shortID(transactionID) {
+		t.Fatalf("expected short IDs of different blocks to differ")
+	}
+}
+
+func TestCompactBlockInvalidPrefilledTransactions(t *testing.T) {
+	transactions := []*externalapi.DomainTransaction{
+		newTestTransaction(subnetworks.SubnetworkIDCoinbase, 0),
+		newTestTransaction(subnetworks.SubnetworkIDNative, 1),
+	}
+	block := newTestBlock(transactions)
+	blockHash := consensushashing.BlockHash(block)
+
+	compactBlock := domainBlockToCompactBlock(block)
+	compactBlock.PrefilledTransactions[0].Index = 2
+	findMempoolTransactions, _ := newTestMempool(nil)
+	_, _, err := reconstructBlockTransactions(blockHash, compactBlock, findMempoolTransactions)
+	if err == nil {
+		t.Fatalf("expected an out of range prefilled transaction index to be rejected")
+	}
+
+	compactBlock.PrefilledTransactions = nil
+	_, _, err = reconstructBlockTransactions(blockHash, compactBlock, findMempoolTransactions)
+	if err == nil {
+		t.Fatalf("expected a compact block without a prefilled coinbase to be rejected")
+	}
+}
+
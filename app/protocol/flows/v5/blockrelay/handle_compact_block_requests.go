@@ -0,0 +1,81 @@
+package blockrelay
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	peerpkg "github.com/karlsend/PYVERT/testfork/karlsend/app/protocol/peer"
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/protocol/protocolerrors"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter/router"
+	"github.com/pkg/errors"
+)
+
+// CompactBlockRequestsContext is the interface for the context needed for the HandleCompactBlockRequests flow.
+type CompactBlockRequestsContext interface {
+	Domain() domain.Domain
+}
+
+// HandleCompactBlockRequests listens to appmessage.MsgRequestCompactBlock messages and sends
+// their corresponding blocks to the requesting peer in compact form. It also serves the
+// appmessage.MsgRequestBlockTransactions messages that follow for transactions the peer
+// is missing.
+func HandleCompactBlockRequests(context CompactBlockRequestsContext, incomingRoute *router.Route,
+	outgoingRoute *router.Route, peer *peerpkg.Peer) error {
+
+	for {
+		message, err := incomingRoute.Dequeue()
+		if err != nil {
+			return err
+		}
+
+		switch message := message.(type) {
+		case *appmessage.MsgRequestCompactBlock:
+			log.Debugf("Got request for compact block %s from %s", message.Hash, peer)
+			block, err := getRelayBlock(context, message.Hash)
+			if err != nil {
+				return err
+			}
+			err = outgoingRoute.Enqueue(domainBlockToCompactBlock(block))
+			if err != nil {
+				return err
+			}
+			log.Debugf("Relayed compact block with hash %s", message.Hash)
+
+		case *appmessage.MsgRequestBlockTransactions:
+			log.Debugf("Got request for %d transactions of block %s from %s",
+				len(message.Indexes), message.BlockHash, peer)
+			block, err := getRelayBlock(context, message.BlockHash)
+			if err != nil {
+				return err
+			}
+			transactions := make([]*appmessage.MsgTx, len(message.Indexes))
+			for i, index := range message.Indexes {
+				if int(index) >= len(block.Transactions) {
+					return protocolerrors.Errorf(true, "requested transaction index %d of block %s "+
+						"is out of range", index, message.BlockHash)
+				}
+				transactions[i] = appmessage.DomainTransactionToMsgTx(block.Transactions[index])
+			}
+			err = outgoingRoute.Enqueue(appmessage.NewMsgBlockTransactions(message.BlockHash, transactions))
+			if err != nil {
+				return err
+			}
+
+		default:
+			return protocolerrors.Errorf(true, "unexpected message %s in the compact block requests flow",
+				message.Command())
+		}
+	}
+}
+
+func getRelayBlock(context CompactBlockRequestsContext, hash *externalapi.DomainHash) (*externalapi.DomainBlock, error) {
+	block, found, err := context.Domain().Consensus().GetBlock(hash)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to fetch requested block hash %s", hash)
+	}
+	if !found {
+		return nil, protocolerrors.Errorf(false, "Relay block %s not found", hash)
+	}
+	return block, nil
+}
+
@@ -2,6 +2,7 @@ package blockrelay
 
 import (
 	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	peerpkg "github.com/karlsend/PYVERT/testfork/karlsend/app/protocol/peer"
 	"github.com/karlsend/PYVERT/testfork/karlsend/app/protocol/protocolerrors"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter/router"
@@ -14,9 +15,9 @@ type HandleIBDBlockRequestsContext interface {
 }
 
 // HandleIBDBlockRequests listens to appmessage.MsgRequestRelayBlocks messages and sends
-// their corresponding blocks to the requesting peer.
+// their corresponding blocks to the requesting peer, respecting the configured upload limits.
 func HandleIBDBlockRequests(context HandleIBDBlockRequestsContext, incomingRoute *router.Route,
-	outgoingRoute *router.Route) error {
+	outgoingRoute *router.Route, peer *peerpkg.Peer) error {
 
 	for {
 		message, err := incomingRoute.Dequeue()
@@ -38,6 +39,11 @@ func HandleIBDBlockRequests(context HandleIBDBlockRequestsContext, incomingRoute
 
 			// TODO (Partial nodes): Convert block to partial block if needed
 
+			err = peer.Connection().WaitForUploadQuota()
+			if err != nil {
+				return err
+			}
+
 			blockMessage := appmessage.DomainBlockToMsgBlock(block)
 			ibdBlockMessage := appmessage.NewMsgIBDBlock(blockMessage)
 			err = outgoingRoute.Enqueue(ibdBlockMessage)
@@ -12,6 +12,7 @@ import (
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/ruleerrors"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/hashset"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/merkle"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/config"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter/router"
 	"github.com/pkg/errors"
@@ -282,44 +283,152 @@ func (flow *handleRelayInvsFlow) requestBlock(requestHash *externalapi.DomainHas
 	// clean from any pending blocks.
 	defer flow.SharedRequestedBlocks().Remove(requestHash)
 
+	if flow.peer.ProtocolVersion() >= compactBlocksProtocolVersion {
+		block, err := flow.requestCompactBlock(requestHash)
+		return block, false, err
+	}
+	block, err := flow.requestFullBlock(requestHash)
+	return block, false, err
+}
+
+func (flow *handleRelayInvsFlow) requestFullBlock(requestHash *externalapi.DomainHash) (*externalapi.DomainBlock, error) {
 	getRelayBlocksMsg := appmessage.NewMsgRequestRelayBlocks([]*externalapi.DomainHash{requestHash})
 	err := flow.outgoingRoute.Enqueue(getRelayBlocksMsg)
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
 
 	msgBlock, err := flow.readMsgBlock()
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
 
 	block := appmessage.MsgBlockToDomainBlock(msgBlock)
 	blockHash := consensushashing.BlockHash(block)
 	if !blockHash.Equal(requestHash) {
-		return nil, false, protocolerrors.Errorf(true, "got unrequested block %s", blockHash)
+		return nil, protocolerrors.Errorf(true, "got unrequested block %s", blockHash)
 	}
 
-	return block, false, nil
+	return block, nil
+}
+
+// requestCompactBlock requests the block in its compact form, and reconstructs it using the
+// transactions in the mempool. Transactions that are missing from the mempool are requested
+// separately. If the reconstructed block doesn't match its merkle root (which may happen if a
+// short ID matched the wrong transaction), the full block is requested instead.
+func (flow *handleRelayInvsFlow) requestCompactBlock(requestHash *externalapi.DomainHash) (*externalapi.DomainBlock, error) {
+	err := flow.outgoingRoute.Enqueue(appmessage.NewMsgRequestCompactBlock(requestHash))
+	if err != nil {
+		return nil, err
+	}
+
+	msgCompactBlock, err := flow.readMsgCompactBlock()
+	if err != nil {
+		return nil, err
+	}
+	header := appmessage.BlockHeaderToDomainBlockHeader(msgCompactBlock.Header)
+	blockHash := consensushashing.HeaderHash(header)
+	if !blockHash.Equal(requestHash) {
+		return nil, protocolerrors.Errorf(true, "got unrequested compact block %s", blockHash)
+	}
+
+	transactions, missingIndexes, err := reconstructBlockTransactions(blockHash, msgCompactBlock,
+		flow.Domain().MiningManager().FindTransactions)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(missingIndexes) > 0 {
+		log.Debugf("Requesting %d out of %d transactions of compact block %s that are missing from the mempool",
+			len(missingIndexes), len(transactions), blockHash)
+		err := flow.outgoingRoute.Enqueue(appmessage.NewMsgRequestBlockTransactions(blockHash, missingIndexes))
+		if err != nil {
+			return nil, err
+		}
+		msgBlockTransactions, err := flow.readMsgBlockTransactions()
+		if err != nil {
+			return nil, err
+		}
+		if !msgBlockTransactions.BlockHash.Equal(blockHash) {
+			return nil, protocolerrors.Errorf(true, "got transactions of block %s while expecting "+
+				"transactions of block %s", msgBlockTransactions.BlockHash, blockHash)
+		}
+		if len(msgBlockTransactions.Transactions) != len(missingIndexes) {
+			return nil, protocolerrors.Errorf(true, "got %d transactions of block %s while expecting %d",
+				len(msgBlockTransactions.Transactions), blockHash, len(missingIndexes))
+		}
+		for i, index := range missingIndexes {
+			transactions[index] = appmessage.MsgTxToDomainTransaction(msgBlockTransactions.Transactions[i])
+		}
+	}
+
+	if !merkle.CalculateHashMerkleRoot(transactions).Equal(header.HashMerkleRoot()) {
+		log.Debugf("Compact block %s was reconstructed with the wrong transactions. "+
+			"Requesting the full block", blockHash)
+		return flow.requestFullBlock(requestHash)
+	}
+
+	return &externalapi.DomainBlock{
+		Header:       header,
+		Transactions: transactions,
+	}, nil
 }
 
 // readMsgBlock returns the next msgBlock in msgChan, and populates invsQueue with any inv messages that meanwhile arrive.
-//
-// Note: this function assumes msgChan can contain only appmessage.MsgInvRelayBlock and appmessage.MsgBlock messages.
-func (flow *handleRelayInvsFlow) readMsgBlock() (msgBlock *appmessage.MsgBlock, err error) {
+func (flow *handleRelayInvsFlow) readMsgBlock() (*appmessage.MsgBlock, error) {
+	message, err := flow.readNonInvMessage()
+	if err != nil {
+		return nil, err
+	}
+	msgBlock, ok := message.(*appmessage.MsgBlock)
+	if !ok {
+		return nil, errors.Errorf("unexpected message %s", message.Command())
+	}
+	return msgBlock, nil
+}
+
+// readMsgCompactBlock returns the next msgCompactBlock in msgChan, and populates invsQueue with any inv
+// messages that meanwhile arrive.
+func (flow *handleRelayInvsFlow) readMsgCompactBlock() (*appmessage.MsgCompactBlock, error) {
+	message, err := flow.readNonInvMessage()
+	if err != nil {
+		return nil, err
+	}
+	msgCompactBlock, ok := message.(*appmessage.MsgCompactBlock)
+	if !ok {
+		return nil, errors.Errorf("unexpected message %s", message.Command())
+	}
+	return msgCompactBlock, nil
+}
+
+// readMsgBlockTransactions returns the next msgBlockTransactions in msgChan, and populates invsQueue with any
+// inv messages that meanwhile arrive.
+func (flow *handleRelayInvsFlow) readMsgBlockTransactions() (*appmessage.MsgBlockTransactions, error) {
+	message, err := flow.readNonInvMessage()
+	if err != nil {
+		return nil, err
+	}
+	msgBlockTransactions, ok := message.(*appmessage.MsgBlockTransactions)
+	if !ok {
+		return nil, errors.Errorf("unexpected message %s", message.Command())
+	}
+	return msgBlockTransactions, nil
+}
+
+// readNonInvMessage returns the next message in msgChan that isn't an appmessage.MsgInvRelayBlock, and
+// populates invsQueue with any inv messages that meanwhile arrive.
+func (flow *handleRelayInvsFlow) readNonInvMessage() (appmessage.Message, error) {
 	for {
 		message, err := flow.incomingRoute.DequeueWithTimeout(common.DefaultTimeout)
 		if err != nil {
 			return nil, err
 		}
 
-		switch message := message.(type) {
-		case *appmessage.MsgInvRelayBlock:
-			flow.invsQueue = append(flow.invsQueue, invRelayBlock{Hash: message.Hash, IsOrphanRoot: false})
-		case *appmessage.MsgBlock:
+		msgInvRelayBlock, ok := message.(*appmessage.MsgInvRelayBlock)
+		if !ok {
 			return message, nil
-		default:
-			return nil, errors.Errorf("unexpected message %s", message.Command())
 		}
+		flow.invsQueue = append(flow.invsQueue, invRelayBlock{Hash: msgInvRelayBlock.Hash, IsOrphanRoot: false})
 	}
 }
 
@@ -5,6 +5,7 @@ import (
 
 	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
 	"github.com/karlsend/PYVERT/testfork/karlsend/app/protocol/common"
+	peerpkg "github.com/karlsend/PYVERT/testfork/karlsend/app/protocol/peer"
 	"github.com/karlsend/PYVERT/testfork/karlsend/app/protocol/protocolerrors"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
@@ -21,17 +22,19 @@ type HandleRequestPruningPointUTXOSetContext interface {
 type handleRequestPruningPointUTXOSetFlow struct {
 	HandleRequestPruningPointUTXOSetContext
 	incomingRoute, outgoingRoute *router.Route
+	peer                         *peerpkg.Peer
 }
 
 // HandleRequestPruningPointUTXOSet listens to appmessage.MsgRequestPruningPointUTXOSet messages and sends
-// the pruning point UTXO set and block body.
+// the pruning point UTXO set and block body, respecting the configured upload limits.
 func HandleRequestPruningPointUTXOSet(context HandleRequestPruningPointUTXOSetContext, incomingRoute,
-	outgoingRoute *router.Route) error {
+	outgoingRoute *router.Route, peer *peerpkg.Peer) error {
 
 	flow := &handleRequestPruningPointUTXOSetFlow{
 		HandleRequestPruningPointUTXOSetContext: context,
 		incomingRoute:                           incomingRoute,
 		outgoingRoute:                           outgoingRoute,
+		peer:                                    peer,
 	}
 
 	return flow.start()
@@ -97,6 +100,11 @@ func (flow *handleRequestPruningPointUTXOSetFlow) sendPruningPointUTXOSet(
 		log.Debugf("Retrieved %d UTXOs for pruning block %s",
 			len(pruningPointUTXOs), msgRequestPruningPointUTXOSet.PruningPointHash)
 
+		err = flow.peer.Connection().WaitForUploadQuota()
+		if err != nil {
+			return err
+		}
+
 		outpointAndUTXOEntryPairs :=
 			appmessage.DomainOutpointAndUTXOEntryPairsToOutpointAndUTXOEntryPairs(pruningPointUTXOs)
 		err = flow.outgoingRoute.Enqueue(appmessage.NewMsgPruningPointUTXOSetChunk(outpointAndUTXOEntryPairs))
@@ -4,6 +4,7 @@ import (
 	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
 	"github.com/karlsend/PYVERT/testfork/karlsend/app/protocol/common"
 	"github.com/karlsend/PYVERT/testfork/karlsend/app/protocol/flowcontext"
+	peerpkg "github.com/karlsend/PYVERT/testfork/karlsend/app/protocol/peer"
 	"github.com/karlsend/PYVERT/testfork/karlsend/app/protocol/protocolerrors"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
@@ -22,22 +23,29 @@ type TransactionsRelayContext interface {
 	SharedRequestedTransactions() *flowcontext.SharedRequestedTransactions
 	OnTransactionAddedToMempool()
 	EnqueueTransactionIDsForPropagation(transactionIDs []*externalapi.DomainTransactionID) error
+	RelayStemTransactions(transactionIDs []*externalapi.DomainTransactionID, source *peerpkg.Peer) error
+	ClearDandelionEmbargoes(transactionIDs []*externalapi.DomainTransactionID)
 	IsNearlySynced() (bool, error)
 }
 
 type handleRelayedTransactionsFlow struct {
 	TransactionsRelayContext
 	incomingRoute, outgoingRoute *router.Route
+	peer                         *peerpkg.Peer
 	invsQueue                    []*appmessage.MsgInvTransaction
 }
 
 // HandleRelayedTransactions listens to appmessage.MsgInvTransaction messages, requests their corresponding transactions if they
 // are missing, adds them to the mempool and propagates them to the rest of the network.
-func HandleRelayedTransactions(context TransactionsRelayContext, incomingRoute *router.Route, outgoingRoute *router.Route) error {
+// Transactions in their Dandelion stem phase are propagated through TransactionsRelayContext.RelayStemTransactions instead.
+func HandleRelayedTransactions(context TransactionsRelayContext, incomingRoute *router.Route, outgoingRoute *router.Route,
+	peer *peerpkg.Peer) error {
+
 	flow := &handleRelayedTransactionsFlow{
 		TransactionsRelayContext: context,
 		incomingRoute:            incomingRoute,
 		outgoingRoute:            outgoingRoute,
+		peer:                     peer,
 		invsQueue:                make([]*appmessage.MsgInvTransaction, 0),
 	}
 	return flow.start()
@@ -50,6 +58,12 @@ func (flow *handleRelayedTransactionsFlow) start() error {
 			return err
 		}
 
+		// The transactions are being broadcast by the network, so
+		// there's no need to broadcast them ourselves
+		if !inv.IsStem {
+			flow.ClearDandelionEmbargoes(inv.TxIDs)
+		}
+
 		isNearlySynced, err := flow.IsNearlySynced()
 		if err != nil {
 			return err
@@ -64,7 +78,7 @@ func (flow *handleRelayedTransactionsFlow) start() error {
 			return err
 		}
 
-		err = flow.receiveTransactions(requestedIDs)
+		err = flow.receiveTransactions(requestedIDs, inv.IsStem)
 		if err != nil {
 			return err
 		}
@@ -129,7 +143,12 @@ func (flow *handleRelayedTransactionsFlow) readInv() (*appmessage.MsgInvTransact
 	return inv, nil
 }
 
-func (flow *handleRelayedTransactionsFlow) broadcastAcceptedTransactions(acceptedTxIDs []*externalapi.DomainTransactionID) error {
+func (flow *handleRelayedTransactionsFlow) broadcastAcceptedTransactions(
+	acceptedTxIDs []*externalapi.DomainTransactionID, isStem bool) error {
+
+	if isStem {
+		return flow.RelayStemTransactions(acceptedTxIDs, flow.peer)
+	}
 	return flow.EnqueueTransactionIDsForPropagation(acceptedTxIDs)
 }
 
@@ -159,7 +178,9 @@ func (flow *handleRelayedTransactionsFlow) readMsgTxOrNotFound() (
 	}
 }
 
-func (flow *handleRelayedTransactionsFlow) receiveTransactions(requestedTransactions []*externalapi.DomainTransactionID) error {
+func (flow *handleRelayedTransactionsFlow) receiveTransactions(
+	requestedTransactions []*externalapi.DomainTransactionID, isStem bool) error {
+
 	// In case the function returns earlier than expected, we want to make sure sharedRequestedTransactions is
 	// clean from any pending transactions.
 	defer flow.SharedRequestedTransactions().RemoveMany(requestedTransactions)
@@ -204,7 +225,7 @@ func (flow *handleRelayedTransactionsFlow) receiveTransactions(requestedTransact
 
 			return protocolerrors.Errorf(true, "rejected transaction %s: %s", txID, ruleErr)
 		}
-		err = flow.broadcastAcceptedTransactions(consensushashing.TransactionIDs(acceptedTransactions))
+		err = flow.broadcastAcceptedTransactions(consensushashing.TransactionIDs(acceptedTransactions), isStem)
 		if err != nil {
 			return err
 		}
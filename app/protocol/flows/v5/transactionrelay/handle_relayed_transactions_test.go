@@ -7,12 +7,14 @@ import (
 
 	"github.com/karlsend/PYVERT/testfork/karlsend/app/protocol/flowcontext"
 	"github.com/karlsend/PYVERT/testfork/karlsend/app/protocol/flows/v5/transactionrelay"
+	peerpkg "github.com/karlsend/PYVERT/testfork/karlsend/app/protocol/peer"
 
 	"github.com/karlsend/PYVERT/testfork/karlsend/app/protocol/protocolerrors"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/testutils"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/miningmanager/blocktemplatebuilder"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/miningmanager/mempool"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/logger"
 	"github.com/karlsend/PYVERT/testfork/karlsend/util/panics"
@@ -45,6 +47,13 @@ func (m *mocTransactionsRelayContext) EnqueueTransactionIDsForPropagation(transa
 	return nil
 }
 
+func (m *mocTransactionsRelayContext) RelayStemTransactions(transactionIDs []*externalapi.DomainTransactionID, source *peerpkg.Peer) error {
+	return nil
+}
+
+func (m *mocTransactionsRelayContext) ClearDandelionEmbargoes(transactionIDs []*externalapi.DomainTransactionID) {
+}
+
 func (m *mocTransactionsRelayContext) OnTransactionAddedToMempool() {
 }
 
@@ -71,7 +80,7 @@ func TestHandleRelayedTransactionsNotFound(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Failed to create a NetAdapter: %v", err)
 		}
-		domainInstance, err := domain.New(consensusConfig, mempool.DefaultConfig(&consensusConfig.Params), tc.Database())
+		domainInstance, err := domain.New(consensusConfig, mempool.DefaultConfig(&consensusConfig.Params), blocktemplatebuilder.DefaultPolicy(&consensusConfig.Params), tc.Database())
 		if err != nil {
 			t.Fatalf("Failed to set up a domain instance: %v", err)
 		}
@@ -129,7 +138,7 @@ func TestHandleRelayedTransactionsNotFound(t *testing.T) {
 			}
 		})
 
-		err = transactionrelay.HandleRelayedTransactions(context, incomingRoute, peerIncomingRoute)
+		err = transactionrelay.HandleRelayedTransactions(context, incomingRoute, peerIncomingRoute, nil)
 		// Since we inserted an unexpected message type to stop the infinity loop,
 		// we expect the error will be infected from this specific message and also the
 		// error will count as a protocol message.
@@ -164,7 +173,7 @@ func TestOnClosedIncomingRoute(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Failed to creat a NetAdapter : %v", err)
 		}
-		domainInstance, err := domain.New(consensusConfig, mempool.DefaultConfig(&consensusConfig.Params), tc.Database())
+		domainInstance, err := domain.New(consensusConfig, mempool.DefaultConfig(&consensusConfig.Params), blocktemplatebuilder.DefaultPolicy(&consensusConfig.Params), tc.Database())
 		if err != nil {
 			t.Fatalf("Failed to set up a domain instance: %v", err)
 		}
@@ -189,7 +198,7 @@ func TestOnClosedIncomingRoute(t *testing.T) {
 			t.Fatalf("Unexpected error from incomingRoute.Enqueue: %v", err)
 		}
 		incomingRoute.Close()
-		err = transactionrelay.HandleRelayedTransactions(context, incomingRoute, outgoingRoute)
+		err = transactionrelay.HandleRelayedTransactions(context, incomingRoute, outgoingRoute, nil)
 		if err == nil || !errors.Is(err, router.ErrRouteClosed) {
 			t.Fatalf("Unexpected error: expected: %v, got : %v", router.ErrRouteClosed, err)
 		}
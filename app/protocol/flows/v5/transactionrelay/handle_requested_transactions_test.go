@@ -11,6 +11,7 @@ import (
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/testutils"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/miningmanager/blocktemplatebuilder"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/miningmanager/mempool"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/config"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/logger"
@@ -38,7 +39,7 @@ func TestHandleRequestedTransactionsNotFound(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Failed to create a NetAdapter: %v", err)
 		}
-		domainInstance, err := domain.New(consensusConfig, mempool.DefaultConfig(&consensusConfig.Params), tc.Database())
+		domainInstance, err := domain.New(consensusConfig, mempool.DefaultConfig(&consensusConfig.Params), blocktemplatebuilder.DefaultPolicy(&consensusConfig.Params), tc.Database())
 		if err != nil {
 			t.Fatalf("Failed to set up a domain Instance: %v", err)
 		}
@@ -1,6 +1,7 @@
 package peer
 
 import (
+	"crypto/ed25519"
 	"sync"
 	"time"
 
@@ -60,6 +61,11 @@ func (p *Peer) ID() *id.ID {
 	return p.connection.ID()
 }
 
+// HasService returns whether the peer advertised the given service
+func (p *Peer) HasService(service appmessage.ServiceFlag) bool {
+	return p.services&service == service
+}
+
 // TimeOffset returns the peer's time offset.
 func (p *Peer) TimeOffset() time.Duration {
 	return p.timeOffset
@@ -90,6 +96,17 @@ func (p *Peer) IsOutbound() bool {
 	return p.connection.IsOutbound()
 }
 
+// IsEncrypted returns whether the connection to the peer is encrypted.
+func (p *Peer) IsEncrypted() bool {
+	return p.connection.IsEncrypted()
+}
+
+// PublicKey returns the P2P public key the peer authenticated with,
+// or nil if the connection to the peer isn't encrypted.
+func (p *Peer) PublicKey() ed25519.PublicKey {
+	return p.connection.PeerPublicKey()
+}
+
 // UpdateFieldsFromMsgVersion updates the peer with the data from the version message.
 func (p *Peer) UpdateFieldsFromMsgVersion(msg *appmessage.MsgVersion, maxProtocolVersion uint32) {
 	// Negotiate the protocol version.
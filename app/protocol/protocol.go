@@ -72,12 +72,13 @@ func (m *Manager) routerInitializer(router *routerpkg.Router, netConnection *net
 			}
 			return
 		}
-		defer m.context.RemoveFromPeers(peer)
 
 		var flows []*common.Flow
 		log.Infof("Registering p2p flows for peer %s for protocol version %d", peer, peer.ProtocolVersion())
 		switch peer.ProtocolVersion() {
-		case 5:
+		// Protocol version 6 only adds compact block relay, which the v5 flows
+		// enable according to the peer's negotiated protocol version
+		case 5, 6:
 			flows = v5.Register(m, router, errChan, &isStopping)
 		default:
 			panic(errors.Errorf("no way to handle protocol version %d", peer.ProtocolVersion()))
@@ -85,6 +86,7 @@ func (m *Manager) routerInitializer(router *routerpkg.Router, netConnection *net
 
 		err = ready.HandleReady(receiveReadyRoute, router.OutgoingRoute(), peer)
 		if err != nil {
+			m.context.RemoveFromPeers(peer)
 			m.handleError(err, netConnection, router.OutgoingRoute())
 			return
 		}
@@ -93,6 +95,10 @@ func (m *Manager) routerInitializer(router *routerpkg.Router, netConnection *net
 
 		flowsWaitGroup := &sync.WaitGroup{}
 		err = m.runFlows(flows, peer, errChan, flowsWaitGroup)
+		// Some flows, such as the ping flow, only notice that the connection was closed
+		// a while later, so the peer is removed as soon as any of its flows exits rather
+		// than after all of them did.
+		m.context.RemoveFromPeers(peer)
 		if err != nil {
 			m.handleError(err, netConnection, router.OutgoingRoute())
 			// We call `flowsWaitGroup.Wait()` in two places instead of deferring, because
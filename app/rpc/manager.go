@@ -8,6 +8,7 @@ import (
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/utxoindex"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/config"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/db/database"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/logger"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/addressmanager"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/connmanager"
@@ -24,6 +25,7 @@ type Manager struct {
 func NewManager(
 	cfg *config.Config,
 	domain domain.Domain,
+	db database.Database,
 	netAdapter *netadapter.NetAdapter,
 	protocolManager *protocol.Manager,
 	connectionManager *connmanager.ConnectionManager,
@@ -36,6 +38,7 @@ func NewManager(
 		context: rpccontext.NewContext(
 			cfg,
 			domain,
+			db,
 			netAdapter,
 			protocolManager,
 			connectionManager,
@@ -135,12 +138,21 @@ func (m *Manager) notifyVirtualChange(virtualChangeSet *externalapi.VirtualChang
 		return err
 	}
 
+	if len(virtualChangeSet.VirtualSelectedParentChainChanges.Removed) > 0 {
+		err = m.notifyReorg(virtualChangeSet)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // NotifyNewBlockTemplate notifies the manager that a new
 // block template is available for miners
 func (m *Manager) NotifyNewBlockTemplate() error {
+	m.context.SignalNewBlockTemplate()
+
 	notification := appmessage.NewNewBlockTemplateNotificationMessage()
 	return m.context.NotificationManager.NotifyNewBlockTemplate(notification)
 }
@@ -237,3 +249,21 @@ func (m *Manager) notifyVirtualSelectedParentChainChanged(virtualChangeSet *exte
 	return nil
 }
 
+func (m *Manager) notifyReorg(virtualChangeSet *externalapi.VirtualChangeSet) error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "RPCManager.NotifyReorg")
+	defer onEnd()
+
+	// Collecting the transactions that lost acceptance is a heavy operation,
+	// so we check if any listeners are interested first
+	if !m.context.NotificationManager.HasReorgListeners() {
+		return nil
+	}
+
+	notification, err := m.context.ConvertVirtualSelectedParentChainChangesToReorgNotificationMessage(
+		virtualChangeSet.VirtualSelectedParentChainChanges)
+	if err != nil {
+		return err
+	}
+	return m.context.NotificationManager.NotifyReorg(notification)
+}
+
@@ -51,6 +51,19 @@ var handlers = map[appmessage.MessageCommand]handler{
 	appmessage.CmdNotifyNewBlockTemplateRequestMessage:                      rpchandlers.HandleNotifyNewBlockTemplate,
 	appmessage.CmdGetCoinSupplyRequestMessage:                               rpchandlers.HandleGetCoinSupply,
 	appmessage.CmdGetMempoolEntriesByAddressesRequestMessage:                rpchandlers.HandleGetMempoolEntriesByAddresses,
+	appmessage.CmdDisconnectPeerRequestMessage:                              rpchandlers.HandleDisconnectPeer,
+	appmessage.CmdRemovePeerRequestMessage:                                  rpchandlers.HandleRemovePeer,
+	appmessage.CmdGetBannedPeersRequestMessage:                              rpchandlers.HandleGetBannedPeers,
+	appmessage.CmdGetConnectionRequestsRequestMessage:                       rpchandlers.HandleGetConnectionRequests,
+	appmessage.CmdInvalidateBlockRequestMessage:                             rpchandlers.HandleInvalidateBlock,
+	appmessage.CmdReconsiderBlockRequestMessage:                             rpchandlers.HandleReconsiderBlock,
+	appmessage.CmdCreateSnapshotRequestMessage:                              rpchandlers.HandleCreateSnapshot,
+	appmessage.CmdGetDAGRegionRequestMessage:                                rpchandlers.HandleGetDAGRegion,
+	appmessage.CmdNotifyReorgRequestMessage:                                 rpchandlers.HandleNotifyReorg,
+	appmessage.CmdGetMiningInfoRequestMessage:                               rpchandlers.HandleGetMiningInfo,
+	appmessage.CmdPrioritiseTransactionRequestMessage:                       rpchandlers.HandlePrioritiseTransaction,
+	appmessage.CmdGenerateBlocksRequestMessage:                              rpchandlers.HandleGenerateBlocks,
+	appmessage.CmdSetMockTimeRequestMessage:                                 rpchandlers.HandleSetMockTime,
 }
 
 func (m *Manager) routerInitializer(router *router.Router, netConnection *netadapter.NetConnection) {
@@ -0,0 +1,108 @@
+package rpccontext
+
+import (
+	"sync"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
+)
+
+// maxHeaderOnlyBlockTemplates is the number of most recent header-only block templates
+// whose transactions are kept, so that the blocks that are mined from them can be
+// submitted without transactions
+const maxHeaderOnlyBlockTemplates = 100
+
+// BlockTemplateID returns the ID of the block template with the given header. Since the
+// timestamp and the nonce of a template are set by its miner, they don't affect its ID,
+// so the ID of a block that is mined from a template is the ID of the template
+func BlockTemplateID(header externalapi.BlockHeader) string {
+	mutableHeader := header.ToMutable()
+	mutableHeader.SetTimeInMilliseconds(0)
+	mutableHeader.SetNonce(0)
+	return consensushashing.HeaderHash(mutableHeader).String()
+}
+
+// headerOnlyBlockTemplates holds the transactions of the most recent header-only block
+// templates by their IDs
+type headerOnlyBlockTemplates struct {
+	lock         sync.Mutex
+	transactions map[string][]*externalapi.DomainTransaction
+	templateIDs  []string
+}
+
+func newHeaderOnlyBlockTemplates() *headerOnlyBlockTemplates {
+	return &headerOnlyBlockTemplates{
+		transactions: make(map[string][]*externalapi.DomainTransaction),
+		templateIDs:  make([]string, 0, maxHeaderOnlyBlockTemplates),
+	}
+}
+
+// AddHeaderOnlyBlockTemplate keeps the transactions of a block template that was sent
+// without them, so that the blocks that are mined from it can be submitted without them
+func (ctx *Context) AddHeaderOnlyBlockTemplate(templateID string, transactions []*externalapi.DomainTransaction) {
+	templates := ctx.headerOnlyBlockTemplates
+	templates.lock.Lock()
+	defer templates.lock.Unlock()
+
+	if _, ok := templates.transactions[templateID]; ok {
+		return
+	}
+	if len(templates.templateIDs) == maxHeaderOnlyBlockTemplates {
+		delete(templates.transactions, templates.templateIDs[0])
+		templates.templateIDs = templates.templateIDs[1:]
+	}
+	templates.transactions[templateID] = transactions
+	templates.templateIDs = append(templates.templateIDs, templateID)
+}
+
+// HeaderOnlyBlockTemplateTransactions returns a copy of the transactions of the
+// header-only block template with the given ID, if it's one of the most recent ones
+func (ctx *Context) HeaderOnlyBlockTemplateTransactions(templateID string) ([]*externalapi.DomainTransaction, bool) {
+	templates := ctx.headerOnlyBlockTemplates
+	templates.lock.Lock()
+	defer templates.lock.Unlock()
+
+	transactions, ok := templates.transactions[templateID]
+	if !ok {
+		return nil, false
+	}
+	// The transactions are cloned since the same template may be mined more than once
+	transactionsClone := make([]*externalapi.DomainTransaction, len(transactions))
+	for i, transaction := range transactions {
+		transactionsClone[i] = transaction.Clone()
+	}
+	return transactionsClone, true
+}
+
+// newBlockTemplateSignal is closed and replaced whenever a new block template is available
+type newBlockTemplateSignal struct {
+	lock    sync.Mutex
+	channel chan struct{}
+}
+
+func newNewBlockTemplateSignal() *newBlockTemplateSignal {
+	return &newBlockTemplateSignal{
+		channel: make(chan struct{}),
+	}
+}
+
+// NewBlockTemplateSignal returns a channel that is closed once a new block template
+// is available
+func (ctx *Context) NewBlockTemplateSignal() <-chan struct{} {
+	signal := ctx.newBlockTemplateSignal
+	signal.lock.Lock()
+	defer signal.lock.Unlock()
+
+	return signal.channel
+}
+
+// SignalNewBlockTemplate closes the channels that were returned by NewBlockTemplateSignal
+func (ctx *Context) SignalNewBlockTemplate() {
+	signal := ctx.newBlockTemplateSignal
+	signal.lock.Lock()
+	defer signal.lock.Unlock()
+
+	close(signal.channel)
+	signal.channel = make(chan struct{})
+}
+
@@ -5,6 +5,7 @@ import (
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/utxoindex"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/config"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/db/database"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/addressmanager"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/connmanager"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter"
@@ -15,6 +16,7 @@ type Context struct {
 	Config            *config.Config
 	NetAdapter        *netadapter.NetAdapter
 	Domain            domain.Domain
+	Database          database.Database
 	ProtocolManager   *protocol.Manager
 	ConnectionManager *connmanager.ConnectionManager
 	AddressManager    *addressmanager.AddressManager
@@ -22,11 +24,16 @@ type Context struct {
 	ShutDownChan      chan<- struct{}
 
 	NotificationManager *NotificationManager
+
+	submittedBlocks          *submittedBlocks
+	headerOnlyBlockTemplates *headerOnlyBlockTemplates
+	newBlockTemplateSignal   *newBlockTemplateSignal
 }
 
 // NewContext creates a new RPC context
 func NewContext(cfg *config.Config,
 	domain domain.Domain,
+	db database.Database,
 	netAdapter *netadapter.NetAdapter,
 	protocolManager *protocol.Manager,
 	connectionManager *connmanager.ConnectionManager,
@@ -35,14 +42,18 @@ func NewContext(cfg *config.Config,
 	shutDownChan chan<- struct{}) *Context {
 
 	context := &Context{
-		Config:            cfg,
-		NetAdapter:        netAdapter,
-		Domain:            domain,
-		ProtocolManager:   protocolManager,
-		ConnectionManager: connectionManager,
-		AddressManager:    addressManager,
-		UTXOIndex:         utxoIndex,
-		ShutDownChan:      shutDownChan,
+		Config:                   cfg,
+		NetAdapter:               netAdapter,
+		Domain:                   domain,
+		Database:                 db,
+		ProtocolManager:          protocolManager,
+		ConnectionManager:        connectionManager,
+		AddressManager:           addressManager,
+		UTXOIndex:                utxoIndex,
+		ShutDownChan:             shutDownChan,
+		submittedBlocks:          newSubmittedBlocks(),
+		headerOnlyBlockTemplates: newHeaderOnlyBlockTemplates(),
+		newBlockTemplateSignal:   newNewBlockTemplateSignal(),
 	}
 	context.NotificationManager = NewNotificationManager(cfg.ActiveNetParams)
 
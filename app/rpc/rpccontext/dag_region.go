@@ -0,0 +1,179 @@
+package rpccontext
+
+import (
+	"sort"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/hashes"
+)
+
+// dagRegionBlockColor is the color of a block in a DAG region, along with the
+// virtual selected parent chain block that merged it
+type dagRegionBlockColor struct {
+	color        string
+	mergingBlock *externalapi.DomainHash
+}
+
+// BuildDAGRegion returns the blocks whose DAA score is between lowDAAScore and highDAAScore
+// (inclusive), that are merged by the virtual selected parent chain or by the virtual, sorted
+// by their DAA score.
+// The colors of the blocks are taken from the merge sets of the chain blocks that merged them,
+// so the virtual selected parent chain is walked from the virtual down to lowDAAScore.
+// Callers are expected to bound how far lowDAAScore is below the virtual DAA score.
+func (ctx *Context) BuildDAGRegion(lowDAAScore, highDAAScore uint64) ([]*appmessage.DAGRegionBlock, error) {
+	consensus := ctx.Domain.Consensus()
+
+	virtualSelectedParent, err := consensus.GetVirtualSelectedParent()
+	if err != nil {
+		return nil, err
+	}
+	colors := make(map[externalapi.DomainHash]*dagRegionBlockColor)
+	chainBlocks := make(map[externalapi.DomainHash]struct{})
+	var regionHashes []*externalapi.DomainHash
+
+	addToRegion := func(blockHash *externalapi.DomainHash, color *dagRegionBlockColor) error {
+		// The merge set of the pruning point might contain blocks that were already pruned
+		blockInfo, err := consensus.GetBlockInfo(blockHash)
+		if err != nil {
+			return err
+		}
+		if !blockInfo.HasHeader() {
+			return nil
+		}
+		header, err := consensus.GetBlockHeader(blockHash)
+		if err != nil {
+			return err
+		}
+		if header.DAAScore() >= lowDAAScore && header.DAAScore() <= highDAAScore {
+			colors[*blockHash] = color
+			regionHashes = append(regionHashes, blockHash)
+		}
+		return nil
+	}
+
+	// The virtual merges the virtual selected parent and its anticone
+	err = addToRegion(virtualSelectedParent, &dagRegionBlockColor{color: appmessage.DAGRegionBlockColorBlue})
+	if err != nil {
+		return nil, err
+	}
+	virtualSelectedParentAnticone, err := consensus.Anticone(virtualSelectedParent)
+	if err != nil {
+		return nil, err
+	}
+	for _, blockHash := range virtualSelectedParentAnticone {
+		err := addToRegion(blockHash, &dagRegionBlockColor{color: appmessage.DAGRegionBlockColorUnmerged})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Every chain block merges its selected parent as a blue, so the colors of
+	// the chain blocks below the virtual selected parent are set by their children
+	for chainBlock := virtualSelectedParent; chainBlock != nil; {
+		chainBlocks[*chainBlock] = struct{}{}
+		header, err := consensus.GetBlockHeader(chainBlock)
+		if err != nil {
+			return nil, err
+		}
+		if header.DAAScore() < lowDAAScore {
+			break
+		}
+
+		blockInfo, err := consensus.GetBlockInfo(chainBlock)
+		if err != nil {
+			return nil, err
+		}
+		for _, blue := range blockInfo.MergeSetBlues {
+			err := addToRegion(blue, &dagRegionBlockColor{
+				color:        appmessage.DAGRegionBlockColorBlue,
+				mergingBlock: chainBlock,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, red := range blockInfo.MergeSetReds {
+			err := addToRegion(red, &dagRegionBlockColor{
+				color:        appmessage.DAGRegionBlockColorRed,
+				mergingBlock: chainBlock,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		chainBlock, err = ctx.existingSelectedParent(blockInfo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	blocks := make([]*appmessage.DAGRegionBlock, len(regionHashes))
+	for i, blockHash := range regionHashes {
+		_, isChainBlock := chainBlocks[*blockHash]
+		blocks[i], err = ctx.buildDAGRegionBlock(blockHash, isChainBlock, colors[*blockHash])
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		if blocks[i].DAAScore != blocks[j].DAAScore {
+			return blocks[i].DAAScore < blocks[j].DAAScore
+		}
+		return blocks[i].Hash < blocks[j].Hash
+	})
+	return blocks, nil
+}
+
+// existingSelectedParent returns the selected parent of the block with the given
+// info, or nil if the block has no selected parent that is stored by the node,
+// as in the case of the genesis or of the pruning point
+func (ctx *Context) existingSelectedParent(blockInfo *externalapi.BlockInfo) (*externalapi.DomainHash, error) {
+	if blockInfo.SelectedParent == nil || blockInfo.SelectedParent.Equal(model.VirtualGenesisBlockHash) {
+		return nil, nil
+	}
+	selectedParentInfo, err := ctx.Domain.Consensus().GetBlockInfo(blockInfo.SelectedParent)
+	if err != nil {
+		return nil, err
+	}
+	if !selectedParentInfo.HasHeader() {
+		return nil, nil
+	}
+	return blockInfo.SelectedParent, nil
+}
+
+func (ctx *Context) buildDAGRegionBlock(blockHash *externalapi.DomainHash, isChainBlock bool,
+	color *dagRegionBlockColor) (*appmessage.DAGRegionBlock, error) {
+
+	header, err := ctx.Domain.Consensus().GetBlockHeader(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	blockInfo, err := ctx.Domain.Consensus().GetBlockInfo(blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	block := &appmessage.DAGRegionBlock{
+		Hash:                blockHash.String(),
+		ParentHashes:        hashes.ToStrings(header.DirectParents()),
+		DAAScore:            header.DAAScore(),
+		BlueScore:           blockInfo.BlueScore,
+		BlueWork:            blockInfo.BlueWork.Text(16),
+		IsChainBlock:        isChainBlock,
+		Color:               color.color,
+		MergeSetBluesHashes: hashes.ToStrings(blockInfo.MergeSetBlues),
+		MergeSetRedsHashes:  hashes.ToStrings(blockInfo.MergeSetReds),
+	}
+	// selectedParentHash will be nil in the genesis block
+	if blockInfo.SelectedParent != nil {
+		block.SelectedParentHash = blockInfo.SelectedParent.String()
+	}
+	if color.mergingBlock != nil {
+		block.MergingBlockHash = color.mergingBlock.String()
+	}
+	return block, nil
+}
+
@@ -0,0 +1,241 @@
+package rpccontext
+
+import (
+	"sync"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/processes/coinbasemanager"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/transactionhelper"
+)
+
+// maxSubmittedBlocks is the number of most recently submitted blocks that are kept for GetMiningInfo
+const maxSubmittedBlocks = 10_000
+
+// submittedBlock is a block that was accepted via SubmitBlock
+type submittedBlock struct {
+	hash            *externalapi.DomainHash
+	daaScore        uint64
+	scriptPublicKey *externalapi.ScriptPublicKey
+}
+
+// submittedBlocks holds the blocks that were accepted via SubmitBlock since the node started,
+// sorted by the time they were submitted
+type submittedBlocks struct {
+	lock   sync.Mutex
+	blocks []*submittedBlock
+}
+
+func newSubmittedBlocks() *submittedBlocks {
+	return &submittedBlocks{}
+}
+
+// AddSubmittedBlock records a block that was accepted via SubmitBlock, so that
+// it's included in the statistics of GetMiningInfo
+func (ctx *Context) AddSubmittedBlock(block *externalapi.DomainBlock) error {
+	_, coinbaseData, _, err := coinbasemanager.ExtractCoinbaseDataBlueScoreAndSubsidy(
+		block.Transactions[transactionhelper.CoinbaseTransactionIndex],
+		ctx.Config.ActiveNetParams.CoinbasePayloadScriptPublicKeyMaxLength)
+	if err != nil {
+		return err
+	}
+
+	ctx.submittedBlocks.lock.Lock()
+	defer ctx.submittedBlocks.lock.Unlock()
+
+	if len(ctx.submittedBlocks.blocks) == maxSubmittedBlocks {
+		ctx.submittedBlocks.blocks = ctx.submittedBlocks.blocks[1:]
+	}
+	ctx.submittedBlocks.blocks = append(ctx.submittedBlocks.blocks, &submittedBlock{
+		hash:            consensushashing.BlockHash(block),
+		daaScore:        block.Header.DAAScore(),
+		scriptPublicKey: coinbaseData.ScriptPublicKey,
+	})
+	return nil
+}
+
+func (ctx *Context) submittedBlocksSince(windowStartDAAScore uint64) []*submittedBlock {
+	ctx.submittedBlocks.lock.Lock()
+	defer ctx.submittedBlocks.lock.Unlock()
+
+	var blocks []*submittedBlock
+	for _, block := range ctx.submittedBlocks.blocks {
+		if block.daaScore >= windowStartDAAScore {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+// miningInfoChainBlock is a virtual selected parent chain block in the window of GetMiningInfo
+type miningInfoChainBlock struct {
+	hash     *externalapi.DomainHash
+	daaScore uint64
+}
+
+// BuildMiningInfo gathers the block counts and coinbase rewards of GetMiningInfo over the blocks whose
+// DAA score is at least windowStartDAAScore.
+// If payScriptPublicKey is nil, the statistics are of the blocks that were submitted via SubmitBlock,
+// and of the rewards paid to their miners. Otherwise, they are of the blocks whose coinbase pays to
+// payScriptPublicKey, and of the rewards paid to it.
+func (ctx *Context) BuildMiningInfo(payScriptPublicKey *externalapi.ScriptPublicKey,
+	windowStartDAAScore uint64) (*appmessage.GetMiningInfoResponseMessage, error) {
+
+	consensus := ctx.Domain.Consensus()
+
+	virtualDAAScore, err := consensus.GetVirtualDAAScore()
+	if err != nil {
+		return nil, err
+	}
+	virtualSelectedParent, err := consensus.GetVirtualSelectedParent()
+	if err != nil {
+		return nil, err
+	}
+
+	// The virtual selected parent is going to be merged as a blue by the next chain block,
+	// while its anticone is not merged by the virtual selected parent chain yet
+	colors := map[externalapi.DomainHash]string{
+		*virtualSelectedParent: appmessage.DAGRegionBlockColorBlue,
+	}
+	virtualSelectedParentAnticone, err := consensus.Anticone(virtualSelectedParent)
+	if err != nil {
+		return nil, err
+	}
+	for _, blockHash := range virtualSelectedParentAnticone {
+		colors[*blockHash] = appmessage.DAGRegionBlockColorUnmerged
+	}
+
+	// Every block in the window is merged by a chain block with a higher DAA
+	// score, so walking the chain down to the window start colors all of them
+	var chainBlocks []*miningInfoChainBlock
+	for chainBlock := virtualSelectedParent; chainBlock != nil; {
+		header, err := consensus.GetBlockHeader(chainBlock)
+		if err != nil {
+			return nil, err
+		}
+		if header.DAAScore() < windowStartDAAScore {
+			break
+		}
+		chainBlocks = append(chainBlocks, &miningInfoChainBlock{hash: chainBlock, daaScore: header.DAAScore()})
+
+		blockInfo, err := consensus.GetBlockInfo(chainBlock)
+		if err != nil {
+			return nil, err
+		}
+		for _, blue := range blockInfo.MergeSetBlues {
+			colors[*blue] = appmessage.DAGRegionBlockColorBlue
+		}
+		for _, red := range blockInfo.MergeSetReds {
+			colors[*red] = appmessage.DAGRegionBlockColorRed
+		}
+
+		chainBlock, err = ctx.existingSelectedParent(blockInfo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var minedBlocks []*externalapi.DomainHash
+	var payScriptPublicKeys []*externalapi.ScriptPublicKey
+	if payScriptPublicKey == nil {
+		for _, block := range ctx.submittedBlocksSince(windowStartDAAScore) {
+			minedBlocks = append(minedBlocks, block.hash)
+			payScriptPublicKeys = appendScriptPublicKeyIfMissing(payScriptPublicKeys, block.scriptPublicKey)
+		}
+	} else {
+		minedBlocks, err = ctx.blocksPayingTo(colors, payScriptPublicKey, windowStartDAAScore)
+		if err != nil {
+			return nil, err
+		}
+		payScriptPublicKeys = []*externalapi.ScriptPublicKey{payScriptPublicKey}
+	}
+
+	response := appmessage.NewGetMiningInfoResponseMessage()
+	response.WindowStartDAAScore = windowStartDAAScore
+	for _, blockHash := range minedBlocks {
+		response.AcceptedBlockCount++
+		switch colors[*blockHash] {
+		case appmessage.DAGRegionBlockColorBlue:
+			response.BlueBlockCount++
+		case appmessage.DAGRegionBlockColorRed:
+			response.RedBlockCount++
+		default:
+			response.PendingBlockCount++
+		}
+	}
+
+	coinbaseMaturity := ctx.Config.ActiveNetParams.BlockCoinbaseMaturity
+	for _, chainBlock := range chainBlocks {
+		block, found, err := consensus.GetBlock(chainBlock.hash)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+		coinbaseTransaction := block.Transactions[transactionhelper.CoinbaseTransactionIndex]
+		isMatured := chainBlock.daaScore+coinbaseMaturity <= virtualDAAScore
+		for _, output := range coinbaseTransaction.Outputs {
+			if !containsScriptPublicKey(payScriptPublicKeys, output.ScriptPublicKey) {
+				continue
+			}
+			if isMatured {
+				response.MaturedRewards += output.Value
+			} else {
+				response.PendingRewards += output.Value
+			}
+		}
+	}
+
+	return response, nil
+}
+
+// blocksPayingTo returns the blocks out of the given ones whose DAA score is at least
+// windowStartDAAScore, and whose coinbase pays to the given script public key
+func (ctx *Context) blocksPayingTo(blocks map[externalapi.DomainHash]string,
+	scriptPublicKey *externalapi.ScriptPublicKey, windowStartDAAScore uint64) ([]*externalapi.DomainHash, error) {
+
+	var blocksPayingTo []*externalapi.DomainHash
+	for blockHash := range blocks {
+		blockHash := blockHash
+		block, found, err := ctx.Domain.Consensus().GetBlock(&blockHash)
+		if err != nil {
+			return nil, err
+		}
+		if !found || block.Header.DAAScore() < windowStartDAAScore {
+			continue
+		}
+		_, coinbaseData, _, err := coinbasemanager.ExtractCoinbaseDataBlueScoreAndSubsidy(
+			block.Transactions[transactionhelper.CoinbaseTransactionIndex],
+			ctx.Config.ActiveNetParams.CoinbasePayloadScriptPublicKeyMaxLength)
+		if err != nil {
+			return nil, err
+		}
+		if coinbaseData.ScriptPublicKey.Equal(scriptPublicKey) {
+			blocksPayingTo = append(blocksPayingTo, &blockHash)
+		}
+	}
+	return blocksPayingTo, nil
+}
+
+func containsScriptPublicKey(scriptPublicKeys []*externalapi.ScriptPublicKey,
+	scriptPublicKey *externalapi.ScriptPublicKey) bool {
+
+	for _, current := range scriptPublicKeys {
+		if current.Equal(scriptPublicKey) {
+			return true
+		}
+	}
+	return false
+}
+
+func appendScriptPublicKeyIfMissing(scriptPublicKeys []*externalapi.ScriptPublicKey,
+	scriptPublicKey *externalapi.ScriptPublicKey) []*externalapi.ScriptPublicKey {
+
+	if containsScriptPublicKey(scriptPublicKeys, scriptPublicKey) {
+		return scriptPublicKeys
+	}
+	return append(scriptPublicKeys, scriptPublicKey)
+}
+
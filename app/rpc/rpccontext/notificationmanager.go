@@ -41,6 +41,7 @@ type NotificationListener struct {
 	propagateVirtualDaaScoreChangedNotifications                bool
 	propagatePruningPointUTXOSetOverrideNotifications           bool
 	propagateNewBlockTemplateNotifications                      bool
+	propagateReorgNotifications                                 bool
 
 	propagateUTXOsChangedNotificationAddresses                                    map[utxoindex.ScriptPublicKeyString]*UTXOsChangedNotificationAddress
 	includeAcceptedTransactionIDsInVirtualSelectedParentChainChangedNotifications bool
@@ -167,6 +168,36 @@ func (nm *NotificationManager) HasListenersThatPropagateVirtualSelectedParentCha
 	return hasListeners, hasListenersThatRequireAcceptedTransactionIDs
 }
 
+// HasReorgListeners indicates if the notification manager has any listeners for `Reorg` events
+func (nm *NotificationManager) HasReorgListeners() bool {
+	nm.RLock()
+	defer nm.RUnlock()
+
+	for _, listener := range nm.listeners {
+		if listener.propagateReorgNotifications {
+			return true
+		}
+	}
+	return false
+}
+
+// NotifyReorg notifies the notification manager that blocks were removed from the
+// DAG's selected parent chain
+func (nm *NotificationManager) NotifyReorg(notification *appmessage.ReorgNotificationMessage) error {
+	nm.RLock()
+	defer nm.RUnlock()
+
+	for router, listener := range nm.listeners {
+		if listener.propagateReorgNotifications {
+			err := router.OutgoingRoute().Enqueue(notification)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // NotifyFinalityConflict notifies the notification manager that there's a finality conflict in the DAG
 func (nm *NotificationManager) NotifyFinalityConflict(notification *appmessage.FinalityConflictNotificationMessage) error {
 	nm.RLock()
@@ -313,6 +344,7 @@ func newNotificationListener(params *dagconfig.Params) *NotificationListener {
 		propagateVirtualSelectedParentBlueScoreChangedNotifications: false,
 		propagateNewBlockTemplateNotifications:                      false,
 		propagatePruningPointUTXOSetOverrideNotifications:           false,
+		propagateReorgNotifications:                                 false,
 	}
 }
 
@@ -335,6 +367,12 @@ func (nl *NotificationListener) PropagateVirtualSelectedParentChainChangedNotifi
 	nl.includeAcceptedTransactionIDsInVirtualSelectedParentChainChangedNotifications = includeAcceptedTransactionIDs
 }
 
+// PropagateReorgNotifications instructs the listener to send reorg notifications
+// to the remote listener
+func (nl *NotificationListener) PropagateReorgNotifications() {
+	nl.propagateReorgNotifications = true
+}
+
 // PropagateFinalityConflictNotifications instructs the listener to send finality conflict notifications
 // to the remote listener
 func (nl *NotificationListener) PropagateFinalityConflictNotifications() {
@@ -0,0 +1,101 @@
+package rpccontext
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/hashes"
+	"github.com/pkg/errors"
+)
+
+// ConvertVirtualSelectedParentChainChangesToReorgNotificationMessage converts
+// VirtualSelectedParentChainChanges that remove at least one chain block to ReorgNotificationMessage
+func (ctx *Context) ConvertVirtualSelectedParentChainChangesToReorgNotificationMessage(
+	selectedParentChainChanges *externalapi.SelectedChainPath) (*appmessage.ReorgNotificationMessage, error) {
+
+	if len(selectedParentChainChanges.Removed) == 0 {
+		return nil, errors.Errorf("no chain blocks were removed")
+	}
+	consensus := ctx.Domain.Consensus()
+
+	// The removed chain blocks are sorted from the old virtual selected parent downwards,
+	// so the selected parent of the last one is the common ancestor of both chains
+	oldVirtualSelectedParent := selectedParentChainChanges.Removed[0]
+	lowestRemovedChainBlock := selectedParentChainChanges.Removed[len(selectedParentChainChanges.Removed)-1]
+	lowestRemovedChainBlockInfo, err := consensus.GetBlockInfo(lowestRemovedChainBlock)
+	if err != nil {
+		return nil, err
+	}
+	commonAncestor := lowestRemovedChainBlockInfo.SelectedParent
+	commonAncestorInfo, err := consensus.GetBlockInfo(commonAncestor)
+	if err != nil {
+		return nil, err
+	}
+	oldVirtualSelectedParentInfo, err := consensus.GetBlockInfo(oldVirtualSelectedParent)
+	if err != nil {
+		return nil, err
+	}
+	depth := oldVirtualSelectedParentInfo.BlueScore - commonAncestorInfo.BlueScore
+
+	removedTransactionIDs, err := ctx.transactionIDsThatLostAcceptance(selectedParentChainChanges)
+	if err != nil {
+		return nil, err
+	}
+
+	return appmessage.NewReorgNotificationMessage(commonAncestor.String(), depth,
+		hashes.ToStrings(selectedParentChainChanges.Removed), hashes.ToStrings(selectedParentChainChanges.Added),
+		removedTransactionIDs), nil
+}
+
+// transactionIDsThatLostAcceptance returns the IDs of the transactions that were accepted
+// by the removed chain blocks and are not accepted by any of the added chain blocks
+func (ctx *Context) transactionIDsThatLostAcceptance(selectedParentChainChanges *externalapi.SelectedChainPath) (
+	[]string, error) {
+
+	acceptedByAddedChainBlocks := make(map[externalapi.DomainTransactionID]struct{})
+	err := ctx.forEachAcceptedTransactionID(selectedParentChainChanges.Added, func(transactionID *externalapi.DomainTransactionID) {
+		acceptedByAddedChainBlocks[*transactionID] = struct{}{}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var removedTransactionIDs []string
+	err = ctx.forEachAcceptedTransactionID(selectedParentChainChanges.Removed, func(transactionID *externalapi.DomainTransactionID) {
+		if _, ok := acceptedByAddedChainBlocks[*transactionID]; !ok {
+			removedTransactionIDs = append(removedTransactionIDs, transactionID.String())
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return removedTransactionIDs, nil
+}
+
+func (ctx *Context) forEachAcceptedTransactionID(chainBlocks []*externalapi.DomainHash,
+	onAccepted func(transactionID *externalapi.DomainTransactionID)) error {
+
+	const chunk = 1000
+	for position := 0; position < len(chainBlocks); position += chunk {
+		end := position + chunk
+		if end > len(chainBlocks) {
+			end = len(chainBlocks)
+		}
+		// We use chunks in order to avoid blocking consensus for too long
+		chainBlocksAcceptanceData, err := ctx.Domain.Consensus().GetBlocksAcceptanceData(chainBlocks[position:end])
+		if err != nil {
+			return err
+		}
+		for _, chainBlockAcceptanceData := range chainBlocksAcceptanceData {
+			for _, blockAcceptanceData := range chainBlockAcceptanceData {
+				for _, transactionAcceptanceData := range blockAcceptanceData.TransactionAcceptanceData {
+					if transactionAcceptanceData.IsAccepted {
+						onAccepted(consensushashing.TransactionID(transactionAcceptanceData.Transaction))
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
@@ -4,6 +4,8 @@ import (
 	"encoding/hex"
 	"math"
 	"math/big"
+	"strings"
+	"unicode/utf8"
 
 	difficultyPackage "github.com/karlsend/PYVERT/testfork/karlsend/util/difficulty"
 	"github.com/pkg/errors"
@@ -14,7 +16,9 @@ import (
 
 	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/processes/coinbasemanager"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/transactionhelper"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/dagconfig"
 )
 
@@ -98,6 +102,8 @@ func (ctx *Context) PopulateBlockWithVerboseData(block *appmessage.RPCBlock, dom
 		transactionIDs[i] = consensushashing.TransactionID(transaction).String()
 	}
 	block.VerboseData.TransactionIDs = transactionIDs
+	block.VerboseData.CoinbaseData = ctx.buildCoinbaseData(
+		domainBlock.Transactions[transactionhelper.CoinbaseTransactionIndex])
 
 	if includeTransactionVerboseData {
 		for _, transaction := range block.Transactions {
@@ -111,6 +117,52 @@ func (ctx *Context) PopulateBlockWithVerboseData(block *appmessage.RPCBlock, dom
 	return nil
 }
 
+// buildCoinbaseData decodes the payload of the given coinbase transaction, so that
+// its block can be attributed to its miner. It returns nil if the payload can't be
+// decoded, as is the case for the genesis payloads of some networks
+func (ctx *Context) buildCoinbaseData(coinbaseTransaction *externalapi.DomainTransaction) *appmessage.RPCCoinbaseData {
+	_, coinbaseData, _, err := coinbasemanager.ExtractCoinbaseDataBlueScoreAndSubsidy(coinbaseTransaction,
+		ctx.Config.ActiveNetParams.CoinbasePayloadScriptPublicKeyMaxLength)
+	if err != nil {
+		return nil
+	}
+
+	// Ignore the error here since an error means the script
+	// couldn't be parsed and there's no additional information about
+	// it anyways
+	_, scriptPublicKeyAddress, _ := txscript.ExtractScriptPubKeyAddress(
+		coinbaseData.ScriptPublicKey, ctx.Config.ActiveNetParams)
+
+	var encodedScriptPublicKeyAddress string
+	if scriptPublicKeyAddress != nil {
+		encodedScriptPublicKeyAddress = scriptPublicKeyAddress.EncodeAddress()
+	}
+	version, minerTag := splitCoinbaseExtraData(coinbaseData.ExtraData)
+	return &appmessage.RPCCoinbaseData{
+		ScriptPublicKey: &appmessage.RPCScriptPublicKey{
+			Version: coinbaseData.ScriptPublicKey.Version,
+			Script:  hex.EncodeToString(coinbaseData.ScriptPublicKey.Script),
+		},
+		ScriptPublicKeyAddress: encodedScriptPublicKeyAddress,
+		ExtraData:              hex.EncodeToString(coinbaseData.ExtraData),
+		Version:                version,
+		MinerTag:               minerTag,
+	}
+}
+
+// splitCoinbaseExtraData splits coinbase extra data of the form "version/tag",
+// which is how GetBlockTemplate builds it, to its version and miner tag. Extra
+// data of any other form is returned as the miner tag. Invalid UTF-8 is replaced,
+// since it can't be sent over RPC
+func splitCoinbaseExtraData(extraData []byte) (version string, minerTag string) {
+	extraDataString := strings.ToValidUTF8(string(extraData), string(utf8.RuneError))
+	separatorIndex := strings.IndexByte(extraDataString, '/')
+	if separatorIndex == -1 {
+		return "", extraDataString
+	}
+	return extraDataString[:separatorIndex], extraDataString[separatorIndex+1:]
+}
+
 // PopulateTransactionWithVerboseData populates the given `transaction` with
 // verbose data from `domainTransaction`
 func (ctx *Context) PopulateTransactionWithVerboseData(
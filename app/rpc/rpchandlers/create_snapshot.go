@@ -0,0 +1,40 @@
+package rpchandlers
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/rpc/rpccontext"
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/snapshot"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter/router"
+)
+
+// HandleCreateSnapshot handles the respectively named RPC command
+func HandleCreateSnapshot(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	if context.Config.SafeRPC {
+		log.Warn("CreateSnapshot RPC command called while node in safe RPC mode -- ignoring.")
+		response := &appmessage.CreateSnapshotResponseMessage{}
+		response.Error =
+			appmessage.RPCErrorf("CreateSnapshot RPC command called while node in safe RPC mode")
+		return response, nil
+	}
+
+	createSnapshotRequest := request.(*appmessage.CreateSnapshotRequestMessage)
+	if createSnapshotRequest.TargetDirectory == "" {
+		errorMessage := &appmessage.CreateSnapshotResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("A target directory must be provided")
+		return errorMessage, nil
+	}
+
+	manifest, err := snapshot.Create(context.Database, context.Domain.Consensus(),
+		context.Config.ActiveNetParams, createSnapshotRequest.TargetDirectory)
+	if err != nil {
+		errorMessage := &appmessage.CreateSnapshotResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not create a snapshot in %s: %s",
+			createSnapshotRequest.TargetDirectory, err)
+		return errorMessage, nil
+	}
+
+	response := appmessage.NewCreateSnapshotResponseMessage(
+		manifest.PruningPointHash, manifest.VirtualSelectedParentHash, manifest.KeyCount)
+	return response, nil
+}
+
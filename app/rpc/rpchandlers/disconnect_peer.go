@@ -0,0 +1,38 @@
+package rpchandlers
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/rpc/rpccontext"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter/router"
+	"github.com/karlsend/PYVERT/testfork/karlsend/util/network"
+)
+
+// HandleDisconnectPeer handles the respectively named RPC command
+func HandleDisconnectPeer(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	if context.Config.SafeRPC {
+		log.Warn("DisconnectPeer RPC command called while node in safe RPC mode -- ignoring.")
+		response := appmessage.NewDisconnectPeerResponseMessage()
+		response.Error =
+			appmessage.RPCErrorf("DisconnectPeer RPC command called while node in safe RPC mode")
+		return response, nil
+	}
+
+	disconnectPeerRequest := request.(*appmessage.DisconnectPeerRequestMessage)
+	address, err := network.NormalizeAddress(disconnectPeerRequest.Address, context.Config.ActiveNetParams.DefaultPort)
+	if err != nil {
+		errorMessage := &appmessage.DisconnectPeerResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not parse address: %s", err)
+		return errorMessage, nil
+	}
+
+	err = context.ConnectionManager.DisconnectPeer(address)
+	if err != nil {
+		errorMessage := &appmessage.DisconnectPeerResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not disconnect peer: %s", err)
+		return errorMessage, nil
+	}
+
+	response := appmessage.NewDisconnectPeerResponseMessage()
+	return response, nil
+}
+
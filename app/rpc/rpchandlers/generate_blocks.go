@@ -0,0 +1,161 @@
+package rpchandlers
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/protocol/protocolerrors"
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/rpc/rpccontext"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/ruleerrors"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/mining"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/transactionid"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/txscript"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter/router"
+	"github.com/karlsend/PYVERT/testfork/karlsend/util"
+	"github.com/karlsend/PYVERT/testfork/karlsend/version"
+	"github.com/pkg/errors"
+)
+
+// maxGeneratedBlockCount is the largest number of blocks that a single GenerateBlocks
+// request generates, so that it doesn't hold its connection for too long
+const maxGeneratedBlockCount = 1000
+
+// areTestControlsEnabled returns whether the RPC commands that control block production
+// and time for tests, such as GenerateBlocks and SetMockTime, are enabled
+func areTestControlsEnabled(context *rpccontext.Context) bool {
+	params := context.Config.NetParams()
+	return params.SkipProofOfWork || params.Net == appmessage.Simnet || params.Net == appmessage.Devnet
+}
+
+// HandleGenerateBlocks handles the respectively named RPC command
+func HandleGenerateBlocks(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	if !areTestControlsEnabled(context) {
+		errorMessage := &appmessage.GenerateBlocksResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("GenerateBlocks is only available on simnet and devnet, " +
+			"or when proof of work is skipped")
+		return errorMessage, nil
+	}
+
+	generateBlocksRequest := request.(*appmessage.GenerateBlocksRequestMessage)
+
+	if generateBlocksRequest.BlockCount == 0 || generateBlocksRequest.BlockCount > maxGeneratedBlockCount {
+		errorMessage := &appmessage.GenerateBlocksResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Block count must be between 1 and %d", maxGeneratedBlockCount)
+		return errorMessage, nil
+	}
+
+	payAddress, err := util.DecodeAddress(generateBlocksRequest.PayAddress, context.Config.ActiveNetParams.Prefix)
+	if err != nil {
+		errorMessage := &appmessage.GenerateBlocksResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not decode address: %s", err)
+		return errorMessage, nil
+	}
+	scriptPublicKey, err := txscript.PayToAddrScript(payAddress)
+	if err != nil {
+		return nil, err
+	}
+	coinbaseData := &externalapi.DomainCoinbaseData{
+		ScriptPublicKey: scriptPublicKey,
+		ExtraData:       []byte(version.Version() + "/" + context.Config.MinerTag),
+	}
+
+	transactions := make([]*externalapi.DomainTransaction, len(generateBlocksRequest.TransactionIDs))
+	for i, transactionIDString := range generateBlocksRequest.TransactionIDs {
+		transactionID, err := transactionid.FromString(transactionIDString)
+		if err != nil {
+			errorMessage := &appmessage.GenerateBlocksResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Transaction ID could not be parsed: %s", err)
+			return errorMessage, nil
+		}
+		transaction, _, found := context.Domain.MiningManager().GetTransaction(transactionID, true, false)
+		if !found {
+			errorMessage := &appmessage.GenerateBlocksResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Transaction %s was not found in the transaction pool", transactionID)
+			return errorMessage, nil
+		}
+		// The transaction is cloned since the transaction pool keeps using it
+		transactions[i] = transaction.Clone()
+	}
+
+	parentHashes := make([]*externalapi.DomainHash, len(generateBlocksRequest.ParentHashes))
+	for i, parentHashString := range generateBlocksRequest.ParentHashes {
+		parentHash, err := externalapi.NewDomainHashFromString(parentHashString)
+		if err != nil {
+			errorMessage := &appmessage.GenerateBlocksResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Parent hash could not be parsed: %s", err)
+			return errorMessage, nil
+		}
+		blockInfo, err := context.Domain.Consensus().GetBlockInfo(parentHash)
+		if err != nil {
+			return nil, err
+		}
+		if !blockInfo.Exists || blockInfo.BlockStatus == externalapi.StatusHeaderOnly ||
+			blockInfo.BlockStatus == externalapi.StatusInvalid {
+
+			errorMessage := &appmessage.GenerateBlocksResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Parent %s is not a valid block with a body", parentHash)
+			return errorMessage, nil
+		}
+		parentHashes[i] = parentHash
+	}
+
+	rd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	blockHashes := make([]string, 0, generateBlocksRequest.BlockCount)
+	for i := uint32(0); i < generateBlocksRequest.BlockCount; i++ {
+		var block *externalapi.DomainBlock
+		if len(parentHashes) == 0 {
+			block, err = context.Domain.Consensus().BuildBlock(coinbaseData, transactions)
+		} else {
+			block, err = context.Domain.Consensus().BuildBlockOverParents(parentHashes, coinbaseData, transactions)
+		}
+		if err != nil {
+			if !errors.As(err, &ruleerrors.RuleError{}) {
+				return nil, err
+			}
+			errorMessage := &appmessage.GenerateBlocksResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Could not build block: %s", err)
+			return errorMessage, nil
+		}
+
+		// The UTXO entries of the transactions are populated while the block is built,
+		// and a block with populated UTXO entries is rejected by consensus
+		for _, transaction := range block.Transactions {
+			for _, input := range transaction.Inputs {
+				input.UTXOEntry = nil
+			}
+		}
+
+		if !context.Config.NetParams().SkipProofOfWork {
+			mining.SolveBlock(block, rd, context.Config.NetParams().FishHashActivationDAAScore)
+		}
+
+		err = context.ProtocolManager.AddBlock(block)
+		if err != nil {
+			isProtocolOrRuleError := errors.As(err, &ruleerrors.RuleError{}) || errors.As(err, &protocolerrors.ProtocolError{})
+			if !isProtocolOrRuleError {
+				return nil, err
+			}
+			errorMessage := &appmessage.GenerateBlocksResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Block rejected. Reason: %s", err)
+			return errorMessage, nil
+		}
+
+		blockHash := consensushashing.BlockHash(block)
+		blockHashes = append(blockHashes, blockHash.String())
+
+		// Only the first block includes the given transactions, and the blocks that follow
+		// a block with custom parents are built over it
+		transactions = nil
+		if len(parentHashes) > 0 {
+			parentHashes = []*externalapi.DomainHash{blockHash}
+		}
+	}
+
+	log.Infof("Generated %d blocks via generateBlocks", len(blockHashes))
+
+	return appmessage.NewGenerateBlocksResponseMessage(blockHashes), nil
+}
+
@@ -0,0 +1,23 @@
+package rpchandlers
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/rpc/rpccontext"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter/router"
+)
+
+// HandleGetBannedPeers handles the respectively named RPC command
+func HandleGetBannedPeers(context *rpccontext.Context, _ *router.Router, _ appmessage.Message) (appmessage.Message, error) {
+	bannedAddresses := context.AddressManager.BannedAddressesWithExpiry()
+	bannedPeers := make([]*appmessage.BannedPeerInfo, len(bannedAddresses))
+	for i, bannedAddress := range bannedAddresses {
+		bannedPeers[i] = &appmessage.BannedPeerInfo{
+			Address:   bannedAddress.NetAddress.IP.String(),
+			BanExpiry: bannedAddress.BanExpiry.UnixMilliseconds(),
+		}
+	}
+
+	response := appmessage.NewGetBannedPeersResponseMessage(bannedPeers)
+	return response, nil
+}
+
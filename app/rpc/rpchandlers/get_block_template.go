@@ -1,16 +1,27 @@
 package rpchandlers
 
 import (
+	"time"
+
 	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
 	"github.com/karlsend/PYVERT/testfork/karlsend/app/rpc/rpccontext"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
-	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/transactionhelper"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/processes/coinbasemanager"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/txscript"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter/router"
 	"github.com/karlsend/PYVERT/testfork/karlsend/util"
 	"github.com/karlsend/PYVERT/testfork/karlsend/version"
 )
 
+// maxLongPollDuration is the longest that a long-polling GetBlockTemplate waits for the
+// template to change. It's shorter than the default timeout of the RPC client
+const maxLongPollDuration = 20 * time.Second
+
+// longPollCheckInterval is the interval in which a long-polling GetBlockTemplate checks
+// whether the template changed. The mining manager caches templates for a second, so
+// checking more often would return the same template
+const longPollCheckInterval = time.Second
+
 // HandleGetBlockTemplate handles the respectively named RPC command
 func HandleGetBlockTemplate(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
 	getBlockTemplateRequest := request.(*appmessage.GetBlockTemplateRequestMessage)
@@ -27,21 +38,58 @@ func HandleGetBlockTemplate(context *rpccontext.Context, _ *router.Router, reque
 		return nil, err
 	}
 
-	coinbaseData := &externalapi.DomainCoinbaseData{ScriptPublicKey: scriptPublicKey, ExtraData: []byte(version.Version() + "/" + getBlockTemplateRequest.ExtraData)}
-
-	templateBlock, isNearlySynced, err := context.Domain.MiningManager().GetBlockTemplate(coinbaseData)
-	if err != nil {
-		return nil, err
+	extraData := getBlockTemplateRequest.ExtraData
+	if extraData == "" {
+		extraData = context.Config.MinerTag
 	}
+	coinbaseData := &externalapi.DomainCoinbaseData{ScriptPublicKey: scriptPublicKey, ExtraData: []byte(version.Version() + "/" + extraData)}
 
-	if uint64(len(templateBlock.Transactions[transactionhelper.CoinbaseTransactionIndex].Payload)) > context.Config.NetParams().MaxCoinbasePayloadLength {
+	// Check the length of the coinbase payload before building the template, since
+	// a block with a payload that is too long is rejected by consensus
+	coinbasePayloadLength := uint64(coinbasemanager.CoinbasePayloadLength(coinbaseData))
+	maxCoinbasePayloadLength := context.Config.NetParams().MaxCoinbasePayloadLength
+	if coinbasePayloadLength > maxCoinbasePayloadLength {
 		errorMessage := &appmessage.GetBlockTemplateResponseMessage{}
-		errorMessage.Error = appmessage.RPCErrorf("Coinbase payload is above max length (%d). Try to shorten the extra data.", context.Config.NetParams().MaxCoinbasePayloadLength)
+		errorMessage.Error = appmessage.RPCErrorf("Coinbase payload of %d bytes is above max length (%d). "+
+			"Try to shorten the extra data by %d bytes.", coinbasePayloadLength, maxCoinbasePayloadLength,
+			coinbasePayloadLength-maxCoinbasePayloadLength)
 		return errorMessage, nil
 	}
 
+	var templateBlock *externalapi.DomainBlock
+	var isNearlySynced bool
+	var templateID string
+	longPollDeadline := time.Now().Add(maxLongPollDuration)
+	for {
+		// The signal is taken before the template is built, so that a new template
+		// that is available right after it's built isn't missed
+		newBlockTemplateSignal := context.NewBlockTemplateSignal()
+
+		templateBlock, isNearlySynced, err = context.Domain.MiningManager().GetBlockTemplate(coinbaseData)
+		if err != nil {
+			return nil, err
+		}
+		templateID = rpccontext.BlockTemplateID(templateBlock.Header)
+
+		if templateID != getBlockTemplateRequest.LongPollTemplateID || !time.Now().Before(longPollDeadline) {
+			break
+		}
+		// Templates also change on new transactions, which aren't signaled, so the
+		// template is checked again once the mining manager refreshes it
+		select {
+		case <-newBlockTemplateSignal:
+		case <-time.After(longPollCheckInterval):
+		}
+	}
+
+	if getBlockTemplateRequest.HeaderOnly {
+		context.AddHeaderOnlyBlockTemplate(templateID, templateBlock.Transactions)
+		templateBlock = &externalapi.DomainBlock{Header: templateBlock.Header}
+	}
+
 	rpcBlock := appmessage.DomainBlockToRPCBlock(templateBlock)
 
-	return appmessage.NewGetBlockTemplateResponseMessage(rpcBlock, context.ProtocolManager.Context().HasPeers() && isNearlySynced), nil
+	return appmessage.NewGetBlockTemplateResponseMessage(rpcBlock,
+		context.ProtocolManager.Context().HasPeers() && isNearlySynced, templateID), nil
 }
 
@@ -1,6 +1,9 @@
 package rpchandlers
 
 import (
+	"encoding/hex"
+	"sort"
+
 	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
 	"github.com/karlsend/PYVERT/testfork/karlsend/app/rpc/rpccontext"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter/router"
@@ -12,6 +15,8 @@ func HandleGetConnectedPeerInfo(context *rpccontext.Context, _ *router.Router, _
 	ibdPeer := context.ProtocolManager.IBDPeer()
 	infos := make([]*appmessage.GetConnectedPeerInfoMessage, 0, len(peers))
 	for _, peer := range peers {
+		bandwidth := peer.Connection().Bandwidth()
+		totalBandwidth := bandwidth.Total()
 		info := &appmessage.GetConnectedPeerInfoMessage{
 			ID:                        peer.ID().String(),
 			Address:                   peer.Address(),
@@ -22,6 +27,13 @@ func HandleGetConnectedPeerInfo(context *rpccontext.Context, _ *router.Router, _
 			AdvertisedProtocolVersion: peer.AdvertisedProtocolVersion(),
 			TimeConnected:             peer.TimeConnected().Milliseconds(),
 			IsIBDPeer:                 peer == ibdPeer,
+			IsEncrypted:               peer.IsEncrypted(),
+			BytesSent:                 totalBandwidth.BytesSent,
+			BytesReceived:             totalBandwidth.BytesReceived,
+			BandwidthByMessageType:    bandwidthByMessageType(bandwidth),
+		}
+		if peer.IsEncrypted() {
+			info.PeerPublicKey = hex.EncodeToString(peer.PublicKey())
 		}
 		infos = append(infos, info)
 	}
@@ -29,3 +41,19 @@ func HandleGetConnectedPeerInfo(context *rpccontext.Context, _ *router.Router, _
 	return response, nil
 }
 
+func bandwidthByMessageType(bandwidth *router.Bandwidth) []*appmessage.PeerMessageTypeBandwidth {
+	byMessageType := bandwidth.ByMessageType()
+	result := make([]*appmessage.PeerMessageTypeBandwidth, 0, len(byMessageType))
+	for command, messageBandwidth := range byMessageType {
+		result = append(result, &appmessage.PeerMessageTypeBandwidth{
+			MessageType:   appmessage.ProtocolMessageCommandToString[command],
+			BytesSent:     messageBandwidth.BytesSent,
+			BytesReceived: messageBandwidth.BytesReceived,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].MessageType < result[j].MessageType
+	})
+	return result
+}
+
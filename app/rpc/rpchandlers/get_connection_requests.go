@@ -0,0 +1,28 @@
+package rpchandlers
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/rpc/rpccontext"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter/router"
+)
+
+// HandleGetConnectionRequests handles the respectively named RPC command
+func HandleGetConnectionRequests(context *rpccontext.Context, _ *router.Router, _ appmessage.Message) (appmessage.Message, error) {
+	connectionRequests := context.ConnectionManager.ConnectionRequests()
+	connectionRequestInfos := make([]*appmessage.ConnectionRequestInfo, len(connectionRequests))
+	for i, connectionRequest := range connectionRequests {
+		connectionRequestInfo := &appmessage.ConnectionRequestInfo{
+			Address:     connectionRequest.Address,
+			IsPermanent: connectionRequest.IsPermanent,
+			IsConnected: connectionRequest.IsConnected,
+		}
+		if !connectionRequest.IsConnected {
+			connectionRequestInfo.NextAttempt = connectionRequest.NextAttempt.UnixMilli()
+		}
+		connectionRequestInfos[i] = connectionRequestInfo
+	}
+
+	response := appmessage.NewGetConnectionRequestsResponseMessage(connectionRequestInfos)
+	return response, nil
+}
+
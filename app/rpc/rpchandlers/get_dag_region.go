@@ -0,0 +1,87 @@
+package rpchandlers
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/rpc/rpccontext"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter/router"
+)
+
+// maxDAGRegionDAAScoreRange is the maximal difference between the
+// high and the low DAA scores of a region requested by GetDAGRegion
+const maxDAGRegionDAAScoreRange = 2000
+
+// HandleGetDAGRegion handles the respectively named RPC command
+func HandleGetDAGRegion(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	getDAGRegionRequest := request.(*appmessage.GetDAGRegionRequestMessage)
+
+	lowDAAScore, highDAAScore := getDAGRegionRequest.LowDAAScore, getDAGRegionRequest.HighDAAScore
+	if getDAGRegionRequest.AroundHash != "" {
+		hash, err := externalapi.NewDomainHashFromString(getDAGRegionRequest.AroundHash)
+		if err != nil {
+			errorMessage := &appmessage.GetDAGRegionResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Hash could not be parsed: %s", err)
+			return errorMessage, nil
+		}
+
+		blockInfo, err := context.Domain.Consensus().GetBlockInfo(hash)
+		if err != nil {
+			return nil, err
+		}
+		if !blockInfo.HasHeader() {
+			errorMessage := &appmessage.GetDAGRegionResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Block %s not found", hash)
+			return errorMessage, nil
+		}
+		header, err := context.Domain.Consensus().GetBlockHeader(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		radius := getDAGRegionRequest.DAAScoreRadius
+		if radius > maxDAGRegionDAAScoreRange/2 {
+			errorMessage := &appmessage.GetDAGRegionResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("The DAA score radius must not exceed %d",
+				maxDAGRegionDAAScoreRange/2)
+			return errorMessage, nil
+		}
+		lowDAAScore, highDAAScore = 0, header.DAAScore()+radius
+		if header.DAAScore() > radius {
+			lowDAAScore = header.DAAScore() - radius
+		}
+	}
+
+	if lowDAAScore > highDAAScore {
+		errorMessage := &appmessage.GetDAGRegionResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("The low DAA score %d is greater than the high DAA score %d",
+			lowDAAScore, highDAAScore)
+		return errorMessage, nil
+	}
+	if highDAAScore-lowDAAScore > maxDAGRegionDAAScoreRange {
+		errorMessage := &appmessage.GetDAGRegionResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("The DAA score range must not exceed %d",
+			maxDAGRegionDAAScoreRange)
+		return errorMessage, nil
+	}
+
+	// BuildDAGRegion walks the virtual selected parent chain from the virtual down to lowDAAScore,
+	// so the depth of the region is limited in addition to its width
+	virtualDAAScore, err := context.Domain.Consensus().GetVirtualDAAScore()
+	if err != nil {
+		return nil, err
+	}
+	pruningDepth := context.Config.ActiveNetParams.PruningDepth()
+	if virtualDAAScore > pruningDepth && lowDAAScore < virtualDAAScore-pruningDepth {
+		errorMessage := &appmessage.GetDAGRegionResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("The low DAA score %d is more than the pruning depth %d "+
+			"below the virtual DAA score %d", lowDAAScore, pruningDepth, virtualDAAScore)
+		return errorMessage, nil
+	}
+
+	blocks, err := context.BuildDAGRegion(lowDAAScore, highDAAScore)
+	if err != nil {
+		return nil, err
+	}
+	return appmessage.NewGetDAGRegionResponseMessage(lowDAAScore, highDAAScore, blocks), nil
+}
+
@@ -0,0 +1,226 @@
+package rpchandlers_test
+
+import (
+	"testing"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/rpc/rpccontext"
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/rpc/rpchandlers"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/testutils"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/config"
+)
+
+func TestHandleGetDAGRegion(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		// With K=0 every block that is merged in addition to the selected parent is red
+		consensusConfig.K = 0
+
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestHandleGetDAGRegion")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		fakeContext := rpccontext.Context{
+			Config: &config.Config{Flags: &config.Flags{NetworkFlags: config.NetworkFlags{ActiveNetParams: &consensusConfig.Params}}},
+			Domain: fakeDomain{tc},
+		}
+
+		addBlock := func(parentHashes ...*externalapi.DomainHash) *externalapi.DomainHash {
+			blockHash, _, err := tc.AddBlock(parentHashes, nil, nil)
+			if err != nil {
+				t.Fatalf("AddBlock: %+v", err)
+			}
+			return blockHash
+		}
+
+		// genesis <- a <- c <- d
+		//        ^- b <-'   ^- e
+		genesis := consensusConfig.GenesisHash
+		a := addBlock(genesis)
+		b := addBlock(genesis)
+		c := addBlock(a, b)
+		d := addBlock(c)
+		e := addBlock(c)
+
+		getDAGRegion := func(request *appmessage.GetDAGRegionRequestMessage) *appmessage.GetDAGRegionResponseMessage {
+			response, err := rpchandlers.HandleGetDAGRegion(&fakeContext, nil, request)
+			if err != nil {
+				t.Fatalf("HandleGetDAGRegion: %+v", err)
+			}
+			return response.(*appmessage.GetDAGRegionResponseMessage)
+		}
+
+		virtualDAAScore, err := tc.GetVirtualDAAScore()
+		if err != nil {
+			t.Fatalf("GetVirtualDAAScore: %+v", err)
+		}
+		response := getDAGRegion(appmessage.NewGetDAGRegionRequestMessage(0, virtualDAAScore, "", 0))
+		if response.Error != nil {
+			t.Fatalf("Unexpected error: %s", response.Error.Message)
+		}
+		if len(response.Blocks) != 6 {
+			t.Fatalf("Expected 6 blocks in the region, but got %d", len(response.Blocks))
+		}
+		blocks := make(map[string]*appmessage.DAGRegionBlock)
+		for i, block := range response.Blocks {
+			blocks[block.Hash] = block
+			if i > 0 && block.DAAScore < response.Blocks[i-1].DAAScore {
+				t.Fatalf("Expected the blocks to be sorted by their DAA score")
+			}
+		}
+
+		virtualSelectedParent, err := tc.GetVirtualSelectedParent()
+		if err != nil {
+			t.Fatalf("GetVirtualSelectedParent: %+v", err)
+		}
+		unmergedTip := d
+		if virtualSelectedParent.Equal(d) {
+			unmergedTip = e
+		}
+		cInfo, err := tc.GetBlockInfo(c)
+		if err != nil {
+			t.Fatalf("GetBlockInfo: %+v", err)
+		}
+		redBlock := a
+		if cInfo.SelectedParent.Equal(a) {
+			redBlock = b
+		}
+
+		expectedBlocks := []struct {
+			name             string
+			hash             *externalapi.DomainHash
+			isChainBlock     bool
+			color            string
+			mergingBlockHash *externalapi.DomainHash
+		}{
+			{"genesis", genesis, true, appmessage.DAGRegionBlockColorBlue, cInfo.SelectedParent},
+			{"c's selected parent", cInfo.SelectedParent, true, appmessage.DAGRegionBlockColorBlue, c},
+			{"c's red parent", redBlock, false, appmessage.DAGRegionBlockColorRed, c},
+			{"c", c, true, appmessage.DAGRegionBlockColorBlue, virtualSelectedParent},
+			{"virtual selected parent", virtualSelectedParent, true, appmessage.DAGRegionBlockColorBlue, nil},
+			{"unmerged tip", unmergedTip, false, appmessage.DAGRegionBlockColorUnmerged, nil},
+		}
+		for _, expected := range expectedBlocks {
+			block, ok := blocks[expected.hash.String()]
+			if !ok {
+				t.Fatalf("%s: expected the block to be in the region", expected.name)
+			}
+			if block.IsChainBlock != expected.isChainBlock {
+				t.Fatalf("%s: expected isChainBlock to be %t", expected.name, expected.isChainBlock)
+			}
+			if block.Color != expected.color {
+				t.Fatalf("%s: expected the color to be %s, but got %s", expected.name, expected.color, block.Color)
+			}
+			expectedMergingBlockHash := ""
+			if expected.mergingBlockHash != nil {
+				expectedMergingBlockHash = expected.mergingBlockHash.String()
+			}
+			if block.MergingBlockHash != expectedMergingBlockHash {
+				t.Fatalf("%s: expected the merging block to be %s, but got %s",
+					expected.name, expectedMergingBlockHash, block.MergingBlockHash)
+			}
+		}
+		if len(blocks[c.String()].ParentHashes) != 2 || len(blocks[c.String()].MergeSetRedsHashes) != 1 {
+			t.Fatalf("Expected c to have 2 parents and 1 red in its merge set")
+		}
+
+		// The region around c with a radius of 0 contains only the blocks with its DAA score
+		cHeader, err := tc.GetBlockHeader(c)
+		if err != nil {
+			t.Fatalf("GetBlockHeader: %+v", err)
+		}
+		response = getDAGRegion(appmessage.NewGetDAGRegionRequestMessage(0, 0, c.String(), 0))
+		if response.Error != nil {
+			t.Fatalf("Unexpected error: %s", response.Error.Message)
+		}
+		if response.LowDAAScore != cHeader.DAAScore() || response.HighDAAScore != cHeader.DAAScore() {
+			t.Fatalf("Expected the region to be of DAA score %d, but got %d-%d",
+				cHeader.DAAScore(), response.LowDAAScore, response.HighDAAScore)
+		}
+		isCInRegion := false
+		for _, block := range response.Blocks {
+			if block.DAAScore != cHeader.DAAScore() {
+				t.Fatalf("Expected only blocks with DAA score %d, but got %d", cHeader.DAAScore(), block.DAAScore)
+			}
+			isCInRegion = isCInRegion || block.Hash == c.String()
+		}
+		if !isCInRegion {
+			t.Fatalf("Expected c to be in the region around it")
+		}
+
+		// Invalid requests
+		invalidRequests := []*appmessage.GetDAGRegionRequestMessage{
+			appmessage.NewGetDAGRegionRequestMessage(2, 1, "", 0),
+			appmessage.NewGetDAGRegionRequestMessage(0, 1_000_000, "", 0),
+			appmessage.NewGetDAGRegionRequestMessage(0, 0, "not a hash", 0),
+			appmessage.NewGetDAGRegionRequestMessage(0, 0, (&externalapi.DomainHash{}).String(), 0),
+			appmessage.NewGetDAGRegionRequestMessage(0, 0, c.String(), 1_000_000),
+		}
+		for _, request := range invalidRequests {
+			response := getDAGRegion(request)
+			if response.Error == nil {
+				t.Fatalf("Expected an error for request %+v", request)
+			}
+		}
+	})
+}
+
+func TestHandleGetDAGRegionDepthLimit(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		// A short finality duration with K=0 makes the pruning depth 6 blocks
+		consensusConfig.K = 0
+		consensusConfig.FinalityDuration = 2 * consensusConfig.TargetTimePerBlock
+		pruningDepth := consensusConfig.PruningDepth()
+
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestHandleGetDAGRegionDepthLimit")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		fakeContext := rpccontext.Context{
+			Config: &config.Config{Flags: &config.Flags{NetworkFlags: config.NetworkFlags{ActiveNetParams: &consensusConfig.Params}}},
+			Domain: fakeDomain{tc},
+		}
+
+		tipHash := consensusConfig.GenesisHash
+		for i := uint64(0); i < 2*pruningDepth; i++ {
+			tipHash, _, err = tc.AddBlock([]*externalapi.DomainHash{tipHash}, nil, nil)
+			if err != nil {
+				t.Fatalf("AddBlock: %+v", err)
+			}
+		}
+		virtualDAAScore, err := tc.GetVirtualDAAScore()
+		if err != nil {
+			t.Fatalf("GetVirtualDAAScore: %+v", err)
+		}
+
+		getDAGRegion := func(lowDAAScore, highDAAScore uint64) *appmessage.GetDAGRegionResponseMessage {
+			request := appmessage.NewGetDAGRegionRequestMessage(lowDAAScore, highDAAScore, "", 0)
+			response, err := rpchandlers.HandleGetDAGRegion(&fakeContext, nil, request)
+			if err != nil {
+				t.Fatalf("HandleGetDAGRegion: %+v", err)
+			}
+			return response.(*appmessage.GetDAGRegionResponseMessage)
+		}
+
+		response := getDAGRegion(virtualDAAScore-pruningDepth, virtualDAAScore)
+		if response.Error != nil {
+			t.Fatalf("Unexpected error for a region at the pruning depth: %s", response.Error.Message)
+		}
+		if len(response.Blocks) == 0 {
+			t.Fatalf("Expected the region at the pruning depth to contain blocks")
+		}
+
+		response = getDAGRegion(virtualDAAScore-pruningDepth-1, virtualDAAScore-pruningDepth-1)
+		if response.Error == nil {
+			t.Fatalf("Expected an error for a region below the pruning depth")
+		}
+	})
+}
+
@@ -0,0 +1,93 @@
+package rpchandlers
+
+import (
+	"time"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/rpc/rpccontext"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/txscript"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter/router"
+	"github.com/karlsend/PYVERT/testfork/karlsend/util"
+)
+
+const (
+	defaultMiningInfoWindowSize  = 3600
+	miningInfoHashrateWindowSize = 1000
+)
+
+// HandleGetMiningInfo handles the respectively named RPC command
+func HandleGetMiningInfo(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	getMiningInfoRequest := request.(*appmessage.GetMiningInfoRequestMessage)
+
+	windowSize := getMiningInfoRequest.WindowSize
+	if windowSize == 0 {
+		windowSize = defaultMiningInfoWindowSize
+	}
+	if context.Config.SafeRPC {
+		const windowSizeLimit = 10000
+		if windowSize > windowSizeLimit {
+			errorMessage := &appmessage.GetMiningInfoResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf(
+				"Requested window size %d is larger than max allowed in RPC safe mode (%d)",
+				windowSize, windowSizeLimit)
+			return errorMessage, nil
+		}
+	}
+	if windowSize > context.Config.ActiveNetParams.PruningDepth() {
+		errorMessage := &appmessage.GetMiningInfoResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Requested window size %d is larger than pruning point depth %d",
+			windowSize, context.Config.ActiveNetParams.PruningDepth())
+		return errorMessage, nil
+	}
+
+	var payScriptPublicKey *externalapi.ScriptPublicKey
+	if getMiningInfoRequest.PayAddress != "" {
+		address, err := util.DecodeAddress(getMiningInfoRequest.PayAddress, context.Config.ActiveNetParams.Prefix)
+		if err != nil {
+			errorMessage := &appmessage.GetMiningInfoResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Could not decode address: %s", err)
+			return errorMessage, nil
+		}
+		payScriptPublicKey, err = txscript.PayToAddrScript(address)
+		if err != nil {
+			errorMessage := &appmessage.GetMiningInfoResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Could not create a scriptPublicKey for address '%s': %s",
+				getMiningInfoRequest.PayAddress, err)
+			return errorMessage, nil
+		}
+	}
+
+	virtualDAAScore, err := context.Domain.Consensus().GetVirtualDAAScore()
+	if err != nil {
+		return nil, err
+	}
+	windowStartDAAScore := uint64(0)
+	if virtualDAAScore > windowSize {
+		windowStartDAAScore = virtualDAAScore - windowSize
+	}
+
+	response, err := context.BuildMiningInfo(payScriptPublicKey, windowStartDAAScore)
+	if err != nil {
+		return nil, err
+	}
+
+	// The hashrate can't be estimated while the DAG is too young, which
+	// shouldn't fail the rest of the request
+	response.NetworkHashesPerSecond, err = context.Domain.Consensus().EstimateNetworkHashesPerSecond(
+		model.VirtualBlockHash, miningInfoHashrateWindowSize)
+	if err != nil {
+		log.Debugf("Could not estimate the network hashes per second: %s", err)
+		response.NetworkHashesPerSecond = 0
+	}
+
+	response.BlockTemplateAgeMilliseconds = -1
+	lastBlockTemplateTime := context.Domain.MiningManager().LastBlockTemplateTime()
+	if !lastBlockTemplateTime.IsZero() {
+		response.BlockTemplateAgeMilliseconds = time.Since(lastBlockTemplateTime).Milliseconds()
+	}
+
+	return response, nil
+}
+
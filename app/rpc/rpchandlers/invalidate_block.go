@@ -0,0 +1,38 @@
+package rpchandlers
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/rpc/rpccontext"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter/router"
+)
+
+// HandleInvalidateBlock handles the respectively named RPC command
+func HandleInvalidateBlock(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	if context.Config.SafeRPC {
+		log.Warn("InvalidateBlock RPC command called while node in safe RPC mode -- ignoring.")
+		response := appmessage.NewInvalidateBlockResponseMessage()
+		response.Error =
+			appmessage.RPCErrorf("InvalidateBlock RPC command called while node in safe RPC mode")
+		return response, nil
+	}
+
+	invalidateBlockRequest := request.(*appmessage.InvalidateBlockRequestMessage)
+	hash, err := externalapi.NewDomainHashFromString(invalidateBlockRequest.BlockHash)
+	if err != nil {
+		errorMessage := &appmessage.InvalidateBlockResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Hash could not be parsed: %s", err)
+		return errorMessage, nil
+	}
+
+	err = context.Domain.Consensus().InvalidateBlock(hash)
+	if err != nil {
+		errorMessage := &appmessage.InvalidateBlockResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not invalidate block %s: %s", hash, err)
+		return errorMessage, nil
+	}
+
+	response := appmessage.NewInvalidateBlockResponseMessage()
+	return response, nil
+}
+
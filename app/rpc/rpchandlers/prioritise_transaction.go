@@ -0,0 +1,47 @@
+package rpchandlers
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/rpc/rpccontext"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/transactionid"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter/router"
+)
+
+// HandlePrioritiseTransaction handles the respectively named RPC command
+func HandlePrioritiseTransaction(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	if context.Config.SafeRPC {
+		log.Warn("PrioritiseTransaction RPC command called while node in safe RPC mode -- ignoring.")
+		response := &appmessage.PrioritiseTransactionResponseMessage{}
+		response.Error =
+			appmessage.RPCErrorf("PrioritiseTransaction RPC command called while node in safe RPC mode")
+		return response, nil
+	}
+
+	prioritiseTransactionRequest := request.(*appmessage.PrioritiseTransactionRequestMessage)
+
+	transactionID, err := transactionid.FromString(prioritiseTransactionRequest.TransactionID)
+	if err != nil {
+		errorMessage := &appmessage.PrioritiseTransactionResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Transaction ID could not be parsed: %s", err)
+		return errorMessage, nil
+	}
+
+	_, _, found := context.Domain.MiningManager().GetTransaction(transactionID, true, false)
+	if !found {
+		errorMessage := &appmessage.PrioritiseTransactionResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Transaction %s was not found in the transaction pool", transactionID)
+		return errorMessage, nil
+	}
+
+	totalFeeDelta, err := context.Domain.MiningManager().PrioritiseTransaction(transactionID, prioritiseTransactionRequest.FeeDelta)
+	if err != nil {
+		errorMessage := &appmessage.PrioritiseTransactionResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not prioritise transaction %s: %s", transactionID, err)
+		return errorMessage, nil
+	}
+
+	log.Infof("Prioritised transaction %s by %d sompi (total fee delta %d sompi)",
+		transactionID, prioritiseTransactionRequest.FeeDelta, totalFeeDelta)
+	return appmessage.NewPrioritiseTransactionResponseMessage(totalFeeDelta), nil
+}
+
@@ -0,0 +1,38 @@
+package rpchandlers
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/rpc/rpccontext"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter/router"
+)
+
+// HandleReconsiderBlock handles the respectively named RPC command
+func HandleReconsiderBlock(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	if context.Config.SafeRPC {
+		log.Warn("ReconsiderBlock RPC command called while node in safe RPC mode -- ignoring.")
+		response := appmessage.NewReconsiderBlockResponseMessage()
+		response.Error =
+			appmessage.RPCErrorf("ReconsiderBlock RPC command called while node in safe RPC mode")
+		return response, nil
+	}
+
+	reconsiderBlockRequest := request.(*appmessage.ReconsiderBlockRequestMessage)
+	hash, err := externalapi.NewDomainHashFromString(reconsiderBlockRequest.BlockHash)
+	if err != nil {
+		errorMessage := &appmessage.ReconsiderBlockResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Hash could not be parsed: %s", err)
+		return errorMessage, nil
+	}
+
+	err = context.Domain.Consensus().ReconsiderBlock(hash)
+	if err != nil {
+		errorMessage := &appmessage.ReconsiderBlockResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not reconsider block %s: %s", hash, err)
+		return errorMessage, nil
+	}
+
+	response := appmessage.NewReconsiderBlockResponseMessage()
+	return response, nil
+}
+
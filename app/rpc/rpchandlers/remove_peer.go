@@ -0,0 +1,38 @@
+package rpchandlers
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/rpc/rpccontext"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter/router"
+	"github.com/karlsend/PYVERT/testfork/karlsend/util/network"
+)
+
+// HandleRemovePeer handles the respectively named RPC command
+func HandleRemovePeer(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	if context.Config.SafeRPC {
+		log.Warn("RemovePeer RPC command called while node in safe RPC mode -- ignoring.")
+		response := appmessage.NewRemovePeerResponseMessage()
+		response.Error =
+			appmessage.RPCErrorf("RemovePeer RPC command called while node in safe RPC mode")
+		return response, nil
+	}
+
+	removePeerRequest := request.(*appmessage.RemovePeerRequestMessage)
+	address, err := network.NormalizeAddress(removePeerRequest.Address, context.Config.ActiveNetParams.DefaultPort)
+	if err != nil {
+		errorMessage := &appmessage.RemovePeerResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not parse address: %s", err)
+		return errorMessage, nil
+	}
+
+	err = context.ConnectionManager.RemoveConnection(address)
+	if err != nil {
+		errorMessage := &appmessage.RemovePeerResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not remove peer: %s", err)
+		return errorMessage, nil
+	}
+
+	response := appmessage.NewRemovePeerResponseMessage()
+	return response, nil
+}
+
@@ -0,0 +1,38 @@
+package rpchandlers
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/rpc/rpccontext"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter/router"
+	"github.com/karlsend/PYVERT/testfork/karlsend/util/mstime"
+)
+
+// HandleSetMockTime handles the respectively named RPC command
+func HandleSetMockTime(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	if !areTestControlsEnabled(context) {
+		errorMessage := &appmessage.SetMockTimeResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("SetMockTime is only available on simnet and devnet, " +
+			"or when proof of work is skipped")
+		return errorMessage, nil
+	}
+
+	setMockTimeRequest := request.(*appmessage.SetMockTimeRequestMessage)
+
+	if setMockTimeRequest.MockTime < 0 {
+		errorMessage := &appmessage.SetMockTimeResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Mock time must not be negative")
+		return errorMessage, nil
+	}
+
+	if setMockTimeRequest.MockTime == 0 {
+		mstime.SetMockTime(mstime.Time{})
+		log.Infof("Unset the mock time")
+	} else {
+		mockTime := mstime.UnixMilliseconds(setMockTimeRequest.MockTime)
+		mstime.SetMockTime(mockTime)
+		log.Infof("Set the mock time to %s", mockTime)
+	}
+
+	return appmessage.NewSetMockTimeResponseMessage(), nil
+}
+
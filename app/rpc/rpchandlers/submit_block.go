@@ -41,6 +41,20 @@ func HandleSubmitBlock(context *rpccontext.Context, _ *router.Router, request ap
 		}, nil
 	}
 
+	// Every block has a coinbase transaction, so a block without transactions was
+	// mined from a header-only template, whose transactions are filled in
+	if len(domainBlock.Transactions) == 0 {
+		transactions, ok := context.HeaderOnlyBlockTemplateTransactions(rpccontext.BlockTemplateID(domainBlock.Header))
+		if !ok {
+			return &appmessage.SubmitBlockResponseMessage{
+				Error: appmessage.RPCErrorf("Block rejected. Reason: block has no transactions, and " +
+					"isn't of any of the recent header-only block templates"),
+				RejectReason: appmessage.RejectReasonBlockInvalid,
+			}, nil
+		}
+		domainBlock.Transactions = transactions
+	}
+
 	if !submitBlockRequest.AllowNonDAABlocks {
 		virtualDAAScore, err := context.Domain.Consensus().GetVirtualDAAScore()
 		if err != nil {
@@ -79,6 +93,14 @@ func HandleSubmitBlock(context *rpccontext.Context, _ *router.Router, request ap
 
 	log.Infof("Accepted block %s via submitBlock", consensushashing.BlockHash(domainBlock))
 
+	// The block is already accepted, so failing to record it for the mining info
+	// shouldn't fail the request
+	err = context.AddSubmittedBlock(domainBlock)
+	if err != nil {
+		log.Warnf("Couldn't record the submitted block %s for the mining info: %s",
+			consensushashing.BlockHash(domainBlock), err)
+	}
+
 	response := appmessage.NewSubmitBlockResponseMessage()
 	return response, nil
 }
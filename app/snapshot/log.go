@@ -0,0 +1,8 @@
+package snapshot
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/logger"
+)
+
+var log = logger.RegisterSubSystem("SNAP")
+
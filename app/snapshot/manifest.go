@@ -0,0 +1,57 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// manifestVersion is the version of the snapshot layout. It should be
+// bumped whenever the manifest or the layout of the snapshot directory
+// changes
+const manifestVersion = 1
+
+const (
+	manifestFilename = "manifest.json"
+	databaseDirname  = "database"
+)
+
+// Manifest describes the state of the node at the time a snapshot was taken
+type Manifest struct {
+	Version                   int    `json:"version"`
+	NetworkName               string `json:"networkName"`
+	PruningPointHash          string `json:"pruningPointHash"`
+	VirtualSelectedParentHash string `json:"virtualSelectedParentHash"`
+	KeyCount                  uint64 `json:"keyCount"`
+	TimestampInMilliseconds   int64  `json:"timestampInMilliseconds"`
+}
+
+func writeManifest(snapshotPath string, manifest *Manifest) error {
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = os.WriteFile(filepath.Join(snapshotPath, manifestFilename), manifestBytes, 0600)
+	return errors.WithStack(err)
+}
+
+// ReadManifest reads the manifest of the snapshot in the given path
+func ReadManifest(snapshotPath string) (*Manifest, error) {
+	manifestBytes, err := os.ReadFile(filepath.Join(snapshotPath, manifestFilename))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed reading the snapshot manifest")
+	}
+	manifest := &Manifest{}
+	err = json.Unmarshal(manifestBytes, manifest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed parsing the snapshot manifest")
+	}
+	if manifest.Version != manifestVersion {
+		return nil, errors.Errorf("unsupported snapshot version %d, expected version %d",
+			manifest.Version, manifestVersion)
+	}
+	return manifest, nil
+}
+
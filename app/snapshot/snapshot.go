@@ -0,0 +1,240 @@
+package snapshot
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/dagconfig"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/db/database"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/db/database/ldb"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/logger"
+	"github.com/karlsend/PYVERT/testfork/karlsend/util/mstime"
+	"github.com/pkg/errors"
+)
+
+// maxSnapshotAttempts is the maximum amount of times that taking a snapshot is
+// attempted, in case the consensus state keeps changing while it's taken
+const maxSnapshotAttempts = 10
+
+// snapshotter is a database that can take consistent snapshots of itself
+type snapshotter interface {
+	Snapshot() (*ldb.Snapshot, error)
+}
+
+// Create takes a consistent snapshot of the given database and writes it, along with
+// a manifest, to the given path, while the database keeps being used. The path must
+// not exist, or be an empty directory.
+func Create(db database.Database, consensus externalapi.Consensus, params *dagconfig.Params,
+	path string) (*Manifest, error) {
+
+	onEnd := logger.LogAndMeasureExecutionTime(log, "snapshot.Create")
+	defer onEnd()
+
+	snapshotDB, ok := db.(snapshotter)
+	if !ok {
+		return nil, errors.New("the database doesn't support snapshots")
+	}
+
+	err := createEmptyDirectory(path)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := create(snapshotDB, consensus, params, path)
+	if err != nil {
+		removeErr := os.RemoveAll(path)
+		if removeErr != nil {
+			log.Warnf("Failed removing the incomplete snapshot in %s: %s", path, removeErr)
+		}
+		return nil, err
+	}
+
+	log.Infof("Created a snapshot of %d keys in %s. Pruning point: %s, virtual selected parent: %s",
+		manifest.KeyCount, path, manifest.PruningPointHash, manifest.VirtualSelectedParentHash)
+	return manifest, nil
+}
+
+func create(db snapshotter, consensus externalapi.Consensus, params *dagconfig.Params,
+	path string) (*Manifest, error) {
+
+	snapshot, pruningPoint, virtualSelectedParent, err := takeSnapshot(db, consensus)
+	if err != nil {
+		return nil, err
+	}
+	defer snapshot.Release()
+
+	log.Infof("Writing a snapshot of the database to %s", path)
+	keyCount, err := snapshot.Backup(filepath.Join(path, databaseDirname))
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{
+		Version:                   manifestVersion,
+		NetworkName:               params.Name,
+		PruningPointHash:          pruningPoint.String(),
+		VirtualSelectedParentHash: virtualSelectedParent.String(),
+		KeyCount:                  keyCount,
+		TimestampInMilliseconds:   mstime.Now().UnixMilliseconds(),
+	}
+	err = writeManifest(path, manifest)
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// takeSnapshot takes a snapshot of the database, along with the pruning point and the virtual
+// selected parent at the time it was taken. Since every change to the consensus state is written
+// in a single transaction, the snapshot is known to match them if they didn't change while it
+// was taken.
+func takeSnapshot(db snapshotter, consensus externalapi.Consensus) (
+	snapshot *ldb.Snapshot, pruningPoint, virtualSelectedParent *externalapi.DomainHash, err error) {
+
+	for i := 0; i < maxSnapshotAttempts; i++ {
+		pruningPointBefore, virtualSelectedParentBefore, err := consensusState(consensus)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		snapshot, err := db.Snapshot()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		pruningPointAfter, virtualSelectedParentAfter, err := consensusState(consensus)
+		if err != nil {
+			snapshot.Release()
+			return nil, nil, nil, err
+		}
+
+		if pruningPointBefore.Equal(pruningPointAfter) &&
+			virtualSelectedParentBefore.Equal(virtualSelectedParentAfter) {
+
+			return snapshot, pruningPointAfter, virtualSelectedParentAfter, nil
+		}
+
+		log.Debugf("The consensus state changed while taking a snapshot. Retrying")
+		snapshot.Release()
+	}
+	return nil, nil, nil, errors.Errorf("the consensus state kept changing during %d attempts "+
+		"to take a snapshot", maxSnapshotAttempts)
+}
+
+func consensusState(consensus externalapi.Consensus) (
+	pruningPoint, virtualSelectedParent *externalapi.DomainHash, err error) {
+
+	pruningPoint, err = consensus.PruningPoint()
+	if err != nil {
+		return nil, nil, err
+	}
+	virtualSelectedParent, err = consensus.GetVirtualSelectedParent()
+	if err != nil {
+		return nil, nil, err
+	}
+	return pruningPoint, virtualSelectedParent, nil
+}
+
+func createEmptyDirectory(path string) error {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return errors.WithStack(err)
+		}
+		return errors.WithStack(os.MkdirAll(path, 0700))
+	}
+	if len(entries) > 0 {
+		return errors.Errorf("the snapshot directory %s is not empty", path)
+	}
+	return nil
+}
+
+// Restore replaces the database in dbPath with the database of the
+// snapshot in snapshotPath. The database must not be open.
+func Restore(snapshotPath string, params *dagconfig.Params, dbPath string) error {
+	manifest, err := ReadManifest(snapshotPath)
+	if err != nil {
+		return err
+	}
+	if manifest.NetworkName != params.Name {
+		return errors.Errorf("the snapshot is of network %s, but the node runs on %s",
+			manifest.NetworkName, params.Name)
+	}
+
+	log.Infof("Restoring the snapshot in %s. Pruning point: %s, virtual selected parent: %s",
+		snapshotPath, manifest.PruningPointHash, manifest.VirtualSelectedParentHash)
+
+	// The snapshot is copied next to the database and only then replaces
+	// it, so that a failed copy leaves the current database as is
+	restoringPath := dbPath + ".restoring"
+	err = os.RemoveAll(restoringPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = copyDirectory(filepath.Join(snapshotPath, databaseDirname), restoringPath)
+	if err != nil {
+		return err
+	}
+
+	err = os.RemoveAll(dbPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = os.Rename(restoringPath, dbPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	log.Infof("Restored the snapshot to %s", dbPath)
+	return nil
+}
+
+// copyDirectory copies the regular files in the source
+// directory to a new destination directory
+func copyDirectory(source, destination string) error {
+	entries, err := os.ReadDir(source)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = os.MkdirAll(destination, 0700)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			return errors.Errorf("unexpected entry %s in the snapshot database", entry.Name())
+		}
+		err := copyFile(filepath.Join(source, entry.Name()), filepath.Join(destination, entry.Name()))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFile(source, destination string) error {
+	sourceFile, err := os.Open(source)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer sourceFile.Close()
+
+	destinationFile, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = io.Copy(destinationFile, sourceFile)
+	if err != nil {
+		destinationFile.Close()
+		return errors.WithStack(err)
+	}
+	err = destinationFile.Sync()
+	if err != nil {
+		destinationFile.Close()
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(destinationFile.Close())
+}
+
@@ -0,0 +1,163 @@
+package snapshot_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/snapshot"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/dagconfig"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/miningmanager/blocktemplatebuilder"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/miningmanager/mempool"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/db/database"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/db/database/ldb"
+)
+
+func TestCreateAndRestore(t *testing.T) {
+	consensusConfig := &consensus.Config{Params: dagconfig.SimnetParams}
+	consensusConfig.SkipProofOfWork = true
+
+	sourceDB, source := openDomain(t, consensusConfig, filepath.Join(t.TempDir(), "source"))
+	defer sourceDB.Close()
+	mineBlocks(t, source, 10)
+
+	snapshotPath := filepath.Join(t.TempDir(), "snapshot")
+	manifest, err := snapshot.Create(sourceDB, source.Consensus(), &consensusConfig.Params, snapshotPath)
+	if err != nil {
+		t.Fatalf("Create: %+v", err)
+	}
+	expectedVirtualSelectedParent, err := source.Consensus().GetVirtualSelectedParent()
+	if err != nil {
+		t.Fatalf("GetVirtualSelectedParent: %+v", err)
+	}
+	if manifest.VirtualSelectedParentHash != expectedVirtualSelectedParent.String() {
+		t.Fatalf("Expected the manifest virtual selected parent to be %s, but got %s",
+			expectedVirtualSelectedParent, manifest.VirtualSelectedParentHash)
+	}
+
+	// Blocks that are added after the snapshot was taken should not be a part of it
+	mineBlocks(t, source, 5)
+
+	readManifest, err := snapshot.ReadManifest(snapshotPath)
+	if err != nil {
+		t.Fatalf("ReadManifest: %+v", err)
+	}
+	if *readManifest != *manifest {
+		t.Fatalf("Expected the read manifest to be %+v, but got %+v", manifest, readManifest)
+	}
+
+	// Restoring should replace any existing database
+	destinationPath := filepath.Join(t.TempDir(), "destination")
+	destinationDB, destination := openDomain(t, consensusConfig, destinationPath)
+	mineBlocks(t, destination, 3)
+	destinationDB.Close()
+
+	err = snapshot.Restore(snapshotPath, &consensusConfig.Params, destinationPath)
+	if err != nil {
+		t.Fatalf("Restore: %+v", err)
+	}
+	destinationDB, destination = openDomain(t, consensusConfig, destinationPath)
+	defer destinationDB.Close()
+
+	virtualSelectedParent, err := destination.Consensus().GetVirtualSelectedParent()
+	if err != nil {
+		t.Fatalf("GetVirtualSelectedParent: %+v", err)
+	}
+	if !virtualSelectedParent.Equal(expectedVirtualSelectedParent) {
+		t.Fatalf("Expected the restored virtual selected parent to be %s, but got %s",
+			expectedVirtualSelectedParent, virtualSelectedParent)
+	}
+	pruningPoint, err := destination.Consensus().PruningPoint()
+	if err != nil {
+		t.Fatalf("PruningPoint: %+v", err)
+	}
+	if pruningPoint.String() != manifest.PruningPointHash {
+		t.Fatalf("Expected the restored pruning point to be %s, but got %s",
+			manifest.PruningPointHash, pruningPoint)
+	}
+
+	report, err := destination.Consensus().VerifyIntegrity()
+	if err != nil {
+		t.Fatalf("VerifyIntegrity: %+v", err)
+	}
+	if len(report.Inconsistencies) != 0 {
+		t.Fatalf("Expected no inconsistencies, but got %d. First: %s",
+			len(report.Inconsistencies), report.Inconsistencies[0].Description)
+	}
+
+	// The restored node should be able to continue from the snapshot
+	mineBlocks(t, destination, 1)
+}
+
+func TestCreateInNonEmptyDirectory(t *testing.T) {
+	consensusConfig := &consensus.Config{Params: dagconfig.SimnetParams}
+	consensusConfig.SkipProofOfWork = true
+
+	db, domainInstance := openDomain(t, consensusConfig, filepath.Join(t.TempDir(), "source"))
+	defer db.Close()
+
+	snapshotPath := t.TempDir()
+	err := ioutil.WriteFile(filepath.Join(snapshotPath, "file"), []byte{}, 0600)
+	if err != nil {
+		t.Fatalf("WriteFile: %+v", err)
+	}
+	_, err = snapshot.Create(db, domainInstance.Consensus(), &consensusConfig.Params, snapshotPath)
+	if err == nil {
+		t.Fatalf("Expected Create to fail on a non-empty directory")
+	}
+}
+
+func TestRestoreWrongNetwork(t *testing.T) {
+	consensusConfig := &consensus.Config{Params: dagconfig.SimnetParams}
+	consensusConfig.SkipProofOfWork = true
+
+	db, domainInstance := openDomain(t, consensusConfig, filepath.Join(t.TempDir(), "source"))
+	defer db.Close()
+
+	snapshotPath := filepath.Join(t.TempDir(), "snapshot")
+	_, err := snapshot.Create(db, domainInstance.Consensus(), &consensusConfig.Params, snapshotPath)
+	if err != nil {
+		t.Fatalf("Create: %+v", err)
+	}
+
+	err = snapshot.Restore(snapshotPath, &dagconfig.DevnetParams, filepath.Join(t.TempDir(), "destination"))
+	if err == nil {
+		t.Fatalf("Expected Restore to fail on a snapshot of another network")
+	}
+}
+
+func openDomain(t *testing.T, consensusConfig *consensus.Config, path string) (database.Database, domain.Domain) {
+	db, err := ldb.NewLevelDB(path, 8)
+	if err != nil {
+		t.Fatalf("NewLevelDB: %+v", err)
+	}
+
+	domainInstance, err := domain.New(consensusConfig, mempool.DefaultConfig(&consensusConfig.Params), blocktemplatebuilder.DefaultPolicy(&consensusConfig.Params), db)
+	if err != nil {
+		t.Fatalf("New: %+v", err)
+	}
+	return db, domainInstance
+}
+
+func mineBlocks(t *testing.T, domainInstance domain.Domain, numBlocks int) {
+	coinbaseData := &externalapi.DomainCoinbaseData{
+		ScriptPublicKey: &externalapi.ScriptPublicKey{},
+		ExtraData:       []byte{},
+	}
+
+	for i := 0; i < numBlocks; i++ {
+		block, err := domainInstance.Consensus().BuildBlock(coinbaseData, nil)
+		if err != nil {
+			t.Fatalf("BuildBlock: %+v", err)
+		}
+
+		err = domainInstance.Consensus().ValidateAndInsertBlock(block, true)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertBlock: %+v", err)
+		}
+	}
+}
+
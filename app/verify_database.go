@@ -0,0 +1,51 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/config"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/db/database"
+	"github.com/pkg/errors"
+)
+
+// maxPrintedInconsistencies is the maximum amount of inconsistencies that are
+// printed by --verify-db. The rest are only counted
+const maxPrintedInconsistencies = 100
+
+func verifyDatabase(cfg *config.Config, db database.Database) error {
+	domain, err := newDomain(cfg, db)
+	if err != nil {
+		log.Errorf("Unable to create the domain: %+v", err)
+		return err
+	}
+
+	report, err := domain.Consensus().VerifyIntegrity()
+	if err != nil {
+		log.Errorf("Verifying the database failed: %+v", err)
+		return err
+	}
+
+	fmt.Printf("Checked %d blocks\n", report.CheckedBlocks)
+	if len(report.Inconsistencies) == 0 {
+		fmt.Println("No inconsistencies were found")
+		return nil
+	}
+
+	fmt.Printf("Found %d inconsistencies\n", len(report.Inconsistencies))
+	if firstInconsistentBlock := report.FirstInconsistentBlock(); firstInconsistentBlock != nil {
+		fmt.Printf("First inconsistent block: %s\n", firstInconsistentBlock)
+	}
+	for i, inconsistency := range report.Inconsistencies {
+		if i == maxPrintedInconsistencies {
+			fmt.Printf("... and %d more\n", len(report.Inconsistencies)-maxPrintedInconsistencies)
+			break
+		}
+		if inconsistency.BlockHash != nil {
+			fmt.Printf("%s: %s\n", inconsistency.BlockHash, inconsistency.Description)
+		} else {
+			fmt.Println(inconsistency.Description)
+		}
+	}
+	return errors.Errorf("found %d inconsistencies in the database", len(report.Inconsistencies))
+}
+
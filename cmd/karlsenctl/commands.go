@@ -41,6 +41,18 @@ var commandTypes = []reflect.Type{
 
 	reflect.TypeOf(protowire.KarlsendMessage_BanRequest{}),
 	reflect.TypeOf(protowire.KarlsendMessage_UnbanRequest{}),
+	reflect.TypeOf(protowire.KarlsendMessage_DisconnectPeerRequest{}),
+	reflect.TypeOf(protowire.KarlsendMessage_RemovePeerRequest{}),
+	reflect.TypeOf(protowire.KarlsendMessage_GetBannedPeersRequest{}),
+	reflect.TypeOf(protowire.KarlsendMessage_GetConnectionRequestsRequest{}),
+	reflect.TypeOf(protowire.KarlsendMessage_InvalidateBlockRequest{}),
+	reflect.TypeOf(protowire.KarlsendMessage_ReconsiderBlockRequest{}),
+	reflect.TypeOf(protowire.KarlsendMessage_CreateSnapshotRequest{}),
+	reflect.TypeOf(protowire.KarlsendMessage_GetDAGRegionRequest{}),
+	reflect.TypeOf(protowire.KarlsendMessage_GetMiningInfoRequest{}),
+	reflect.TypeOf(protowire.KarlsendMessage_PrioritiseTransactionRequest{}),
+	reflect.TypeOf(protowire.KarlsendMessage_GenerateBlocksRequest{}),
+	reflect.TypeOf(protowire.KarlsendMessage_SetMockTimeRequest{}),
 }
 
 type commandDescription struct {
@@ -17,6 +17,7 @@ type configFlags struct {
 	RequestJSON                        string `short:"j" long:"json" description:"The request in JSON format"`
 	ListCommands                       bool   `short:"l" long:"list-commands" description:"List all commands and exit"`
 	AllowConnectionToDifferentVersions bool   `short:"a" long:"allow-connection-to-different-versions" description:"Allow connections to versions different than karlsenctl's version'"`
+	DOT                                bool   `long:"dot" description:"Print the response of a getDAGRegionRequest as a graphviz DOT graph instead of JSON"`
 	CommandAndParameters               []string
 	config.NetworkFlags
 }
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter/server/grpcserver/protowire"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// shortHashLength is the number of hash characters that are shown in block labels
+const shortHashLength = 8
+
+var dotFillColors = map[string]string{
+	appmessage.DAGRegionBlockColorBlue:     "lightblue",
+	appmessage.DAGRegionBlockColorRed:      "salmon",
+	appmessage.DAGRegionBlockColorUnmerged: "lightgray",
+}
+
+// dagRegionResponseToDOT converts the response of a GetDAGRegion request to a graphviz
+// DOT graph. Blocks are filled by their color, chain blocks and selected parent edges
+// are bold, and edges to parents outside of the region are omitted.
+func dagRegionResponseToDOT(response string) string {
+	karlsendMessage := &protowire.KarlsendMessage{}
+	err := protojson.Unmarshal([]byte(response), karlsendMessage)
+	if err != nil {
+		printErrorAndExit(fmt.Sprintf("error parsing the response from the RPC server: %s", err))
+	}
+	dagRegionResponse := karlsendMessage.GetGetDAGRegionResponse()
+	if dagRegionResponse == nil {
+		printErrorAndExit("--dot is supported only for getDAGRegionRequest")
+	}
+	if dagRegionResponse.Error != nil {
+		printErrorAndExit(fmt.Sprintf("error getting the DAG region: %s", dagRegionResponse.Error.Message))
+	}
+
+	regionHashes := make(map[string]struct{}, len(dagRegionResponse.Blocks))
+	for _, block := range dagRegionResponse.Blocks {
+		regionHashes[block.Hash] = struct{}{}
+	}
+
+	var dotBuilder strings.Builder
+	dotBuilder.WriteString("digraph {\n\trankdir = RL;\n\tnode [shape = box, style = filled];\n\n")
+	for _, block := range dagRegionResponse.Blocks {
+		penWidth := 1
+		if block.IsChainBlock {
+			penWidth = 3
+		}
+		dotBuilder.WriteString(fmt.Sprintf("\t\"%s\" [label = \"%s\\nDAA score: %d\\nBlue score: %d\", fillcolor = \"%s\", penwidth = %d];\n",
+			block.Hash, shortHash(block.Hash), block.DaaScore, block.BlueScore, dotFillColors[block.Color], penWidth))
+	}
+	dotBuilder.WriteString("\n")
+	for _, block := range dagRegionResponse.Blocks {
+		for _, parentHash := range block.ParentHashes {
+			if _, ok := regionHashes[parentHash]; !ok {
+				continue
+			}
+			penWidth := 1
+			if parentHash == block.SelectedParentHash {
+				penWidth = 3
+			}
+			dotBuilder.WriteString(fmt.Sprintf("\t\"%s\" -> \"%s\" [penwidth = %d];\n", block.Hash, parentHash, penWidth))
+		}
+	}
+	dotBuilder.WriteString("}")
+	return dotBuilder.String()
+}
+
+func shortHash(hash string) string {
+	if len(hash) <= shortHashLength {
+		return hash
+	}
+	return hash[:shortHashLength]
+}
+
@@ -60,6 +60,10 @@ func main() {
 	timeout := time.Duration(cfg.Timeout) * time.Second
 	select {
 	case responseString := <-responseChan:
+		if cfg.DOT {
+			fmt.Println(dagRegionResponseToDOT(responseString))
+			return
+		}
 		prettyResponseString := prettifyResponse(responseString)
 		fmt.Println(prettyResponseString)
 	case <-time.After(timeout):
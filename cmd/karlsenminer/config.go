@@ -20,6 +20,7 @@ const (
 	defaultLogFilename          = "karlsenminer.log"
 	defaultErrLogFilename       = "karlsenminer_err.log"
 	defaultTargetBlockRateRatio = 2.0
+	defaultNumThreads           = 1
 )
 
 var (
@@ -38,12 +39,15 @@ type configFlags struct {
 	MineWhenNotSynced     bool     `long:"mine-when-not-synced" description:"Mine even if the node is not synced with the rest of the network."`
 	Profile               string   `long:"profile" description:"Enable HTTP profiling on given port -- NOTE port must be between 1024 and 65536"`
 	TargetBlocksPerSecond *float64 `long:"target-blocks-per-second" description:"Sets a maximum block rate. 0 means no limit (The default one is 2 * target network block rate)"`
+	FishHashFullDataset   bool     `long:"fishhash-full-dataset" description:"Generate the full FishHash dataset (about 4.8GB of memory) to mine FishHash blocks faster than with the light cache"`
+	NumThreads            int      `short:"t" long:"threads" description:"Number of threads to mine with"`
 	config.NetworkFlags
 }
 
 func parseConfig() (*configFlags, error) {
 	cfg := &configFlags{
-		RPCServer: defaultRPCServer,
+		RPCServer:  defaultRPCServer,
+		NumThreads: defaultNumThreads,
 	}
 	parser := flags.NewParser(cfg, flags.PrintErrors|flags.HelpFlag)
 	_, err := parser.Parse()
@@ -75,6 +79,10 @@ func parseConfig() (*configFlags, error) {
 		cfg.TargetBlocksPerSecond = &targetBlocksPerSecond
 	}
 
+	if cfg.NumThreads < 1 {
+		return nil, errors.Errorf("The number of threads must be at least 1")
+	}
+
 	if cfg.Profile != "" {
 		profilePort, err := strconv.Atoi(cfg.Profile)
 		if err != nil || profilePort < 1024 || profilePort > 65535 {
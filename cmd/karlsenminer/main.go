@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/pow"
 	"github.com/karlsend/PYVERT/testfork/karlsend/util"
 
 	"github.com/karlsend/PYVERT/testfork/karlsend/version"
@@ -46,9 +47,16 @@ func main() {
 		printErrorAndExit(errors.Errorf("Error decoding mining address: %s", err))
 	}
 
+	if cfg.FishHashFullDataset {
+		log.Infof("Generating the full FishHash dataset. This might take a while")
+		pow.PrepareFishHashFullDataset()
+		log.Infof("Finished generating the full FishHash dataset")
+	}
+
 	doneChan := make(chan struct{})
 	spawn("mineLoop", func() {
-		err = mineLoop(client, cfg.NumberOfBlocks, *cfg.TargetBlocksPerSecond, cfg.MineWhenNotSynced, miningAddr)
+		err = mineLoop(client, cfg.NumberOfBlocks, *cfg.TargetBlocksPerSecond, cfg.MineWhenNotSynced, miningAddr,
+			cfg.ActiveNetParams.FishHashActivationDAAScore, cfg.NumThreads)
 		if err != nil {
 			panic(errors.Wrap(err, "error in mine loop"))
 		}
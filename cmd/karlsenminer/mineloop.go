@@ -2,7 +2,9 @@ package main
 
 import (
 	nativeerrors "errors"
+	"math"
 	"math/rand"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -23,7 +25,7 @@ var hashesTried uint64
 const logHashRateInterval = 10 * time.Second
 
 func mineLoop(client *minerClient, numberOfBlocks uint64, targetBlocksPerSecond float64, mineWhenNotSynced bool,
-	miningAddr util.Address) error {
+	miningAddr util.Address, fishHashActivationDAAScore uint64, numThreads int) error {
 	rand.Seed(time.Now().UnixNano()) // Seed the global concurrent-safe random source.
 
 	errChan := make(chan error)
@@ -36,7 +38,7 @@ func mineLoop(client *minerClient, numberOfBlocks uint64, targetBlocksPerSecond
 	foundBlockChan := make(chan *externalapi.DomainBlock, router.DefaultMaxMessages/2)
 
 	spawn("templatesLoop", func() {
-		templatesLoop(client, miningAddr, errChan)
+		templatesLoop(client, miningAddr, fishHashActivationDAAScore, errChan)
 	})
 
 	spawn("blocksLoop", func() {
@@ -58,7 +60,7 @@ func mineLoop(client *minerClient, numberOfBlocks uint64, targetBlocksPerSecond
 		}
 		windowStart := time.Now()
 		for blockIndex := 1; ; blockIndex++ {
-			foundBlockChan <- mineNextBlock(mineWhenNotSynced)
+			foundBlockChan <- mineNextBlock(mineWhenNotSynced, numThreads)
 			if hasBlockRateTarget {
 				<-blockTicker.C
 				if (blockIndex % windowSize) == 0 {
@@ -135,29 +137,99 @@ func handleFoundBlock(client *minerClient, block *externalapi.DomainBlock) error
 	return nil
 }
 
-func mineNextBlock(mineWhenNotSynced bool) *externalapi.DomainBlock {
-	nonce := rand.Uint64() // Use the global concurrent-safe random source.
+func mineNextBlock(mineWhenNotSynced bool, numThreads int) *externalapi.DomainBlock {
 	for {
-		nonce++
-		// For each nonce we try to build a block from the most up to date
-		// block template.
-		// In the rare case where the nonce space is exhausted for a specific
-		// block, it'll keep looping the nonce until a new block template
-		// is discovered.
-		block, state := getBlockForMining(mineWhenNotSynced)
-		state.Nonce = nonce
-		atomic.AddUint64(&hashesTried, 1)
+		// We always mine the most up to date block template, so
+		// the workers are restarted whenever the template changes.
+		block, state, templateID := getBlockForMining(mineWhenNotSynced)
+		foundBlock := mineTemplate(block, state, templateID, numThreads)
+		if foundBlock != nil {
+			log.Infof("Found block %s with parents %s", consensushashing.BlockHash(foundBlock),
+				foundBlock.Header.DirectParents())
+			return foundBlock
+		}
+	}
+}
+
+// mineTemplate partitions the nonce space of the given template between numThreads
+// workers, and returns the block once one of them finds a valid nonce, or nil if the
+// template was replaced before that.
+func mineTemplate(block *externalapi.DomainBlock, state *pow.State, templateID uint64,
+	numThreads int) *externalapi.DomainBlock {
+
+	var isDone uint32
+	foundNonceChan := make(chan uint64, 1)
+	waitGroup := sync.WaitGroup{}
+
+	// Use the global concurrent-safe random source.
+	for _, workerFirstNonce := range workerFirstNonces(rand.Uint64(), numThreads) {
+		workerState := *state
+		workerState.Nonce = workerFirstNonce
+		waitGroup.Add(1)
+		spawn("miningWorker", func() {
+			defer waitGroup.Done()
+			mineNonceRange(&workerState, templateID, &isDone, foundNonceChan)
+		})
+	}
+	waitGroup.Wait()
+
+	select {
+	case nonce := <-foundNonceChan:
+		mutHeader := block.Header.ToMutable()
+		mutHeader.SetNonce(nonce)
+		block.Header = mutHeader.ToImmutable()
+		return block
+	default:
+		return nil
+	}
+}
+
+// workerFirstNonces partitions the nonce space into numThreads ranges of equal size, the first of
+// which starts at firstNonce, and returns the first nonce of every range. The ranges wrap around
+// math.MaxUint64.
+func workerFirstNonces(firstNonce uint64, numThreads int) []uint64 {
+	nonceRangeSize := math.MaxUint64 / uint64(numThreads)
+	firstNonces := make([]uint64, numThreads)
+	for i := range firstNonces {
+		firstNonces[i] = firstNonce + uint64(i)*nonceRangeSize
+	}
+	return firstNonces
+}
+
+// mineNonceRange increments the nonce of the given state until it finds a valid one, another
+// worker sets isDone, or the template changes.
+// In the rare case where the nonce range is exhausted, it'll keep looping the nonce until one
+// of these happens.
+func mineNonceRange(state *pow.State, templateID uint64, isDone *uint32, foundNonceChan chan<- uint64) {
+	const hashesTriedFlushInterval = 100
+	workerHashesTried := uint64(0)
+	defer func() {
+		atomic.AddUint64(&hashesTried, workerHashesTried)
+	}()
+
+	for atomic.LoadUint32(isDone) == 0 {
+		if templatemanager.TemplateID() != templateID {
+			atomic.StoreUint32(isDone, 1)
+			return
+		}
+
+		workerHashesTried++
+		if workerHashesTried == hashesTriedFlushInterval {
+			atomic.AddUint64(&hashesTried, workerHashesTried)
+			workerHashesTried = 0
+		}
+
 		if state.CheckProofOfWork() {
-			mutHeader := block.Header.ToMutable()
-			mutHeader.SetNonce(nonce)
-			block.Header = mutHeader.ToImmutable()
-			log.Infof("Found block %s with parents %s", consensushashing.BlockHash(block), block.Header.DirectParents())
-			return block
+			if atomic.CompareAndSwapUint32(isDone, 0, 1) {
+				foundNonceChan <- state.Nonce
+			}
+			return
 		}
+		state.IncrementNonce()
 	}
 }
 
-func getBlockForMining(mineWhenNotSynced bool) (*externalapi.DomainBlock, *pow.State) {
+func getBlockForMining(mineWhenNotSynced bool) (*externalapi.DomainBlock, *pow.State, uint64) {
 	tryCount := 0
 
 	const sleepTime = 500 * time.Millisecond
@@ -167,7 +239,7 @@ func getBlockForMining(mineWhenNotSynced bool) (*externalapi.DomainBlock, *pow.S
 		tryCount++
 
 		shouldLog := (tryCount-1)%10 == 0
-		template, state, isSynced := templatemanager.Get()
+		template, state, templateID, isSynced := templatemanager.Get()
 		if template == nil {
 			if shouldLog {
 				log.Info("Waiting for the initial template")
@@ -183,11 +255,12 @@ func getBlockForMining(mineWhenNotSynced bool) (*externalapi.DomainBlock, *pow.S
 			continue
 		}
 
-		return template, state
+		return template, state, templateID
 	}
 }
 
-func templatesLoop(client *minerClient, miningAddr util.Address, errChan chan error) {
+func templatesLoop(client *minerClient, miningAddr util.Address, fishHashActivationDAAScore uint64,
+	errChan chan error) {
 	getBlockTemplate := func() {
 		template, err := client.GetBlockTemplate(miningAddr.String(), "karlsenminer-"+version.Version())
 		if nativeerrors.Is(err, router.ErrTimeout) {
@@ -207,7 +280,7 @@ func templatesLoop(client *minerClient, miningAddr util.Address, errChan chan er
 			errChan <- errors.Wrapf(err, "Error getting block template from %s", client.Address())
 			return
 		}
-		err = templatemanager.Set(template)
+		err = templatemanager.Set(template, fishHashActivationDAAScore)
 		if err != nil {
 			errChan <- errors.Wrapf(err, "Error setting block template from %s", client.Address())
 			return
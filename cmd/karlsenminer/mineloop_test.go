@@ -0,0 +1,118 @@
+package main
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/cmd/karlsenminer/templatemanager"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/blockheader"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/pow"
+)
+
+func TestWorkerFirstNonces(t *testing.T) {
+	for _, numThreads := range []int{1, 2, 3, 7, 16, 64} {
+		for _, firstNonce := range []uint64{0, 1, math.MaxUint64 - 5, math.MaxUint64, 0x123456789abcdef0} {
+			firstNonces := workerFirstNonces(firstNonce, numThreads)
+			if len(firstNonces) != numThreads {
+				t.Fatalf("Expected %d first nonces, but got %d", numThreads, len(firstNonces))
+			}
+			if firstNonces[0] != firstNonce {
+				t.Fatalf("Expected the first range to start at %d, but it starts at %d", firstNonce, firstNonces[0])
+			}
+
+			// Every worker tries the nonces from its first nonce up to the first nonce of the
+			// next range, so no first nonce of another worker may be within that many nonces
+			nonceRangeSize := math.MaxUint64 / uint64(numThreads)
+			for i, start := range firstNonces {
+				for j, otherStart := range firstNonces {
+					if i != j && otherStart-start < nonceRangeSize {
+						t.Fatalf("The nonce range of worker %d, starting at %d, overlaps the one of "+
+							"worker %d, starting at %d, with %d threads", i, start, j, otherStart, numThreads)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestMineTemplate(t *testing.T) {
+	block, state := newTestTemplate(t)
+	// A target that requires tens of thousands of hashes on average, so that all the workers
+	// run concurrently for a while
+	state.Target.Rsh(new(big.Int).Lsh(big.NewInt(1), 256), 16)
+
+	const numThreads = 8
+	foundBlock := mineTemplate(block, state, templatemanager.TemplateID(), numThreads)
+	if foundBlock == nil {
+		t.Fatalf("Expected mineTemplate to find a block")
+	}
+
+	foundState := *state
+	foundState.Nonce = foundBlock.Header.Nonce()
+	if !foundState.CheckProofOfWork() {
+		t.Fatalf("The nonce %d of the found block doesn't satisfy the target", foundBlock.Header.Nonce())
+	}
+	if state.Nonce != 0 {
+		t.Fatalf("Expected the workers not to modify the shared state, but its nonce is %d", state.Nonce)
+	}
+}
+
+func TestMineTemplateStopsOnNewTemplate(t *testing.T) {
+	block, state := newTestTemplate(t)
+	// No nonce satisfies a zero target
+	state.Target.SetInt64(0)
+
+	foundBlockChan := make(chan *externalapi.DomainBlock)
+	templateID := templatemanager.TemplateID()
+	spawn("TestMineTemplateStopsOnNewTemplate-mineTemplate", func() {
+		foundBlockChan <- mineTemplate(block, state, templateID, 4)
+	})
+
+	time.Sleep(100 * time.Millisecond)
+	setTestTemplate(t, block)
+
+	select {
+	case foundBlock := <-foundBlockChan:
+		if foundBlock != nil {
+			t.Fatalf("Expected mineTemplate not to find a block")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("Timeout waiting for the workers to stop after the template changed")
+	}
+}
+
+// newTestTemplate sets a template with a kHeavyHash header as the current one, and returns
+// it along with its state
+func newTestTemplate(t *testing.T) (*externalapi.DomainBlock, *pow.State) {
+	header := blockheader.NewImmutableBlockHeader(
+		0,
+		[]externalapi.BlockLevelParents{[]*externalapi.DomainHash{{}}},
+		&externalapi.DomainHash{},
+		&externalapi.DomainHash{},
+		&externalapi.DomainHash{},
+		time.Now().UnixMilli(),
+		0x207fffff,
+		0,
+		0,
+		0,
+		big.NewInt(0),
+		&externalapi.DomainHash{},
+	)
+	block := &externalapi.DomainBlock{Header: header, Transactions: []*externalapi.DomainTransaction{}}
+	setTestTemplate(t, block)
+
+	return block, pow.NewState(header.ToMutable(), math.MaxUint64)
+}
+
+func setTestTemplate(t *testing.T, block *externalapi.DomainBlock) {
+	template := appmessage.NewGetBlockTemplateResponseMessage(appmessage.DomainBlockToRPCBlock(block), true, "")
+	err := templatemanager.Set(template, math.MaxUint64)
+	if err != nil {
+		t.Fatalf("Error setting the template: %+v", err)
+	}
+}
+
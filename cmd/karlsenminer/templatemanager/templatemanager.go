@@ -2,6 +2,7 @@ package templatemanager
 
 import (
 	"sync"
+	"sync/atomic"
 
 	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
@@ -10,24 +11,30 @@ import (
 
 var currentTemplate *externalapi.DomainBlock
 var currentState *pow.State
+var currentTemplateID uint64
 var isSynced bool
 var lock = &sync.Mutex{}
 
-// Get returns the template to work on
-func Get() (*externalapi.DomainBlock, *pow.State, bool) {
+// Get returns the template to work on, along with its ID
+func Get() (*externalapi.DomainBlock, *pow.State, uint64, bool) {
 	lock.Lock()
 	defer lock.Unlock()
 	// Shallow copy the block so when the user replaces the header it won't affect the template here.
 	if currentTemplate == nil {
-		return nil, nil, false
+		return nil, nil, 0, false
 	}
 	block := *currentTemplate
 	state := *currentState
-	return &block, &state, isSynced
+	return &block, &state, atomic.LoadUint64(&currentTemplateID), isSynced
+}
+
+// TemplateID returns the ID of the current template, which changes every time the template is set
+func TemplateID() uint64 {
+	return atomic.LoadUint64(&currentTemplateID)
 }
 
 // Set sets the current template to work on
-func Set(template *appmessage.GetBlockTemplateResponseMessage) error {
+func Set(template *appmessage.GetBlockTemplateResponseMessage, fishHashActivationDAAScore uint64) error {
 	block, err := appmessage.RPCBlockToDomainBlock(template.Block)
 	if err != nil {
 		return err
@@ -35,7 +42,8 @@ func Set(template *appmessage.GetBlockTemplateResponseMessage) error {
 	lock.Lock()
 	defer lock.Unlock()
 	currentTemplate = block
-	currentState = pow.NewState(block.Header.ToMutable())
+	currentState = pow.NewState(block.Header.ToMutable(), fishHashActivationDAAScore)
+	atomic.AddUint64(&currentTemplateID, 1)
 	isSynced = template.IsSynced
 	return nil
 }
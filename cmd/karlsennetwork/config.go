@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/pkg/errors"
+)
+
+const (
+	genesisSubCmd = "genesis"
+)
+
+type configFlags struct{}
+
+type genesisConfig struct {
+	NetworkDefinitionFile string `long:"network-definition-file" short:"f" description:"The network definition file to create the genesis of" required:"true"`
+	Write                 bool   `long:"write" short:"w" description:"Write the mined genesis back to the network definition file instead of printing it"`
+}
+
+func parseCommandLine() (subCommand string, config interface{}) {
+	cfg := &configFlags{}
+	parser := flags.NewParser(cfg, flags.PrintErrors|flags.HelpFlag)
+
+	genesisConf := &genesisConfig{}
+	parser.AddCommand(genesisSubCmd, "Mines the genesis block of a network definition",
+		"Mines the genesis block of a network definition, and prints the definition with the nonce, "+
+			"timestamp and hash of the genesis filled in. Nodes accept a network definition only if "+
+			"its genesis hash is specified.", genesisConf)
+
+	_, err := parser.Parse()
+	if err != nil {
+		var flagsErr *flags.Error
+		if ok := errors.As(err, &flagsErr); ok && flagsErr.Type == flags.ErrHelp {
+			os.Exit(0)
+		} else {
+			os.Exit(1)
+		}
+		return "", nil
+	}
+
+	switch parser.Command.Active.Name {
+	case genesisSubCmd:
+		config = genesisConf
+	}
+
+	return parser.Command.Active.Name, config
+}
+
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/mining"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/txscript"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/dagconfig"
+	"github.com/karlsend/PYVERT/testfork/karlsend/util/mstime"
+	"github.com/pkg/errors"
+)
+
+func genesis(conf *genesisConfig) error {
+	definition, err := dagconfig.ReadNetworkDefinition(conf.NetworkDefinitionFile)
+	if err != nil {
+		return err
+	}
+
+	// The genesis is about to be mined again, so its old hash is irrelevant
+	definition.Genesis.Hash = ""
+	if definition.Genesis.TimeInMilliseconds == 0 {
+		definition.Genesis.TimeInMilliseconds = mstime.Now().UnixMilliseconds()
+	}
+	if definition.Genesis.CoinbasePayload == "" {
+		params, err := definition.Params()
+		if err != nil {
+			return err
+		}
+		definition.Genesis.CoinbasePayload = hex.EncodeToString(defaultGenesisCoinbasePayload(params))
+	}
+
+	params, err := definition.Params()
+	if err != nil {
+		return err
+	}
+	genesisBlock := params.GenesisBlock
+	if !params.SkipProofOfWork {
+		fmt.Fprintf(os.Stderr, "Mining the genesis block of %s...\n", params.Name)
+		start := time.Now()
+		random := rand.New(rand.NewSource(time.Now().UnixNano()))
+		mining.SolveBlock(genesisBlock, random, params.FishHashActivationDAAScore)
+		fmt.Fprintf(os.Stderr, "Found a nonce in %s\n", time.Since(start))
+	}
+	definition.Genesis.Bits = genesisBlock.Header.Bits()
+	definition.Genesis.Nonce = genesisBlock.Header.Nonce()
+
+	// Recompute the parameters from the updated definition, to make sure that
+	// loading it results in the mined genesis
+	definition.Genesis.Hash = ""
+	params, err = definition.Params()
+	if err != nil {
+		return err
+	}
+	definition.Genesis.Hash = params.GenesisHash.String()
+
+	definitionJSON, err := json.MarshalIndent(definition, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	definitionJSON = append(definitionJSON, '\n')
+
+	fmt.Fprintf(os.Stderr, "Genesis nonce: %d\n", definition.Genesis.Nonce)
+	fmt.Fprintf(os.Stderr, "Genesis timestamp: %d\n", definition.Genesis.TimeInMilliseconds)
+	fmt.Fprintf(os.Stderr, "Genesis hash: %s\n", definition.Genesis.Hash)
+
+	if conf.Write {
+		err = os.WriteFile(conf.NetworkDefinitionFile, definitionJSON, 0644)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		fmt.Fprintf(os.Stderr, "Wrote the network definition to %s\n", conf.NetworkDefinitionFile)
+		return nil
+	}
+	_, err = os.Stdout.Write(definitionJSON)
+	return errors.WithStack(err)
+}
+
+// defaultGenesisCoinbasePayload returns a coinbase payload in the format of the genesis payloads
+// of the standard networks, which pays the genesis reward to an unspendable script and carries
+// the name of the network as its extra data
+func defaultGenesisCoinbasePayload(params *dagconfig.Params) []byte {
+	const blueScore = 0
+	const scriptVersion = 0
+	script := []byte{txscript.OpFalse}
+
+	payload := make([]byte, 18, 19+len(script)+len(params.Name))
+	binary.LittleEndian.PutUint64(payload[:8], blueScore)
+	binary.LittleEndian.PutUint64(payload[8:16], params.SubsidyGenesisReward)
+	binary.LittleEndian.PutUint16(payload[16:18], scriptVersion)
+	payload = append(payload, byte(len(script)))
+	payload = append(payload, script...)
+	return append(payload, params.Name...)
+}
+
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/config"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/logger"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/util"
+	"github.com/pkg/errors"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/karlsend/PYVERT/testfork/karlsend/version"
+)
+
+const (
+	defaultLogFilename        = "karlsenstratum.log"
+	defaultErrLogFilename     = "karlsenstratum_err.log"
+	defaultLogLevel           = "info"
+	defaultListen             = "0.0.0.0:5555"
+	defaultMinShareDifficulty = 1.0
+	defaultSharesPerMinute    = 20.0
+)
+
+var (
+	// Default configuration options
+	defaultAppDir     = util.AppDir("karlsenstratum", false)
+	defaultLogFile    = filepath.Join(defaultAppDir, defaultLogFilename)
+	defaultErrLogFile = filepath.Join(defaultAppDir, defaultErrLogFilename)
+	defaultRPCServer  = "localhost"
+)
+
+type configFlags struct {
+	ShowVersion         bool    `short:"V" long:"version" description:"Display version information and exit"`
+	RPCServer           string  `short:"s" long:"rpcserver" description:"RPC server to connect to"`
+	Listen              string  `short:"l" long:"stratum-listen" description:"Interface/port to listen for stratum connections"`
+	MiningAddr          string  `long:"miningaddr" description:"Address to mine to"`
+	MinShareDifficulty  float64 `long:"min-share-diff" description:"Initial and minimal share difficulty of every worker. Difficulty 1 is 2^32 hashes per share on average"`
+	SharesPerMinute     float64 `long:"shares-per-minute" description:"Share rate per worker that the share difficulty is adjusted to"`
+	MineWhenNotSynced   bool    `long:"mine-when-not-synced" description:"Send jobs to the miners even if the node is not synced with the rest of the network."`
+	Profile             string  `long:"profile" description:"Enable HTTP profiling on given port -- NOTE port must be between 1024 and 65536"`
+	FishHashFullDataset bool    `long:"fishhash-full-dataset" description:"Generate the full FishHash dataset (about 4.8GB of memory) to validate FishHash shares faster than with the light cache"`
+	LogLevel            string  `short:"d" long:"loglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems"`
+	config.NetworkFlags
+}
+
+func parseConfig() (*configFlags, error) {
+	cfg := &configFlags{
+		RPCServer:          defaultRPCServer,
+		Listen:             defaultListen,
+		MinShareDifficulty: defaultMinShareDifficulty,
+		SharesPerMinute:    defaultSharesPerMinute,
+		LogLevel:           defaultLogLevel,
+	}
+	parser := flags.NewParser(cfg, flags.PrintErrors|flags.HelpFlag)
+	_, err := parser.Parse()
+
+	// If special error ErrHelp catched by -h or --help
+	if ourErr, ok := err.(*flags.Error); ok && ourErr.Type == flags.ErrHelp {
+		os.Exit(0)
+	}
+
+	// Show the version and exit if the version flag was specified.
+	if cfg.ShowVersion {
+		appName := filepath.Base(os.Args[0])
+		appName = strings.TrimSuffix(appName, filepath.Ext(appName))
+		fmt.Println(appName, "version", version.Version())
+		os.Exit(0)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = cfg.ResolveNetwork(parser)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.MinShareDifficulty <= 0 {
+		return nil, errors.Errorf("The minimal share difficulty must be positive")
+	}
+
+	if cfg.SharesPerMinute <= 0 {
+		return nil, errors.Errorf("The number of shares per minute must be positive")
+	}
+
+	if cfg.Profile != "" {
+		profilePort, err := strconv.Atoi(cfg.Profile)
+		if err != nil || profilePort < 1024 || profilePort > 65535 {
+			return nil, errors.New("The profile port must be between 1024 and 65535")
+		}
+	}
+
+	if cfg.MiningAddr == "" {
+		fmt.Fprintln(os.Stderr, errors.New("Error parsing command-line arguments: --miningaddr is required"))
+		os.Exit(1)
+	}
+
+	initLog(defaultLogFile, defaultErrLogFile)
+	err = logger.ParseAndSetLogLevels(cfg.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
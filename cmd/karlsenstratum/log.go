@@ -0,0 +1,16 @@
+package main
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/logger"
+	"github.com/karlsend/PYVERT/testfork/karlsend/util/panics"
+)
+
+var (
+	log   = logger.RegisterSubSystem("KSST")
+	spawn = panics.GoroutineWrapperFunc(log)
+)
+
+func initLog(logFile, errLogFile string) {
+	logger.InitLog(logFile, errLogFile)
+}
+
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/cmd/karlsenstratum/stratumserver"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/pow"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/logger"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/rpcclient"
+	"github.com/karlsend/PYVERT/testfork/karlsend/util"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/version"
+
+	"github.com/pkg/errors"
+
+	_ "net/http/pprof"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/os/signal"
+	"github.com/karlsend/PYVERT/testfork/karlsend/util/panics"
+	"github.com/karlsend/PYVERT/testfork/karlsend/util/profiling"
+)
+
+const rpcTimeout = 10 * time.Second
+
+func main() {
+	defer panics.HandlePanic(log, "MAIN", nil)
+	interrupt := signal.InterruptListener()
+
+	cfg, err := parseConfig()
+	if err != nil {
+		printErrorAndExit(errors.Errorf("Error parsing command-line arguments: %s", err))
+	}
+	defer logger.BackendLog.Close()
+
+	// Show version at startup.
+	log.Infof("Version %s", version.Version())
+
+	// Enable http profiling server if requested.
+	if cfg.Profile != "" {
+		profiling.Start(cfg.Profile, log)
+	}
+
+	miningAddr, err := util.DecodeAddress(cfg.MiningAddr, cfg.ActiveNetParams.Prefix)
+	if err != nil {
+		printErrorAndExit(errors.Errorf("Error decoding mining address: %s", err))
+	}
+
+	rpcAddress, err := cfg.NetParams().NormalizeRPCServerAddress(cfg.RPCServer)
+	if err != nil {
+		printErrorAndExit(err)
+	}
+	client, err := rpcclient.NewRPCClient(rpcAddress)
+	if err != nil {
+		panic(errors.Wrap(err, "error connecting to the RPC server"))
+	}
+	client.SetTimeout(rpcTimeout)
+	defer client.Close()
+	log.Infof("Connected to %s", rpcAddress)
+
+	if cfg.FishHashFullDataset {
+		log.Infof("Generating the full FishHash dataset. This might take a while")
+		pow.PrepareFishHashFullDataset()
+		log.Infof("Finished generating the full FishHash dataset")
+	}
+
+	server := stratumserver.New(&stratumserver.Config{
+		ListenAddress:              cfg.Listen,
+		MiningAddress:              miningAddr.String(),
+		ExtraData:                  "karlsenstratum-" + version.Version(),
+		FishHashActivationDAAScore: cfg.ActiveNetParams.FishHashActivationDAAScore,
+		MinShareDifficulty:         cfg.MinShareDifficulty,
+		SharesPerMinute:            cfg.SharesPerMinute,
+		MineWhenNotSynced:          cfg.MineWhenNotSynced,
+	}, client)
+	err = server.Start()
+	if err != nil {
+		printErrorAndExit(err)
+	}
+	defer server.Stop()
+
+	<-interrupt
+}
+
+func printErrorAndExit(err error) {
+	fmt.Fprintf(os.Stderr, "%+v\n", err)
+	os.Exit(1)
+}
+
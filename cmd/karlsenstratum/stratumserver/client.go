@@ -0,0 +1,367 @@
+package stratumserver
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// extranonceSize is the number of most significant nonce bytes that are
+	// assigned to each miner by the server
+	extranonceSize = 2
+	// nonceSize is the number of bytes in a nonce
+	nonceSize = 8
+
+	maxRequestSize = 4096
+	writeTimeout   = 10 * time.Second
+)
+
+// stratumClient is a single miner connection
+type stratumClient struct {
+	server        *Server
+	conn          net.Conn
+	remoteAddress string
+	extranonce    uint16
+
+	writeLock sync.Mutex
+	jobChan   chan *job
+	closeChan chan struct{}
+	closeOnce sync.Once
+
+	lock               sync.Mutex
+	isSubscribed       bool
+	isAuthorized       bool
+	workerName         string
+	varDiff            *varDiff
+	previousDifficulty float64
+}
+
+func newStratumClient(server *Server, conn net.Conn, extranonce uint16) *stratumClient {
+	return &stratumClient{
+		server:             server,
+		conn:               conn,
+		remoteAddress:      conn.RemoteAddr().String(),
+		extranonce:         extranonce,
+		jobChan:            make(chan *job, 1),
+		closeChan:          make(chan struct{}),
+		varDiff:            newVarDiff(server.cfg.MinShareDifficulty, server.cfg.SharesPerMinute, time.Now()),
+		previousDifficulty: server.cfg.MinShareDifficulty,
+	}
+}
+
+func (c *stratumClient) disconnect() {
+	c.closeOnce.Do(func() {
+		close(c.closeChan)
+		c.conn.Close()
+	})
+}
+
+// handleRequests reads and handles the requests of the miner until it disconnects
+func (c *stratumClient) handleRequests() {
+	scanner := bufio.NewScanner(c.conn)
+	scanner.Buffer(make([]byte, maxRequestSize), maxRequestSize)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		req := &request{}
+		err := json.Unmarshal([]byte(line), req)
+		if err != nil {
+			log.Warnf("Received a malformed request from %s: %s", c.remoteAddress, err)
+			return
+		}
+		err = c.handleRequest(req)
+		if err != nil {
+			log.Warnf("Error handling %s from %s: %s", req.Method, c.remoteAddress, err)
+			return
+		}
+	}
+	err := scanner.Err()
+	if err != nil && !c.isClosed() {
+		log.Debugf("Error reading from %s: %s", c.remoteAddress, err)
+	}
+}
+
+func (c *stratumClient) isClosed() bool {
+	select {
+	case <-c.closeChan:
+		return true
+	default:
+		return false
+	}
+}
+
+// handleRequest handles a single request. Stratum errors are returned to the
+// miner, while any other error disconnects it.
+func (c *stratumClient) handleRequest(req *request) error {
+	var err error
+	switch req.Method {
+	case methodSubscribe:
+		err = c.handleSubscribe(req)
+	case methodExtranonceSubscribe:
+		err = c.respond(req, true)
+	case methodAuthorize:
+		err = c.handleAuthorize(req)
+	case methodSubmit:
+		err = c.handleSubmit(req)
+	default:
+		err = newStratumError(errorCodeOther, "unknown method %s", req.Method)
+	}
+
+	stratumErr := &stratumError{}
+	if errors.As(err, &stratumErr) {
+		log.Debugf("Rejected %s from %s: %s", req.Method, c.remoteAddress, stratumErr.message)
+		return c.send(&response{
+			ID:    req.ID,
+			Error: []interface{}{stratumErr.code, stratumErr.message, nil},
+		})
+	}
+	return err
+}
+
+func (c *stratumClient) handleSubscribe(req *request) error {
+	c.lock.Lock()
+	c.isSubscribed = true
+	c.lock.Unlock()
+
+	err := c.respond(req, []interface{}{true, stratumProtocolVersion})
+	if err != nil {
+		return err
+	}
+	return c.notify(methodSetExtranonce, c.extranonceString(), nonceSize-extranonceSize)
+}
+
+func (c *stratumClient) handleAuthorize(req *request) error {
+	workerName, err := req.stringParam(0)
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	if !c.isSubscribed {
+		c.lock.Unlock()
+		return newStratumError(errorCodeNotSubscribed, "not subscribed")
+	}
+	c.isAuthorized = true
+	c.workerName = workerName
+	difficulty := c.varDiff.difficulty
+	c.lock.Unlock()
+
+	log.Infof("Worker %s authorized from %s", workerName, c.remoteAddress)
+	err = c.respond(req, true)
+	if err != nil {
+		return err
+	}
+	err = c.notify(methodSetDifficulty, difficulty)
+	if err != nil {
+		return err
+	}
+
+	latestJob := c.server.jobs.latest()
+	if latestJob != nil {
+		c.queueJob(latestJob)
+	}
+	return nil
+}
+
+func (c *stratumClient) handleSubmit(req *request) error {
+	c.lock.Lock()
+	isAuthorized, workerName := c.isAuthorized, c.workerName
+	c.lock.Unlock()
+	if !isAuthorized {
+		return newStratumError(errorCodeUnauthorized, "unauthorized worker")
+	}
+
+	jobID, err := req.stringParam(1)
+	if err != nil {
+		return err
+	}
+	nonceString, err := req.stringParam(2)
+	if err != nil {
+		return err
+	}
+	nonce, err := c.parseNonce(nonceString)
+	if err != nil {
+		return err
+	}
+
+	job, ok := c.server.jobs.get(jobID)
+	if !ok {
+		return newStratumError(errorCodeJobNotFound, "job %s not found", jobID)
+	}
+	if !job.addSubmittedNonce(nonce) {
+		return newStratumError(errorCodeDuplicateShare, "duplicate share")
+	}
+
+	state := *job.state
+	state.Nonce = nonce
+	powValue := state.CalculateProofOfWorkValue()
+
+	// A block is always worth a share, even if the share difficulty is higher than the
+	// difficulty of the block
+	isBlock := powValue.Cmp(&state.Target) <= 0
+	if isBlock {
+		c.server.submitBlock(job, nonce, workerName)
+	} else if powValue.Cmp(c.shareTarget()) > 0 {
+		return newStratumError(errorCodeLowDifficulty, "low difficulty share")
+	}
+
+	err = c.addShare()
+	if err != nil {
+		return err
+	}
+	return c.respond(req, true)
+}
+
+// extranonceString returns the extranonce of the miner as the hex string that's sent to it
+func (c *stratumClient) extranonceString() string {
+	return fmt.Sprintf("%0*x", extranonceSize*2, c.extranonce)
+}
+
+// parseNonce parses a hex nonce submitted by the miner. A nonce is either a full nonceSize
+// bytes nonce, or at most nonceSize-extranonceSize bytes, in which case it's prefixed with
+// the extranonce of the miner.
+func (c *stratumClient) parseNonce(nonceString string) (uint64, error) {
+	nonceString = strings.TrimPrefix(nonceString, "0x")
+	switch {
+	case len(nonceString) == nonceSize*2:
+	case len(nonceString) <= (nonceSize-extranonceSize)*2:
+		padding := strings.Repeat("0", (nonceSize-extranonceSize)*2-len(nonceString))
+		nonceString = c.extranonceString() + padding + nonceString
+	default:
+		return 0, newStratumError(errorCodeMalformedRequest,
+			"nonce %s is neither %d bytes nor at most %d bytes", nonceString, nonceSize, nonceSize-extranonceSize)
+	}
+	nonce, err := strconv.ParseUint(nonceString, 16, 64)
+	if err != nil {
+		return 0, newStratumError(errorCodeMalformedRequest, "malformed nonce %s", nonceString)
+	}
+	return nonce, nil
+}
+
+// shareTarget returns the target a hash has to meet in order to be a share. Jobs that were sent
+// before the last difficulty change are still being mined with the previous difficulty, so the
+// easier of the two is used.
+func (c *stratumClient) shareTarget() *big.Int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return shareDifficultyToTarget(math.Min(c.varDiff.difficulty, c.previousDifficulty))
+}
+
+// addShare counts an accepted share, and notifies the miner if its difficulty
+// was retargeted as a result
+func (c *stratumClient) addShare() error {
+	c.lock.Lock()
+	c.varDiff.addShare()
+	difficulty, isRetargeted := c.retarget()
+	c.lock.Unlock()
+
+	if !isRetargeted {
+		return nil
+	}
+	return c.notify(methodSetDifficulty, difficulty)
+}
+
+// retarget retargets the difficulty of the miner, and returns it along with whether it was changed.
+// c.lock must be held when calling this function.
+func (c *stratumClient) retarget() (float64, bool) {
+	previousDifficulty := c.varDiff.difficulty
+	if !c.varDiff.retarget(time.Now()) {
+		return previousDifficulty, false
+	}
+	c.previousDifficulty = previousDifficulty
+	log.Debugf("Retargeted the difficulty of worker %s from %g to %g",
+		c.workerName, previousDifficulty, c.varDiff.difficulty)
+	return c.varDiff.difficulty, true
+}
+
+// queueJob queues the given job to be sent to the miner, replacing any job that
+// wasn't sent yet
+func (c *stratumClient) queueJob(job *job) {
+	for {
+		select {
+		case c.jobChan <- job:
+			return
+		default:
+		}
+		select {
+		case <-c.jobChan:
+		default:
+		}
+	}
+}
+
+// jobsLoop sends the queued jobs to the miner once it's authorized
+func (c *stratumClient) jobsLoop() {
+	for {
+		select {
+		case <-c.closeChan:
+			return
+		case job := <-c.jobChan:
+			err := c.sendJob(job)
+			if err != nil {
+				log.Debugf("Error sending job %s to %s: %s", job.id, c.remoteAddress, err)
+				c.disconnect()
+				return
+			}
+		}
+	}
+}
+
+func (c *stratumClient) sendJob(job *job) error {
+	c.lock.Lock()
+	if !c.isAuthorized {
+		c.lock.Unlock()
+		return nil
+	}
+	// Miners that don't submit shares are retargeted here, since they
+	// don't trigger a retarget by themselves
+	difficulty, isRetargeted := c.retarget()
+	c.lock.Unlock()
+
+	if isRetargeted {
+		err := c.notify(methodSetDifficulty, difficulty)
+		if err != nil {
+			return err
+		}
+	}
+	return c.notify(methodNotify, job.notifyParams()...)
+}
+
+func (c *stratumClient) respond(req *request, result interface{}) error {
+	return c.send(&response{ID: req.ID, Result: result})
+}
+
+func (c *stratumClient) notify(method string, params ...interface{}) error {
+	return c.send(&notification{Method: method, Params: params})
+}
+
+func (c *stratumClient) send(message interface{}) error {
+	messageBytes, err := json.Marshal(message)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	messageBytes = append(messageBytes, '\n')
+
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+	err = c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = c.conn.Write(messageBytes)
+	return errors.WithStack(err)
+}
+
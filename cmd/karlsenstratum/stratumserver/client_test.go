@@ -0,0 +1,48 @@
+package stratumserver
+
+import (
+	"testing"
+)
+
+func TestParseNonce(t *testing.T) {
+	client := &stratumClient{extranonce: 0xabcd}
+
+	tests := []struct {
+		name          string
+		nonceString   string
+		expectedNonce uint64
+		expectedError bool
+	}{
+		{name: "empty", nonceString: "", expectedNonce: 0xabcd000000000000},
+		{name: "without extranonce", nonceString: "123456789abc", expectedNonce: 0xabcd123456789abc},
+		{name: "with 0x prefix", nonceString: "0x123456789abc", expectedNonce: 0xabcd123456789abc},
+		{name: "13 chars", nonceString: "123456789abcd", expectedError: true},
+		{name: "15 chars", nonceString: "123456789abcdef", expectedError: true},
+		{name: "full nonce", nonceString: "0123456789abcdef", expectedNonce: 0x0123456789abcdef},
+		{name: "17 chars", nonceString: "0123456789abcdef0", expectedError: true},
+		{name: "not hex", nonceString: "xyz", expectedError: true},
+	}
+
+	for _, test := range tests {
+		nonce, err := client.parseNonce(test.nonceString)
+		if test.expectedError {
+			if err == nil {
+				t.Errorf("%s: expected an error, but got nonce %x", test.name, nonce)
+				continue
+			}
+			stratumErr, ok := err.(*stratumError)
+			if !ok || stratumErr.code != errorCodeMalformedRequest {
+				t.Errorf("%s: expected a malformed request error, but got: %s", test.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.name, err)
+			continue
+		}
+		if nonce != test.expectedNonce {
+			t.Errorf("%s: expected nonce %x, but got %x", test.name, test.expectedNonce, nonce)
+		}
+	}
+}
+
@@ -0,0 +1,105 @@
+package stratumserver
+
+import (
+	"encoding/binary"
+	"strconv"
+	"sync"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/pow"
+)
+
+// maxJobs is the number of recent jobs shares are accepted for. Older
+// jobs are forgotten, and shares for them are rejected as stale.
+const maxJobs = 64
+
+// job is a block template that was sent to the miners
+type job struct {
+	id    string
+	block *externalapi.DomainBlock
+	state *pow.State
+
+	submittedNoncesLock sync.Mutex
+	submittedNonces     map[uint64]struct{}
+}
+
+// notifyParams returns the parameters of the mining.notify message of the job:
+// the job ID, the pre-PoW hash as four little endian uint64 words, and the timestamp
+func (j *job) notifyParams() []interface{} {
+	prePowHash := j.state.PrePowHash().ByteArray()
+	words := make([]uint64, 4)
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64(prePowHash[i*8:])
+	}
+	return []interface{}{j.id, words, j.state.Timestamp}
+}
+
+// addSubmittedNonce marks the given nonce as submitted, and returns false if it
+// was already submitted before
+func (j *job) addSubmittedNonce(nonce uint64) bool {
+	j.submittedNoncesLock.Lock()
+	defer j.submittedNoncesLock.Unlock()
+
+	if _, ok := j.submittedNonces[nonce]; ok {
+		return false
+	}
+	j.submittedNonces[nonce] = struct{}{}
+	return true
+}
+
+// jobStore holds the most recent jobs by their IDs
+type jobStore struct {
+	lock      sync.RWMutex
+	nextID    uint64
+	jobs      map[string]*job
+	jobIDs    []string
+	latestJob *job
+}
+
+func newJobStore() *jobStore {
+	return &jobStore{
+		jobs:   make(map[string]*job),
+		jobIDs: make([]string, 0, maxJobs),
+	}
+}
+
+// add creates a new job out of the given block template and makes it the latest job
+func (js *jobStore) add(block *externalapi.DomainBlock, state *pow.State) *job {
+	js.lock.Lock()
+	defer js.lock.Unlock()
+
+	js.nextID++
+	newJob := &job{
+		id:              strconv.FormatUint(js.nextID, 10),
+		block:           block,
+		state:           state,
+		submittedNonces: make(map[uint64]struct{}),
+	}
+
+	if len(js.jobIDs) == maxJobs {
+		delete(js.jobs, js.jobIDs[0])
+		js.jobIDs = js.jobIDs[1:]
+	}
+	js.jobs[newJob.id] = newJob
+	js.jobIDs = append(js.jobIDs, newJob.id)
+	js.latestJob = newJob
+	return newJob
+}
+
+// get returns the job with the given ID, if it's still stored
+func (js *jobStore) get(id string) (*job, bool) {
+	js.lock.RLock()
+	defer js.lock.RUnlock()
+
+	job, ok := js.jobs[id]
+	return job, ok
+}
+
+// latest returns the most recent job, or nil if there isn't any
+func (js *jobStore) latest() *job {
+	js.lock.RLock()
+	defer js.lock.RUnlock()
+
+	return js.latestJob
+}
+
@@ -0,0 +1,10 @@
+package stratumserver
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/logger"
+	"github.com/karlsend/PYVERT/testfork/karlsend/util/panics"
+)
+
+var log = logger.RegisterSubSystem("STRM")
+var spawn = panics.GoroutineWrapperFunc(log)
+
@@ -0,0 +1,81 @@
+package stratumserver
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	methodSubscribe           = "mining.subscribe"
+	methodExtranonceSubscribe = "mining.extranonce.subscribe"
+	methodAuthorize           = "mining.authorize"
+	methodSubmit              = "mining.submit"
+	methodSetDifficulty       = "mining.set_difficulty"
+	methodSetExtranonce       = "mining.set_extranonce"
+	methodNotify              = "mining.notify"
+
+	// stratumProtocolVersion is the protocol version returned to mining.subscribe
+	stratumProtocolVersion = "EthereumStratum/1.0.0"
+)
+
+// Stratum error codes, as returned in the error field of responses
+const (
+	errorCodeOther            = 20
+	errorCodeJobNotFound      = 21
+	errorCodeDuplicateShare   = 22
+	errorCodeLowDifficulty    = 23
+	errorCodeUnauthorized     = 24
+	errorCodeNotSubscribed    = 25
+	errorCodeMalformedRequest = 26
+)
+
+// request is a stratum JSON-RPC request sent by a miner
+type request struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params []interface{}   `json:"params"`
+}
+
+// response is a stratum JSON-RPC response to a request.
+// Errors are in the form [code, message, null]
+type response struct {
+	ID     json.RawMessage `json:"id"`
+	Result interface{}     `json:"result"`
+	Error  []interface{}   `json:"error"`
+}
+
+// notification is a stratum JSON-RPC request sent by the server, which
+// expects no response
+type notification struct {
+	ID     interface{}   `json:"id"`
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+}
+
+// stratumError is an error that's returned to the miner with the given code
+type stratumError struct {
+	code    int
+	message string
+}
+
+func (e *stratumError) Error() string {
+	return e.message
+}
+
+func newStratumError(code int, format string, args ...interface{}) *stratumError {
+	return &stratumError{code: code, message: fmt.Sprintf(format, args...)}
+}
+
+func (r *request) stringParam(index int) (string, error) {
+	if index >= len(r.Params) {
+		return "", newStratumError(errorCodeMalformedRequest, "%s expects at least %d parameters",
+			r.Method, index+1)
+	}
+	param, ok := r.Params[index].(string)
+	if !ok {
+		return "", newStratumError(errorCodeMalformedRequest, "parameter %d of %s must be a string",
+			index, r.Method)
+	}
+	return param, nil
+}
+
@@ -0,0 +1,269 @@
+package stratumserver
+
+import (
+	"math"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/pow"
+	"github.com/pkg/errors"
+)
+
+// templateRefreshInterval is the interval in which the block template is refreshed even if
+// the node didn't notify about a new one, so that the timestamps of the jobs stay recent
+const templateRefreshInterval = time.Second
+
+// Config is the configuration of a stratum server
+type Config struct {
+	// ListenAddress is the TCP address miners connect to
+	ListenAddress string
+	// MiningAddress is the address the coinbase of the mined blocks pays to
+	MiningAddress string
+	// ExtraData is added to the coinbase payload of the mined blocks
+	ExtraData string
+	// FishHashActivationDAAScore is the DAA score from which blocks are mined with FishHash
+	FishHashActivationDAAScore uint64
+	// MinShareDifficulty is the initial and minimal share difficulty of every worker
+	MinShareDifficulty float64
+	// SharesPerMinute is the share rate vardiff aims for
+	SharesPerMinute float64
+	// MineWhenNotSynced makes the server send jobs even if the node is not synced
+	MineWhenNotSynced bool
+}
+
+// NodeClient is the part of the node's RPC client that the stratum server uses
+type NodeClient interface {
+	GetBlockTemplateWithOptions(miningAddress, extraData, longPollTemplateID string,
+		headerOnly bool) (*appmessage.GetBlockTemplateResponseMessage, error)
+	SubmitBlock(block *externalapi.DomainBlock) (appmessage.RejectReason, error)
+	RegisterForNewBlockTemplateNotifications(
+		onNewBlockTemplate func(notification *appmessage.NewBlockTemplateNotificationMessage)) error
+}
+
+// Server is a stratum server that converts the block templates of a node into
+// stratum jobs, validates the shares of the miners, and submits the blocks they find
+// to the node
+type Server struct {
+	cfg      *Config
+	client   NodeClient
+	listener net.Listener
+	jobs     *jobStore
+
+	clientsLock      sync.Mutex
+	clients          map[*stratumClient]struct{}
+	extranoncesInUse map[uint16]struct{}
+	nextExtranonce   uint16
+
+	isNotSyncedLogged    bool
+	newBlockTemplateChan chan struct{}
+	shutdownChan         chan struct{}
+	shutdown             uint32
+}
+
+// New creates a new stratum server that gets its block templates from the given client
+func New(cfg *Config, client NodeClient) *Server {
+	return &Server{
+		cfg:                  cfg,
+		client:               client,
+		jobs:                 newJobStore(),
+		clients:              make(map[*stratumClient]struct{}),
+		extranoncesInUse:     make(map[uint16]struct{}),
+		newBlockTemplateChan: make(chan struct{}, 1),
+		shutdownChan:         make(chan struct{}),
+	}
+}
+
+// Start starts listening to miners and requesting block templates from the node
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.cfg.ListenAddress)
+	if err != nil {
+		return errors.Wrapf(err, "error listening to TCP on %s", s.cfg.ListenAddress)
+	}
+	s.listener = listener
+
+	err = s.client.RegisterForNewBlockTemplateNotifications(func(_ *appmessage.NewBlockTemplateNotificationMessage) {
+		s.signalNewBlockTemplate()
+	})
+	if err != nil {
+		listener.Close()
+		return errors.Wrapf(err, "error requesting new-block-template notifications")
+	}
+
+	spawn("Server.templatesLoop", s.templatesLoop)
+	spawn("Server.acceptLoop", s.acceptLoop)
+
+	log.Infof("Stratum server listening on %s", listener.Addr())
+	return nil
+}
+
+// Stop stops the server and disconnects all the miners
+func (s *Server) Stop() error {
+	if atomic.AddUint32(&s.shutdown, 1) != 1 {
+		return errors.New("stratum server stopped more than once")
+	}
+	close(s.shutdownChan)
+	err := s.listener.Close()
+
+	s.clientsLock.Lock()
+	defer s.clientsLock.Unlock()
+	for client := range s.clients {
+		client.disconnect()
+	}
+	return errors.WithStack(err)
+}
+
+func (s *Server) isShutdown() bool {
+	return atomic.LoadUint32(&s.shutdown) != 0
+}
+
+func (s *Server) signalNewBlockTemplate() {
+	select {
+	case s.newBlockTemplateChan <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Server) acceptLoop() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if s.isShutdown() {
+				return
+			}
+			log.Warnf("Error accepting a stratum connection: %s", err)
+			continue
+		}
+
+		client, err := s.addClient(conn)
+		if err != nil {
+			log.Warnf("Refused the stratum connection from %s: %s", conn.RemoteAddr(), err)
+			conn.Close()
+			continue
+		}
+		spawn("stratumClient.handleRequests", func() {
+			defer s.removeClient(client)
+			client.handleRequests()
+		})
+		spawn("stratumClient.jobsLoop", client.jobsLoop)
+	}
+}
+
+func (s *Server) addClient(conn net.Conn) (*stratumClient, error) {
+	s.clientsLock.Lock()
+	defer s.clientsLock.Unlock()
+
+	extranonce, err := s.allocateExtranonce()
+	if err != nil {
+		return nil, err
+	}
+
+	client := newStratumClient(s, conn, extranonce)
+	s.clients[client] = struct{}{}
+	log.Infof("Miner %s connected", client.remoteAddress)
+	return client, nil
+}
+
+// allocateExtranonce returns an extranonce that isn't used by any connected miner, so that
+// miners don't search the same nonces.
+// s.clientsLock must be held when calling this function.
+func (s *Server) allocateExtranonce() (uint16, error) {
+	if len(s.extranoncesInUse) > math.MaxUint16 {
+		return 0, errors.Errorf("all %d extranonces are in use", len(s.extranoncesInUse))
+	}
+	for {
+		extranonce := s.nextExtranonce
+		s.nextExtranonce++
+		if _, ok := s.extranoncesInUse[extranonce]; !ok {
+			s.extranoncesInUse[extranonce] = struct{}{}
+			return extranonce, nil
+		}
+	}
+}
+
+func (s *Server) removeClient(client *stratumClient) {
+	s.clientsLock.Lock()
+	defer s.clientsLock.Unlock()
+
+	client.disconnect()
+	delete(s.clients, client)
+	delete(s.extranoncesInUse, client.extranonce)
+	log.Infof("Miner %s disconnected", client.remoteAddress)
+}
+
+func (s *Server) templatesLoop() {
+	ticker := time.NewTicker(templateRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		err := s.refreshTemplate()
+		if err != nil {
+			log.Warnf("Error refreshing the block template: %s", err)
+		}
+
+		select {
+		case <-s.shutdownChan:
+			return
+		case <-s.newBlockTemplateChan:
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshTemplate requests a block template from the node, and sends it as a new job to all
+// the authorized miners
+func (s *Server) refreshTemplate() error {
+	// Jobs only need the header of the template, and the node fills in the transactions
+	// of the blocks that are mined from header-only templates when they're submitted
+	template, err := s.client.GetBlockTemplateWithOptions(s.cfg.MiningAddress, s.cfg.ExtraData, "", true)
+	if err != nil {
+		return err
+	}
+	if !template.IsSynced && !s.cfg.MineWhenNotSynced {
+		if !s.isNotSyncedLogged {
+			log.Warnf("The node is not synced. Jobs will be sent once it is")
+			s.isNotSyncedLogged = true
+		}
+		return nil
+	}
+	s.isNotSyncedLogged = false
+
+	block, err := appmessage.RPCBlockToDomainBlock(template.Block)
+	if err != nil {
+		return err
+	}
+	state := pow.NewState(block.Header.ToMutable(), s.cfg.FishHashActivationDAAScore)
+	newJob := s.jobs.add(block, state)
+
+	s.clientsLock.Lock()
+	defer s.clientsLock.Unlock()
+	for client := range s.clients {
+		client.queueJob(newJob)
+	}
+	return nil
+}
+
+// submitBlock submits the block of the given job with the given nonce to the node
+func (s *Server) submitBlock(job *job, nonce uint64, workerName string) {
+	header := job.block.Header.ToMutable()
+	header.SetNonce(nonce)
+	block := &externalapi.DomainBlock{
+		Header:       header.ToImmutable(),
+		Transactions: job.block.Transactions,
+	}
+	blockHash := consensushashing.BlockHash(block)
+
+	log.Infof("Worker %s found block %s", workerName, blockHash)
+	rejectReason, err := s.client.SubmitBlock(block)
+	if err != nil {
+		log.Warnf("Block %s was rejected (%s): %s", blockHash, rejectReason, err)
+		return
+	}
+	log.Infof("Submitted block %s", blockHash)
+	s.signalNewBlockTemplate()
+}
+
@@ -0,0 +1,40 @@
+package stratumserver
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAllocateExtranonce(t *testing.T) {
+	server := New(&Config{}, nil)
+
+	for i := 0; i <= math.MaxUint16; i++ {
+		extranonce, err := server.allocateExtranonce()
+		if err != nil {
+			t.Fatalf("allocateExtranonce #%d: %s", i, err)
+		}
+		if extranonce != uint16(i) {
+			t.Fatalf("allocateExtranonce #%d: expected extranonce %d, but got %d", i, i, extranonce)
+		}
+	}
+
+	_, err := server.allocateExtranonce()
+	if err == nil {
+		t.Fatalf("allocateExtranonce: expected an error once all the extranonces are in use")
+	}
+
+	// Once an extranonce is released it's the only one that can be handed out again
+	delete(server.extranoncesInUse, 1234)
+	extranonce, err := server.allocateExtranonce()
+	if err != nil {
+		t.Fatalf("allocateExtranonce: %s", err)
+	}
+	if extranonce != 1234 {
+		t.Fatalf("allocateExtranonce: expected the released extranonce 1234, but got %d", extranonce)
+	}
+	_, err = server.allocateExtranonce()
+	if err == nil {
+		t.Fatalf("allocateExtranonce: expected an error once all the extranonces are in use")
+	}
+}
+
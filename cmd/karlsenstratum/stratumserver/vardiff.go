@@ -0,0 +1,96 @@
+package stratumserver
+
+import (
+	"math"
+	"math/big"
+	"time"
+)
+
+const (
+	// varDiffRetargetInterval is the interval over which the share rate of a worker is measured
+	varDiffRetargetInterval = 30 * time.Second
+	// varDiffMinRetargetInterval is the minimal interval before the difficulty of a worker that
+	// submits shares much faster than expected is retargeted
+	varDiffMinRetargetInterval = 5 * time.Second
+	// varDiffMaxAdjustmentFactor bounds the change of the difficulty in a single retarget
+	varDiffMaxAdjustmentFactor = 4.0
+	// varDiffTolerance is the relative deviation from the expected share rate that is
+	// tolerated without changing the difficulty
+	varDiffTolerance = 0.25
+)
+
+// shareDifficultyOneTarget is the share target of difficulty 1, which is met once every
+// 2^32 hashes on average
+var shareDifficultyOneTarget = new(big.Int).Lsh(big.NewInt(1), 256-32)
+
+// maxShareTarget is the largest possible hash value, which every hash meets
+var maxShareTarget = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// shareDifficultyToTarget returns the target a hash has to meet to be a share of the given difficulty
+func shareDifficultyToTarget(difficulty float64) *big.Int {
+	target, _ := new(big.Float).Quo(new(big.Float).SetInt(shareDifficultyOneTarget),
+		big.NewFloat(difficulty)).Int(nil)
+	if target.Cmp(maxShareTarget) > 0 {
+		return maxShareTarget
+	}
+	return target
+}
+
+// varDiff adjusts the share difficulty of a worker so that it submits about
+// sharesPerMinute shares a minute
+type varDiff struct {
+	difficulty      float64
+	minDifficulty   float64
+	sharesPerMinute float64
+
+	windowStart    time.Time
+	sharesInWindow int
+}
+
+func newVarDiff(minDifficulty, sharesPerMinute float64, now time.Time) *varDiff {
+	return &varDiff{
+		difficulty:      minDifficulty,
+		minDifficulty:   minDifficulty,
+		sharesPerMinute: sharesPerMinute,
+		windowStart:     now,
+	}
+}
+
+// addShare counts an accepted share of the worker
+func (vd *varDiff) addShare() {
+	vd.sharesInWindow++
+}
+
+// retarget recalculates the difficulty according to the share rate since the
+// last retarget, and returns whether it was changed.
+// The difficulty is retargeted once every varDiffRetargetInterval, or earlier if the
+// worker already submitted the shares expected for the whole interval.
+func (vd *varDiff) retarget(now time.Time) bool {
+	elapsed := now.Sub(vd.windowStart)
+	if elapsed < varDiffMinRetargetInterval {
+		return false
+	}
+	expectedSharesInInterval := vd.sharesPerMinute * varDiffRetargetInterval.Minutes()
+	if elapsed < varDiffRetargetInterval && float64(vd.sharesInWindow) < expectedSharesInInterval {
+		return false
+	}
+
+	expectedShares := vd.sharesPerMinute * elapsed.Minutes()
+	ratio := float64(vd.sharesInWindow) / expectedShares
+	ratio = math.Max(ratio, 1/varDiffMaxAdjustmentFactor)
+	ratio = math.Min(ratio, varDiffMaxAdjustmentFactor)
+
+	vd.windowStart = now
+	vd.sharesInWindow = 0
+
+	if math.Abs(ratio-1) < varDiffTolerance {
+		return false
+	}
+	newDifficulty := math.Max(vd.difficulty*ratio, vd.minDifficulty)
+	if newDifficulty == vd.difficulty {
+		return false
+	}
+	vd.difficulty = newDifficulty
+	return true
+}
+
@@ -171,6 +171,19 @@ func (s *consensus) BuildBlock(coinbaseData *externalapi.DomainCoinbaseData,
 	return block, err
 }
 
+// BuildBlockOverParents builds a block over the given parents rather than over the
+// current state, with the given coinbaseData and the given transactions
+func (s *consensus) BuildBlockOverParents(parentHashes []*externalapi.DomainHash,
+	coinbaseData *externalapi.DomainCoinbaseData, transactions []*externalapi.DomainTransaction) (
+	*externalapi.DomainBlock, error) {
+
+	// Require write lock because BuildBlockOverParents stages temporary data
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.blockBuilder.BuildBlockOverParents(parentHashes, coinbaseData, transactions)
+}
+
 // BuildBlockTemplate builds a block over the current state, with the transactions
 // selected by the given transactionSelector plus metadata information related to
 // coinbase rewards and node sync status
@@ -989,6 +1002,48 @@ func (s *consensus) resolveVirtualChunkNoLock(maxBlocksToResolve uint64) (*exter
 	return virtualChangeSet, isCompletelyResolved, nil
 }
 
+// InvalidateBlock manually marks the given block and its future as disqualified
+// from the selected chain, and re-resolves the virtual without them
+func (s *consensus) InvalidateBlock(blockHash *externalapi.DomainHash) error {
+	err := s.applyManualOverride(blockHash, s.consensusStateManager.InvalidateBlock)
+	if err != nil {
+		return err
+	}
+
+	return s.ResolveVirtual(nil)
+}
+
+// ReconsiderBlock removes a manual invalidation that was set by InvalidateBlock,
+// and re-resolves the virtual
+func (s *consensus) ReconsiderBlock(blockHash *externalapi.DomainHash) error {
+	err := s.applyManualOverride(blockHash, s.consensusStateManager.ReconsiderBlock)
+	if err != nil {
+		return err
+	}
+
+	return s.ResolveVirtual(nil)
+}
+
+func (s *consensus) applyManualOverride(blockHash *externalapi.DomainHash,
+	override func(blockHash *externalapi.DomainHash) (*externalapi.VirtualChangeSet, error)) error {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stagingArea := model.NewStagingArea()
+	err := s.validateBlockHashExists(stagingArea, blockHash)
+	if err != nil {
+		return err
+	}
+
+	virtualChangeSet, err := override(blockHash)
+	if err != nil {
+		return err
+	}
+
+	return s.sendVirtualChangedEvent(virtualChangeSet, true)
+}
+
 func (s *consensus) BuildPruningPointProof() (*externalapi.PruningPointProof, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
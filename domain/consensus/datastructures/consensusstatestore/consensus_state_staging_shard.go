@@ -6,17 +6,19 @@ import (
 )
 
 type consensusStateStagingShard struct {
-	store                  *consensusStateStore
-	tipsStaging            []*externalapi.DomainHash
-	virtualUTXODiffStaging externalapi.UTXODiff
+	store                    *consensusStateStore
+	tipsStaging              []*externalapi.DomainHash
+	virtualUTXODiffStaging   externalapi.UTXODiff
+	invalidatedBlocksStaging []*externalapi.DomainHash
 }
 
 func (bs *consensusStateStore) stagingShard(stagingArea *model.StagingArea) *consensusStateStagingShard {
 	return stagingArea.GetOrCreateShard(bs.shardID, func() model.StagingShard {
 		return &consensusStateStagingShard{
-			store:                  bs,
-			tipsStaging:            nil,
-			virtualUTXODiffStaging: nil,
+			store:                    bs,
+			tipsStaging:              nil,
+			virtualUTXODiffStaging:   nil,
+			invalidatedBlocksStaging: nil,
 		}
 	}).(*consensusStateStagingShard)
 }
@@ -32,10 +34,15 @@ func (csss *consensusStateStagingShard) Commit(dbTx model.DBTransaction) error {
 		return err
 	}
 
+	err = csss.commitInvalidatedBlocks(dbTx)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func (csss *consensusStateStagingShard) isStaged() bool {
-	return csss.tipsStaging != nil || csss.virtualUTXODiffStaging != nil
+	return csss.tipsStaging != nil || csss.virtualUTXODiffStaging != nil || csss.invalidatedBlocksStaging != nil
 }
 
@@ -15,6 +15,8 @@ type consensusStateStore struct {
 	virtualUTXOSetCache             *utxolrucache.LRUCache
 	tipsCache                       []*externalapi.DomainHash
 	tipsKey                         model.DBKey
+	invalidatedBlocksCache          []*externalapi.DomainHash
+	invalidatedBlocksBucket         model.DBBucket
 	utxoSetBucket                   model.DBBucket
 	importingPruningPointUTXOSetKey model.DBKey
 }
@@ -27,6 +29,7 @@ func New(prefixBucket model.DBBucket, utxoSetCacheSize int, preallocate bool) mo
 		tipsKey:                         prefixBucket.Key(tipsKeyName),
 		importingPruningPointUTXOSetKey: prefixBucket.Key(importingPruningPointUTXOSetKeyName),
 		utxoSetBucket:                   prefixBucket.Bucket(utxoSetBucketName),
+		invalidatedBlocksBucket:         prefixBucket.Bucket(invalidatedBlocksBucketName),
 	}
 }
 
@@ -0,0 +1,84 @@
+package consensusstatestore
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+)
+
+var invalidatedBlocksBucketName = []byte("manually-invalidated-blocks")
+
+func (css *consensusStateStore) InvalidatedBlocks(stagingArea *model.StagingArea, dbContext model.DBReader) (
+	[]*externalapi.DomainHash, error) {
+
+	stagingShard := css.stagingShard(stagingArea)
+
+	if stagingShard.invalidatedBlocksStaging != nil {
+		return externalapi.CloneHashes(stagingShard.invalidatedBlocksStaging), nil
+	}
+
+	invalidatedBlocks, err := css.committedInvalidatedBlocks(dbContext)
+	if err != nil {
+		return nil, err
+	}
+	return externalapi.CloneHashes(invalidatedBlocks), nil
+}
+
+func (css *consensusStateStore) committedInvalidatedBlocks(dbContext model.DBReader) ([]*externalapi.DomainHash, error) {
+	if css.invalidatedBlocksCache != nil {
+		return css.invalidatedBlocksCache, nil
+	}
+
+	cursor, err := dbContext.Cursor(css.invalidatedBlocksBucket)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+
+	invalidatedBlocks := []*externalapi.DomainHash{}
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		key, err := cursor.Key()
+		if err != nil {
+			return nil, err
+		}
+		blockHash, err := externalapi.NewDomainHashFromByteSlice(key.Suffix())
+		if err != nil {
+			return nil, err
+		}
+		invalidatedBlocks = append(invalidatedBlocks, blockHash)
+	}
+	css.invalidatedBlocksCache = invalidatedBlocks
+	return invalidatedBlocks, nil
+}
+
+func (css *consensusStateStore) StageInvalidatedBlocks(stagingArea *model.StagingArea, blockHashes []*externalapi.DomainHash) {
+	stagingShard := css.stagingShard(stagingArea)
+
+	stagingShard.invalidatedBlocksStaging = externalapi.CloneHashes(blockHashes)
+}
+
+func (csss *consensusStateStagingShard) commitInvalidatedBlocks(dbTx model.DBTransaction) error {
+	if csss.invalidatedBlocksStaging == nil {
+		return nil
+	}
+
+	previousInvalidatedBlocks, err := csss.store.committedInvalidatedBlocks(dbTx)
+	if err != nil {
+		return err
+	}
+	for _, blockHash := range previousInvalidatedBlocks {
+		err = dbTx.Delete(csss.store.invalidatedBlocksBucket.Key(blockHash.ByteSlice()))
+		if err != nil {
+			return err
+		}
+	}
+	for _, blockHash := range csss.invalidatedBlocksStaging {
+		err = dbTx.Put(csss.store.invalidatedBlocksBucket.Key(blockHash.ByteSlice()), []byte{})
+		if err != nil {
+			return err
+		}
+	}
+	csss.store.invalidatedBlocksCache = csss.invalidatedBlocksStaging
+
+	return nil
+}
+
@@ -41,9 +41,9 @@ func (uds *utxoDiffStore) Stage(stagingArea *model.StagingArea, blockHash *exter
 
 	stagingShard.utxoDiffToAdd[*blockHash] = utxoDiff
 
-	if utxoDiffChild != nil {
-		stagingShard.utxoDiffChildToAdd[*blockHash] = utxoDiffChild
-	}
+	// A nil utxoDiffChild means that the diff is relative to the virtual, so
+	// any previously stored utxoDiffChild is removed on commit
+	stagingShard.utxoDiffChildToAdd[*blockHash] = utxoDiffChild
 }
 
 func (uds *utxoDiffStore) IsStaged(stagingArea *model.StagingArea) bool {
@@ -112,8 +112,8 @@ func (uds *utxoDiffStore) UTXODiffChild(dbContext model.DBReader, stagingArea *m
 func (uds *utxoDiffStore) HasUTXODiffChild(dbContext model.DBReader, stagingArea *model.StagingArea, blockHash *externalapi.DomainHash) (bool, error) {
 	stagingShard := uds.stagingShard(stagingArea)
 
-	if _, ok := stagingShard.utxoDiffChildToAdd[*blockHash]; ok {
-		return true, nil
+	if utxoDiffChild, ok := stagingShard.utxoDiffChildToAdd[*blockHash]; ok {
+		return utxoDiffChild != nil, nil
 	}
 
 	if uds.utxoDiffChildCache.Has(blockHash) {
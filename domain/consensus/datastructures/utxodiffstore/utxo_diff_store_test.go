@@ -0,0 +1,98 @@
+package utxodiffstore
+
+import (
+	"testing"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/database"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/utxo"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/db/database/ldb"
+)
+
+// TestUTXODiffChildRemovalAcrossRestart verifies that staging a nil UTXODiffChild removes
+// the stored one from the database, and not only from the cache, so that it stays removed
+// after the database is reopened
+func TestUTXODiffChildRemovalAcrossRestart(t *testing.T) {
+	datadir := t.TempDir()
+	blockHash := externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{1})
+	utxoDiffChild := externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{2})
+
+	// withStore opens the database with a new store, so that nothing is cached from before
+	withStore := func(f func(dbManager model.DBManager, store model.UTXODiffStore)) {
+		db, err := ldb.NewLevelDB(datadir, 8)
+		if err != nil {
+			t.Fatalf("Error opening the database: %+v", err)
+		}
+		defer func() {
+			err := db.Close()
+			if err != nil {
+				t.Fatalf("Error closing the database: %+v", err)
+			}
+		}()
+		f(database.New(db), New(database.MakeBucket(nil), 10, false))
+	}
+	stage := func(dbManager model.DBManager, store model.UTXODiffStore, utxoDiffChild *externalapi.DomainHash) {
+		stagingArea := model.NewStagingArea()
+		store.Stage(stagingArea, blockHash, utxo.NewUTXODiff(), utxoDiffChild)
+		dbTx, err := dbManager.Begin()
+		if err != nil {
+			t.Fatalf("Error beginning a database transaction: %+v", err)
+		}
+		err = stagingArea.Commit(dbTx)
+		if err != nil {
+			t.Fatalf("Error committing the staging area: %+v", err)
+		}
+		err = dbTx.Commit()
+		if err != nil {
+			t.Fatalf("Error committing the database transaction: %+v", err)
+		}
+	}
+	expectUTXODiffChild := func(dbManager model.DBManager, store model.UTXODiffStore,
+		expectedUTXODiffChild *externalapi.DomainHash) {
+
+		stagingArea := model.NewStagingArea()
+		hasUTXODiffChild, err := store.HasUTXODiffChild(dbManager, stagingArea, blockHash)
+		if err != nil {
+			t.Fatalf("HasUTXODiffChild: %+v", err)
+		}
+		if hasUTXODiffChild != (expectedUTXODiffChild != nil) {
+			t.Fatalf("Expected HasUTXODiffChild to be %t, but got %t", expectedUTXODiffChild != nil, hasUTXODiffChild)
+		}
+		if hasUTXODiffChild {
+			actualUTXODiffChild, err := store.UTXODiffChild(dbManager, stagingArea, blockHash)
+			if err != nil {
+				t.Fatalf("UTXODiffChild: %+v", err)
+			}
+			if !actualUTXODiffChild.Equal(expectedUTXODiffChild) {
+				t.Fatalf("Expected the UTXODiffChild %s, but got %s", expectedUTXODiffChild, actualUTXODiffChild)
+			}
+		}
+		_, err = store.UTXODiff(dbManager, stagingArea, blockHash)
+		if err != nil {
+			t.Fatalf("Expected the UTXODiff to be kept, but got: %+v", err)
+		}
+	}
+
+	withStore(func(dbManager model.DBManager, store model.UTXODiffStore) {
+		stage(dbManager, store, utxoDiffChild)
+		expectUTXODiffChild(dbManager, store, utxoDiffChild)
+	})
+	withStore(func(dbManager model.DBManager, store model.UTXODiffStore) {
+		// This also reads the child into the cache before it's removed
+		expectUTXODiffChild(dbManager, store, utxoDiffChild)
+
+		stage(dbManager, store, nil)
+		expectUTXODiffChild(dbManager, store, nil)
+	})
+	withStore(func(dbManager model.DBManager, store model.UTXODiffStore) {
+		expectUTXODiffChild(dbManager, store, nil)
+
+		stage(dbManager, store, utxoDiffChild)
+		expectUTXODiffChild(dbManager, store, utxoDiffChild)
+	})
+	withStore(func(dbManager model.DBManager, store model.UTXODiffStore) {
+		expectUTXODiffChild(dbManager, store, utxoDiffChild)
+	})
+}
+
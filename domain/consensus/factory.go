@@ -202,6 +202,7 @@ func (f *factory) NewConsensus(config *Config, db infrastructuredatabase.Databas
 
 		config.GenesisHash,
 		config.MaxBlockLevel,
+		config.FishHashActivationDAAScore,
 	)
 
 	txMassCalculator := txmass.NewCalculator(config.MassPerTxByte, config.MassPerScriptPubKeyByte, config.MassPerSigOp)
@@ -236,7 +237,9 @@ func (f *factory) NewConsensus(config *Config, db infrastructuredatabase.Databas
 		config.DisableDifficultyAdjustment,
 		config.TargetTimePerBlock,
 		config.GenesisHash,
-		config.GenesisBlock.Header.Bits())
+		config.GenesisBlock.Header.Bits(),
+		config.FishHashActivationDAAScore,
+		config.FishHashActivationBits)
 	coinbaseManager := coinbasemanager.New(
 		dbManager,
 
@@ -350,6 +353,9 @@ func (f *factory) NewConsensus(config *Config, db infrastructuredatabase.Databas
 		config.TimestampDeviationTolerance,
 		config.TargetTimePerBlock,
 		config.MaxBlockLevel,
+		config.FinalityDepth(),
+		config.Checkpoints,
+		config.FishHashActivationDAAScore,
 
 		dbManager,
 		difficultyManager,
@@ -407,6 +413,7 @@ func (f *factory) NewConsensus(config *Config, db infrastructuredatabase.Databas
 		finalityManager,
 		blockParentBuilder,
 		pruningManager,
+		reachabilityManager,
 
 		acceptanceDataStore,
 		blockRelationStore,
@@ -470,6 +477,8 @@ func (f *factory) NewConsensus(config *Config, db infrastructuredatabase.Databas
 		config.K,
 		config.PruningProofM,
 		config.MaxBlockLevel,
+		config.Checkpoints,
+		config.FishHashActivationDAAScore,
 	)
 
 	c := &consensus{
@@ -0,0 +1,445 @@
+package consensus
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/database"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/hashset"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/multiset"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/utxo"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/logger"
+)
+
+// integrityProgressLogInterval is the amount of blocks between progress logs of VerifyIntegrity
+const integrityProgressLogInterval = 100_000
+
+type integrityChecker struct {
+	*consensus
+	stagingArea *model.StagingArea
+	report      *externalapi.IntegrityReport
+}
+
+// VerifyIntegrity walks the consensus stores and verifies their structural invariants. It also
+// recomputes the multisets of the virtual UTXO set and of the pruning point UTXO set and compares
+// them to their commitments. Blocks are checked in topological order, so the first reported
+// inconsistent block is the lowest one.
+func (s *consensus) VerifyIntegrity() (*externalapi.IntegrityReport, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	onEnd := logger.LogAndMeasureExecutionTime(log, "VerifyIntegrity")
+	defer onEnd()
+
+	ic := &integrityChecker{
+		consensus:   s,
+		stagingArea: model.NewStagingArea(),
+		report:      &externalapi.IntegrityReport{},
+	}
+
+	blockHashes, err := ic.collectBlockHashes()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Verifying %d blocks", len(blockHashes))
+	for i, blockHash := range blockHashes {
+		err := ic.verifyBlock(blockHash)
+		if err != nil {
+			return nil, err
+		}
+		ic.report.CheckedBlocks++
+
+		if (i+1)%integrityProgressLogInterval == 0 {
+			log.Infof("Verified %d out of %d blocks", i+1, len(blockHashes))
+		}
+	}
+
+	log.Infof("Verifying the virtual UTXO set")
+	err = ic.verifyVirtualUTXOSet()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Verifying the pruning point UTXO set")
+	err = ic.verifyPruningPointUTXOSet()
+	if err != nil {
+		return nil, err
+	}
+
+	return ic.report, nil
+}
+
+func (ic *integrityChecker) addInconsistency(blockHash *externalapi.DomainHash, format string, args ...interface{}) {
+	ic.report.Inconsistencies = append(ic.report.Inconsistencies, &externalapi.IntegrityInconsistency{
+		BlockHash:   blockHash,
+		Description: fmt.Sprintf(format, args...),
+	})
+}
+
+// collectBlockHashes returns all the blocks in the past of the tips and of the headers
+// selected tip, sorted by blue work. Blocks without a header or relations are reported
+// and are not traversed any further
+func (ic *integrityChecker) collectBlockHashes() ([]*externalapi.DomainHash, error) {
+	tips, err := ic.consensusStateStore.Tips(ic.stagingArea, ic.databaseContext)
+	if err != nil {
+		return nil, err
+	}
+	headersSelectedTip, err := ic.headersSelectedTipStore.HeadersSelectedTip(ic.databaseContext, ic.stagingArea)
+	if err != nil {
+		return nil, err
+	}
+
+	type hashAndHeader struct {
+		hash   *externalapi.DomainHash
+		header externalapi.BlockHeader
+	}
+	var blocks []hashAndHeader
+	visited := make(map[externalapi.DomainHash]struct{})
+	queue := append([]*externalapi.DomainHash{headersSelectedTip}, tips...)
+	for len(queue) > 0 {
+		blockHash := queue[0]
+		queue = queue[1:]
+		if blockHash.Equal(model.VirtualGenesisBlockHash) {
+			continue
+		}
+		if _, ok := visited[*blockHash]; ok {
+			continue
+		}
+		visited[*blockHash] = struct{}{}
+
+		header, err := ic.blockHeaderStore.BlockHeader(ic.databaseContext, ic.stagingArea, blockHash)
+		if err != nil {
+			if !database.IsNotFoundError(err) {
+				return nil, err
+			}
+			ic.addInconsistency(blockHash, "block header is missing")
+			continue
+		}
+		blocks = append(blocks, hashAndHeader{hash: blockHash, header: header})
+
+		blockRelations, err := ic.blockRelationStores[0].BlockRelation(ic.databaseContext, ic.stagingArea, blockHash)
+		if err != nil {
+			if !database.IsNotFoundError(err) {
+				return nil, err
+			}
+			// Reported by verifyBlock
+			continue
+		}
+		queue = append(queue, blockRelations.Parents...)
+	}
+
+	sort.Slice(blocks, func(i, j int) bool {
+		blueWorkComparison := blocks[i].header.BlueWork().Cmp(blocks[j].header.BlueWork())
+		if blueWorkComparison != 0 {
+			return blueWorkComparison < 0
+		}
+		return blocks[i].hash.Less(blocks[j].hash)
+	})
+
+	blockHashes := make([]*externalapi.DomainHash, len(blocks))
+	for i, block := range blocks {
+		blockHashes[i] = block.hash
+	}
+	return blockHashes, nil
+}
+
+func (ic *integrityChecker) verifyBlock(blockHash *externalapi.DomainHash) error {
+	header, err := ic.blockHeaderStore.BlockHeader(ic.databaseContext, ic.stagingArea, blockHash)
+	if err != nil {
+		return err
+	}
+	headerHash := consensushashing.HeaderHash(header)
+	if !headerHash.Equal(blockHash) {
+		ic.addInconsistency(blockHash, "block header hashes to %s", headerHash)
+	}
+
+	status, err := ic.blockStatusStore.Get(ic.databaseContext, ic.stagingArea, blockHash)
+	if err != nil {
+		if !database.IsNotFoundError(err) {
+			return err
+		}
+		ic.addInconsistency(blockHash, "block status is missing")
+		return nil
+	}
+
+	err = ic.verifyBlockRelations(blockHash, header)
+	if err != nil {
+		return err
+	}
+	err = ic.verifyGHOSTDAGData(blockHash, header)
+	if err != nil {
+		return err
+	}
+	err = ic.verifyReachabilityData(blockHash)
+	if err != nil {
+		return err
+	}
+
+	if status != externalapi.StatusHeaderOnly {
+		hasBlock, err := ic.blockStore.HasBlock(ic.databaseContext, ic.stagingArea, blockHash)
+		if err != nil {
+			return err
+		}
+		if !hasBlock {
+			ic.addInconsistency(blockHash, "block has status %s but its body is missing", status)
+		}
+	}
+
+	if status == externalapi.StatusUTXOValid {
+		return ic.verifyUTXOData(blockHash, header)
+	}
+	return nil
+}
+
+func (ic *integrityChecker) verifyBlockRelations(blockHash *externalapi.DomainHash, header externalapi.BlockHeader) error {
+	blockRelations, err := ic.blockRelationStores[0].BlockRelation(ic.databaseContext, ic.stagingArea, blockHash)
+	if err != nil {
+		if !database.IsNotFoundError(err) {
+			return err
+		}
+		ic.addInconsistency(blockHash, "block relations are missing")
+		return nil
+	}
+
+	directParents := hashset.NewFromSlice(header.DirectParents()...)
+	for _, parent := range blockRelations.Parents {
+		if parent.Equal(model.VirtualGenesisBlockHash) {
+			continue
+		}
+		if !directParents.Contains(parent) {
+			ic.addInconsistency(blockHash, "parent %s is not a direct parent in the block header", parent)
+		}
+
+		parentRelations, err := ic.blockRelationStores[0].BlockRelation(ic.databaseContext, ic.stagingArea, parent)
+		if err != nil {
+			if !database.IsNotFoundError(err) {
+				return err
+			}
+			ic.addInconsistency(blockHash, "relations of parent %s are missing", parent)
+			continue
+		}
+		if !hashset.NewFromSlice(parentRelations.Children...).Contains(blockHash) {
+			ic.addInconsistency(blockHash, "block is not a child of its parent %s", parent)
+		}
+	}
+
+	for _, child := range blockRelations.Children {
+		childRelations, err := ic.blockRelationStores[0].BlockRelation(ic.databaseContext, ic.stagingArea, child)
+		if err != nil {
+			if !database.IsNotFoundError(err) {
+				return err
+			}
+			ic.addInconsistency(blockHash, "relations of child %s are missing", child)
+			continue
+		}
+		if !hashset.NewFromSlice(childRelations.Parents...).Contains(blockHash) {
+			ic.addInconsistency(blockHash, "block is not a parent of its child %s", child)
+		}
+	}
+	return nil
+}
+
+func (ic *integrityChecker) verifyGHOSTDAGData(blockHash *externalapi.DomainHash, header externalapi.BlockHeader) error {
+	ghostdagData, err := ic.ghostdagDataStores[0].Get(ic.databaseContext, ic.stagingArea, blockHash, false)
+	if database.IsNotFoundError(err) {
+		// Blocks that were received with trusted data might only have trusted GHOSTDAG data
+		ghostdagData, err = ic.ghostdagDataStores[0].Get(ic.databaseContext, ic.stagingArea, blockHash, true)
+	}
+	if err != nil {
+		if !database.IsNotFoundError(err) {
+			return err
+		}
+		ic.addInconsistency(blockHash, "GHOSTDAG data is missing")
+		return nil
+	}
+
+	if ghostdagData.BlueScore() != header.BlueScore() {
+		ic.addInconsistency(blockHash, "GHOSTDAG blue score %d doesn't match the header blue score %d",
+			ghostdagData.BlueScore(), header.BlueScore())
+	}
+	if ghostdagData.BlueWork().Cmp(header.BlueWork()) != 0 {
+		ic.addInconsistency(blockHash, "GHOSTDAG blue work %s doesn't match the header blue work %s",
+			ghostdagData.BlueWork(), header.BlueWork())
+	}
+
+	selectedParent := ghostdagData.SelectedParent()
+	if selectedParent != nil && !selectedParent.Equal(model.VirtualGenesisBlockHash) {
+		hasSelectedParent, err := ic.blockHeaderStore.HasBlockHeader(ic.databaseContext, ic.stagingArea, selectedParent)
+		if err != nil {
+			return err
+		}
+		if !hasSelectedParent {
+			ic.addInconsistency(blockHash, "selected parent %s is missing", selectedParent)
+		}
+	}
+	return nil
+}
+
+func (ic *integrityChecker) verifyReachabilityData(blockHash *externalapi.DomainHash) error {
+	hasReachabilityData, err := ic.reachabilityDataStore.HasReachabilityData(ic.databaseContext, ic.stagingArea, blockHash)
+	if err != nil {
+		return err
+	}
+	if !hasReachabilityData {
+		ic.addInconsistency(blockHash, "reachability data is missing")
+		return nil
+	}
+
+	blockRelations, err := ic.blockRelationStores[0].BlockRelation(ic.databaseContext, ic.stagingArea, blockHash)
+	if err != nil {
+		if !database.IsNotFoundError(err) {
+			return err
+		}
+		// Reported by verifyBlockRelations
+		return nil
+	}
+	for _, parent := range blockRelations.Parents {
+		hasParentReachabilityData, err := ic.reachabilityDataStore.HasReachabilityData(ic.databaseContext, ic.stagingArea, parent)
+		if err != nil {
+			return err
+		}
+		if !hasParentReachabilityData {
+			ic.addInconsistency(blockHash, "reachability data of parent %s is missing", parent)
+			continue
+		}
+
+		isParentAncestor, err := ic.reachabilityManager.IsDAGAncestorOf(ic.stagingArea, parent, blockHash)
+		if err != nil {
+			return err
+		}
+		if !isParentAncestor {
+			ic.addInconsistency(blockHash, "reachability doesn't consider parent %s an ancestor of the block", parent)
+		}
+	}
+	return nil
+}
+
+func (ic *integrityChecker) verifyUTXOData(blockHash *externalapi.DomainHash, header externalapi.BlockHeader) error {
+	_, err := ic.utxoDiffStore.UTXODiff(ic.databaseContext, ic.stagingArea, blockHash)
+	if err != nil {
+		if !database.IsNotFoundError(err) {
+			return err
+		}
+		ic.addInconsistency(blockHash, "block is UTXO valid but its UTXO diff is missing")
+	}
+
+	hasUTXODiffChild, err := ic.utxoDiffStore.HasUTXODiffChild(ic.databaseContext, ic.stagingArea, blockHash)
+	if err != nil {
+		return err
+	}
+	if hasUTXODiffChild {
+		utxoDiffChild, err := ic.utxoDiffStore.UTXODiffChild(ic.databaseContext, ic.stagingArea, blockHash)
+		if err != nil {
+			return err
+		}
+		utxoDiffChildStatus, err := ic.blockStatusStore.Get(ic.databaseContext, ic.stagingArea, utxoDiffChild)
+		if err != nil {
+			if !database.IsNotFoundError(err) {
+				return err
+			}
+			ic.addInconsistency(blockHash, "UTXO diff child %s is missing", utxoDiffChild)
+		} else if utxoDiffChildStatus != externalapi.StatusUTXOValid {
+			ic.addInconsistency(blockHash, "UTXO diff child %s has status %s", utxoDiffChild, utxoDiffChildStatus)
+		}
+	}
+
+	blockMultiset, err := ic.multisetStore.Get(ic.databaseContext, ic.stagingArea, blockHash)
+	if err != nil {
+		if !database.IsNotFoundError(err) {
+			return err
+		}
+		ic.addInconsistency(blockHash, "block is UTXO valid but its multiset is missing")
+		return nil
+	}
+	if !blockMultiset.Hash().Equal(header.UTXOCommitment()) {
+		ic.addInconsistency(blockHash, "multiset hash %s doesn't match the header UTXO commitment %s",
+			blockMultiset.Hash(), header.UTXOCommitment())
+	}
+	return nil
+}
+
+// verifyVirtualUTXOSet recomputes the multiset of the virtual UTXO set and compares
+// it to the virtual multiset, which is the UTXO commitment of the next block
+func (ic *integrityChecker) verifyVirtualUTXOSet() error {
+	virtualMultiset, err := ic.multisetStore.Get(ic.databaseContext, ic.stagingArea, model.VirtualBlockHash)
+	if err != nil {
+		if !database.IsNotFoundError(err) {
+			return err
+		}
+		ic.addInconsistency(nil, "the virtual multiset is missing")
+		return nil
+	}
+
+	utxoSetIterator, err := ic.consensusStateStore.VirtualUTXOSetIterator(ic.databaseContext, ic.stagingArea)
+	if err != nil {
+		return err
+	}
+	defer utxoSetIterator.Close()
+
+	utxoSetHash, err := utxoSetMultisetHash(utxoSetIterator)
+	if err != nil {
+		return err
+	}
+	if !utxoSetHash.Equal(virtualMultiset.Hash()) {
+		ic.addInconsistency(nil, "the virtual UTXO set hashes to %s, but the virtual UTXO commitment is %s",
+			utxoSetHash, virtualMultiset.Hash())
+	}
+	return nil
+}
+
+// verifyPruningPointUTXOSet recomputes the multiset of the pruning point UTXO
+// set and compares it to the UTXO commitment of the pruning point header
+func (ic *integrityChecker) verifyPruningPointUTXOSet() error {
+	pruningPoint, err := ic.pruningStore.PruningPoint(ic.databaseContext, ic.stagingArea)
+	if err != nil {
+		return err
+	}
+	if pruningPoint.Equal(ic.genesisHash) {
+		return nil
+	}
+	pruningPointHeader, err := ic.blockHeaderStore.BlockHeader(ic.databaseContext, ic.stagingArea, pruningPoint)
+	if err != nil {
+		if !database.IsNotFoundError(err) {
+			return err
+		}
+		ic.addInconsistency(pruningPoint, "the pruning point header is missing")
+		return nil
+	}
+
+	utxoSetIterator, err := ic.pruningStore.PruningPointUTXOIterator(ic.databaseContext)
+	if err != nil {
+		return err
+	}
+	defer utxoSetIterator.Close()
+
+	utxoSetHash, err := utxoSetMultisetHash(utxoSetIterator)
+	if err != nil {
+		return err
+	}
+	if !utxoSetHash.Equal(pruningPointHeader.UTXOCommitment()) {
+		ic.addInconsistency(pruningPoint, "the pruning point UTXO set hashes to %s, but the header UTXO commitment is %s",
+			utxoSetHash, pruningPointHeader.UTXOCommitment())
+	}
+	return nil
+}
+
+func utxoSetMultisetHash(utxoSetIterator externalapi.ReadOnlyUTXOSetIterator) (*externalapi.DomainHash, error) {
+	utxoSetMultiset := multiset.New()
+	for ok := utxoSetIterator.First(); ok; ok = utxoSetIterator.Next() {
+		outpoint, entry, err := utxoSetIterator.Get()
+		if err != nil {
+			return nil, err
+		}
+		serializedUTXO, err := utxo.SerializeUTXO(entry, outpoint)
+		if err != nil {
+			return nil, err
+		}
+		utxoSetMultiset.Add(serializedUTXO)
+	}
+	return utxoSetMultiset.Hash(), nil
+}
+
@@ -0,0 +1,83 @@
+package consensus_test
+
+import (
+	"testing"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/testutils"
+	"github.com/karlsend/PYVERT/testfork/karlsend/util/staging"
+)
+
+func TestVerifyIntegrity(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestVerifyIntegrity")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		const chainLength = 10
+		chain := make([]*externalapi.DomainHash, 0, chainLength)
+		previousBlockHash := consensusConfig.GenesisHash
+		for i := 0; i < chainLength; i++ {
+			previousBlockHash, _, err = tc.AddBlock([]*externalapi.DomainHash{previousBlockHash}, nil, nil)
+			if err != nil {
+				t.Fatalf("AddBlock: %+v", err)
+			}
+			chain = append(chain, previousBlockHash)
+		}
+		_, _, err = tc.AddBlock([]*externalapi.DomainHash{chain[2]}, nil, nil)
+		if err != nil {
+			t.Fatalf("AddBlock: %+v", err)
+		}
+
+		report, err := tc.VerifyIntegrity()
+		if err != nil {
+			t.Fatalf("VerifyIntegrity: %+v", err)
+		}
+		if len(report.Inconsistencies) != 0 {
+			t.Fatalf("Expected no inconsistencies, but got %d. First: %s",
+				len(report.Inconsistencies), report.Inconsistencies[0].Description)
+		}
+		const expectedCheckedBlocks = chainLength + 2 // The chain, the side block and the genesis
+		if report.CheckedBlocks != expectedCheckedBlocks {
+			t.Fatalf("Expected %d checked blocks, but got %d", expectedCheckedBlocks, report.CheckedBlocks)
+		}
+
+		// Detach chain[6] from its parent, and delete the multiset of the lower chain[3]
+		stagingArea := model.NewStagingArea()
+		parentRelations, err := tc.BlockRelationStore().BlockRelation(tc.DatabaseContext(), stagingArea, chain[5])
+		if err != nil {
+			t.Fatalf("BlockRelation: %+v", err)
+		}
+		tc.BlockRelationStore().StageBlockRelation(stagingArea, chain[5], &model.BlockRelations{
+			Parents:  parentRelations.Parents,
+			Children: nil,
+		})
+		tc.MultisetStore().Delete(stagingArea, chain[3])
+		err = staging.CommitAllChanges(tc.DatabaseContext(), stagingArea)
+		if err != nil {
+			t.Fatalf("CommitAllChanges: %+v", err)
+		}
+
+		report, err = tc.VerifyIntegrity()
+		if err != nil {
+			t.Fatalf("VerifyIntegrity: %+v", err)
+		}
+		if len(report.Inconsistencies) != 2 {
+			t.Fatalf("Expected 2 inconsistencies, but got %d", len(report.Inconsistencies))
+		}
+		if !report.FirstInconsistentBlock().Equal(chain[3]) {
+			t.Fatalf("Expected the first inconsistent block to be %s, but got %s",
+				chain[3], report.FirstInconsistentBlock())
+		}
+		if !report.Inconsistencies[1].BlockHash.Equal(chain[6]) {
+			t.Fatalf("Expected the second inconsistent block to be %s, but got %s",
+				chain[6], report.Inconsistencies[1].BlockHash)
+		}
+	})
+}
+
@@ -69,7 +69,7 @@ type BaseBlockHeader interface {
 	BlueScore() uint64
 	BlueWork() *big.Int
 	PruningPoint() *DomainHash
-	BlockLevel(maxBlockLevel int) int
+	BlockLevel(maxBlockLevel int, fishHashActivationDAAScore uint64) int
 	Equal(other BaseBlockHeader) bool
 }
 
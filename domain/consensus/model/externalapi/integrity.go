@@ -0,0 +1,26 @@
+package externalapi
+
+// IntegrityReport is the result of a consensus database integrity check
+type IntegrityReport struct {
+	CheckedBlocks   uint64
+	Inconsistencies []*IntegrityInconsistency
+}
+
+// IntegrityInconsistency describes a single violation of the consensus invariants.
+// BlockHash is nil for inconsistencies that are not related to a specific block
+type IntegrityInconsistency struct {
+	BlockHash   *DomainHash
+	Description string
+}
+
+// FirstInconsistentBlock returns the hash of the first block that was found to be
+// inconsistent, or nil if no block was
+func (report *IntegrityReport) FirstInconsistentBlock() *DomainHash {
+	for _, inconsistency := range report.Inconsistencies {
+		if inconsistency.BlockHash != nil {
+			return inconsistency.BlockHash
+		}
+	}
+	return nil
+}
+
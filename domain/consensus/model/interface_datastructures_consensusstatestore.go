@@ -16,6 +16,9 @@ type ConsensusStateStore interface {
 	StageTips(stagingArea *StagingArea, tipHashes []*externalapi.DomainHash)
 	Tips(stagingArea *StagingArea, dbContext DBReader) ([]*externalapi.DomainHash, error)
 
+	StageInvalidatedBlocks(stagingArea *StagingArea, blockHashes []*externalapi.DomainHash)
+	InvalidatedBlocks(stagingArea *StagingArea, dbContext DBReader) ([]*externalapi.DomainHash, error)
+
 	StartImportingPruningPointUTXOSet(dbContext DBWriter) error
 	HadStartedImportingPruningPointUTXOSet(dbContext DBWriter) (bool, error)
 	ImportPruningPointUTXOSetIntoVirtualUTXOSet(dbContext DBWriter, pruningPointUTXOSetIterator externalapi.ReadOnlyUTXOSetIterator) error
@@ -17,6 +17,11 @@ import (
 	"github.com/karlsend/PYVERT/testfork/karlsend/util/mstime"
 )
 
+// tempBlockHash is the hash under which the data of a block that is built over given
+// parents is staged. The staged data is discarded along with its staging area
+var tempBlockHash = externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1})
+
 type blockBuilder struct {
 	databaseContext model.DBManager
 	genesisHash     *externalapi.DomainHash
@@ -30,6 +35,7 @@ type blockBuilder struct {
 	finalityManager       model.FinalityManager
 	pruningManager        model.PruningManager
 	blockParentBuilder    model.BlockParentBuilder
+	reachabilityManager   model.ReachabilityManager
 
 	acceptanceDataStore model.AcceptanceDataStore
 	blockRelationStore  model.BlockRelationStore
@@ -52,6 +58,7 @@ func New(
 	finalityManager model.FinalityManager,
 	blockParentBuilder model.BlockParentBuilder,
 	pruningManager model.PruningManager,
+	reachabilityManager model.ReachabilityManager,
 
 	acceptanceDataStore model.AcceptanceDataStore,
 	blockRelationStore model.BlockRelationStore,
@@ -73,6 +80,7 @@ func New(
 		finalityManager:       finalityManager,
 		blockParentBuilder:    blockParentBuilder,
 		pruningManager:        pruningManager,
+		reachabilityManager:   reachabilityManager,
 
 		acceptanceDataStore: acceptanceDataStore,
 		blockRelationStore:  blockRelationStore,
@@ -124,6 +132,146 @@ func (bb *blockBuilder) buildBlock(stagingArea *model.StagingArea, coinbaseData
 	}, coinbaseHasRedReward, nil
 }
 
+// BuildBlockOverParents builds a block over the given parents rather than over
+// the current state, with the given coinbaseData and the given transactions
+func (bb *blockBuilder) BuildBlockOverParents(parentHashes []*externalapi.DomainHash,
+	coinbaseData *externalapi.DomainCoinbaseData, transactions []*externalapi.DomainTransaction) (
+	*externalapi.DomainBlock, error) {
+
+	onEnd := logger.LogAndMeasureExecutionTime(log, "BuildBlockOverParents")
+	defer onEnd()
+
+	stagingArea := model.NewStagingArea()
+
+	block, _, err := bb.buildBlockOverParents(stagingArea, parentHashes, coinbaseData, transactions)
+	return block, err
+}
+
+// buildBlockOverParents builds a block over the given parents, and returns it together
+// with its past UTXO-diff from the virtual. The block is staged under tempBlockHash.
+func (bb *blockBuilder) buildBlockOverParents(stagingArea *model.StagingArea, parentHashes []*externalapi.DomainHash,
+	coinbaseData *externalapi.DomainCoinbaseData, transactions []*externalapi.DomainTransaction) (
+	*externalapi.DomainBlock, externalapi.UTXODiff, error) {
+
+	bits, daaScore, ghostdagData, err := bb.stageTempBlock(stagingArea, parentHashes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The past UTXO of the new block is calculated from the one of its selected parent,
+	// so the status of the selected parent has to be resolved first
+	selectedParentStatus, err := bb.consensusStateManager.ResolveBlockStatus(
+		stagingArea, ghostdagData.SelectedParent(), false)
+	if err != nil {
+		return nil, nil, err
+	}
+	if selectedParentStatus == externalapi.StatusDisqualifiedFromChain {
+		return nil, nil, errors.Errorf("Error building block with selectedParent %s with status DisqualifiedFromChain",
+			ghostdagData.SelectedParent())
+	}
+
+	pastUTXO, acceptanceData, multiset, err :=
+		bb.consensusStateManager.CalculatePastUTXOAndAcceptanceData(stagingArea, tempBlockHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	bb.acceptanceDataStore.Stage(stagingArea, tempBlockHash, acceptanceData)
+
+	coinbase, _, err := bb.coinbaseManager.ExpectedCoinbaseTransaction(stagingArea, tempBlockHash, coinbaseData)
+	if err != nil {
+		return nil, nil, err
+	}
+	transactionsWithCoinbase := append([]*externalapi.DomainTransaction{coinbase}, transactions...)
+
+	err = bb.reachabilityManager.AddBlock(stagingArea, tempBlockHash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parents, err := bb.newTempBlockParents(stagingArea, daaScore, parentHashes)
+	if err != nil {
+		return nil, nil, err
+	}
+	timeInMilliseconds, err := bb.newBlockTime(stagingArea, tempBlockHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	hashMerkleRoot := bb.newBlockHashMerkleRoot(transactionsWithCoinbase)
+	acceptedIDMerkleRoot, err := bb.calculateAcceptedIDMerkleRoot(acceptanceData)
+	if err != nil {
+		return nil, nil, err
+	}
+	pruningPoint, err := bb.newBlockPruningPoint(stagingArea, tempBlockHash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := blockheader.NewImmutableBlockHeader(
+		constants.BlockVersion,
+		parents,
+		hashMerkleRoot,
+		acceptedIDMerkleRoot,
+		multiset.Hash(),
+		timeInMilliseconds,
+		bits,
+		0,
+		daaScore,
+		ghostdagData.BlueScore(),
+		ghostdagData.BlueWork(),
+		pruningPoint,
+	)
+
+	return &externalapi.DomainBlock{
+		Header:       header,
+		Transactions: transactionsWithCoinbase,
+	}, pastUTXO, nil
+}
+
+// stageTempBlock stages the relations, GHOSTDAG data and DAA data of a block with the
+// given parents under tempBlockHash, and returns its difficulty bits, DAA score and GHOSTDAG data
+func (bb *blockBuilder) stageTempBlock(stagingArea *model.StagingArea, parentHashes []*externalapi.DomainHash) (
+	uint32, uint64, *externalapi.BlockGHOSTDAGData, error) {
+
+	bb.blockRelationStore.StageBlockRelation(stagingArea, tempBlockHash, &model.BlockRelations{Parents: parentHashes})
+
+	err := bb.ghostdagManager.GHOSTDAG(stagingArea, tempBlockHash)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	bits, err := bb.difficultyManager.StageDAADataAndReturnRequiredDifficulty(stagingArea, tempBlockHash, false)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	daaScore, err := bb.daaBlocksStore.DAAScore(bb.databaseContext, stagingArea, tempBlockHash)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	ghostdagData, err := bb.ghostdagDataStore.Get(bb.databaseContext, stagingArea, tempBlockHash, false)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	return bits, daaScore, ghostdagData, nil
+}
+
+// newTempBlockParents returns the parents of the block staged under tempBlockHash,
+// with the parents of every level sorted, so that they don't depend on the order of parentHashes
+func (bb *blockBuilder) newTempBlockParents(stagingArea *model.StagingArea, daaScore uint64,
+	parentHashes []*externalapi.DomainHash) ([]externalapi.BlockLevelParents, error) {
+
+	parents, err := bb.blockParentBuilder.BuildParents(stagingArea, daaScore, parentHashes)
+	if err != nil {
+		return nil, err
+	}
+	for _, blockLevelParents := range parents {
+		sort.Slice(blockLevelParents, func(i, j int) bool {
+			return blockLevelParents[i].Less(blockLevelParents[j])
+		})
+	}
+	return parents, nil
+}
+
 func (bb *blockBuilder) validateTransactions(stagingArea *model.StagingArea,
 	transactions []*externalapi.DomainTransaction) error {
 
@@ -199,7 +347,7 @@ func (bb *blockBuilder) buildHeader(stagingArea *model.StagingArea, transactions
 		return nil, err
 	}
 
-	timeInMilliseconds, err := bb.newBlockTime(stagingArea)
+	timeInMilliseconds, err := bb.newBlockTime(stagingArea, model.VirtualBlockHash)
 	if err != nil {
 		return nil, err
 	}
@@ -249,7 +397,7 @@ func (bb *blockBuilder) newBlockParents(stagingArea *model.StagingArea, daaScore
 	return bb.blockParentBuilder.BuildParents(stagingArea, daaScore, virtualBlockRelations.Parents)
 }
 
-func (bb *blockBuilder) newBlockTime(stagingArea *model.StagingArea) (int64, error) {
+func (bb *blockBuilder) newBlockTime(stagingArea *model.StagingArea, blockHash *externalapi.DomainHash) (int64, error) {
 	// The timestamp for the block must not be before the median timestamp
 	// of the last several blocks. Thus, choose the maximum between the
 	// current time and one second after the past median time. The current
@@ -257,7 +405,7 @@ func (bb *blockBuilder) newBlockTime(stagingArea *model.StagingArea) (int64, err
 	// block timestamp does not supported a precision greater than one
 	// millisecond.
 	newTimestamp := mstime.Now().UnixMilliseconds()
-	minTimestamp, err := bb.minBlockTime(stagingArea, model.VirtualBlockHash)
+	minTimestamp, err := bb.minBlockTime(stagingArea, blockHash)
 	if err != nil {
 		return 0, err
 	}
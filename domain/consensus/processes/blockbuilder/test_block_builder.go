@@ -3,7 +3,6 @@ package blockbuilder
 import (
 	"encoding/binary"
 	"math/big"
-	"sort"
 
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
@@ -22,9 +21,6 @@ type testBlockBuilder struct {
 	nonceCounter  uint64
 }
 
-var tempBlockHash = externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{
-	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1})
-
 // NewTestBlockBuilder creates an instance of a TestBlockBuilder
 func NewTestBlockBuilder(baseBlockBuilder model.BlockBuilder, testConsensus testapi.TestConsensus) testapi.TestBlockBuilder {
 	return &testBlockBuilder{
@@ -84,17 +80,11 @@ func (bb *testBlockBuilder) buildUTXOInvalidHeader(stagingArea *model.StagingAre
 		return nil, err
 	}
 
-	parents, err := bb.blockParentBuilder.BuildParents(stagingArea, daaScore, parentHashes)
+	parents, err := bb.newTempBlockParents(stagingArea, daaScore, parentHashes)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, blockLevelParents := range parents {
-		sort.Slice(blockLevelParents, func(i, j int) bool {
-			return blockLevelParents[i].Less(blockLevelParents[j])
-		})
-	}
-
 	bb.nonceCounter++
 	return blockheader.NewImmutableBlockHeader(
 		constants.BlockVersion,
@@ -112,38 +102,6 @@ func (bb *testBlockBuilder) buildUTXOInvalidHeader(stagingArea *model.StagingAre
 	), nil
 }
 
-func (bb *testBlockBuilder) buildHeaderWithParents(stagingArea *model.StagingArea,
-	parentHashes []*externalapi.DomainHash, bits uint32, transactions []*externalapi.DomainTransaction,
-	acceptanceData externalapi.AcceptanceData, multiset model.Multiset, daaScore, blueScore uint64, blueWork *big.Int) (externalapi.BlockHeader, error) {
-
-	header, err := bb.buildUTXOInvalidHeader(stagingArea, parentHashes, bits, daaScore, blueScore, blueWork, transactions)
-	if err != nil {
-		return nil, err
-	}
-
-	hashMerkleRoot := bb.newBlockHashMerkleRoot(transactions)
-	acceptedIDMerkleRoot, err := bb.calculateAcceptedIDMerkleRoot(acceptanceData)
-	if err != nil {
-		return nil, err
-	}
-	utxoCommitment := multiset.Hash()
-
-	return blockheader.NewImmutableBlockHeader(
-		header.Version(),
-		header.Parents(),
-		hashMerkleRoot,
-		acceptedIDMerkleRoot,
-		utxoCommitment,
-		header.TimeInMilliseconds(),
-		header.Bits(),
-		header.Nonce(),
-		header.DAAScore(),
-		header.BlueScore(),
-		header.BlueWork(),
-		header.PruningPoint(),
-	), nil
-}
-
 func (bb *testBlockBuilder) buildBlockWithParents(stagingArea *model.StagingArea, parentHashes []*externalapi.DomainHash,
 	coinbaseData *externalapi.DomainCoinbaseData, transactions []*externalapi.DomainTransaction) (
 	*externalapi.DomainBlock, externalapi.UTXODiff, error) {
@@ -160,68 +118,24 @@ func (bb *testBlockBuilder) buildBlockWithParents(stagingArea *model.StagingArea
 		}
 	}
 
-	bb.blockRelationStore.StageBlockRelation(stagingArea, tempBlockHash, &model.BlockRelations{Parents: parentHashes})
-
-	err := bb.ghostdagManager.GHOSTDAG(stagingArea, tempBlockHash)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	bits, err := bb.difficultyManager.StageDAADataAndReturnRequiredDifficulty(stagingArea, tempBlockHash, false)
-	if err != nil {
-		return nil, nil, err
-	}
-	daaScore, err := bb.daaBlocksStore.DAAScore(bb.databaseContext, stagingArea, tempBlockHash)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	ghostdagData, err := bb.ghostdagDataStore.Get(bb.databaseContext, stagingArea, tempBlockHash, false)
-	if err != nil {
-		return nil, nil, err
-	}
-	blueWork := ghostdagData.BlueWork()
-	blueScore := ghostdagData.BlueScore()
-
-	selectedParentStatus, err := bb.testConsensus.ConsensusStateManager().ResolveBlockStatus(
-		stagingArea, ghostdagData.SelectedParent(), false)
-	if err != nil {
-		return nil, nil, err
-	}
-	if selectedParentStatus == externalapi.StatusDisqualifiedFromChain {
-		return nil, nil, errors.Errorf("Error building block with selectedParent %s with status DisqualifiedFromChain",
-			ghostdagData.SelectedParent())
-	}
-
-	pastUTXO, acceptanceData, multiset, err :=
-		bb.consensusStateManager.CalculatePastUTXOAndAcceptanceData(stagingArea, tempBlockHash)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	bb.acceptanceDataStore.Stage(stagingArea, tempBlockHash, acceptanceData)
-
-	coinbase, _, err := bb.coinbaseManager.ExpectedCoinbaseTransaction(stagingArea, tempBlockHash, coinbaseData)
-	if err != nil {
-		return nil, nil, err
-	}
-	transactionsWithCoinbase := append([]*externalapi.DomainTransaction{coinbase}, transactions...)
-
-	err = bb.testConsensus.ReachabilityManager().AddBlock(stagingArea, tempBlockHash)
+	block, pastUTXO, err := bb.buildBlockOverParents(stagingArea, parentHashes, coinbaseData, transactions)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	header, err := bb.buildHeaderWithParents(
-		stagingArea, parentHashes, bits, transactionsWithCoinbase, acceptanceData, multiset, daaScore, blueScore, blueWork)
+	// Test blocks get the earliest valid timestamp rather than the current time, so that
+	// they're deterministic, and a unique nonce, so that blocks with the same parents differ
+	timeInMilliseconds, err := bb.minBlockTime(stagingArea, tempBlockHash)
 	if err != nil {
 		return nil, nil, err
 	}
+	header := block.Header.ToMutable()
+	header.SetTimeInMilliseconds(timeInMilliseconds)
+	bb.nonceCounter++
+	header.SetNonce(bb.nonceCounter)
+	block.Header = header.ToImmutable()
 
-	return &externalapi.DomainBlock{
-		Header:       header,
-		Transactions: transactionsWithCoinbase,
-	}, pastUTXO, nil
+	return block, pastUTXO, nil
 }
 
 func (bb *testBlockBuilder) BuildUTXOInvalidHeader(parentHashes []*externalapi.DomainHash) (externalapi.BlockHeader,
@@ -240,23 +154,7 @@ func (bb *testBlockBuilder) BuildUTXOInvalidBlock(parentHashes []*externalapi.Do
 
 	stagingArea := model.NewStagingArea()
 
-	bb.blockRelationStore.StageBlockRelation(stagingArea, tempBlockHash, &model.BlockRelations{Parents: parentHashes})
-
-	err := bb.ghostdagManager.GHOSTDAG(stagingArea, tempBlockHash)
-	if err != nil {
-		return nil, err
-	}
-
-	bits, err := bb.difficultyManager.StageDAADataAndReturnRequiredDifficulty(stagingArea, tempBlockHash, false)
-	if err != nil {
-		return nil, err
-	}
-	daaScore, err := bb.daaBlocksStore.DAAScore(bb.databaseContext, stagingArea, tempBlockHash)
-	if err != nil {
-		return nil, err
-	}
-
-	ghostdagData, err := bb.ghostdagDataStore.Get(bb.databaseContext, stagingArea, tempBlockHash, false)
+	bits, daaScore, ghostdagData, err := bb.stageTempBlock(stagingArea, parentHashes)
 	if err != nil {
 		return nil, err
 	}
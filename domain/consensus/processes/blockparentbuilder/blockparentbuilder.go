@@ -16,8 +16,9 @@ type blockParentBuilder struct {
 	reachabilityDataStore model.ReachabilityDataStore
 	pruningStore          model.PruningStore
 
-	genesisHash   *externalapi.DomainHash
-	maxBlockLevel int
+	genesisHash                *externalapi.DomainHash
+	maxBlockLevel              int
+	fishHashActivationDAAScore uint64
 }
 
 // New creates a new instance of a BlockParentBuilder
@@ -32,6 +33,7 @@ func New(
 
 	genesisHash *externalapi.DomainHash,
 	maxBlockLevel int,
+	fishHashActivationDAAScore uint64,
 ) model.BlockParentBuilder {
 	return &blockParentBuilder{
 		databaseContext:    databaseContext,
@@ -43,6 +45,8 @@ func New(
 		pruningStore:          pruningStore,
 		genesisHash:           genesisHash,
 		maxBlockLevel:         maxBlockLevel,
+
+		fishHashActivationDAAScore: fishHashActivationDAAScore,
 	}
 }
 
@@ -105,7 +109,7 @@ func (bpb *blockParentBuilder) BuildParents(stagingArea *model.StagingArea,
 	// all the block levels they occupy
 	for _, directParentHeader := range directParentHeaders {
 		directParentHash := consensushashing.HeaderHash(directParentHeader)
-		blockLevel := directParentHeader.BlockLevel(bpb.maxBlockLevel)
+		blockLevel := directParentHeader.BlockLevel(bpb.maxBlockLevel, bpb.fishHashActivationDAAScore)
 		for i := 0; i <= blockLevel; i++ {
 			if _, exists := candidatesByLevelToReferenceBlocksMap[i]; !exists {
 				candidatesByLevelToReferenceBlocksMap[i] = make(map[externalapi.DomainHash][]*externalapi.DomainHash)
@@ -8,6 +8,7 @@ import (
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/merkle"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/subnetworks"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/transactionhelper"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/workerpool"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/logger"
 	"github.com/pkg/errors"
 )
@@ -139,15 +140,17 @@ func (v *blockValidator) checkBlockTransactionOrder(block *externalapi.DomainBlo
 }
 
 func (v *blockValidator) checkTransactionsInIsolation(block *externalapi.DomainBlock) error {
-	for _, tx := range block.Transactions {
-		err := v.transactionValidator.ValidateTransactionInIsolation(tx, block.Header.DAAScore())
-		if err != nil {
-			return errors.Wrapf(err, "transaction %s failed isolation "+
-				"check", consensushashing.TransactionID(tx))
+	return workerpool.Run(len(block.Transactions), func() workerpool.WorkFunc {
+		return func(transactionIndex int) error {
+			tx := block.Transactions[transactionIndex]
+			err := v.transactionValidator.ValidateTransactionInIsolation(tx, block.Header.DAAScore())
+			if err != nil {
+				return errors.Wrapf(err, "transaction %s failed isolation "+
+					"check", consensushashing.TransactionID(tx))
+			}
+			return nil
 		}
-	}
-
-	return nil
+	})
 }
 
 func (v *blockValidator) checkBlockHashMerkleRoot(block *externalapi.DomainBlock) error {
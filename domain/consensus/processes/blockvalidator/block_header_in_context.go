@@ -86,6 +86,13 @@ func (v *blockValidator) ValidateHeaderInContext(stagingArea *model.StagingArea,
 		return err
 	}
 
+	if !isBlockWithTrustedData {
+		err = v.checkCheckpoints(stagingArea, blockHash, header)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = v.checkBlueWork(stagingArea, blockHash, header)
 	if err != nil {
 		return err
@@ -8,6 +8,7 @@ import (
 
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/dagconfig"
 	"github.com/karlsend/PYVERT/testfork/karlsend/util/difficulty"
 )
 
@@ -25,6 +26,9 @@ type blockValidator struct {
 	timestampDeviationTolerance int
 	targetTimePerBlock          time.Duration
 	maxBlockLevel               int
+	finalityDepth               uint64
+	checkpoints                 []dagconfig.Checkpoint
+	fishHashActivationDAAScore  uint64
 
 	databaseContext       model.DBReader
 	difficultyManager     model.DifficultyManager
@@ -64,6 +68,9 @@ func New(powMax *big.Int,
 	timestampDeviationTolerance int,
 	targetTimePerBlock time.Duration,
 	maxBlockLevel int,
+	finalityDepth uint64,
+	checkpoints []dagconfig.Checkpoint,
+	fishHashActivationDAAScore uint64,
 
 	databaseContext model.DBReader,
 
@@ -103,6 +110,9 @@ func New(powMax *big.Int,
 		mergeSetSizeLimit:          mergeSetSizeLimit,
 		maxBlockParents:            maxBlockParents,
 		maxBlockLevel:              maxBlockLevel,
+		finalityDepth:              finalityDepth,
+		checkpoints:                checkpoints,
+		fishHashActivationDAAScore: fishHashActivationDAAScore,
 
 		timestampDeviationTolerance: timestampDeviationTolerance,
 		targetTimePerBlock:          targetTimePerBlock,
@@ -0,0 +1,72 @@
+package blockvalidator
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/ruleerrors"
+	"github.com/pkg/errors"
+)
+
+// checkCheckpoints validates that the block doesn't conflict with any of the checkpoints.
+// Blocks in the anticone of a checkpoint might have a slightly higher DAA score than the
+// checkpoint itself, so the checkpoint is required to be in the selected parent chain only
+// of blocks that are at least a finality depth above it.
+func (v *blockValidator) checkCheckpoints(stagingArea *model.StagingArea, blockHash *externalapi.DomainHash,
+	header externalapi.BlockHeader) error {
+
+	for _, checkpoint := range v.checkpoints {
+		if blockHash.Equal(checkpoint.Hash) {
+			if header.DAAScore() != checkpoint.DAAScore {
+				return errors.Wrapf(ruleerrors.ErrCheckpointMismatch, "checkpoint %s has a DAA score of %d "+
+					"instead of %d", blockHash, header.DAAScore(), checkpoint.DAAScore)
+			}
+			continue
+		}
+		if header.DAAScore() < checkpoint.DAAScore+v.finalityDepth {
+			continue
+		}
+
+		hasCheckpointReachabilityData, err := v.reachabilityStore.HasReachabilityData(
+			v.databaseContext, stagingArea, checkpoint.Hash)
+		if err != nil {
+			return err
+		}
+		if !hasCheckpointReachabilityData {
+			// A checkpoint below the pruning point might have already been pruned, in which
+			// case it was validated against the pruning point proof
+			isBelowPruningPoint, err := v.isBelowPruningPoint(stagingArea, checkpoint.DAAScore)
+			if err != nil {
+				return err
+			}
+			if isBelowPruningPoint {
+				continue
+			}
+			return errors.Wrapf(ruleerrors.ErrCheckpointMismatch, "block %s doesn't have the checkpoint %s "+
+				"in its past", blockHash, checkpoint.Hash)
+		}
+
+		isCheckpointInSelectedParentChain, err := v.dagTopologyManagers[0].IsInSelectedParentChainOf(
+			stagingArea, checkpoint.Hash, blockHash)
+		if err != nil {
+			return err
+		}
+		if !isCheckpointInSelectedParentChain {
+			return errors.Wrapf(ruleerrors.ErrCheckpointMismatch, "the checkpoint %s is not in the selected "+
+				"parent chain of block %s", checkpoint.Hash, blockHash)
+		}
+	}
+	return nil
+}
+
+func (v *blockValidator) isBelowPruningPoint(stagingArea *model.StagingArea, daaScore uint64) (bool, error) {
+	pruningPoint, err := v.pruningStore.PruningPoint(v.databaseContext, stagingArea)
+	if err != nil {
+		return false, err
+	}
+	pruningPointHeader, err := v.blockHeaderStore.BlockHeader(v.databaseContext, stagingArea, pruningPoint)
+	if err != nil {
+		return false, err
+	}
+	return daaScore < pruningPointHeader.DAAScore(), nil
+}
+
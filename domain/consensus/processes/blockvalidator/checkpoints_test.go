@@ -0,0 +1,93 @@
+package blockvalidator_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/ruleerrors"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/testutils"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/dagconfig"
+)
+
+func TestCheckpoints(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.FinalityDuration = 10 * consensusConfig.TargetTimePerBlock
+		finalityDepth := consensusConfig.FinalityDepth()
+
+		factory := consensus.NewFactory()
+		tcWithoutCheckpoints, teardownWithoutCheckpoints, err := factory.NewTestConsensus(consensusConfig,
+			"TestCheckpoints")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardownWithoutCheckpoints(false)
+
+		// Build two chains over the genesis, that are both long enough to
+		// pass the DAA score of the checkpoint by a finality depth
+		buildChain := func() []*externalapi.DomainBlock {
+			chain := make([]*externalapi.DomainBlock, 0, finalityDepth+2)
+			tipHash := consensusConfig.GenesisHash
+			for i := uint64(0); i < finalityDepth+2; i++ {
+				var err error
+				tipHash, _, err = tcWithoutCheckpoints.AddBlock([]*externalapi.DomainHash{tipHash}, nil, nil)
+				if err != nil {
+					t.Fatalf("AddBlock: %+v", err)
+				}
+				block, found, err := tcWithoutCheckpoints.GetBlock(tipHash)
+				if err != nil {
+					t.Fatalf("GetBlock: %+v", err)
+				}
+				if !found {
+					t.Fatalf("block %s not found", tipHash)
+				}
+				chain = append(chain, block)
+			}
+			return chain
+		}
+		checkpointChain := buildChain()
+		conflictingChain := buildChain()
+
+		checkpointBlock := checkpointChain[0]
+		consensusConfig.Checkpoints = []dagconfig.Checkpoint{{
+			DAAScore: checkpointBlock.Header.DAAScore(),
+			Hash:     consensushashing.BlockHash(checkpointBlock),
+		}}
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestCheckpoints")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		for _, block := range checkpointChain {
+			err := tc.ValidateAndInsertBlock(block, true)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertBlock: %+v", err)
+			}
+		}
+
+		// The blocks of the conflicting chain are valid until they're a finality
+		// depth above the checkpoint
+		isConflictRejected := false
+		for _, block := range conflictingChain {
+			err := tc.ValidateAndInsertBlock(block, true)
+			if block.Header.DAAScore() < checkpointBlock.Header.DAAScore()+finalityDepth {
+				if err != nil {
+					t.Fatalf("ValidateAndInsertBlock: %+v", err)
+				}
+				continue
+			}
+			if !errors.Is(err, ruleerrors.ErrCheckpointMismatch) {
+				t.Fatalf("Expected ErrCheckpointMismatch, but got: %+v", err)
+			}
+			isConflictRejected = true
+			break
+		}
+		if !isConflictRejected {
+			t.Fatalf("Expected the conflicting chain to be rejected")
+		}
+	})
+}
+
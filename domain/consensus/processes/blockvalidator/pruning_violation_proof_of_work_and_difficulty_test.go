@@ -38,7 +38,7 @@ func TestPOW(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		invalidBlockWrongPOW = solveBlockWithWrongPOW(invalidBlockWrongPOW)
+		invalidBlockWrongPOW = solveBlockWithWrongPOW(invalidBlockWrongPOW, consensusConfig.FishHashActivationDAAScore)
 		err = tc.ValidateAndInsertBlock(invalidBlockWrongPOW, true)
 		if !errors.Is(err, ruleerrors.ErrInvalidPoW) {
 			t.Fatalf("Expected block to be invalid with err: %v, instead found: %v", ruleerrors.ErrInvalidPoW, err)
@@ -103,7 +103,7 @@ func TestPOW(t *testing.T) {
 		random := rand.New(rand.NewSource(0))
 		// Difficulty is too high on mainnet to actually mine.
 		if consensusConfig.Name != "kaspa-mainnet" {
-			mining.SolveBlock(validBlock, random)
+			mining.SolveBlock(validBlock, random, consensusConfig.FishHashActivationDAAScore)
 			err = tc.ValidateAndInsertBlock(validBlock, true)
 			if err != nil {
 				t.Fatal(err)
@@ -112,10 +112,83 @@ func TestPOW(t *testing.T) {
 	})
 }
 
+// TestFishHashPOW tests that the POW of blocks above the FishHash activation DAA score
+// is validated with FishHash.
+func TestFishHashPOW(t *testing.T) {
+	testutils.ForAllNets(t, false, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.FishHashActivationDAAScore = consensusConfig.GenesisBlock.Header.DAAScore() + 1
+		consensusConfig.FishHashActivationBits = difficulty.BigToCompact(new(big.Int).Rsh(consensusConfig.PowMax, 1))
+
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestFishHashPOW")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		// The block above the genesis has the DAA score of the genesis, so it's still mined with kHeavyHash
+		random := rand.New(rand.NewSource(0))
+		kHeavyHashParent, _, err := tc.BuildBlockWithParents([]*externalapi.DomainHash{consensusConfig.GenesisHash}, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mining.SolveBlock(kHeavyHashParent, random, consensusConfig.FishHashActivationDAAScore)
+		err = tc.ValidateAndInsertBlock(kHeavyHashParent, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parentHash := consensushashing.BlockHash(kHeavyHashParent)
+
+		// A block that is only valid under kHeavyHash should be rejected
+		kHeavyHashBlock, _, err := tc.BuildBlockWithParents([]*externalapi.DomainHash{parentHash}, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if kHeavyHashBlock.Header.DAAScore() < consensusConfig.FishHashActivationDAAScore {
+			t.Fatalf("Expected the DAA score of the block to be at least %d, but got %d",
+				consensusConfig.FishHashActivationDAAScore, kHeavyHashBlock.Header.DAAScore())
+		}
+		kHeavyHashBlock = solveBlockWithKHeavyHashOnly(kHeavyHashBlock, consensusConfig.FishHashActivationDAAScore)
+		err = tc.ValidateAndInsertBlock(kHeavyHashBlock, true)
+		if !errors.Is(err, ruleerrors.ErrInvalidPoW) {
+			t.Fatalf("Expected block to be invalid with err: %v, instead found: %v", ruleerrors.ErrInvalidPoW, err)
+		}
+
+		validBlock, _, err := tc.BuildBlockWithParents([]*externalapi.DomainHash{parentHash}, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mining.SolveBlock(validBlock, random, consensusConfig.FishHashActivationDAAScore)
+		err = tc.ValidateAndInsertBlock(validBlock, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+// solveBlockWithKHeavyHashOnly increments the given block's nonce until its kHeavyHash POW is valid but its
+// FishHash POW isn't (for test!).
+func solveBlockWithKHeavyHashOnly(block *externalapi.DomainBlock, fishHashActivationDAAScore uint64) *externalapi.DomainBlock {
+	header := block.Header.ToMutable()
+	kHeavyHashState := pow.NewState(header, math.MaxUint64)
+	fishHashState := pow.NewState(header, fishHashActivationDAAScore)
+	for i := uint64(0); i < math.MaxUint64; i++ {
+		kHeavyHashState.Nonce = i
+		fishHashState.Nonce = i
+		if kHeavyHashState.CheckProofOfWork() && !fishHashState.CheckProofOfWork() {
+			header.SetNonce(i)
+			block.Header = header.ToImmutable()
+			return block
+		}
+	}
+
+	panic("Failed to solve block! cannot find a nonce that is only valid under kHeavyHash for the test")
+}
+
 // solveBlockWithWrongPOW increments the given block's nonce until it gets wrong POW (for test!).
-func solveBlockWithWrongPOW(block *externalapi.DomainBlock) *externalapi.DomainBlock {
+func solveBlockWithWrongPOW(block *externalapi.DomainBlock, fishHashActivationDAAScore uint64) *externalapi.DomainBlock {
 	header := block.Header.ToMutable()
-	state := pow.NewState(header)
+	state := pow.NewState(header, fishHashActivationDAAScore)
 	for i := uint64(0); i < math.MaxUint64; i++ {
 		state.Nonce = i
 		if !state.CheckProofOfWork() {
@@ -303,7 +376,7 @@ func TestValidateDifficulty(t *testing.T) {
 		factory.SetTestDifficultyManager(func(_ model.DBReader, _ model.GHOSTDAGManager, _ model.GHOSTDAGDataStore,
 			_ model.BlockHeaderStore, daaBlocksStore model.DAABlocksStore, _ model.DAGTopologyManager,
 			_ model.DAGTraversalManager, _ *big.Int, _ int, _ bool, _ time.Duration,
-			_ *externalapi.DomainHash, _ uint32) model.DifficultyManager {
+			_ *externalapi.DomainHash, _ uint32, _ uint64, _ uint32) model.DifficultyManager {
 
 			mocDifficulty.daaBlocksStore = daaBlocksStore
 			return mocDifficulty
@@ -6,6 +6,7 @@ import (
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/processes/coinbasemanager"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/testutils"
 )
 
@@ -57,3 +58,30 @@ func TestExtractCoinbaseDataBlueScoreAndSubsidy(t *testing.T) {
 	})
 }
 
+func TestCoinbasePayloadLength(t *testing.T) {
+	coinbaseData := &externalapi.DomainCoinbaseData{
+		ScriptPublicKey: &externalapi.ScriptPublicKey{
+			Script:  make([]byte, 34),
+			Version: 0,
+		},
+		ExtraData: []byte("1.1.0/pool"),
+	}
+
+	payload, err := coinbasemanager.ModifyCoinbasePayload(make([]byte, 16), coinbaseData, 150)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payload) != coinbasemanager.CoinbasePayloadLength(coinbaseData) {
+		t.Fatalf("expected a payload of %d bytes but got %d", coinbasemanager.CoinbasePayloadLength(coinbaseData), len(payload))
+	}
+
+	_, extractedCoinbaseData, _, err := coinbasemanager.ExtractCoinbaseDataBlueScoreAndSubsidy(
+		&externalapi.DomainTransaction{Payload: payload}, 150)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !extractedCoinbaseData.Equal(coinbaseData) {
+		t.Fatalf("the extracted coinbase data is different from the original coinbase data")
+	}
+}
+
@@ -24,7 +24,7 @@ func (c *coinbaseManager) serializeCoinbasePayload(blueScore uint64,
 			"longer than the max allowed length of %d", c.coinbasePayloadScriptPublicKeyMaxLength)
 	}
 
-	payload := make([]byte, uint64Len+lengthOfVersionScriptPubKey+lengthOfScriptPubKeyLength+scriptLengthOfScriptPubKey+len(coinbaseData.ExtraData)+lengthOfSubsidy)
+	payload := make([]byte, CoinbasePayloadLength(coinbaseData))
 	binary.LittleEndian.PutUint64(payload[:uint64Len], blueScore)
 	binary.LittleEndian.PutUint64(payload[uint64Len:], subsidy)
 
@@ -36,6 +36,12 @@ func (c *coinbaseManager) serializeCoinbasePayload(blueScore uint64,
 	return payload, nil
 }
 
+// CoinbasePayloadLength returns the length of the coinbase payload that is built from the given coinbase data.
+func CoinbasePayloadLength(coinbaseData *externalapi.DomainCoinbaseData) int {
+	return uint64Len + lengthOfSubsidy + lengthOfVersionScriptPubKey + lengthOfScriptPubKeyLength +
+		len(coinbaseData.ScriptPublicKey.Script) + len(coinbaseData.ExtraData)
+}
+
 // ModifyCoinbasePayload modifies the coinbase payload based on the provided scriptPubKey and extra data.
 func ModifyCoinbasePayload(payload []byte, coinbaseData *externalapi.DomainCoinbaseData, coinbasePayloadScriptPublicKeyMaxLength uint8) ([]byte, error) {
 
@@ -45,7 +51,7 @@ func ModifyCoinbasePayload(payload []byte, coinbaseData *externalapi.DomainCoinb
 			"longer than the max allowed length of %d", coinbasePayloadScriptPublicKeyMaxLength)
 	}
 
-	newPayloadLen := uint64Len + lengthOfVersionScriptPubKey + lengthOfScriptPubKeyLength + scriptLengthOfScriptPubKey + len(coinbaseData.ExtraData) + lengthOfSubsidy
+	newPayloadLen := CoinbasePayloadLength(coinbaseData)
 	if len(payload) != newPayloadLen {
 		newPayload := make([]byte, newPayloadLen)
 		copy(newPayload, payload[:uint64Len+lengthOfSubsidy])
@@ -64,6 +70,14 @@ func ModifyCoinbasePayload(payload []byte, coinbaseData *externalapi.DomainCoinb
 func (c *coinbaseManager) ExtractCoinbaseDataBlueScoreAndSubsidy(coinbaseTx *externalapi.DomainTransaction) (
 	blueScore uint64, coinbaseData *externalapi.DomainCoinbaseData, subsidy uint64, err error) {
 
+	return ExtractCoinbaseDataBlueScoreAndSubsidy(coinbaseTx, c.coinbasePayloadScriptPublicKeyMaxLength)
+}
+
+// ExtractCoinbaseDataBlueScoreAndSubsidy deserializes the coinbase payload to its component (scriptPubKey, extra data, and subsidy).
+// It's meant for users of consensus data that have no access to a coinbase manager.
+func ExtractCoinbaseDataBlueScoreAndSubsidy(coinbaseTx *externalapi.DomainTransaction, coinbasePayloadScriptPublicKeyMaxLength uint8) (
+	blueScore uint64, coinbaseData *externalapi.DomainCoinbaseData, subsidy uint64, err error) {
+
 	minLength := uint64Len + lengthOfSubsidy + lengthOfVersionScriptPubKey + lengthOfScriptPubKeyLength
 	if len(coinbaseTx.Payload) < minLength {
 		return 0, nil, 0, errors.Wrapf(ruleerrors.ErrBadCoinbasePayloadLen,
@@ -77,9 +91,9 @@ func (c *coinbaseManager) ExtractCoinbaseDataBlueScoreAndSubsidy(coinbaseTx *ext
 
 	scriptPubKeyScriptLength := coinbaseTx.Payload[uint64Len+lengthOfSubsidy+lengthOfVersionScriptPubKey]
 
-	if scriptPubKeyScriptLength > c.coinbasePayloadScriptPublicKeyMaxLength {
+	if scriptPubKeyScriptLength > coinbasePayloadScriptPublicKeyMaxLength {
 		return 0, nil, 0, errors.Wrapf(ruleerrors.ErrBadCoinbasePayloadLen, "coinbase's payload script public key is "+
-			"longer than the max allowed length of %d", c.coinbasePayloadScriptPublicKeyMaxLength)
+			"longer than the max allowed length of %d", coinbasePayloadScriptPublicKeyMaxLength)
 	}
 
 	if len(coinbaseTx.Payload) < minLength+int(scriptPubKeyScriptLength) {
@@ -16,8 +16,18 @@ func (csm *consensusStateManager) AddBlock(stagingArea *model.StagingArea, block
 	onEnd := logger.LogAndMeasureExecutionTime(log, "csm.AddBlock")
 	defer onEnd()
 
+	isInInvalidatedFuture, err := csm.isInInvalidatedFuture(stagingArea, blockHash)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if isInInvalidatedFuture {
+		log.Debugf("Block %s is in the future of a manually invalidated block, "+
+			"therefore it's disqualified from the chain", blockHash)
+		csm.blockStatusStore.Stage(stagingArea, blockHash, externalapi.StatusDisqualifiedFromChain)
+	}
+
 	var reversalData *model.UTXODiffReversalData
-	if updateVirtual {
+	if updateVirtual && !isInInvalidatedFuture {
 		log.Debugf("Resolving whether the block %s is the next virtual selected parent", blockHash)
 		isCandidateToBeNextVirtualSelectedParent, err := csm.isCandidateToBeNextVirtualSelectedParent(stagingArea, blockHash)
 		if err != nil {
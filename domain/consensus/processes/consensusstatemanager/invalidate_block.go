@@ -0,0 +1,310 @@
+package consensusstatemanager
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/hashset"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/db/database"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/logger"
+	"github.com/karlsend/PYVERT/testfork/karlsend/util/staging"
+	"github.com/pkg/errors"
+)
+
+// InvalidateBlock manually marks the given block and its entire future as disqualified
+// from the selected chain, and moves the virtual to the remaining valid blocks.
+// The override is persisted, and applies to blocks that are added to the future
+// of the given block later on, until ReconsiderBlock is called.
+func (csm *consensusStateManager) InvalidateBlock(blockHash *externalapi.DomainHash) (*externalapi.VirtualChangeSet, error) {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "csm.InvalidateBlock")
+	defer onEnd()
+
+	stagingArea := model.NewStagingArea()
+
+	if blockHash.Equal(csm.genesisHash) {
+		return nil, errors.Errorf("the genesis block cannot be invalidated")
+	}
+
+	status, err := csm.blockStatusStore.Get(csm.databaseContext, stagingArea, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if status == externalapi.StatusHeaderOnly || status == externalapi.StatusInvalid {
+		return nil, errors.Errorf("block %s has status %s and cannot be invalidated", blockHash, status)
+	}
+	err = csm.checkBlockIsAboveFinalityPoint(stagingArea, blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	invalidatedBlocks, err := csm.consensusStateStore.InvalidatedBlocks(stagingArea, csm.databaseContext)
+	if err != nil {
+		return nil, err
+	}
+	for _, invalidatedBlock := range invalidatedBlocks {
+		if invalidatedBlock.Equal(blockHash) {
+			return nil, errors.Errorf("block %s is already invalidated", blockHash)
+		}
+	}
+	csm.consensusStateStore.StageInvalidatedBlocks(stagingArea, append(invalidatedBlocks, blockHash))
+
+	future, err := csm.blockAndFutureWithBodies(stagingArea, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("Invalidating block %s and the %d blocks in its future", blockHash, len(future)-1)
+	for _, futureBlock := range future {
+		csm.blockStatusStore.Stage(stagingArea, futureBlock, externalapi.StatusDisqualifiedFromChain)
+	}
+
+	return csm.updateVirtualAfterManualOverride(stagingArea)
+}
+
+// ReconsiderBlock removes a manual invalidation that was previously set by InvalidateBlock.
+// Blocks in the future of the given block that aren't covered by another invalidation get
+// back the status they had before, and the virtual is moved accordingly.
+func (csm *consensusStateManager) ReconsiderBlock(blockHash *externalapi.DomainHash) (*externalapi.VirtualChangeSet, error) {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "csm.ReconsiderBlock")
+	defer onEnd()
+
+	stagingArea := model.NewStagingArea()
+
+	invalidatedBlocks, err := csm.consensusStateStore.InvalidatedBlocks(stagingArea, csm.databaseContext)
+	if err != nil {
+		return nil, err
+	}
+	remainingInvalidatedBlocks := make([]*externalapi.DomainHash, 0, len(invalidatedBlocks))
+	for _, invalidatedBlock := range invalidatedBlocks {
+		if !invalidatedBlock.Equal(blockHash) {
+			remainingInvalidatedBlocks = append(remainingInvalidatedBlocks, invalidatedBlock)
+		}
+	}
+	if len(remainingInvalidatedBlocks) == len(invalidatedBlocks) {
+		return nil, errors.Errorf("block %s is not invalidated", blockHash)
+	}
+	err = csm.checkBlockIsAboveFinalityPoint(stagingArea, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	csm.consensusStateStore.StageInvalidatedBlocks(stagingArea, remainingInvalidatedBlocks)
+
+	future, err := csm.blockAndFutureWithBodies(stagingArea, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("Reconsidering block %s and the %d blocks in its future", blockHash, len(future)-1)
+	for _, futureBlock := range future {
+		isInvalidated, err := csm.isInvalidatedOrInFutureOf(stagingArea, futureBlock, remainingInvalidatedBlocks)
+		if err != nil {
+			return nil, err
+		}
+		if isInvalidated {
+			continue
+		}
+
+		status, err := csm.blockStatusStore.Get(csm.databaseContext, stagingArea, futureBlock)
+		if err != nil {
+			return nil, err
+		}
+		if status != externalapi.StatusDisqualifiedFromChain {
+			continue
+		}
+
+		// Only blocks that passed UTXO verification have a multiset, so this tells
+		// apart blocks that were valid before the invalidation from blocks that
+		// were never verified or were disqualified on their own
+		_, err = csm.multisetStore.Get(csm.databaseContext, stagingArea, futureBlock)
+		if database.IsNotFoundError(err) {
+			csm.blockStatusStore.Stage(stagingArea, futureBlock, externalapi.StatusUTXOPendingVerification)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		csm.blockStatusStore.Stage(stagingArea, futureBlock, externalapi.StatusUTXOValid)
+	}
+
+	return csm.updateVirtualAfterManualOverride(stagingArea)
+}
+
+// updateVirtualAfterManualOverride picks the virtual parents out of the current tips,
+// using the statuses staged in stagingArea, and commits the result. Unlike the regular
+// flow, the virtual selected parent may move to a block that doesn't win the previous
+// one, so the UTXO diff of the previous selected tip is re-rooted on the new one.
+func (csm *consensusStateManager) updateVirtualAfterManualOverride(
+	stagingArea *model.StagingArea) (*externalapi.VirtualChangeSet, error) {
+
+	previousVirtualSelectedParent, err := csm.virtualSelectedParent(stagingArea)
+	if err != nil {
+		return nil, err
+	}
+
+	tips, err := csm.consensusStateStore.Tips(stagingArea, csm.databaseContext)
+	if err != nil {
+		return nil, err
+	}
+	virtualParents, err := csm.pickVirtualParents(stagingArea, tips)
+	if err != nil {
+		return nil, err
+	}
+	newVirtualSelectedParent, err := csm.ghostdagManager.ChooseSelectedParent(stagingArea, virtualParents...)
+	if err != nil {
+		return nil, err
+	}
+
+	if !newVirtualSelectedParent.Equal(previousVirtualSelectedParent) {
+		isViolatingFinality, _, err := csm.isViolatingFinality(stagingArea, newVirtualSelectedParent)
+		if err != nil {
+			return nil, err
+		}
+		if isViolatingFinality {
+			return nil, errors.Errorf("the new virtual selected parent %s would violate finality",
+				newVirtualSelectedParent)
+		}
+
+		log.Debugf("Moving the virtual selected parent from %s to %s",
+			previousVirtualSelectedParent, newVirtualSelectedParent)
+		previousVirtualSelectedParentUTXOSet, err := csm.restorePastUTXO(stagingArea, previousVirtualSelectedParent)
+		if err != nil {
+			return nil, err
+		}
+		newVirtualSelectedParentUTXOSet, err := csm.restorePastUTXO(stagingArea, newVirtualSelectedParent)
+		if err != nil {
+			return nil, err
+		}
+		previousVirtualSelectedParentUTXODiff, err := newVirtualSelectedParentUTXOSet.DiffFrom(previousVirtualSelectedParentUTXOSet)
+		if err != nil {
+			return nil, err
+		}
+		csm.stageDiff(stagingArea, previousVirtualSelectedParent, previousVirtualSelectedParentUTXODiff, newVirtualSelectedParent)
+		csm.stageDiff(stagingArea, newVirtualSelectedParent, newVirtualSelectedParentUTXOSet, nil)
+	}
+
+	virtualUTXODiff, err := csm.updateVirtualWithParents(stagingArea, virtualParents)
+	if err != nil {
+		return nil, err
+	}
+
+	selectedParentChainChanges, err := csm.dagTraversalManager.
+		CalculateChainPath(stagingArea, previousVirtualSelectedParent, newVirtualSelectedParent)
+	if err != nil {
+		return nil, err
+	}
+
+	err = staging.CommitAllChanges(csm.databaseContext, stagingArea)
+	if err != nil {
+		return nil, err
+	}
+
+	return &externalapi.VirtualChangeSet{
+		VirtualSelectedParentChainChanges: selectedParentChainChanges,
+		VirtualUTXODiff:                   virtualUTXODiff,
+		VirtualParents:                    virtualParents,
+	}, nil
+}
+
+// checkBlockIsAboveFinalityPoint returns an error if the given block isn't in the future of
+// both the virtual finality point and the pruning point. The virtual can't move away from
+// such a block without violating finality, and its future may span most of the DAG.
+func (csm *consensusStateManager) checkBlockIsAboveFinalityPoint(stagingArea *model.StagingArea,
+	blockHash *externalapi.DomainHash) error {
+
+	pruningPoint, err := csm.pruningStore.PruningPoint(csm.databaseContext, stagingArea)
+	if err != nil {
+		return err
+	}
+	virtualFinalityPoint, err := csm.finalityManager.VirtualFinalityPoint(stagingArea)
+	if err != nil {
+		return err
+	}
+	for _, point := range []*externalapi.DomainHash{pruningPoint, virtualFinalityPoint} {
+		isInFutureOfPoint, err := csm.dagTopologyManager.IsAncestorOf(stagingArea, point, blockHash)
+		if err != nil {
+			return err
+		}
+		if !isInFutureOfPoint || point.Equal(blockHash) {
+			return errors.Errorf("block %s is not in the future of the finality point %s and "+
+				"the pruning point %s", blockHash, virtualFinalityPoint, pruningPoint)
+		}
+	}
+	return nil
+}
+
+// blockAndFutureWithBodies returns the given block and all the blocks in its future,
+// excluding headers-only blocks, which can't have blocks with bodies in their future.
+// The future of a block is merged by the chain blocks above it, so the walk stops with
+// an error once it visits more blocks than the merge sets of the chain blocks between
+// the given block and the headers selected tip can hold.
+func (csm *consensusStateManager) blockAndFutureWithBodies(stagingArea *model.StagingArea,
+	blockHash *externalapi.DomainHash) ([]*externalapi.DomainHash, error) {
+
+	blockGHOSTDAGData, err := csm.ghostdagDataStore.Get(csm.databaseContext, stagingArea, blockHash, false)
+	if err != nil {
+		return nil, err
+	}
+	headersSelectedTip, err := csm.headersSelectedTipStore.HeadersSelectedTip(csm.databaseContext, stagingArea)
+	if err != nil {
+		return nil, err
+	}
+	headersSelectedTipGHOSTDAGData, err := csm.ghostdagDataStore.Get(csm.databaseContext, stagingArea, headersSelectedTip, false)
+	if err != nil {
+		return nil, err
+	}
+	blueScoreDistance := uint64(0)
+	if headersSelectedTipGHOSTDAGData.BlueScore() > blockGHOSTDAGData.BlueScore() {
+		blueScoreDistance = headersSelectedTipGHOSTDAGData.BlueScore() - blockGHOSTDAGData.BlueScore()
+	}
+	maxFutureSize := (blueScoreDistance + 1) * csm.mergeSetSizeLimit
+
+	visited := hashset.New()
+	visited.Add(blockHash)
+	future := []*externalapi.DomainHash{blockHash}
+	for i := 0; i < len(future); i++ {
+		children, err := csm.dagTopologyManager.Children(stagingArea, future[i])
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			if child.Equal(model.VirtualBlockHash) || visited.Contains(child) {
+				continue
+			}
+			visited.Add(child)
+			if uint64(visited.Length()) > maxFutureSize {
+				return nil, errors.Errorf("the future of block %s has more than %d blocks", blockHash, maxFutureSize)
+			}
+
+			status, err := csm.blockStatusStore.Get(csm.databaseContext, stagingArea, child)
+			if err != nil {
+				return nil, err
+			}
+			if status == externalapi.StatusHeaderOnly {
+				continue
+			}
+			future = append(future, child)
+		}
+	}
+	return future, nil
+}
+
+// isInInvalidatedFuture returns whether the given block was manually invalidated,
+// or is in the future of a manually invalidated block
+func (csm *consensusStateManager) isInInvalidatedFuture(stagingArea *model.StagingArea,
+	blockHash *externalapi.DomainHash) (bool, error) {
+
+	invalidatedBlocks, err := csm.consensusStateStore.InvalidatedBlocks(stagingArea, csm.databaseContext)
+	if err != nil {
+		return false, err
+	}
+	return csm.isInvalidatedOrInFutureOf(stagingArea, blockHash, invalidatedBlocks)
+}
+
+func (csm *consensusStateManager) isInvalidatedOrInFutureOf(stagingArea *model.StagingArea,
+	blockHash *externalapi.DomainHash, invalidatedBlocks []*externalapi.DomainHash) (bool, error) {
+
+	for _, invalidatedBlock := range invalidatedBlocks {
+		if invalidatedBlock.Equal(blockHash) {
+			return true, nil
+		}
+	}
+	return csm.dagTopologyManager.IsAnyAncestorOf(stagingArea, invalidatedBlocks, blockHash)
+}
+
@@ -0,0 +1,178 @@
+package consensusstatemanager_test
+
+import (
+	"testing"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/testapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/testutils"
+)
+
+func TestInvalidateAndReconsiderBlock(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		factory := consensus.NewFactory()
+
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestInvalidateAndReconsiderBlock")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		hashes := []*externalapi.DomainHash{consensusConfig.GenesisHash}
+
+		// Create a chain of blocks
+		const mainChainLength = 5
+		mainChain := make([]*externalapi.DomainHash, 0, mainChainLength)
+		previousBlockHash := consensusConfig.GenesisHash
+		for i := 0; i < mainChainLength; i++ {
+			previousBlockHash, _, err = tc.AddBlock([]*externalapi.DomainHash{previousBlockHash}, nil, nil)
+			if err != nil {
+				t.Fatalf("Error mining block no. %d in main chain: %+v", i, err)
+			}
+			mainChain = append(mainChain, previousBlockHash)
+			hashes = append(hashes, previousBlockHash)
+		}
+
+		// Create a shorter side chain that branches off the main chain
+		const sideChainLength = 2
+		previousBlockHash = mainChain[1]
+		for i := 0; i < sideChainLength; i++ {
+			previousBlockHash, _, err = tc.AddBlock([]*externalapi.DomainHash{previousBlockHash}, nil, nil)
+			if err != nil {
+				t.Fatalf("Error mining block no. %d in side chain: %+v", i, err)
+			}
+			hashes = append(hashes, previousBlockHash)
+		}
+		sideChainTip := previousBlockHash
+		mainChainTip := mainChain[mainChainLength-1]
+
+		verifyVirtualSelectedParent(t, tc, mainChainTip)
+		verifyUtxoDiffPaths(t, tc, hashes)
+
+		// The block builder refuses to build on top of disqualified blocks, so the block
+		// that is later added to the invalidated chain is built in advance
+		blockOnInvalidatedChain, _, err := tc.BuildBlockWithParents([]*externalapi.DomainHash{mainChainTip}, nil, nil)
+		if err != nil {
+			t.Fatalf("BuildBlockWithParents: %+v", err)
+		}
+		blockOnInvalidatedChainHash := consensushashing.BlockHash(blockOnInvalidatedChain)
+
+		err = tc.InvalidateBlock(consensusConfig.GenesisHash)
+		if err == nil {
+			t.Fatalf("InvalidateBlock: expected an error when invalidating the genesis block")
+		}
+
+		invalidatedBlock := mainChain[2]
+		err = tc.InvalidateBlock(invalidatedBlock)
+		if err != nil {
+			t.Fatalf("InvalidateBlock: %+v", err)
+		}
+		err = tc.InvalidateBlock(invalidatedBlock)
+		if err == nil {
+			t.Fatalf("InvalidateBlock: expected an error when invalidating an already invalidated block")
+		}
+
+		for _, blockHash := range mainChain[2:] {
+			verifyBlockStatus(t, tc, blockHash, externalapi.StatusDisqualifiedFromChain)
+		}
+		verifyVirtualSelectedParent(t, tc, sideChainTip)
+		verifyUtxoDiffPaths(t, tc, hashes)
+
+		// Blocks that are added to the future of an invalidated block are disqualified as well
+		err = tc.ValidateAndInsertBlock(blockOnInvalidatedChain, true)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertBlock: %+v", err)
+		}
+		hashes = append(hashes, blockOnInvalidatedChainHash)
+		verifyBlockStatus(t, tc, blockOnInvalidatedChainHash, externalapi.StatusDisqualifiedFromChain)
+		verifyVirtualSelectedParent(t, tc, sideChainTip)
+		verifyUtxoDiffPaths(t, tc, hashes)
+
+		err = tc.ReconsiderBlock(mainChain[1])
+		if err == nil {
+			t.Fatalf("ReconsiderBlock: expected an error when reconsidering a block that isn't invalidated")
+		}
+
+		err = tc.ReconsiderBlock(invalidatedBlock)
+		if err != nil {
+			t.Fatalf("ReconsiderBlock: %+v", err)
+		}
+
+		for _, blockHash := range mainChain[2:] {
+			verifyBlockStatus(t, tc, blockHash, externalapi.StatusUTXOValid)
+		}
+		verifyBlockStatus(t, tc, blockOnInvalidatedChainHash, externalapi.StatusUTXOValid)
+		verifyVirtualSelectedParent(t, tc, blockOnInvalidatedChainHash)
+		verifyUtxoDiffPaths(t, tc, hashes)
+	})
+}
+
+func TestInvalidateBlockBelowFinalityPoint(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.FinalityDuration = 5 * consensusConfig.TargetTimePerBlock
+
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestInvalidateBlockBelowFinalityPoint")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		const chainLength = 20
+		chain := make([]*externalapi.DomainHash, 0, chainLength)
+		previousBlockHash := consensusConfig.GenesisHash
+		for i := 0; i < chainLength; i++ {
+			previousBlockHash, _, err = tc.AddBlock([]*externalapi.DomainHash{previousBlockHash}, nil, nil)
+			if err != nil {
+				t.Fatalf("Error mining block no. %d in the chain: %+v", i, err)
+			}
+			chain = append(chain, previousBlockHash)
+		}
+
+		virtualFinalityPoint, err := tc.FinalityManager().VirtualFinalityPoint(model.NewStagingArea())
+		if err != nil {
+			t.Fatalf("VirtualFinalityPoint: %+v", err)
+		}
+		for _, blockHash := range []*externalapi.DomainHash{chain[2], virtualFinalityPoint} {
+			err = tc.InvalidateBlock(blockHash)
+			if err == nil {
+				t.Fatalf("InvalidateBlock: expected an error when invalidating block %s, which isn't "+
+					"above the finality point", blockHash)
+			}
+			verifyBlockStatus(t, tc, blockHash, externalapi.StatusUTXOValid)
+		}
+		verifyVirtualSelectedParent(t, tc, chain[chainLength-1])
+
+		err = tc.InvalidateBlock(chain[chainLength-2])
+		if err != nil {
+			t.Fatalf("InvalidateBlock: %+v", err)
+		}
+		verifyVirtualSelectedParent(t, tc, chain[chainLength-3])
+	})
+}
+
+func verifyBlockStatus(t *testing.T, tc testapi.TestConsensus, blockHash *externalapi.DomainHash,
+	expectedStatus externalapi.BlockStatus) {
+
+	status, err := tc.BlockStatusStore().Get(tc.DatabaseContext(), model.NewStagingArea(), blockHash)
+	if err != nil {
+		t.Fatalf("Error getting the status of block %s: %+v", blockHash, err)
+	}
+	if status != expectedStatus {
+		t.Fatalf("Expected block %s to have status %s, but got %s", blockHash, expectedStatus, status)
+	}
+}
+
+func verifyVirtualSelectedParent(t *testing.T, tc testapi.TestConsensus, expected *externalapi.DomainHash) {
+	virtualSelectedParent, err := tc.GetVirtualSelectedParent()
+	if err != nil {
+		t.Fatalf("GetVirtualSelectedParent: %+v", err)
+	}
+	if !virtualSelectedParent.Equal(expected) {
+		t.Fatalf("Expected the virtual selected parent to be %s, but got %s", expected, virtualSelectedParent)
+	}
+}
+
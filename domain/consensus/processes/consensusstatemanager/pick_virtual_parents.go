@@ -56,6 +56,11 @@ func (csm *consensusStateManager) pickVirtualParents(stagingArea *model.StagingA
 		}
 	}
 
+	invalidatedBlocks, err := csm.consensusStateStore.InvalidatedBlocks(stagingArea, csm.databaseContext)
+	if err != nil {
+		return nil, err
+	}
+
 	selectedVirtualParents := []*externalapi.DomainHash{virtualSelectedParent}
 	mergeSetSize := uint64(1) // starts counting from 1 because selectedParent is already in the mergeSet
 
@@ -64,6 +69,21 @@ func (csm *consensusStateManager) pickVirtualParents(stagingArea *model.StagingA
 		candidate := candidates[0]
 		candidates = candidates[1:]
 
+		// Blocks in the future of a manually invalidated block must not be merged by the
+		// virtual, so we replace them with their parents
+		isInInvalidatedFuture, err := csm.isInvalidatedOrInFutureOf(stagingArea, candidate, invalidatedBlocks)
+		if err != nil {
+			return nil, err
+		}
+		if isInInvalidatedFuture {
+			candidates, err = csm.replaceWithParents(stagingArea, candidate, candidates, selectedVirtualParents)
+			if err != nil {
+				return nil, err
+			}
+			log.Debugf("Block %s is in the future of a manually invalidated block, instead adding its parents", candidate)
+			continue
+		}
+
 		log.Debugf("Attempting to add %s to the virtual parents", candidate)
 		log.Debugf("The current merge set size is %d", mergeSetSize)
 
@@ -134,6 +154,35 @@ func (csm *consensusStateManager) removeHashesInFutureOf(stagingArea *model.Stag
 	return hashes[:i], nil
 }
 
+// replaceWithParents adds to candidates the parents of the given block that aren't already
+// in the past of any of the candidates or of selectedVirtualParents
+func (csm *consensusStateManager) replaceWithParents(stagingArea *model.StagingArea, blockHash *externalapi.DomainHash,
+	candidates []*externalapi.DomainHash, selectedVirtualParents []*externalapi.DomainHash) ([]*externalapi.DomainHash, error) {
+
+	parents, err := csm.dagTopologyManager.Parents(stagingArea, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	for _, parent := range parents {
+		isInPastOfSelectedVirtualParents, err := csm.dagTopologyManager.IsAnyAncestorOf(stagingArea, selectedVirtualParents, parent)
+		if err != nil {
+			return nil, err
+		}
+		if isInPastOfSelectedVirtualParents {
+			continue
+		}
+		isInPastOfCandidates, err := csm.dagTopologyManager.IsAnyAncestorOf(stagingArea, candidates, parent)
+		if err != nil {
+			return nil, err
+		}
+		if isInPastOfCandidates {
+			continue
+		}
+		candidates = append(candidates, parent)
+	}
+	return candidates, nil
+}
+
 func (csm *consensusStateManager) selectVirtualSelectedParent(stagingArea *model.StagingArea,
 	candidatesHeap model.BlockHeap) (*externalapi.DomainHash, error) {
 
@@ -0,0 +1,94 @@
+package consensusstatemanager_test
+
+import (
+	"testing"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/testapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/testutils"
+)
+
+// TestUTXODiffChildrenAcrossReorgs verifies the UTXO diff children that regular block
+// processing leaves behind, as the virtual selected parent moves between competing chains.
+// Staging a nil UTXO diff child removes the stored one, which block processing relies on
+// only for blocks that don't have one, so no block is expected to lose its path to the
+// virtual selected parent.
+func TestUTXODiffChildrenAcrossReorgs(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		factory := consensus.NewFactory()
+
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestUTXODiffChildrenAcrossReorgs")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		hashes := []*externalapi.DomainHash{consensusConfig.GenesisHash}
+		addChain := func(parentHash *externalapi.DomainHash, length int) *externalapi.DomainHash {
+			for i := 0; i < length; i++ {
+				parentHash, _, err = tc.AddBlock([]*externalapi.DomainHash{parentHash}, nil, nil)
+				if err != nil {
+					t.Fatalf("AddBlock: %+v", err)
+				}
+				hashes = append(hashes, parentHash)
+				verifyUTXODiffChildren(t, tc, hashes)
+			}
+			return parentHash
+		}
+
+		chainATip := addChain(consensusConfig.GenesisHash, 4)
+		verifyVirtualSelectedParent(t, tc, chainATip)
+
+		// The second chain overtakes the first one
+		chainBTip := addChain(consensusConfig.GenesisHash, 6)
+		verifyVirtualSelectedParent(t, tc, chainBTip)
+
+		// The first chain overtakes the second one back, so its blocks return to the selected chain
+		chainATip = addChain(chainATip, 4)
+		verifyVirtualSelectedParent(t, tc, chainATip)
+
+		mergingBlock, _, err := tc.AddBlock([]*externalapi.DomainHash{chainATip, chainBTip}, nil, nil)
+		if err != nil {
+			t.Fatalf("AddBlock: %+v", err)
+		}
+		hashes = append(hashes, mergingBlock)
+		verifyUTXODiffChildren(t, tc, hashes)
+		verifyVirtualSelectedParent(t, tc, mergingBlock)
+	})
+}
+
+// verifyUTXODiffChildren verifies that the virtual selected parent is the only UTXO valid
+// block without a UTXO diff child, and that all other blocks have a path of UTXO diff
+// children to it
+func verifyUTXODiffChildren(t *testing.T, tc testapi.TestConsensus, hashes []*externalapi.DomainHash) {
+	stagingArea := model.NewStagingArea()
+
+	virtualSelectedParent, err := tc.GetVirtualSelectedParent()
+	if err != nil {
+		t.Fatalf("GetVirtualSelectedParent: %+v", err)
+	}
+	for _, blockHash := range hashes {
+		// Blocks that were never on the virtual selected chain aren't resolved, so they have no UTXO diff
+		status, err := tc.BlockStatusStore().Get(tc.DatabaseContext(), stagingArea, blockHash)
+		if err != nil {
+			t.Fatalf("BlockStatusStore().Get: %+v", err)
+		}
+		if status != externalapi.StatusUTXOValid {
+			continue
+		}
+		hasUTXODiffChild, err := tc.UTXODiffStore().HasUTXODiffChild(tc.DatabaseContext(), stagingArea, blockHash)
+		if err != nil {
+			t.Fatalf("HasUTXODiffChild: %+v", err)
+		}
+		isVirtualSelectedParent := blockHash.Equal(virtualSelectedParent)
+		if hasUTXODiffChild == isVirtualSelectedParent {
+			t.Fatalf("Expected HasUTXODiffChild of block %s to be %t, but got %t",
+				blockHash, !isVirtualSelectedParent, hasUTXODiffChild)
+		}
+	}
+
+	verifyUtxoDiffPaths(t, tc, hashes)
+}
+
@@ -68,30 +68,33 @@ func (csm *consensusStateManager) validateBlockTransactionsAgainstPastUTXO(stagi
 	}
 	log.Tracef("The past median time of %s is %d", blockHash, selectedParentMedianTime)
 
-	for i, transaction := range block.Transactions {
-		transactionID := consensushashing.TransactionID(transaction)
-		log.Tracef("Validating transaction %s in block %s against "+
-			"the block's past UTXO", transactionID, blockHash)
-		if i == transactionhelper.CoinbaseTransactionIndex {
-			log.Tracef("Skipping transaction %s because it is the coinbase", transactionID)
-			continue
-		}
-
-		log.Tracef("Populating transaction %s with UTXO entries", transactionID)
+	// All the transactions are populated first, so that their scripts can be verified together.
+	// The transactions after the first one that can't be populated are never validated, as
+	// if they were validated one after another.
+	transactions := block.Transactions[transactionhelper.CoinbaseTransactionIndex+1:]
+	populatedTransactionCount := len(transactions)
+	var populateErr error
+	for i, transaction := range transactions {
+		log.Tracef("Populating transaction %s with UTXO entries", consensushashing.TransactionID(transaction))
 		err = csm.populateTransactionWithUTXOEntriesFromVirtualOrDiff(stagingArea, transaction, pastUTXODiff)
 		if err != nil {
-			return err
+			populatedTransactionCount = i
+			populateErr = err
+			break
 		}
+	}
 
-		log.Tracef("Validating transaction %s and populating it with fee", transactionID)
-		err = csm.transactionValidator.ValidateTransactionInContextAndPopulateFee(
-			stagingArea, transaction, blockHash)
-		if err != nil {
-			return err
-		}
-		log.Tracef("Validation against the block's past UTXO "+
-			"passed for transaction %s in block %s", transactionID, blockHash)
+	log.Tracef("Validating %d transactions in block %s against the block's past UTXO and populating them with fee",
+		populatedTransactionCount, blockHash)
+	err = csm.transactionValidator.ValidateTransactionsInContextAndPopulateFee(
+		stagingArea, transactions[:populatedTransactionCount], blockHash)
+	if err != nil {
+		return err
+	}
+	if populateErr != nil {
+		return populateErr
 	}
+	log.Tracef("Validation against the block's past UTXO passed for all transactions in block %s", blockHash)
 	return nil
 }
 
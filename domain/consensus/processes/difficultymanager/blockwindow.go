@@ -14,6 +14,7 @@ type difficultyBlock struct {
 	Bits               uint32
 	hash               *externalapi.DomainHash
 	blueWork           *big.Int
+	daaScore           uint64
 }
 
 type blockWindow []difficultyBlock
@@ -30,6 +31,7 @@ func (dm *difficultyManager) getDifficultyBlock(
 		Bits:               header.Bits(),
 		hash:               blockHash,
 		blueWork:           header.BlueWork(),
+		daaScore:           header.DAAScore(),
 	}, nil
 }
 
@@ -87,6 +89,16 @@ func (window blockWindow) minMaxTimestamps() (min, max int64, minIndex int) {
 	return
 }
 
+func (window blockWindow) minDAAScore() uint64 {
+	min := uint64(math.MaxUint64)
+	for _, block := range window {
+		if block.daaScore < min {
+			min = block.daaScore
+		}
+	}
+	return min
+}
+
 func (window *blockWindow) remove(n int) {
 	(*window)[n] = (*window)[len(*window)-1]
 	*window = (*window)[:len(*window)-1]
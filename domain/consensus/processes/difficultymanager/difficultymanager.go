@@ -29,6 +29,8 @@ type difficultyManager struct {
 	disableDifficultyAdjustment    bool
 	targetTimePerBlock             time.Duration
 	genesisBits                    uint32
+	fishHashActivationDAAScore     uint64
+	fishHashActivationBits         uint32
 }
 
 // New instantiates a new DifficultyManager
@@ -44,7 +46,9 @@ func New(databaseContext model.DBReader,
 	disableDifficultyAdjustment bool,
 	targetTimePerBlock time.Duration,
 	genesisHash *externalapi.DomainHash,
-	genesisBits uint32) model.DifficultyManager {
+	genesisBits uint32,
+	fishHashActivationDAAScore uint64,
+	fishHashActivationBits uint32) model.DifficultyManager {
 	return &difficultyManager{
 		databaseContext:                databaseContext,
 		ghostdagManager:                ghostdagManager,
@@ -59,6 +63,8 @@ func New(databaseContext model.DBReader,
 		targetTimePerBlock:             targetTimePerBlock,
 		genesisHash:                    genesisHash,
 		genesisBits:                    genesisBits,
+		fishHashActivationDAAScore:     fishHashActivationDAAScore,
+		fishHashActivationBits:         fishHashActivationBits,
 	}
 }
 
@@ -87,7 +93,7 @@ func (dm *difficultyManager) StageDAADataAndReturnRequiredDifficulty(
 		return 0, err
 	}
 
-	return dm.requiredDifficultyFromTargetsWindow(targetsWindow)
+	return dm.requiredDifficultyForBlock(stagingArea, blockHash, targetsWindow)
 }
 
 // RequiredDifficulty returns the difficulty required for some block
@@ -97,6 +103,26 @@ func (dm *difficultyManager) RequiredDifficulty(stagingArea *model.StagingArea,
 		return 0, err
 	}
 
+	return dm.requiredDifficultyForBlock(stagingArea, blockHash, targetsWindow)
+}
+
+// requiredDifficultyForBlock returns the required difficulty of the given block. Blocks that use FishHash
+// require fishHashActivationBits as long as their window contains blocks that use kHeavyHash, since the
+// difficulty of kHeavyHash blocks says nothing about the FishHash hashrate of the network.
+func (dm *difficultyManager) requiredDifficultyForBlock(stagingArea *model.StagingArea,
+	blockHash *externalapi.DomainHash, targetsWindow blockWindow) (uint32, error) {
+
+	if dm.disableDifficultyAdjustment || dm.fishHashActivationDAAScore == 0 {
+		return dm.requiredDifficultyFromTargetsWindow(targetsWindow)
+	}
+
+	daaScore, err := dm.daaBlocksStore.DAAScore(dm.databaseContext, stagingArea, blockHash)
+	if err != nil {
+		return 0, err
+	}
+	if daaScore >= dm.fishHashActivationDAAScore && targetsWindow.minDAAScore() < dm.fishHashActivationDAAScore {
+		return dm.fishHashActivationBits, nil
+	}
 	return dm.requiredDifficultyFromTargetsWindow(targetsWindow)
 }
 
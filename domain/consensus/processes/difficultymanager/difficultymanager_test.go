@@ -1,6 +1,7 @@
 package difficultymanager_test
 
 import (
+	"math/big"
 	"testing"
 	"time"
 
@@ -351,6 +352,76 @@ func TestDAAScore(t *testing.T) {
 	})
 }
 
+func TestFishHashActivationDifficulty(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		if consensusConfig.DisableDifficultyAdjustment {
+			return
+		}
+
+		consensusConfig.K = 1
+		consensusConfig.DifficultyAdjustmentWindowSize = 140
+		windowSize := uint64(consensusConfig.DifficultyAdjustmentWindowSize)
+		consensusConfig.FishHashActivationDAAScore = consensusConfig.GenesisBlock.Header.DAAScore() + 2*windowSize
+		consensusConfig.FishHashActivationBits = difficulty.BigToCompact(new(big.Int).Rsh(consensusConfig.PowMax, 10))
+
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestFishHashActivationDifficulty")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		stagingArea := model.NewStagingArea()
+		addBlock := func(parentHash *externalapi.DomainHash, timeDelta int64) (*externalapi.DomainBlock, *externalapi.DomainHash) {
+			parentHeader, err := tc.BlockHeaderStore().BlockHeader(tc.DatabaseContext(), stagingArea, parentHash)
+			if err != nil {
+				t.Fatalf("BlockHeader: %+v", err)
+			}
+			block, _, err := tc.BuildBlockWithParents([]*externalapi.DomainHash{parentHash}, nil, nil)
+			if err != nil {
+				t.Fatalf("BuildBlockWithParents: %+v", err)
+			}
+			newHeader := block.Header.ToMutable()
+			newHeader.SetTimeInMilliseconds(parentHeader.TimeInMilliseconds() + timeDelta)
+			block.Header = newHeader.ToImmutable()
+			err = tc.ValidateAndInsertBlock(block, true)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertBlock: %+v", err)
+			}
+			return block, consensushashing.BlockHash(block)
+		}
+
+		tipHash := consensusConfig.GenesisHash
+		for {
+			var tip *externalapi.DomainBlock
+			tip, tipHash = addBlock(tipHash, consensusConfig.TargetTimePerBlock.Milliseconds())
+			if tip.Header.DAAScore() >= consensusConfig.FishHashActivationDAAScore-1 {
+				break
+			}
+			if tip.Header.Bits() != consensusConfig.GenesisBlock.Header.Bits() {
+				t.Fatalf("As long as the block rate remains the same, the difficulty shouldn't change")
+			}
+		}
+
+		// Blocks are added faster than the target block rate, which shouldn't affect the difficulty
+		// until the window consists of FishHash blocks only
+		for {
+			var tip *externalapi.DomainBlock
+			tip, tipHash = addBlock(tipHash, consensusConfig.TargetTimePerBlock.Milliseconds()/2)
+			if tip.Header.DAAScore() >= consensusConfig.FishHashActivationDAAScore+windowSize {
+				if compareBits(tip.Header.Bits(), consensusConfig.FishHashActivationBits) >= 0 {
+					t.Fatalf("The difficulty should increase once the window consists of FishHash blocks only")
+				}
+				break
+			}
+			if tip.Header.Bits() != consensusConfig.FishHashActivationBits {
+				t.Fatalf("Expected block with DAA score %d to require the FishHash activation difficulty, "+
+					"but got bits %x", tip.Header.DAAScore(), tip.Header.Bits())
+			}
+		}
+	})
+}
+
 func compareBits(a uint32, b uint32) int {
 	aTarget := difficulty.CompactToBig(a)
 	bTarget := difficulty.CompactToBig(b)
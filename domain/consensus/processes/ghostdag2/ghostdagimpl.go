@@ -275,6 +275,11 @@ func (gh *ghostdagHelper) checkIfDestroy(stagingArea *model.StagingArea, blockBl
 	var k = int(gh.k)
 	counter := 0
 	for _, blue := range *blueSet {
+		// A block is not in its own anticone, regardless of whether
+		// the DAG topology manager considers it its own ancestor
+		if blue.Equal(blockBlue) {
+			continue
+		}
 		isAnticone, err := gh.isAnticone(stagingArea, blue, blockBlue)
 		if err != nil {
 			return true, err
@@ -282,7 +287,9 @@ func (gh *ghostdagHelper) checkIfDestroy(stagingArea *model.StagingArea, blockBl
 		if isAnticone {
 			counter++
 		}
-		if counter > k {
+		// The added block is in the anticone of blockBlue as well, so
+		// blockBlue may have at most k-1 other blues in its anticone
+		if counter >= k {
 			return true, nil
 		}
 	}
@@ -0,0 +1,360 @@
+package ghostdagmanager_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/testapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/processes/ghostdag2"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/processes/ghostdagmanager"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/dagconfig"
+)
+
+const (
+	maxFuzzK          = 20
+	maxFuzzWidth      = 8
+	maxFuzzDAGSize    = 100
+	maxMinimizePasses = 5
+)
+
+// randomDAG describes a DAG by the parents of each of its blocks. Block 0
+// is the genesis, and the parents of every other block are lower blocks.
+type randomDAG struct {
+	k       externalapi.KType
+	parents [][]int
+}
+
+func (dag *randomDAG) String() string {
+	builder := strings.Builder{}
+	builder.WriteString(fmt.Sprintf("K: %d\n", dag.k))
+	for block := 1; block < len(dag.parents); block++ {
+		builder.WriteString(fmt.Sprintf("%d <- %v\n", block, dag.parents[block]))
+	}
+	return builder.String()
+}
+
+// generateRandomDAG generates a DAG of the given size by adding rounds of up to maxWidth
+// blocks, where every block in a round points to a random subset of the current tips.
+// Blocks of the same round don't see each other, so wider rounds make wider DAGs.
+func generateRandomDAG(random *rand.Rand, k externalapi.KType, maxWidth int, size int,
+	maxBlockParents int) *randomDAG {
+
+	dag := &randomDAG{k: k, parents: [][]int{nil}}
+	tips := []int{0}
+	for len(dag.parents) < size {
+		width := 1 + random.Intn(maxWidth)
+		if width > size-len(dag.parents) {
+			width = size - len(dag.parents)
+		}
+
+		pointedTips := make(map[int]bool)
+		newTips := make([]int, 0, width)
+		for i := 0; i < width; i++ {
+			maxParents := len(tips)
+			if maxParents > maxBlockParents {
+				maxParents = maxBlockParents
+			}
+			numParents := 1 + random.Intn(maxParents)
+			parents := make([]int, 0, numParents)
+			for _, tipIndex := range random.Perm(len(tips))[:numParents] {
+				parents = append(parents, tips[tipIndex])
+				pointedTips[tips[tipIndex]] = true
+			}
+			newTips = append(newTips, len(dag.parents))
+			dag.parents = append(dag.parents, parents)
+		}
+
+		for _, tip := range tips {
+			if !pointedTips[tip] {
+				newTips = append(newTips, tip)
+			}
+		}
+		tips = newTips
+	}
+	return dag
+}
+
+// ghostdagMismatch describes the first block on which the GHOSTDAG implementations disagree
+type ghostdagMismatch struct {
+	block       int
+	field       string
+	original    interface{}
+	alternative interface{}
+}
+
+func (mismatch *ghostdagMismatch) String() string {
+	return fmt.Sprintf("block %d: the %s is %v in the original implementation, but %v in the alternative one",
+		mismatch.block, mismatch.field, mismatch.original, mismatch.alternative)
+}
+
+// FuzzGHOSTDAGImplementations builds random DAGs with varying K and widths, and checks that
+// ghostdagmanager and ghostdag2 calculate the same GHOSTDAG data for every block. When they
+// disagree, the DAG is minimized and reported as a counterexample.
+func FuzzGHOSTDAGImplementations(f *testing.F) {
+	f.Add(int64(0), uint8(0), uint8(1), uint8(30))
+	f.Add(int64(1), uint8(1), uint8(3), uint8(60))
+	f.Add(int64(2), uint8(3), uint8(5), uint8(80))
+	f.Add(int64(3), uint8(5), uint8(7), uint8(97))
+	f.Add(int64(4), uint8(18), uint8(7), uint8(97))
+
+	f.Fuzz(func(t *testing.T, seed int64, k uint8, maxWidth uint8, size uint8) {
+		consensusConfig := &consensus.Config{Params: dagconfig.SimnetParams}
+		consensusConfig.K = externalapi.KType(k % (maxFuzzK + 1))
+		consensusConfig.SkipProofOfWork = true
+
+		random := rand.New(rand.NewSource(seed))
+		dag := generateRandomDAG(random, consensusConfig.K, 1+int(maxWidth%maxFuzzWidth),
+			2+int(size)%(maxFuzzDAGSize-1), int(consensusConfig.MaxBlockParents))
+
+		mismatch, err := compareGHOSTDAGImplementations(consensusConfig, dag)
+		if err != nil {
+			t.Fatalf("compareGHOSTDAGImplementations: %+v", err)
+		}
+		if mismatch == nil {
+			return
+		}
+
+		dag, mismatch = minimizeCounterexample(consensusConfig, dag, mismatch)
+		t.Fatalf("The GHOSTDAG implementations disagree on %s\nMinimal counterexample:\n%s", mismatch, dag)
+	})
+}
+
+// compareGHOSTDAGImplementations builds the given DAG with a test consensus, and runs both
+// GHOSTDAG implementations over its blocks, each with its own GHOSTDAG data store. It returns
+// the first mismatch between the implementations, or nil if they agree on all the blocks.
+func compareGHOSTDAGImplementations(consensusConfig *consensus.Config, dag *randomDAG) (*ghostdagMismatch, error) {
+	factory := consensus.NewFactory()
+	tc, teardown, err := factory.NewTestConsensus(consensusConfig, "compareGHOSTDAGImplementations")
+	if err != nil {
+		return nil, err
+	}
+	defer teardown(false)
+
+	hashes, err := buildRandomDAG(tc, consensusConfig.GenesisHash, dag)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make(map[externalapi.DomainHash]int, len(hashes))
+	for index, hash := range hashes {
+		indexes[*hash] = index
+	}
+	hashesToIndexes := func(hashes []*externalapi.DomainHash) []int {
+		result := make([]int, len(hashes))
+		for i, hash := range hashes {
+			result[i] = indexes[*hash]
+		}
+		return result
+	}
+
+	// The stored GHOSTDAG data of the genesis points to the virtual genesis, so the implementations
+	// get a copy of it without a selected parent, like in the DAG fixtures
+	stagingArea := model.NewStagingArea()
+	storedGenesisGHOSTDAGData, err := tc.GHOSTDAGDataStore().Get(tc.DatabaseContext(), stagingArea, hashes[0], false)
+	if err != nil {
+		return nil, err
+	}
+	genesisGHOSTDAGData := externalapi.NewBlockGHOSTDAGData(storedGenesisGHOSTDAGData.BlueScore(),
+		storedGenesisGHOSTDAGData.BlueWork(), nil, nil, nil, nil)
+	originalStore := &GHOSTDAGDataStoreImpl{dagMap: map[externalapi.DomainHash]*externalapi.BlockGHOSTDAGData{
+		*hashes[0]: genesisGHOSTDAGData,
+	}}
+	alternativeStore := &GHOSTDAGDataStoreImpl{dagMap: map[externalapi.DomainHash]*externalapi.BlockGHOSTDAGData{
+		*hashes[0]: genesisGHOSTDAGData,
+	}}
+	original := ghostdagmanager.New(tc.DatabaseContext(), tc.DAGTopologyManager(), originalStore,
+		tc.BlockHeaderStore(), dag.k, hashes[0])
+	alternative := ghostdag2.New(tc.DatabaseContext(), tc.DAGTopologyManager(), alternativeStore,
+		tc.BlockHeaderStore(), dag.k, hashes[0])
+
+	for block := 1; block < len(hashes); block++ {
+		err := original.GHOSTDAG(stagingArea, hashes[block])
+		if err != nil {
+			return nil, err
+		}
+		err = alternative.GHOSTDAG(stagingArea, hashes[block])
+		if err != nil {
+			return nil, err
+		}
+		originalData := originalStore.dagMap[*hashes[block]]
+		alternativeData := alternativeStore.dagMap[*hashes[block]]
+
+		if !originalData.SelectedParent().Equal(alternativeData.SelectedParent()) {
+			return &ghostdagMismatch{block, "selected parent",
+				indexes[*originalData.SelectedParent()], indexes[*alternativeData.SelectedParent()]}, nil
+		}
+		if originalData.BlueScore() != alternativeData.BlueScore() {
+			return &ghostdagMismatch{block, "blue score", originalData.BlueScore(), alternativeData.BlueScore()}, nil
+		}
+		if originalData.BlueWork().Cmp(alternativeData.BlueWork()) != 0 {
+			return &ghostdagMismatch{block, "blue work", originalData.BlueWork(), alternativeData.BlueWork()}, nil
+		}
+		originalBlues, alternativeBlues :=
+			hashesToIndexes(originalData.MergeSetBlues()), hashesToIndexes(alternativeData.MergeSetBlues())
+		if !reflect.DeepEqual(originalBlues, alternativeBlues) {
+			return &ghostdagMismatch{block, "merge set blues", originalBlues, alternativeBlues}, nil
+		}
+		originalReds, alternativeReds :=
+			hashesToIndexes(originalData.MergeSetReds()), hashesToIndexes(alternativeData.MergeSetReds())
+		if !reflect.DeepEqual(originalReds, alternativeReds) {
+			return &ghostdagMismatch{block, "merge set reds", originalReds, alternativeReds}, nil
+		}
+	}
+	return nil, nil
+}
+
+// buildRandomDAG adds the blocks of the given DAG to the test consensus, and returns their hashes
+func buildRandomDAG(tc testapi.TestConsensus, genesisHash *externalapi.DomainHash,
+	dag *randomDAG) ([]*externalapi.DomainHash, error) {
+
+	hashes := make([]*externalapi.DomainHash, len(dag.parents))
+	hashes[0] = genesisHash
+	for block := 1; block < len(dag.parents); block++ {
+		parentHashes := make([]*externalapi.DomainHash, len(dag.parents[block]))
+		for i, parent := range dag.parents[block] {
+			parentHashes[i] = hashes[parent]
+		}
+		blockHash, _, err := tc.AddBlock(parentHashes, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		hashes[block] = blockHash
+	}
+	return hashes, nil
+}
+
+// minimizeCounterexample shrinks a DAG on which the GHOSTDAG implementations disagree. It first
+// keeps only the past of the first mismatching block, and then repeatedly removes single blocks
+// for as long as the implementations keep disagreeing.
+func minimizeCounterexample(consensusConfig *consensus.Config, dag *randomDAG,
+	mismatch *ghostdagMismatch) (*randomDAG, *ghostdagMismatch) {
+
+	past := dag.past(mismatch.block)
+	past[mismatch.block] = true
+	candidate := dag.keep(past)
+	candidateMismatch, err := compareGHOSTDAGImplementations(consensusConfig, candidate)
+	if err == nil && candidateMismatch != nil {
+		dag, mismatch = candidate, candidateMismatch
+	}
+
+	for pass := 0; pass < maxMinimizePasses; pass++ {
+		removedAny := false
+		for block := len(dag.parents) - 1; block > 0; block-- {
+			candidate, ok := dag.withoutBlock(block, int(consensusConfig.MaxBlockParents))
+			if !ok {
+				continue
+			}
+			candidateMismatch, err := compareGHOSTDAGImplementations(consensusConfig, candidate)
+			if err != nil || candidateMismatch == nil {
+				continue
+			}
+			dag, mismatch = candidate, candidateMismatch
+			removedAny = true
+		}
+		if !removedAny {
+			break
+		}
+	}
+	return dag, mismatch
+}
+
+// past returns the set of blocks in the past of the given block
+func (dag *randomDAG) past(block int) map[int]bool {
+	past := make(map[int]bool)
+	queue := append([]int{}, dag.parents[block]...)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if past[current] {
+			continue
+		}
+		past[current] = true
+		queue = append(queue, dag.parents[current]...)
+	}
+	return past
+}
+
+// keep returns the DAG that consists only of the given blocks, which must be closed under
+// taking parents
+func (dag *randomDAG) keep(blocks map[int]bool) *randomDAG {
+	newIndexes := make(map[int]int)
+	result := &randomDAG{k: dag.k}
+	for block := range dag.parents {
+		if !blocks[block] && block != 0 {
+			continue
+		}
+		newIndexes[block] = len(result.parents)
+		parents := make([]int, len(dag.parents[block]))
+		for i, parent := range dag.parents[block] {
+			parents[i] = newIndexes[parent]
+		}
+		result.parents = append(result.parents, parents)
+	}
+	return result
+}
+
+// withoutBlock returns the DAG without the given block, where the children of the block point to
+// its parents instead. Parents that become ancestors of other parents are dropped, so that every
+// block keeps pointing only to blocks that are in the anticone of each other.
+// It returns false if a block ends up with more parents than allowed.
+func (dag *randomDAG) withoutBlock(removed int, maxBlockParents int) (*randomDAG, bool) {
+	result := &randomDAG{k: dag.k, parents: make([][]int, len(dag.parents))}
+	for block := range dag.parents {
+		parents := make([]int, 0, len(dag.parents[block]))
+		for _, parent := range dag.parents[block] {
+			if parent != removed {
+				parents = appendIfMissing(parents, parent)
+				continue
+			}
+			for _, grandparent := range dag.parents[removed] {
+				parents = appendIfMissing(parents, grandparent)
+			}
+		}
+		result.parents[block] = parents
+	}
+
+	// The pasts are not affected by dropping redundant parents, since a dropped
+	// parent is still reachable through another parent
+	for block := range result.parents {
+		parents := make([]int, 0, len(result.parents[block]))
+		for _, parent := range result.parents[block] {
+			isRedundant := false
+			for _, otherParent := range result.parents[block] {
+				if otherParent != parent && result.past(otherParent)[parent] {
+					isRedundant = true
+					break
+				}
+			}
+			if !isRedundant {
+				parents = append(parents, parent)
+			}
+		}
+		if len(parents) > maxBlockParents {
+			return nil, false
+		}
+		result.parents[block] = parents
+	}
+
+	blocks := make(map[int]bool, len(result.parents)-1)
+	for block := range result.parents {
+		if block != removed {
+			blocks[block] = true
+		}
+	}
+	return result.keep(blocks), true
+}
+
+func appendIfMissing(slice []int, value int) []int {
+	for _, item := range slice {
+		if item == value {
+			return slice
+		}
+	}
+	return append(slice, value)
+}
+
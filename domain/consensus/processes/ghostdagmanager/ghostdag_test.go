@@ -17,6 +17,7 @@ import (
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/blockheader"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/constants"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/testutils"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/dagconfig"
 	"github.com/karlsend/PYVERT/testfork/karlsend/util/difficulty"
 	"github.com/pkg/errors"
 )
@@ -285,6 +286,73 @@ func TestBlueWork(t *testing.T) {
 	}
 }
 
+// TestGHOSTDAGBlueWithKBluesInItsAnticone checks a DAG in which a blue of the merge set of block 7
+// already has K blues in its anticone, so the block that's added to the blues would make it K+1.
+// The DAG fixtures don't catch this, since their mock DAG topology manager doesn't consider a block
+// its own ancestor, so the DAG is built with a test consensus instead.
+func TestGHOSTDAGBlueWithKBluesInItsAnticone(t *testing.T) {
+	implementationFactories := []implManager{
+		{ghostdagmanager.New, "Original"},
+		{ghostdag2.New, "Tal's impl"},
+	}
+	consensusConfig := &consensus.Config{Params: dagconfig.SimnetParams}
+	consensusConfig.K = 3
+	consensusConfig.SkipProofOfWork = true
+
+	factory := consensus.NewFactory()
+	tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestGHOSTDAGBlueWithKBluesInItsAnticone")
+	if err != nil {
+		t.Fatalf("Error setting up consensus: %+v", err)
+	}
+	defer teardown(false)
+
+	parents := [][]int{nil, {0}, {0}, {1}, {0}, {3}, {4, 2, 3}, {6, 5}}
+	hashes := []*externalapi.DomainHash{consensusConfig.GenesisHash}
+	for block := 1; block < len(parents); block++ {
+		parentHashes := make([]*externalapi.DomainHash, len(parents[block]))
+		for i, parent := range parents[block] {
+			parentHashes[i] = hashes[parent]
+		}
+		blockHash, _, err := tc.AddBlock(parentHashes, nil, nil)
+		if err != nil {
+			t.Fatalf("AddBlock: %+v", err)
+		}
+		hashes = append(hashes, blockHash)
+	}
+
+	stagingArea := model.NewStagingArea()
+	storedGenesisGHOSTDAGData, err := tc.GHOSTDAGDataStore().Get(tc.DatabaseContext(), stagingArea, hashes[0], false)
+	if err != nil {
+		t.Fatalf("GHOSTDAGDataStore().Get: %+v", err)
+	}
+	for _, factory := range implementationFactories {
+		ghostdagDataStore := &GHOSTDAGDataStoreImpl{dagMap: map[externalapi.DomainHash]*externalapi.BlockGHOSTDAGData{
+			*hashes[0]: externalapi.NewBlockGHOSTDAGData(storedGenesisGHOSTDAGData.BlueScore(),
+				storedGenesisGHOSTDAGData.BlueWork(), nil, nil, nil, nil),
+		}}
+		g := factory.function(tc.DatabaseContext(), tc.DAGTopologyManager(), ghostdagDataStore,
+			tc.BlockHeaderStore(), consensusConfig.K, hashes[0])
+		for _, blockHash := range hashes[1:] {
+			err := g.GHOSTDAG(stagingArea, blockHash)
+			if err != nil {
+				t.Fatalf("%s: GHOSTDAG: %+v", factory.implName, err)
+			}
+		}
+
+		// Block 5 is red in the merge set of block 7, since the blue block 2 already has K blues
+		// in its anticone: blocks 1, 3 and 4
+		ghostdagData := ghostdagDataStore.dagMap[*hashes[7]]
+		if ghostdagData.BlueScore() != 6 {
+			t.Fatalf("%s: expected the blue score of block 7 to be 6, but got %d",
+				factory.implName, ghostdagData.BlueScore())
+		}
+		if len(ghostdagData.MergeSetReds()) != 1 || !ghostdagData.MergeSetReds()[0].Equal(hashes[5]) {
+			t.Fatalf("%s: expected block 5 to be the only red in the merge set of block 7, but got %v",
+				factory.implName, ghostdagData.MergeSetReds())
+		}
+	}
+}
+
 func hashesToStrings(arr []*externalapi.DomainHash) []string {
 	var strArr = make([]string, len(arr))
 	for i, hash := range arr {
@@ -0,0 +1,66 @@
+package pruningproofmanager
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/ruleerrors"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
+	"github.com/pkg/errors"
+)
+
+// validateProofCheckpoints validates that the pruning point proof doesn't conflict with any of
+// the checkpoints. Every proof header with the hash of a checkpoint must have its DAA score, and
+// since the checkpoints are in the selected parent chain of the pruning point, a level 0 selected
+// parent chain that passes the DAA score of a checkpoint must pass through the checkpoint itself.
+// Checkpoints below the lowest block of the proof can't be validated against it.
+func (ppm *pruningProofManager) validateProofCheckpoints(stagingArea *model.StagingArea,
+	pruningPointProof *externalapi.PruningPointProof, blockHeaderStore model.BlockHeaderStore,
+	level0GHOSTDAGDataStore model.GHOSTDAGDataStore, pruningPoint *externalapi.DomainHash) error {
+
+	if len(ppm.checkpoints) == 0 {
+		return nil
+	}
+
+	for _, headers := range pruningPointProof.Headers {
+		for _, header := range headers {
+			blockHash := consensushashing.HeaderHash(header)
+			for _, checkpoint := range ppm.checkpoints {
+				if blockHash.Equal(checkpoint.Hash) && header.DAAScore() != checkpoint.DAAScore {
+					return errors.Wrapf(ruleerrors.ErrCheckpointMismatch, "checkpoint %s has a DAA score of %d "+
+						"instead of %d in the pruning point proof", blockHash, header.DAAScore(), checkpoint.DAAScore)
+				}
+			}
+		}
+	}
+
+	isChainAboveCheckpoint := make([]bool, len(ppm.checkpoints))
+	isCheckpointInChain := make([]bool, len(ppm.checkpoints))
+	for current := pruningPoint; !current.Equal(model.VirtualGenesisBlockHash); {
+		header, err := blockHeaderStore.BlockHeader(ppm.databaseContext, stagingArea, current)
+		if err != nil {
+			return err
+		}
+		for i, checkpoint := range ppm.checkpoints {
+			if current.Equal(checkpoint.Hash) {
+				isCheckpointInChain[i] = true
+			}
+			if header.DAAScore() >= checkpoint.DAAScore {
+				isChainAboveCheckpoint[i] = true
+				continue
+			}
+			if isChainAboveCheckpoint[i] && !isCheckpointInChain[i] {
+				return errors.Wrapf(ruleerrors.ErrCheckpointMismatch, "the selected parent chain of the pruning "+
+					"point proof passes the DAA score %d of checkpoint %s without passing through it",
+					checkpoint.DAAScore, checkpoint.Hash)
+			}
+		}
+
+		ghostdagData, err := level0GHOSTDAGDataStore.Get(ppm.databaseContext, stagingArea, current, false)
+		if err != nil {
+			return err
+		}
+		current = ghostdagData.SelectedParent()
+	}
+	return nil
+}
+
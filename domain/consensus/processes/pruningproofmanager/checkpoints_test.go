@@ -0,0 +1,129 @@
+package pruningproofmanager_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/testapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/ruleerrors"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/testutils"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/dagconfig"
+)
+
+func TestPruningPointProofCheckpoints(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		// This is done to reduce the pruning depth to 6 blocks
+		finalityDepth := 5
+		consensusConfig.FinalityDuration = time.Duration(finalityDepth) * consensusConfig.TargetTimePerBlock
+		consensusConfig.K = 0
+		consensusConfig.PruningProofM = 1
+		consensusConfig.Checkpoints = nil
+
+		factory := consensus.NewFactory()
+		tcSyncer, teardownSyncer, err := factory.NewTestConsensus(consensusConfig, "TestPruningPointProofCheckpoints")
+		if err != nil {
+			t.Fatalf("Error setting up tcSyncer: %+v", err)
+		}
+		defer teardownSyncer(false)
+
+		addChain := func(tc testapi.TestConsensus, length uint64,
+			coinbaseData *externalapi.DomainCoinbaseData) []*externalapi.DomainHash {
+
+			chain := make([]*externalapi.DomainHash, 0, length)
+			tipHash := consensusConfig.GenesisHash
+			for i := uint64(0); i < length; i++ {
+				tipHash, _, err = tc.AddBlock([]*externalapi.DomainHash{tipHash}, coinbaseData, nil)
+				if err != nil {
+					t.Fatalf("AddBlock: %+v", err)
+				}
+				chain = append(chain, tipHash)
+			}
+			return chain
+		}
+
+		chainLength := 3 * consensusConfig.PruningDepth()
+		addChain(tcSyncer, chainLength, nil)
+
+		pruningPoint, err := tcSyncer.PruningPoint()
+		if err != nil {
+			t.Fatalf("PruningPoint: %+v", err)
+		}
+		if pruningPoint.Equal(consensusConfig.GenesisHash) {
+			t.Fatalf("Expected the pruning point to move from the genesis")
+		}
+		pruningPointProof, err := tcSyncer.BuildPruningPointProof()
+		if err != nil {
+			t.Fatalf("BuildPruningPointProof: %+v", err)
+		}
+		pruningPointHeader, err := tcSyncer.GetBlockHeader(pruningPoint)
+		if err != nil {
+			t.Fatalf("GetBlockHeader: %+v", err)
+		}
+
+		// A chain of another node, that has a different block with the same DAA score as the pruning point
+		tcOther, teardownOther, err := factory.NewTestConsensus(consensusConfig, "TestPruningPointProofCheckpointsOther")
+		if err != nil {
+			t.Fatalf("Error setting up tcOther: %+v", err)
+		}
+		defer teardownOther(false)
+		otherChain := addChain(tcOther, chainLength, &externalapi.DomainCoinbaseData{
+			ScriptPublicKey: &externalapi.ScriptPublicKey{Script: nil, Version: 0},
+			ExtraData:       []byte("other"),
+		})
+		var conflictingBlock *externalapi.DomainHash
+		for _, blockHash := range otherChain {
+			header, err := tcOther.GetBlockHeader(blockHash)
+			if err != nil {
+				t.Fatalf("GetBlockHeader: %+v", err)
+			}
+			if header.DAAScore() == pruningPointHeader.DAAScore() {
+				conflictingBlock = blockHash
+				break
+			}
+		}
+		if conflictingBlock == nil || conflictingBlock.Equal(pruningPoint) {
+			t.Fatalf("Expected another block with the DAA score %d of the pruning point",
+				pruningPointHeader.DAAScore())
+		}
+
+		validateProofWithCheckpoint := func(name string, checkpoint dagconfig.Checkpoint) error {
+			synceeConfig := *consensusConfig
+			synceeConfig.Checkpoints = []dagconfig.Checkpoint{checkpoint}
+			tcSyncee, teardownSyncee, err := factory.NewTestConsensus(&synceeConfig, name)
+			if err != nil {
+				t.Fatalf("Error setting up tcSyncee: %+v", err)
+			}
+			defer teardownSyncee(false)
+
+			return tcSyncee.ValidatePruningPointProof(pruningPointProof)
+		}
+
+		err = validateProofWithCheckpoint("TestPruningPointProofCheckpointsMatching", dagconfig.Checkpoint{
+			DAAScore: pruningPointHeader.DAAScore(),
+			Hash:     pruningPoint,
+		})
+		if err != nil {
+			t.Fatalf("Expected a proof that matches the checkpoint to be valid, but got: %+v", err)
+		}
+
+		err = validateProofWithCheckpoint("TestPruningPointProofCheckpointsWrongDAAScore", dagconfig.Checkpoint{
+			DAAScore: pruningPointHeader.DAAScore() + 1,
+			Hash:     pruningPoint,
+		})
+		if !errors.Is(err, ruleerrors.ErrCheckpointMismatch) {
+			t.Fatalf("Expected ErrCheckpointMismatch for a checkpoint with a different DAA score, but got: %+v", err)
+		}
+
+		err = validateProofWithCheckpoint("TestPruningPointProofCheckpointsConflicting", dagconfig.Checkpoint{
+			DAAScore: pruningPointHeader.DAAScore(),
+			Hash:     conflictingBlock,
+		})
+		if !errors.Is(err, ruleerrors.ErrCheckpointMismatch) {
+			t.Fatalf("Expected ErrCheckpointMismatch for a checkpoint that isn't in the proof chain, but got: %+v", err)
+		}
+	})
+}
+
@@ -17,6 +17,7 @@ import (
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/ruleerrors"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/hashset"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/dagconfig"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/db/database"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/logger"
 	"github.com/karlsend/PYVERT/testfork/karlsend/util/staging"
@@ -46,6 +47,9 @@ type pruningProofManager struct {
 	k             externalapi.KType
 	pruningProofM uint64
 	maxBlockLevel int
+	checkpoints   []dagconfig.Checkpoint
+
+	fishHashActivationDAAScore uint64
 
 	cachedPruningPoint *externalapi.DomainHash
 	cachedProof        *externalapi.PruningPointProof
@@ -75,6 +79,8 @@ func New(
 	k externalapi.KType,
 	pruningProofM uint64,
 	maxBlockLevel int,
+	checkpoints []dagconfig.Checkpoint,
+	fishHashActivationDAAScore uint64,
 ) model.PruningProofManager {
 
 	return &pruningProofManager{
@@ -99,6 +105,9 @@ func New(
 		k:             k,
 		pruningProofM: pruningProofM,
 		maxBlockLevel: maxBlockLevel,
+		checkpoints:   checkpoints,
+
+		fishHashActivationDAAScore: fishHashActivationDAAScore,
 	}
 }
 
@@ -147,7 +156,7 @@ func (ppm *pruningProofManager) buildPruningPointProof(stagingArea *model.Stagin
 	maxLevel := len(ppm.parentsManager.Parents(pruningPointHeader)) - 1
 	headersByLevel := make(map[int][]externalapi.BlockHeader)
 	selectedTipByLevel := make([]*externalapi.DomainHash, maxLevel+1)
-	pruningPointLevel := pruningPointHeader.BlockLevel(ppm.maxBlockLevel)
+	pruningPointLevel := pruningPointHeader.BlockLevel(ppm.maxBlockLevel, ppm.fishHashActivationDAAScore)
 	for blockLevel := maxLevel; blockLevel >= 0; blockLevel-- {
 		var selectedTip *externalapi.DomainHash
 		if blockLevel <= pruningPointLevel {
@@ -329,7 +338,7 @@ func (ppm *pruningProofManager) ValidatePruningPointProof(pruningPointProof *ext
 	level0Headers := pruningPointProof.Headers[0]
 	pruningPointHeader := level0Headers[len(level0Headers)-1]
 	pruningPoint := consensushashing.HeaderHash(pruningPointHeader)
-	pruningPointBlockLevel := pruningPointHeader.BlockLevel(ppm.maxBlockLevel)
+	pruningPointBlockLevel := pruningPointHeader.BlockLevel(ppm.maxBlockLevel, ppm.fishHashActivationDAAScore)
 	maxLevel := len(ppm.parentsManager.Parents(pruningPointHeader)) - 1
 	if maxLevel >= len(pruningPointProof.Headers) {
 		return errors.Wrapf(ruleerrors.ErrPruningProofEmpty, "proof has only %d levels while pruning point "+
@@ -373,9 +382,9 @@ func (ppm *pruningProofManager) ValidatePruningPointProof(pruningPointProof *ext
 		var selectedTip *externalapi.DomainHash
 		for i, header := range headers {
 			blockHash := consensushashing.HeaderHash(header)
-			if header.BlockLevel(ppm.maxBlockLevel) < blockLevel {
+			if header.BlockLevel(ppm.maxBlockLevel, ppm.fishHashActivationDAAScore) < blockLevel {
 				return errors.Wrapf(ruleerrors.ErrPruningProofWrongBlockLevel, "block %s level is %d when it's "+
-					"expected to be at least %d", blockHash, header.BlockLevel(ppm.maxBlockLevel), blockLevel)
+					"expected to be at least %d", blockHash, header.BlockLevel(ppm.maxBlockLevel, ppm.fishHashActivationDAAScore), blockLevel)
 			}
 
 			blockHeaderStore.Stage(stagingArea, blockHash, header)
@@ -457,6 +466,11 @@ func (ppm *pruningProofManager) ValidatePruningPointProof(pruningPointProof *ext
 		selectedTipByLevel[blockLevel] = selectedTip
 	}
 
+	err = ppm.validateProofCheckpoints(stagingArea, pruningPointProof, blockHeaderStore, ghostdagDataStores[0], pruningPoint)
+	if err != nil {
+		return err
+	}
+
 	currentDAGPruningPoint, err := ppm.pruningStore.PruningPoint(ppm.databaseContext, model.NewStagingArea())
 	if err != nil {
 		return err
@@ -865,9 +879,9 @@ func (ppm *pruningProofManager) ApplyPruningPointProof(pruningPointProof *extern
 			stagingArea := model.NewStagingArea()
 
 			blockHash := consensushashing.HeaderHash(header)
-			if header.BlockLevel(ppm.maxBlockLevel) < blockLevel {
+			if header.BlockLevel(ppm.maxBlockLevel, ppm.fishHashActivationDAAScore) < blockLevel {
 				return errors.Wrapf(ruleerrors.ErrPruningProofWrongBlockLevel, "block %s level is %d when it's "+
-					"expected to be at least %d", blockHash, header.BlockLevel(ppm.maxBlockLevel), blockLevel)
+					"expected to be at least %d", blockHash, header.BlockLevel(ppm.maxBlockLevel, ppm.fishHashActivationDAAScore), blockLevel)
 			}
 
 			ppm.blockHeaderStore.Stage(stagingArea, blockHash, header)
@@ -10,6 +10,7 @@ import (
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/constants"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/transactionhelper"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/txscript"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/workerpool"
 	"github.com/pkg/errors"
 )
 
@@ -68,39 +69,64 @@ func (v *transactionValidator) ValidateTransactionInContextIgnoringUTXO(stagingA
 func (v *transactionValidator) ValidateTransactionInContextAndPopulateFee(stagingArea *model.StagingArea,
 	tx *externalapi.DomainTransaction, povBlockHash *externalapi.DomainHash) error {
 
-	err := v.checkTransactionCoinbaseMaturity(stagingArea, povBlockHash, tx)
-	if err != nil {
-		return err
+	return v.ValidateTransactionsInContextAndPopulateFee(stagingArea, []*externalapi.DomainTransaction{tx}, povBlockHash)
+}
+
+// ValidateTransactionsInContextAndPopulateFee validates the given transactions against their referenced
+// UTXO, and populates their fee fields. The scripts of all the inputs of all the transactions are verified
+// in a single worker pool, but the returned error is the one that validating the transactions one after
+// another would return.
+//
+// Note: if the function fails, there's no guarantee that the transaction fee fields will remain unaffected.
+func (v *transactionValidator) ValidateTransactionsInContextAndPopulateFee(stagingArea *model.StagingArea,
+	txs []*externalapi.DomainTransaction, povBlockHash *externalapi.DomainHash) error {
+
+	// Scripts are verified last, so the transactions after the first one that fails
+	// any other check are never reached
+	failedTransactionCount := len(txs)
+	var failedTransactionErr error
+	for i, tx := range txs {
+		err := v.validateTransactionInContextIgnoringScriptsAndPopulateFee(stagingArea, tx, povBlockHash)
+		if err != nil {
+			failedTransactionCount = i
+			failedTransactionErr = err
+			break
+		}
 	}
 
-	totalSompiIn, err := v.checkTransactionInputAmounts(tx)
+	err := v.validateTransactionsScripts(txs[:failedTransactionCount])
 	if err != nil {
 		return err
 	}
+	return failedTransactionErr
+}
 
-	totalSompiOut, err := v.checkTransactionOutputAmounts(tx, totalSompiIn)
+func (v *transactionValidator) validateTransactionInContextIgnoringScriptsAndPopulateFee(
+	stagingArea *model.StagingArea, tx *externalapi.DomainTransaction, povBlockHash *externalapi.DomainHash) error {
+
+	err := v.checkTransactionCoinbaseMaturity(stagingArea, povBlockHash, tx)
 	if err != nil {
 		return err
 	}
 
-	tx.Fee = totalSompiIn - totalSompiOut
-
-	err = v.checkTransactionSequenceLock(stagingArea, povBlockHash, tx)
+	totalSompiIn, err := v.checkTransactionInputAmounts(tx)
 	if err != nil {
 		return err
 	}
 
-	err = v.validateTransactionSigOpCounts(tx)
+	totalSompiOut, err := v.checkTransactionOutputAmounts(tx, totalSompiIn)
 	if err != nil {
 		return err
 	}
 
-	err = v.validateTransactionScripts(tx)
+	tx.Fee = totalSompiIn - totalSompiOut
+
+	err = v.checkTransactionSequenceLock(stagingArea, povBlockHash, tx)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return v.validateTransactionSigOpCounts(tx)
 }
 
 func (v *transactionValidator) checkTransactionCoinbaseMaturity(stagingArea *model.StagingArea,
@@ -225,42 +251,86 @@ func (v *transactionValidator) checkTransactionSequenceLock(stagingArea *model.S
 	return nil
 }
 
-func (v *transactionValidator) validateTransactionScripts(tx *externalapi.DomainTransaction) error {
-	var missingOutpoints []*externalapi.DomainOutpoint
-	sighashReusedValues := &consensushashing.SighashReusedValues{}
+// transactionInput identifies a single input of one of the transactions passed to validateTransactionsScripts
+type transactionInput struct {
+	transactionIndex int
+	inputIndex       int
+}
 
-	for i, input := range tx.Inputs {
-		// Create a new script engine for the script pair.
-		sigScript := input.SignatureScript
-		utxoEntry := input.UTXOEntry
-		if utxoEntry == nil {
-			missingOutpoints = append(missingOutpoints, &input.PreviousOutpoint)
-			continue
+func (v *transactionValidator) validateTransactionsScripts(txs []*externalapi.DomainTransaction) error {
+	var inputs []transactionInput
+	for transactionIndex, tx := range txs {
+		for inputIndex := range tx.Inputs {
+			inputs = append(inputs, transactionInput{transactionIndex: transactionIndex, inputIndex: inputIndex})
 		}
+	}
 
-		scriptPubKey := utxoEntry.ScriptPublicKey()
-		vm, err := txscript.NewEngine(scriptPubKey, tx, i, txscript.ScriptNoFlags, v.sigCache, v.sigCacheECDSA, sighashReusedValues)
-		if err != nil {
-			return errors.Wrapf(ruleerrors.ErrScriptMalformed, "failed to parse input "+
-				"%d which references output %s - "+
-				"%s (input script bytes %x, prev "+
-				"output script bytes %x)",
-				i,
-				input.PreviousOutpoint, err, sigScript, scriptPubKey)
+	// Script errors take precedence over missing outpoints, and are reported
+	// for the first invalid input by transaction and input index
+	err := workerpool.Run(len(inputs), func() workerpool.WorkFunc {
+		// SighashReusedValues is filled lazily for a single transaction, so every worker
+		// needs its own, and has to replace it whenever it moves to the next transaction.
+		// Inputs are handed out in increasing order, so a worker never returns to a
+		// transaction it has left.
+		var sighashReusedValues *consensushashing.SighashReusedValues
+		lastTransactionIndex := -1
+		return func(index int) error {
+			input := inputs[index]
+			if input.transactionIndex != lastTransactionIndex {
+				sighashReusedValues = &consensushashing.SighashReusedValues{}
+				lastTransactionIndex = input.transactionIndex
+			}
+			return v.validateTransactionScript(txs[input.transactionIndex], input.inputIndex, sighashReusedValues)
 		}
+	})
+	if err != nil {
+		return err
+	}
 
-		// Execute the script pair.
-		if err := vm.Execute(); err != nil {
-			return errors.Wrapf(ruleerrors.ErrScriptValidation, "failed to validate input "+
-				"%d which references output %s - "+
-				"%s (input script bytes %x, prev output "+
-				"script bytes %x)",
-				i,
-				input.PreviousOutpoint, err, sigScript, scriptPubKey)
+	for _, tx := range txs {
+		var missingOutpoints []*externalapi.DomainOutpoint
+		for _, input := range tx.Inputs {
+			if input.UTXOEntry == nil {
+				missingOutpoints = append(missingOutpoints, &input.PreviousOutpoint)
+			}
+		}
+		if len(missingOutpoints) > 0 {
+			return ruleerrors.NewErrMissingTxOut(missingOutpoints)
 		}
 	}
-	if len(missingOutpoints) > 0 {
-		return ruleerrors.NewErrMissingTxOut(missingOutpoints)
+	return nil
+}
+
+func (v *transactionValidator) validateTransactionScript(tx *externalapi.DomainTransaction, inputIndex int,
+	sighashReusedValues *consensushashing.SighashReusedValues) error {
+
+	input := tx.Inputs[inputIndex]
+	utxoEntry := input.UTXOEntry
+	if utxoEntry == nil {
+		return nil
+	}
+
+	// Create a new script engine for the script pair.
+	sigScript := input.SignatureScript
+	scriptPubKey := utxoEntry.ScriptPublicKey()
+	vm, err := txscript.NewEngine(scriptPubKey, tx, inputIndex, txscript.ScriptNoFlags, v.sigCache, v.sigCacheECDSA, sighashReusedValues)
+	if err != nil {
+		return errors.Wrapf(ruleerrors.ErrScriptMalformed, "failed to parse input "+
+			"%d which references output %s - "+
+			"%s (input script bytes %x, prev "+
+			"output script bytes %x)",
+			inputIndex,
+			input.PreviousOutpoint, err, sigScript, scriptPubKey)
+	}
+
+	// Execute the script pair.
+	if err := vm.Execute(); err != nil {
+		return errors.Wrapf(ruleerrors.ErrScriptValidation, "failed to validate input "+
+			"%d which references output %s - "+
+			"%s (input script bytes %x, prev output "+
+			"script bytes %x)",
+			inputIndex,
+			input.PreviousOutpoint, err, sigScript, scriptPubKey)
 	}
 	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/testutils"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/txscript"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/utxo"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/dagconfig"
 	"github.com/karlsend/PYVERT/testfork/karlsend/util"
 	"github.com/kaspanet/go-secp256k1"
 
@@ -519,3 +520,209 @@ func TestSigningTwoInputsECDSA(t *testing.T) {
 	})
 }
 
+func BenchmarkValidateTransactionInContextAndPopulateFee(b *testing.B) {
+	consensusConfig := &consensus.Config{Params: dagconfig.DevnetParams}
+	factory := consensus.NewFactory()
+	tc, tearDown, err := factory.NewTestConsensus(consensusConfig,
+		"BenchmarkValidateTransactionInContextAndPopulateFee")
+	if err != nil {
+		b.Fatalf("Failed create a NewTestConsensus: %s", err)
+	}
+	defer tearDown(false)
+
+	privateKey, err := secp256k1.GenerateSchnorrKeyPair()
+	if err != nil {
+		b.Fatalf("Failed to generate a private key: %v", err)
+	}
+	publicKey, err := privateKey.SchnorrPublicKey()
+	if err != nil {
+		b.Fatalf("Failed to generate a public key: %v", err)
+	}
+	publicKeySerialized, err := publicKey.Serialize()
+	if err != nil {
+		b.Fatalf("Failed to serialize public key: %v", err)
+	}
+	addr, err := util.NewAddressPublicKey(publicKeySerialized[:], consensusConfig.Prefix)
+	if err != nil {
+		b.Fatalf("Failed to generate p2pk address: %v", err)
+	}
+	scriptPublicKey, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		b.Fatalf("PayToAddrScript: unexpected error: %v", err)
+	}
+
+	const inputCount = 100
+	inputs := make([]*externalapi.DomainTransactionInput, inputCount)
+	for i := range inputs {
+		inputs[i] = &externalapi.DomainTransactionInput{
+			PreviousOutpoint: externalapi.DomainOutpoint{Index: uint32(i)},
+			Sequence:         constants.MaxTxInSequenceNum,
+			SigOpCount:       1,
+			UTXOEntry:        utxo.NewUTXOEntry(100_000_000, scriptPublicKey, false, 0),
+		}
+	}
+	tx := &externalapi.DomainTransaction{
+		Version:      constants.MaxTransactionVersion,
+		Inputs:       inputs,
+		Outputs:      []*externalapi.DomainTransactionOutput{{ScriptPublicKey: scriptPublicKey}},
+		SubnetworkID: subnetworks.SubnetworkIDNative,
+	}
+
+	stagingArea := model.NewStagingArea()
+	povBlockHash := externalapi.NewDomainHashFromByteArray(&[32]byte{0x01})
+	tc.DAABlocksStore().StageDAAScore(stagingArea, povBlockHash, consensusConfig.BlockCoinbaseMaturity)
+	tc.GHOSTDAGDataStore().Stage(stagingArea, povBlockHash, externalapi.NewBlockGHOSTDAGData(
+		0,
+		nil,
+		consensusConfig.GenesisHash,
+		nil,
+		nil,
+		nil), false)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Change the transaction and re-sign it, so that the signature cache
+		// doesn't skip the verification
+		b.StopTimer()
+		tx.Outputs[0].Value = uint64(i) + 1
+		sighashReusedValues := &consensushashing.SighashReusedValues{}
+		for inputIndex, input := range tx.Inputs {
+			input.SignatureScript, err = txscript.SignatureScript(tx, inputIndex, consensushashing.SigHashAll, privateKey,
+				sighashReusedValues)
+			if err != nil {
+				b.Fatalf("Failed to create a sigScript: %v", err)
+			}
+		}
+		b.StartTimer()
+
+		err = tc.TransactionValidator().ValidateTransactionInContextAndPopulateFee(stagingArea, tx, povBlockHash)
+		if err != nil {
+			b.Fatalf("ValidateTransactionInContextAndPopulateFee: %+v", err)
+		}
+	}
+}
+
+func TestValidateTransactionsInContextAndPopulateFee(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		factory := consensus.NewFactory()
+		tc, tearDown, err := factory.NewTestConsensus(consensusConfig,
+			"TestValidateTransactionsInContextAndPopulateFee")
+		if err != nil {
+			t.Fatalf("Failed create a NewTestConsensus: %s", err)
+		}
+		defer tearDown(false)
+
+		privateKey, err := secp256k1.GenerateSchnorrKeyPair()
+		if err != nil {
+			t.Fatalf("Failed to generate a private key: %v", err)
+		}
+		publicKey, err := privateKey.SchnorrPublicKey()
+		if err != nil {
+			t.Fatalf("Failed to generate a public key: %v", err)
+		}
+		publicKeySerialized, err := publicKey.Serialize()
+		if err != nil {
+			t.Fatalf("Failed to serialize public key: %v", err)
+		}
+		addr, err := util.NewAddressPublicKey(publicKeySerialized[:], consensusConfig.Prefix)
+		if err != nil {
+			t.Fatalf("Failed to generate p2pk address: %v", err)
+		}
+		scriptPublicKey, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			t.Fatalf("PayToAddrScript: unexpected error: %v", err)
+		}
+
+		// newSignedTransaction returns a transaction with three inputs of 100 sompi each,
+		// that's signed before its output value is set
+		const inputCount = 3
+		newSignedTransaction := func(index uint32, signedOutputValue uint64, outputValue uint64) *externalapi.DomainTransaction {
+			inputs := make([]*externalapi.DomainTransactionInput, inputCount)
+			for i := range inputs {
+				inputs[i] = &externalapi.DomainTransactionInput{
+					PreviousOutpoint: externalapi.DomainOutpoint{
+						TransactionID: *externalapi.NewDomainTransactionIDFromByteArray(&[32]byte{byte(index)}),
+						Index:         uint32(i),
+					},
+					Sequence:   constants.MaxTxInSequenceNum,
+					SigOpCount: 1,
+					UTXOEntry:  utxo.NewUTXOEntry(100, scriptPublicKey, false, 0),
+				}
+			}
+			tx := &externalapi.DomainTransaction{
+				Version:      constants.MaxTransactionVersion,
+				Inputs:       inputs,
+				Outputs:      []*externalapi.DomainTransactionOutput{{Value: signedOutputValue, ScriptPublicKey: scriptPublicKey}},
+				SubnetworkID: subnetworks.SubnetworkIDNative,
+			}
+			sighashReusedValues := &consensushashing.SighashReusedValues{}
+			for inputIndex, input := range tx.Inputs {
+				input.SignatureScript, err = txscript.SignatureScript(tx, inputIndex, consensushashing.SigHashAll,
+					privateKey, sighashReusedValues)
+				if err != nil {
+					t.Fatalf("Failed to create a sigScript: %v", err)
+				}
+			}
+			tx.Outputs[0].Value = outputValue
+			return tx
+		}
+
+		stagingArea := model.NewStagingArea()
+		povBlockHash := externalapi.NewDomainHashFromByteArray(&[32]byte{0x01})
+		tc.DAABlocksStore().StageDAAScore(stagingArea, povBlockHash, consensusConfig.BlockCoinbaseMaturity)
+		tc.GHOSTDAGDataStore().Stage(stagingArea, povBlockHash, externalapi.NewBlockGHOSTDAGData(
+			0,
+			nil,
+			consensusConfig.GenesisHash,
+			nil,
+			nil,
+			nil), false)
+
+		valid := func(index uint32) *externalapi.DomainTransaction {
+			return newSignedTransaction(index, 100+uint64(index), 100+uint64(index))
+		}
+		badSignature := func(index uint32) *externalapi.DomainTransaction {
+			return newSignedTransaction(index, 100, 101)
+		}
+		spendTooHigh := func(index uint32) *externalapi.DomainTransaction {
+			return newSignedTransaction(index, 1000, 1000)
+		}
+
+		tests := []struct {
+			name          string
+			txs           []*externalapi.DomainTransaction
+			expectedError error
+		}{
+			{
+				name: "all transactions are valid",
+				txs:  []*externalapi.DomainTransaction{valid(0), valid(1), valid(2), valid(3)},
+			},
+			{
+				name:          "a script error precedes an error of a later transaction",
+				txs:           []*externalapi.DomainTransaction{valid(0), badSignature(1), spendTooHigh(2)},
+				expectedError: ruleerrors.ErrScriptValidation,
+			},
+			{
+				name:          "an error precedes a script error of a later transaction",
+				txs:           []*externalapi.DomainTransaction{valid(0), spendTooHigh(1), badSignature(2)},
+				expectedError: ruleerrors.ErrSpendTooHigh,
+			},
+		}
+		for _, test := range tests {
+			err := tc.TransactionValidator().ValidateTransactionsInContextAndPopulateFee(stagingArea, test.txs, povBlockHash)
+			if !errors.Is(err, test.expectedError) {
+				t.Fatalf("%s: expected error %v, but got %+v", test.name, test.expectedError, err)
+			}
+			if test.expectedError != nil {
+				continue
+			}
+			for i, tx := range test.txs {
+				expectedFee := uint64(inputCount*100 - 100 - i)
+				if tx.Fee != expectedFee {
+					t.Fatalf("%s: expected the fee of transaction %d to be %d, but got %d", test.name, i, expectedFee, tx.Fee)
+				}
+			}
+		}
+	})
+}
+
@@ -244,6 +244,10 @@ var (
 	ErrCoinbaseWithInputs                             = newRuleError("ErrCoinbaseWithInputs")
 	ErrCoinbaseTooManyOutputs                         = newRuleError("ErrCoinbaseTooManyOutputs")
 	ErrCoinbaseTooLongScriptPublicKey                 = newRuleError("ErrCoinbaseTooLongScriptPublicKey")
+
+	// ErrCheckpointMismatch indicates that a block header or a pruning point proof
+	// conflicts with one of the checkpoints of the network.
+	ErrCheckpointMismatch = newRuleError("ErrCheckpointMismatch")
 )
 
 // RuleError identifies a rule violation. It is used to indicate that
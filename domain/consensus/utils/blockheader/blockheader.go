@@ -23,6 +23,10 @@ type blockHeader struct {
 
 	isBlockLevelCached bool
 	blockLevel         int
+	// The block level depends on the arguments it was calculated with, so they're
+	// cached along with it
+	blockLevelMaxBlockLevel              int
+	blockLevelFishHashActivationDAAScore uint64
 }
 
 func (bh *blockHeader) BlueScore() uint64 {
@@ -185,9 +189,12 @@ func (bh *blockHeader) ToMutable() externalapi.MutableBlockHeader {
 	return bh.clone()
 }
 
-func (bh *blockHeader) BlockLevel(maxBlockLevel int) int {
-	if !bh.isBlockLevelCached {
-		bh.blockLevel = pow.BlockLevel(bh, maxBlockLevel)
+func (bh *blockHeader) BlockLevel(maxBlockLevel int, fishHashActivationDAAScore uint64) int {
+	if !bh.isBlockLevelCached || bh.blockLevelMaxBlockLevel != maxBlockLevel ||
+		bh.blockLevelFishHashActivationDAAScore != fishHashActivationDAAScore {
+		bh.blockLevel = pow.BlockLevel(bh, maxBlockLevel, fishHashActivationDAAScore)
+		bh.blockLevelMaxBlockLevel = maxBlockLevel
+		bh.blockLevelFishHashActivationDAAScore = fishHashActivationDAAScore
 		bh.isBlockLevelCached = true
 	}
 
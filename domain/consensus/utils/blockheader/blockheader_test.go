@@ -1,10 +1,12 @@
 package blockheader
 
 import (
+	"math"
 	"math/big"
 	"testing"
 
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/pow"
 )
 
 func TestDomainBlockHeader_Equal(t *testing.T) {
@@ -39,6 +41,8 @@ func TestDomainBlockHeader_Equal(t *testing.T) {
 						externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{10}),
 						false,
 						0,
+						0,
+						0,
 					},
 					expectedResult: false,
 				},
@@ -60,6 +64,8 @@ func TestDomainBlockHeader_Equal(t *testing.T) {
 				externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{11}),
 				false,
 				0,
+				0,
+				0,
 			},
 			headersToCompareTo: []headerToCompare{
 				{
@@ -82,6 +88,8 @@ func TestDomainBlockHeader_Equal(t *testing.T) {
 						externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{11}),
 						false,
 						0,
+						0,
+						0,
 					},
 					expectedResult: true,
 				},
@@ -101,6 +109,8 @@ func TestDomainBlockHeader_Equal(t *testing.T) {
 						externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{11}),
 						false,
 						0,
+						0,
+						0,
 					},
 					expectedResult: false,
 				},
@@ -122,6 +132,8 @@ func TestDomainBlockHeader_Equal(t *testing.T) {
 						externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{11}),
 						false,
 						0,
+						0,
+						0,
 					},
 					expectedResult: false,
 				},
@@ -141,6 +153,8 @@ func TestDomainBlockHeader_Equal(t *testing.T) {
 						externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{11}),
 						false,
 						0,
+						0,
+						0,
 					},
 					expectedResult: false,
 				},
@@ -160,6 +174,8 @@ func TestDomainBlockHeader_Equal(t *testing.T) {
 						externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{11}),
 						false,
 						0,
+						0,
+						0,
 					},
 					expectedResult: false,
 				},
@@ -179,6 +195,8 @@ func TestDomainBlockHeader_Equal(t *testing.T) {
 						externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{11}),
 						false,
 						0,
+						0,
+						0,
 					},
 					expectedResult: false,
 				},
@@ -198,6 +216,8 @@ func TestDomainBlockHeader_Equal(t *testing.T) {
 						externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{11}),
 						false,
 						0,
+						0,
+						0,
 					},
 					expectedResult: false,
 				},
@@ -217,6 +237,8 @@ func TestDomainBlockHeader_Equal(t *testing.T) {
 						externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{11}),
 						false,
 						0,
+						0,
+						0,
 					},
 					expectedResult: false,
 				},
@@ -236,6 +258,8 @@ func TestDomainBlockHeader_Equal(t *testing.T) {
 						externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{11}),
 						false,
 						0,
+						0,
+						0,
 					},
 					expectedResult: false,
 				},
@@ -255,6 +279,8 @@ func TestDomainBlockHeader_Equal(t *testing.T) {
 						externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{11}),
 						false,
 						0,
+						0,
+						0,
 					},
 					expectedResult: false,
 				},
@@ -274,6 +300,8 @@ func TestDomainBlockHeader_Equal(t *testing.T) {
 						externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{11}),
 						false,
 						0,
+						0,
+						0,
 					},
 					expectedResult: false,
 				},
@@ -293,6 +321,8 @@ func TestDomainBlockHeader_Equal(t *testing.T) {
 						externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{11}),
 						false,
 						0,
+						0,
+						0,
 					},
 					expectedResult: false,
 				},
@@ -312,6 +342,8 @@ func TestDomainBlockHeader_Equal(t *testing.T) {
 						externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{11}),
 						false,
 						0,
+						0,
+						0,
 					},
 					expectedResult: false,
 				},
@@ -331,6 +363,8 @@ func TestDomainBlockHeader_Equal(t *testing.T) {
 						externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{100}),
 						false,
 						0,
+						0,
+						0,
 					},
 					expectedResult: false,
 				},
@@ -353,3 +387,41 @@ func TestDomainBlockHeader_Equal(t *testing.T) {
 	}
 }
 
+func TestBlockLevelArguments(t *testing.T) {
+	const maxBlockLevel = 300
+	parents := []externalapi.BlockLevelParents{{externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{1})}}
+	newHeader := func(nonce uint64) externalapi.BlockHeader {
+		return NewImmutableBlockHeader(0, parents, &externalapi.DomainHash{}, &externalapi.DomainHash{},
+			&externalapi.DomainHash{}, 0, 0x207fffff, nonce, 5, 0, big.NewInt(0), &externalapi.DomainHash{})
+	}
+
+	// Find a header whose level differs between kHeavyHash and FishHash
+	var header externalapi.BlockHeader
+	for nonce := uint64(0); ; nonce++ {
+		if nonce == 100 {
+			t.Fatalf("Couldn't find a header whose level differs between kHeavyHash and FishHash")
+		}
+		header = newHeader(nonce)
+		if pow.BlockLevel(header, maxBlockLevel, 0) != pow.BlockLevel(header, maxBlockLevel, math.MaxUint64) {
+			break
+		}
+	}
+
+	tests := []struct {
+		maxBlockLevel              int
+		fishHashActivationDAAScore uint64
+	}{
+		{maxBlockLevel: maxBlockLevel, fishHashActivationDAAScore: math.MaxUint64},
+		{maxBlockLevel: maxBlockLevel, fishHashActivationDAAScore: 0},
+		{maxBlockLevel: maxBlockLevel + 10, fishHashActivationDAAScore: 0},
+		{maxBlockLevel: maxBlockLevel, fishHashActivationDAAScore: math.MaxUint64},
+	}
+	for i, test := range tests {
+		expected := pow.BlockLevel(header, test.maxBlockLevel, test.fishHashActivationDAAScore)
+		level := header.BlockLevel(test.maxBlockLevel, test.fishHashActivationDAAScore)
+		if level != expected {
+			t.Fatalf("Test #%d: Expected block level %d but got %d", i, expected, level)
+		}
+	}
+}
+
@@ -10,9 +10,9 @@ import (
 )
 
 // SolveBlock increments the given block's nonce until it matches the difficulty requirements in its bits field
-func SolveBlock(block *externalapi.DomainBlock, rd *rand.Rand) {
+func SolveBlock(block *externalapi.DomainBlock, rd *rand.Rand, fishHashActivationDAAScore uint64) {
 	header := block.Header.ToMutable()
-	state := pow.NewState(header)
+	state := pow.NewState(header, fishHashActivationDAAScore)
 	for state.Nonce = rd.Uint64(); state.Nonce < math.MaxUint64; state.Nonce++ {
 		if state.CheckProofOfWork() {
 			header.SetNonce(state.Nonce)
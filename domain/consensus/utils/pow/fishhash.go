@@ -0,0 +1,254 @@
+package pow
+
+import (
+	"encoding/binary"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"golang.org/x/crypto/sha3"
+	"lukechampine.com/blake3"
+)
+
+const (
+	// fishHashLightCacheNumItems is the number of 64 byte items in the light cache
+	fishHashLightCacheNumItems = 1179641
+	// fishHashFullDatasetNumItems is the number of 128 byte items in the full dataset
+	fishHashFullDatasetNumItems = 37748717
+	// fishHashFullDatasetItemParents is the number of light cache items that are mixed
+	// into each half of a full dataset item
+	fishHashFullDatasetItemParents = 512
+	// fishHashNumDatasetAccesses is the number of mixing rounds of the FishHash kernel,
+	// each of them accessing three full dataset items
+	fishHashNumDatasetAccesses = 32
+	// fishHashLightCacheRounds is the number of RandMemoHash rounds used to build the light cache
+	fishHashLightCacheRounds = 3
+
+	fnvPrime = 0x01000193
+)
+
+// fishHashSeed is the seed the light cache is generated from
+var fishHashSeed = [32]byte{
+	0xeb, 0x01, 0x63, 0xae, 0xf2, 0xab, 0x1c, 0x5a, 0x66, 0x31, 0x0c, 0x1c, 0x14, 0xd6, 0x0f, 0x42,
+	0x55, 0xa9, 0xb3, 0x9b, 0x0e, 0xdf, 0x26, 0x53, 0x98, 0x44, 0xf1, 0x17, 0xad, 0x67, 0x21, 0x19,
+}
+
+// hash512 and hash1024 hold their bytes as little endian 32 bit words
+type hash512 [16]uint32
+type hash1024 [32]uint32
+
+func (h *hash512) bytes() []byte {
+	bytes := make([]byte, 64)
+	for i, word := range h {
+		binary.LittleEndian.PutUint32(bytes[i*4:], word)
+	}
+	return bytes
+}
+
+func newHash512(bytes []byte) hash512 {
+	var result hash512
+	for i := range result {
+		result[i] = binary.LittleEndian.Uint32(bytes[i*4:])
+	}
+	return result
+}
+
+func keccak512(data []byte) hash512 {
+	hasher := sha3.NewLegacyKeccak512()
+	// This write can never return an error, this is part of the hash.Hash interface contract.
+	hasher.Write(data)
+	return newHash512(hasher.Sum(nil))
+}
+
+func fnv1(u, v uint32) uint32 {
+	return (u * fnvPrime) ^ v
+}
+
+// fishHashContext holds the light cache of FishHash, and optionally its full dataset.
+// Dataset items that are missing are calculated from the light cache on demand.
+type fishHashContext struct {
+	lightCache          []hash512
+	fullDatasetNumItems uint32
+
+	fullDataset        []hash1024
+	fullDatasetOnce    sync.Once
+	isFullDatasetReady uint32
+}
+
+var sharedFishHashContext *fishHashContext
+var sharedFishHashContextOnce sync.Once
+
+// getSharedFishHashContext returns the FishHash context used for proof of work
+// calculations, building its light cache on first use
+func getSharedFishHashContext() *fishHashContext {
+	sharedFishHashContextOnce.Do(func() {
+		sharedFishHashContext = newFishHashContext(fishHashLightCacheNumItems, fishHashFullDatasetNumItems)
+	})
+	return sharedFishHashContext
+}
+
+// PrepareFishHashFullDataset generates the full FishHash dataset (about 4.8GB of memory),
+// which makes FishHash calculations much faster than with the light cache alone.
+// It's meant for miners, and takes a while to complete.
+func PrepareFishHashFullDataset() {
+	getSharedFishHashContext().prepareFullDataset()
+}
+
+func newFishHashContext(lightCacheNumItems int, fullDatasetNumItems uint32) *fishHashContext {
+	return &fishHashContext{
+		lightCache:          buildLightCache(lightCacheNumItems),
+		fullDatasetNumItems: fullDatasetNumItems,
+	}
+}
+
+func buildLightCache(numItems int) []hash512 {
+	lightCache := make([]hash512, numItems)
+	lightCache[0] = keccak512(fishHashSeed[:])
+	for i := 1; i < numItems; i++ {
+		lightCache[i] = keccak512(lightCache[i-1].bytes())
+	}
+
+	for round := 0; round < fishHashLightCacheRounds; round++ {
+		for i := 0; i < numItems; i++ {
+			v := lightCache[i][0] % uint32(numItems)
+			w := (numItems + i - 1) % numItems
+			var x hash512
+			for j := range x {
+				x[j] = lightCache[v][j] ^ lightCache[w][j]
+			}
+			lightCache[i] = keccak512(x.bytes())
+		}
+	}
+	return lightCache
+}
+
+func (ctx *fishHashContext) prepareFullDataset() {
+	ctx.fullDatasetOnce.Do(func() {
+		fullDataset := make([]hash1024, ctx.fullDatasetNumItems)
+		numThreads := uint32(runtime.NumCPU())
+		itemsPerThread := (ctx.fullDatasetNumItems + numThreads - 1) / numThreads
+
+		waitGroup := sync.WaitGroup{}
+		for start := uint32(0); start < ctx.fullDatasetNumItems; start += itemsPerThread {
+			end := start + itemsPerThread
+			if end > ctx.fullDatasetNumItems {
+				end = ctx.fullDatasetNumItems
+			}
+			waitGroup.Add(1)
+			go func(start, end uint32) {
+				defer waitGroup.Done()
+				for i := start; i < end; i++ {
+					fullDataset[i] = ctx.calculateDatasetItem(i)
+				}
+			}(start, end)
+		}
+		waitGroup.Wait()
+
+		ctx.fullDataset = fullDataset
+		atomic.StoreUint32(&ctx.isFullDatasetReady, 1)
+	})
+}
+
+func (ctx *fishHashContext) lookup(index uint32) hash1024 {
+	if atomic.LoadUint32(&ctx.isFullDatasetReady) == 1 {
+		return ctx.fullDataset[index]
+	}
+	return ctx.calculateDatasetItem(index)
+}
+
+// calculateDatasetItem calculates a full dataset item out of the light cache. Each of
+// its halves is the result of mixing pseudo-randomly selected light cache items.
+func (ctx *fishHashContext) calculateDatasetItem(index uint32) hash1024 {
+	item0 := ctx.newDatasetItemState(uint64(index) * 2)
+	item1 := ctx.newDatasetItemState(uint64(index)*2 + 1)
+	for round := uint32(0); round < fishHashFullDatasetItemParents; round++ {
+		item0.update(round)
+		item1.update(round)
+	}
+
+	var item hash1024
+	final0, final1 := item0.final(), item1.final()
+	copy(item[:16], final0[:])
+	copy(item[16:], final1[:])
+	return item
+}
+
+type datasetItemState struct {
+	lightCache []hash512
+	seed       uint32
+	mix        hash512
+}
+
+func (ctx *fishHashContext) newDatasetItemState(index uint64) *datasetItemState {
+	mix := ctx.lightCache[index%uint64(len(ctx.lightCache))]
+	mix[0] ^= uint32(index)
+	return &datasetItemState{
+		lightCache: ctx.lightCache,
+		seed:       uint32(index),
+		mix:        keccak512(mix.bytes()),
+	}
+}
+
+func (state *datasetItemState) update(round uint32) {
+	t := fnv1(state.seed^round, state.mix[round%uint32(len(state.mix))])
+	parent := &state.lightCache[t%uint32(len(state.lightCache))]
+	for i := range state.mix {
+		state.mix[i] = fnv1(state.mix[i], parent[i])
+	}
+}
+
+func (state *datasetItemState) final() hash512 {
+	return keccak512(state.mix.bytes())
+}
+
+// kernel mixes the seed with pseudo-randomly selected full dataset items, and collapses
+// the result into 32 bytes
+func (ctx *fishHashContext) kernel(seed hash512) [32]byte {
+	var mix hash1024
+	copy(mix[:16], seed[:])
+	copy(mix[16:], seed[:])
+
+	for i := 0; i < fishHashNumDatasetAccesses; i++ {
+		fetch0 := ctx.lookup(mix[0] % ctx.fullDatasetNumItems)
+		fetch1 := ctx.lookup(mix[4] % ctx.fullDatasetNumItems)
+		fetch2 := ctx.lookup(mix[8] % ctx.fullDatasetNumItems)
+
+		for j := range mix {
+			fetch1[j] = fnv1(mix[j], fetch1[j])
+			fetch2[j] = mix[j] ^ fetch2[j]
+		}
+
+		for j := 0; j < len(mix); j += 2 {
+			word0 := uint64(fetch0[j]) | uint64(fetch0[j+1])<<32
+			word1 := uint64(fetch1[j]) | uint64(fetch1[j+1])<<32
+			word2 := uint64(fetch2[j]) | uint64(fetch2[j+1])<<32
+			newWord := word0*word1 + word2
+			mix[j] = uint32(newWord)
+			mix[j+1] = uint32(newWord >> 32)
+		}
+	}
+
+	var mixHash [32]byte
+	for i := 0; i < len(mix); i += 4 {
+		h1 := fnv1(mix[i], mix[i+1])
+		h2 := fnv1(h1, mix[i+2])
+		h3 := fnv1(h2, mix[i+3])
+		binary.LittleEndian.PutUint32(mixHash[i:], h3)
+	}
+	return mixHash
+}
+
+// fishHash calculates the FishHash of the given hash. As in the FishHash reference, the
+// seed of the kernel is the 64 byte blake3 of the input, and the result is the blake3 of
+// the seed followed by the mix hash of the kernel.
+func fishHash(ctx *fishHashContext, hash *externalapi.DomainHash) *externalapi.DomainHash {
+	seedBytes := blake3.Sum512(hash.ByteSlice())
+	seed := newHash512(seedBytes[:])
+	mixHash := ctx.kernel(seed)
+
+	finalData := append(seedBytes[:], mixHash[:]...)
+	finalHash := blake3.Sum256(finalData)
+	return externalapi.NewDomainHashFromByteArray(&finalHash)
+}
+
@@ -0,0 +1,101 @@
+package pow
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+)
+
+func TestKeccak512(t *testing.T) {
+	// FishHash uses the original Keccak padding rather than the one of SHA3-512
+	const expected = "0eab42de4c3ceb9235fc91acffe746b29c29a8c366b7c60e4e67c466f36a4304" +
+		"c00fa9caf9d87976ba469bcbe06713b435f091ef2769fb160cdab33d3670680e"
+	hash := keccak512(nil)
+	if hex.EncodeToString(hash.bytes()) != expected {
+		t.Fatalf("Unexpected keccak512 of an empty input: %x", hash.bytes())
+	}
+}
+
+func TestFishHash(t *testing.T) {
+	ctx := getSharedFishHashContext()
+
+	// These vectors pin the light cache and the light verification path. A change in
+	// any of them changes the proof of work of every FishHash block. They were produced
+	// by this implementation, and have yet to be checked against the vectors published
+	// with the FishHash reference.
+	const expectedFirstLightCacheItem = "e070091affe5df638e918fdcee7dd589bf474f96712867bea28410a5d3d0d120" +
+		"cc16c36651a224461a94ffd24d5c7c96c848978bbf51424214b9b5927a2758f8"
+	const expectedLastLightCacheItem = "0e61b858d98ffe1aa095fd324eece8d78268bad77d5a9ad5bf3c406de0d54b96" +
+		"9832c6f076df77b7c4d73515bf0032e5264649700e1defa9edb7a09b4ed317ed"
+	if hex.EncodeToString(ctx.lightCache[0].bytes()) != expectedFirstLightCacheItem {
+		t.Fatalf("Unexpected first light cache item: %x", ctx.lightCache[0].bytes())
+	}
+	lastLightCacheItem := ctx.lightCache[len(ctx.lightCache)-1]
+	if hex.EncodeToString(lastLightCacheItem.bytes()) != expectedLastLightCacheItem {
+		t.Fatalf("Unexpected last light cache item: %x", lastLightCacheItem.bytes())
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    "0000000000000000000000000000000000000000000000000000000000000000",
+			expected: "c045c582a4c357e1504d242a558cff7117cc8bba1f638a6fa3546095e493c096",
+		},
+		{
+			input:    "0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20",
+			expected: "6c27188762687773cf45c5863f2caee271694c21a483ec09f5cfd10c150dbea7",
+		},
+		{
+			input:    "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			expected: "818cfb8817d1832a0684c798c0de4cb1bfe4dbc816b19e60b7ae6b7f8bfc751b",
+		},
+	}
+	for _, test := range tests {
+		input, err := externalapi.NewDomainHashFromString(test.input)
+		if err != nil {
+			t.Fatalf("NewDomainHashFromString: %+v", err)
+		}
+		result := fishHash(ctx, input)
+		if result.String() != test.expected {
+			t.Fatalf("Unexpected FishHash of %s: expected %s but got %s", test.input, test.expected, result)
+		}
+	}
+}
+
+func TestFishHashFullDataset(t *testing.T) {
+	// Small sizes are used in order to keep the full dataset generation short
+	const lightCacheNumItems = 1021
+	const fullDatasetNumItems = 4099
+	lightContext := newFishHashContext(lightCacheNumItems, fullDatasetNumItems)
+	fullContext := newFishHashContext(lightCacheNumItems, fullDatasetNumItems)
+	fullContext.prepareFullDataset()
+
+	for i := uint32(0); i < fullDatasetNumItems; i += 97 {
+		if fullContext.lookup(i) != lightContext.lookup(i) {
+			t.Fatalf("Full dataset item %d is different than the one calculated from the light cache", i)
+		}
+	}
+
+	for i := byte(0); i < 10; i++ {
+		input := externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{i})
+		fullResult := fishHash(fullContext, input)
+		lightResult := fishHash(lightContext, input)
+		if !fullResult.Equal(lightResult) {
+			t.Fatalf("The FishHash of %s is %s with the full dataset but %s with the light cache",
+				input, fullResult, lightResult)
+		}
+	}
+}
+
+func BenchmarkFishHashLight(b *testing.B) {
+	ctx := getSharedFishHashContext()
+	hash := &externalapi.DomainHash{}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		hash = fishHash(ctx, hash)
+	}
+}
+
@@ -2,15 +2,52 @@ package pow
 
 import (
 	"math"
+	"sync"
 
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/hashes"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/lrucache"
 )
 
 const eps float64 = 1e-9
 
+// matrixCacheSize is the number of matrices kept in matrixCache. Headers are
+// validated more than once, and miners build a new state for every template
+// of the same block, so a small cache saves most of the matrix generations.
+// Note that once the cache is full, lrucache evicts a random matrix rather than
+// the least recently used one. Since the same pre-PoW hashes are requested in
+// short bursts, that's good enough.
+const matrixCacheSize = 128
+
+var matrixCache = lrucache.New(matrixCacheSize, true)
+var matrixCacheLock sync.Mutex
+
 type matrix [64][64]uint16
 
+// packedMatrix holds a matrix with four rows packed into every uint64, in 16 bit lanes,
+// so that HeavyHash multiplies four rows with every multiplication. The lanes never
+// carry into each other, since the products of 4 bit matrices and vectors are at most
+// 64*15*15 = 14400.
+type packedMatrix [16][64]uint64
+
+// getPackedMatrix returns the packed matrix of the given pre-PoW hash, generating
+// it only if it's not in matrixCache
+func getPackedMatrix(prePowHash *externalapi.DomainHash) *packedMatrix {
+	matrixCacheLock.Lock()
+	cachedMatrix, ok := matrixCache.Get(prePowHash)
+	matrixCacheLock.Unlock()
+	if ok {
+		return cachedMatrix.(*packedMatrix)
+	}
+
+	packed := generateMatrix(prePowHash).pack()
+
+	matrixCacheLock.Lock()
+	defer matrixCacheLock.Unlock()
+	matrixCache.Add(prePowHash, packed)
+	return packed
+}
+
 func generateMatrix(hash *externalapi.DomainHash) *matrix {
 	var mat matrix
 	generator := newxoShiRo256PlusPlus(hash)
@@ -63,27 +100,48 @@ func (mat *matrix) computeRank() int {
 	return rank
 }
 
+// pack returns the packed form of the matrix, which must have only 4 bit elements
+func (mat *matrix) pack() *packedMatrix {
+	var packed packedMatrix
+	for i := range mat {
+		for j := range mat[i] {
+			packed[i/4][j] |= uint64(mat[i][j]) << (16 * (i % 4))
+		}
+	}
+	return &packed
+}
+
+// HeavyHash multiplies the matrix by the given hash, and hashes the result
 func (mat *matrix) HeavyHash(hash *externalapi.DomainHash) *externalapi.DomainHash {
+	return mat.pack().HeavyHash(hash)
+}
+
+// HeavyHash multiplies the matrix by the given hash, and hashes the result
+func (mat *packedMatrix) HeavyHash(hash *externalapi.DomainHash) *externalapi.DomainHash {
 	hashBytes := hash.ByteArray()
-	var vector [64]uint16
-	var product [64]uint16
+	var vector [64]uint64
 	for i := 0; i < 32; i++ {
-		vector[2*i] = uint16(hashBytes[i] >> 4)
-		vector[2*i+1] = uint16(hashBytes[i] & 0x0F)
+		vector[2*i] = uint64(hashBytes[i] >> 4)
+		vector[2*i+1] = uint64(hashBytes[i] & 0x0F)
 	}
-	// Matrix-vector multiplication, and convert to 4 bits.
-	for i := 0; i < 64; i++ {
-		var sum uint16
-		for j := 0; j < 64; j++ {
-			sum += mat[i][j] * vector[j]
+
+	// Matrix-vector multiplication of four rows at a time, and convert to 4 bits.
+	var product [64]byte
+	for i := range mat {
+		var sums uint64
+		rows := &mat[i]
+		for j := range vector {
+			sums += rows[j] * vector[j]
+		}
+		for lane := 0; lane < 4; lane++ {
+			product[4*i+lane] = byte(uint16(sums>>(16*lane)) >> 10)
 		}
-		product[i] = sum >> 10
 	}
 
 	// Concatenate 4 LSBs back to 8 bit xor with sum1
 	var res [32]byte
 	for i := range res {
-		res[i] = hashBytes[i] ^ (byte(product[2*i]<<4) | byte(product[2*i+1]))
+		res[i] = hashBytes[i] ^ (product[2*i]<<4 | product[2*i+1])
 	}
 	// Hash again
 	writer := hashes.NewHeavyHashWriter()
@@ -43,6 +43,17 @@ func BenchmarkMatrix_Rank(b *testing.B) {
 	}
 }
 
+func BenchmarkPackedMatrix_HeavyHash(b *testing.B) {
+	input := []byte("BenchmarkMatrix_HeavyHash")
+	writer := hashes.NewPoWHashWriter()
+	writer.InfallibleWrite(input)
+	hash := writer.Finalize()
+	matrix := generateMatrix(hash).pack()
+	for i := 0; i < b.N; i++ {
+		hash = matrix.HeavyHash(hash)
+	}
+}
+
 func TestMatrix_Rank(t *testing.T) {
 	var mat matrix
 	if mat.computeRank() != 0 {
@@ -74,6 +85,126 @@ func TestMatrix_Rank(t *testing.T) {
 	}
 }
 
+// randomMatrix returns a matrix of random 4 bit elements, regardless of its rank
+func randomMatrix(r *rand.Rand) *matrix {
+	var mat matrix
+	for i := range mat {
+		for j := range mat[i] {
+			mat[i][j] = uint16(r.Intn(16))
+		}
+	}
+	return &mat
+}
+
+// heavyHashReference is a straightforward implementation of HeavyHash
+// that the optimized one is tested against
+func heavyHashReference(mat *matrix, hash *externalapi.DomainHash) *externalapi.DomainHash {
+	hashBytes := hash.ByteArray()
+	var vector [64]uint16
+	var product [64]uint16
+	for i := 0; i < 32; i++ {
+		vector[2*i] = uint16(hashBytes[i] >> 4)
+		vector[2*i+1] = uint16(hashBytes[i] & 0x0F)
+	}
+	for i := 0; i < 64; i++ {
+		var sum uint16
+		for j := 0; j < 64; j++ {
+			sum += mat[i][j] * vector[j]
+		}
+		product[i] = sum >> 10
+	}
+
+	var res [32]byte
+	for i := range res {
+		res[i] = hashBytes[i] ^ (byte(product[2*i]<<4) | byte(product[2*i+1]))
+	}
+	writer := hashes.NewHeavyHashWriter()
+	writer.InfallibleWrite(res[:])
+	return writer.Finalize()
+}
+
+func TestPackedMatrix_HeavyHash(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	var maxMatrix matrix
+	for i := range maxMatrix {
+		for j := range maxMatrix[i] {
+			maxMatrix[i][j] = 0x0F
+		}
+	}
+	matrices := []*matrix{&maxMatrix, &testMatrix}
+	for i := 0; i < 100; i++ {
+		matrices = append(matrices, randomMatrix(r))
+	}
+
+	// Every byte value is tested in every position of the hash, as well as the hash
+	// of maximal nibbles that maximizes the products of maxMatrix
+	hashes := []*externalapi.DomainHash{
+		externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{
+			0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+			0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}),
+	}
+	for position := 0; position < externalapi.DomainHashSize; position++ {
+		for value := 0; value < 256; value++ {
+			var hashBytes [externalapi.DomainHashSize]byte
+			r.Read(hashBytes[:])
+			hashBytes[position] = byte(value)
+			hashes = append(hashes, externalapi.NewDomainHashFromByteArray(&hashBytes))
+		}
+	}
+
+	for i, mat := range matrices {
+		packed := mat.pack()
+		for _, hash := range hashes {
+			expected := heavyHashReference(mat, hash)
+			if !packed.HeavyHash(hash).Equal(expected) {
+				t.Fatalf("Packed HeavyHash of matrix %d and hash %s doesn't match the reference implementation", i, hash)
+			}
+			if i < 4 && !mat.HeavyHash(hash).Equal(expected) {
+				t.Fatalf("HeavyHash of matrix %d and hash %s doesn't match the reference implementation", i, hash)
+			}
+		}
+	}
+}
+
+func TestGetPackedMatrix(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	hashes := make([]*externalapi.DomainHash, matrixCacheSize*2)
+	for i := range hashes {
+		var hashBytes [externalapi.DomainHashSize]byte
+		r.Read(hashBytes[:])
+		hashes[i] = externalapi.NewDomainHashFromByteArray(&hashBytes)
+	}
+
+	// The first pass misses the cache for every hash and fills it beyond its capacity,
+	// and the second pass runs into both matrices that are still cached and ones that
+	// have been evicted
+	for pass := 0; pass < 2; pass++ {
+		hits, misses := 0, 0
+		for _, hash := range hashes {
+			matrixCacheLock.Lock()
+			isCached := matrixCache.Has(hash)
+			matrixCacheLock.Unlock()
+			if isCached {
+				hits++
+			} else {
+				misses++
+			}
+
+			expected := generateMatrix(hash).pack()
+			if *getPackedMatrix(hash) != *expected {
+				t.Fatalf("getPackedMatrix() of hash %s doesn't match generateMatrix() (cached: %t)", hash, isCached)
+			}
+		}
+		if pass == 0 && hits != 0 {
+			t.Fatalf("Expected the first pass to miss the cache every time, but it hit it %d times", hits)
+		}
+		if pass == 1 && (hits == 0 || misses == 0) {
+			t.Fatalf("Expected the second pass to both hit and miss the cache, but got %d hits and %d misses",
+				hits, misses)
+		}
+	}
+}
+
 func TestGenerateMatrix(t *testing.T) {
 	hashBytes := [32]byte{42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42}
 	hash := externalapi.NewDomainHashFromByteArray(&hashBytes)
@@ -14,16 +14,18 @@ import (
 
 // State is an intermediate data structure with pre-computed values to speed up mining.
 type State struct {
-	mat        matrix
+	mat        *packedMatrix
 	Timestamp  int64
 	Nonce      uint64
 	Target     big.Int
 	prePowHash externalapi.DomainHash
+	isFishHash bool
 }
 
 // NewState creates a new state with pre-computed values to speed up mining
-// It takes the target from the Bits field
-func NewState(header externalapi.MutableBlockHeader) *State {
+// It takes the target from the Bits field, and uses FishHash instead of kHeavyHash
+// if the DAA score of the header is at least fishHashActivationDAAScore
+func NewState(header externalapi.MutableBlockHeader, fishHashActivationDAAScore uint64) *State {
 	target := difficulty.CompactToBig(header.Bits())
 	// Zero out the time and nonce.
 	timestamp, nonce := header.TimeInMilliseconds(), header.Nonce()
@@ -33,13 +35,17 @@ func NewState(header externalapi.MutableBlockHeader) *State {
 	header.SetTimeInMilliseconds(timestamp)
 	header.SetNonce(nonce)
 
-	return &State{
+	state := &State{
 		Target:     *target,
 		prePowHash: *prePowHash,
-		mat:        *generateMatrix(prePowHash),
 		Timestamp:  timestamp,
 		Nonce:      nonce,
+		isFishHash: header.DAAScore() >= fishHashActivationDAAScore,
 	}
+	if !state.isFishHash {
+		state.mat = getPackedMatrix(prePowHash)
+	}
+	return state
 }
 
 // CalculateProofOfWorkValue hashes the internal header and returns its big.Int value
@@ -58,10 +64,20 @@ func (state *State) CalculateProofOfWorkValue() *big.Int {
 		panic(errors.Wrap(err, "this should never happen. Hash digest should never return an error"))
 	}
 	powHash := writer.Finalize()
+	if state.isFishHash {
+		return toBig(fishHash(getSharedFishHashContext(), powHash))
+	}
 	heavyHash := state.mat.HeavyHash(powHash)
 	return toBig(heavyHash)
 }
 
+// PrePowHash returns the hash of the header with its timestamp and nonce zeroed out,
+// which is the part of the proof of work input that doesn't change while mining
+func (state *State) PrePowHash() *externalapi.DomainHash {
+	prePowHash := state.prePowHash
+	return &prePowHash
+}
+
 // IncrementNonce the nonce in State by 1
 func (state *State) IncrementNonce() {
 	state.Nonce++
@@ -79,8 +95,8 @@ func (state *State) CheckProofOfWork() bool {
 
 // CheckProofOfWorkByBits check's if the block has a valid PoW according to its Bits field
 // it does not check if the difficulty itself is valid or less than the maximum for the appropriate network
-func CheckProofOfWorkByBits(header externalapi.MutableBlockHeader) bool {
-	return NewState(header).CheckProofOfWork()
+func CheckProofOfWorkByBits(header externalapi.MutableBlockHeader, fishHashActivationDAAScore uint64) bool {
+	return NewState(header, fishHashActivationDAAScore).CheckProofOfWork()
 }
 
 // ToBig converts a externalapi.DomainHash into a big.Int treated as a little endian string.
@@ -96,14 +112,14 @@ func toBig(hash *externalapi.DomainHash) *big.Int {
 }
 
 // BlockLevel returns the block level of the given header.
-func BlockLevel(header externalapi.BlockHeader, maxBlockLevel int) int {
+func BlockLevel(header externalapi.BlockHeader, maxBlockLevel int, fishHashActivationDAAScore uint64) int {
 	// Genesis is defined to be the root of all blocks at all levels, so we define it to be the maximal
 	// block level.
 	if len(header.DirectParents()) == 0 {
 		return maxBlockLevel
 	}
 
-	proofOfWorkValue := NewState(header.ToMutable()).CalculateProofOfWorkValue()
+	proofOfWorkValue := NewState(header.ToMutable(), fishHashActivationDAAScore).CalculateProofOfWorkValue()
 	level := maxBlockLevel - proofOfWorkValue.BitLen()
 	// If the block has a level lower than genesis make it zero.
 	if level < 0 {
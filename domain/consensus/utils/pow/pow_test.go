@@ -0,0 +1,41 @@
+package pow_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/blockheader"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/pow"
+)
+
+func TestFishHashActivation(t *testing.T) {
+	const daaScore = 1000
+	header := blockheader.NewImmutableBlockHeader(
+		0,
+		[]externalapi.BlockLevelParents{[]*externalapi.DomainHash{
+			externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{1})}},
+		externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{2}),
+		externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{3}),
+		externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{4}),
+		5,
+		0x207fffff,
+		6,
+		daaScore,
+		7,
+		big.NewInt(8),
+		externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{9}),
+	)
+
+	kHeavyHashValue := pow.NewState(header.ToMutable(), daaScore+1).CalculateProofOfWorkValue()
+	fishHashValue := pow.NewState(header.ToMutable(), daaScore).CalculateProofOfWorkValue()
+	if kHeavyHashValue.Cmp(fishHashValue) == 0 {
+		t.Fatalf("Expected the proof of work value to change once FishHash is activated")
+	}
+
+	fishHashValueActivatedEarlier := pow.NewState(header.ToMutable(), 0).CalculateProofOfWorkValue()
+	if fishHashValue.Cmp(fishHashValueActivatedEarlier) != 0 {
+		t.Fatalf("Expected the same proof of work value regardless of how early FishHash was activated")
+	}
+}
+
@@ -52,6 +52,17 @@ func (t ScriptClass) String() string {
 	return scriptClassToName[t]
 }
 
+// ScriptClassFromName returns the script class whose human-readable name, as
+// returned by String, is the given one. It returns false if there's no such class.
+func ScriptClassFromName(name string) (ScriptClass, bool) {
+	for class, className := range scriptClassToName {
+		if className == name {
+			return ScriptClass(class), true
+		}
+	}
+	return 0, false
+}
+
 // isPayToPubkey returns true if the script passed is a pay-to-pubkey
 // transaction, false otherwise.
 func isPayToPubkey(pops []parsedOpcode) bool {
@@ -568,3 +568,22 @@ func TestStringifyClass(t *testing.T) {
 	}
 }
 
+// TestScriptClassFromName ensures that ScriptClassFromName is the inverse of
+// ScriptClass.String for all the script classes.
+func TestScriptClassFromName(t *testing.T) {
+	t.Parallel()
+
+	for _, class := range []ScriptClass{NonStandardTy, PubKeyTy, PubKeyECDSATy, ScriptHashTy} {
+		parsedClass, ok := ScriptClassFromName(class.String())
+		if !ok || parsedClass != class {
+			t.Errorf("ScriptClassFromName(%q): got (%s, %t), want (%s, true)",
+				class.String(), parsedClass, ok, class)
+		}
+	}
+
+	_, ok := ScriptClassFromName("Invalid")
+	if ok {
+		t.Errorf("ScriptClassFromName: unexpectedly parsed an invalid script class name")
+	}
+}
+
@@ -0,0 +1,86 @@
+// Package workerpool fans out independent validation tasks over a bounded
+// pool of goroutines, while keeping the reported error deterministic.
+package workerpool
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// WorkFunc processes the task with the given index
+type WorkFunc func(index int) error
+
+// Size returns the maximum amount of goroutines that Run uses
+func Size() int {
+	return runtime.GOMAXPROCS(0)
+}
+
+// Run calls a WorkFunc for every index in [0, taskCount), using up to
+// Size() goroutines. newWorker is called once per goroutine, so that state
+// that isn't safe for concurrent use can be kept per worker.
+//
+// If any of the tasks fail, Run returns the error of the task with the
+// lowest index, regardless of the order in which the tasks were processed.
+// Tasks with a higher index than a failed task might not be processed at all.
+func Run(taskCount int, newWorker func() WorkFunc) error {
+	workerCount := Size()
+	if workerCount > taskCount {
+		workerCount = taskCount
+	}
+
+	if workerCount <= 1 {
+		work := newWorker()
+		for i := 0; i < taskCount; i++ {
+			err := work(i)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	errs := make([]error, taskCount)
+	nextIndex := int64(-1)
+	lowestFailedIndex := int64(taskCount)
+
+	waitGroup := sync.WaitGroup{}
+	waitGroup.Add(workerCount)
+	for i := 0; i < workerCount; i++ {
+		go func() {
+			defer waitGroup.Done()
+
+			work := newWorker()
+			for {
+				// Indexes are handed out in increasing order, so once an index is
+				// above a failed one, all the indexes after it are as well
+				index := atomic.AddInt64(&nextIndex, 1)
+				if index >= int64(taskCount) || index > atomic.LoadInt64(&lowestFailedIndex) {
+					return
+				}
+
+				err := work(int(index))
+				if err != nil {
+					errs[index] = err
+					setIfLower(&lowestFailedIndex, index)
+				}
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	if lowestFailedIndex < int64(taskCount) {
+		return errs[lowestFailedIndex]
+	}
+	return nil
+}
+
+func setIfLower(value *int64, candidate int64) {
+	for {
+		current := atomic.LoadInt64(value)
+		if candidate >= current || atomic.CompareAndSwapInt64(value, current, candidate) {
+			return
+		}
+	}
+}
+
@@ -0,0 +1,52 @@
+package workerpool
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	for _, taskCount := range []int{0, 1, 2, 10, 1000} {
+		processed := make([]int32, taskCount)
+		workerCount := int32(0)
+		err := Run(taskCount, func() WorkFunc {
+			atomic.AddInt32(&workerCount, 1)
+			return func(index int) error {
+				atomic.AddInt32(&processed[index], 1)
+				return nil
+			}
+		})
+		if err != nil {
+			t.Fatalf("Run: %s", err)
+		}
+		for i, count := range processed {
+			if count != 1 {
+				t.Fatalf("task %d out of %d was processed %d times", i, taskCount, count)
+			}
+		}
+		if int(workerCount) > Size() {
+			t.Fatalf("expected at most %d workers but got %d", Size(), workerCount)
+		}
+	}
+}
+
+func TestRunReturnsLowestFailedIndex(t *testing.T) {
+	const taskCount = 1000
+	failingIndexes := map[int]struct{}{17: {}, 18: {}, 500: {}, 999: {}}
+
+	for i := 0; i < 100; i++ {
+		err := Run(taskCount, func() WorkFunc {
+			return func(index int) error {
+				if _, ok := failingIndexes[index]; ok {
+					return fmt.Errorf("task %d failed", index)
+				}
+				return nil
+			}
+		})
+		if err == nil || err.Error() != "task 17 failed" {
+			t.Fatalf("expected the error of task 17 but got: %v", err)
+		}
+	}
+}
+
@@ -0,0 +1,44 @@
+package dagconfig
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/pkg/errors"
+)
+
+// Checkpoint identifies a block in the selected parent chain of the network by
+// its DAA score and hash.
+// Every block whose DAA score is at least a finality depth above the DAA score of
+// a checkpoint must have the checkpoint in its selected parent chain, and headers
+// or pruning point proofs that conflict with a checkpoint are rejected.
+type Checkpoint struct {
+	DAAScore uint64
+	Hash     *externalapi.DomainHash
+}
+
+// mainnetCheckpoints are the hard-coded checkpoints of the main network
+var mainnetCheckpoints = []Checkpoint{}
+
+// testnetCheckpoints are the hard-coded checkpoints of the test network
+var testnetCheckpoints = []Checkpoint{}
+
+// ParseCheckpoint parses a checkpoint in the form <DAA score>:<block hash>
+func ParseCheckpoint(checkpointString string) (Checkpoint, error) {
+	parts := strings.Split(checkpointString, ":")
+	if len(parts) != 2 {
+		return Checkpoint{}, errors.Errorf("checkpoint %s is not in the form <DAA score>:<block hash>",
+			checkpointString)
+	}
+	daaScore, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return Checkpoint{}, errors.Wrapf(err, "invalid DAA score in checkpoint %s", checkpointString)
+	}
+	hash, err := externalapi.NewDomainHashFromString(parts[1])
+	if err != nil {
+		return Checkpoint{}, errors.Wrapf(err, "invalid block hash in checkpoint %s", checkpointString)
+	}
+	return Checkpoint{DAAScore: daaScore, Hash: hash}, nil
+}
+
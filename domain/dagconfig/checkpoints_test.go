@@ -0,0 +1,32 @@
+package dagconfig
+
+import (
+	"testing"
+)
+
+func TestParseCheckpoint(t *testing.T) {
+	const hashString = "0101010101010101010101010101010101010101010101010101010101010101"
+	checkpoint, err := ParseCheckpoint("1234:" + hashString)
+	if err != nil {
+		t.Fatalf("ParseCheckpoint: %+v", err)
+	}
+	if checkpoint.DAAScore != 1234 || checkpoint.Hash.String() != hashString {
+		t.Fatalf("Unexpected checkpoint %d:%s", checkpoint.DAAScore, checkpoint.Hash)
+	}
+
+	invalidCheckpoints := []string{
+		"",
+		"1234",
+		hashString,
+		"-1:" + hashString,
+		"1234:banana",
+		"1234:" + hashString + ":1",
+	}
+	for _, checkpointString := range invalidCheckpoints {
+		_, err := ParseCheckpoint(checkpointString)
+		if err == nil {
+			t.Fatalf("Expected an error for checkpoint %q", checkpointString)
+		}
+	}
+}
+
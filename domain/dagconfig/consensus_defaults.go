@@ -1,6 +1,7 @@
 package dagconfig
 
 import (
+	"math"
 	"time"
 
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/constants"
@@ -85,5 +86,9 @@ const (
 	defaultDeflationaryPhaseDaaScore = 15778800 - 259200
 
 	defaultMergeDepth = 3600
+
+	// defaultFishHashActivationDAAScore is the DAA score from which KarlsenHashv2 (FishHash)
+	// replaces kHeavyHash as the proof of work. It's not scheduled to activate yet.
+	defaultFishHashActivationDAAScore = math.MaxUint64
 )
 
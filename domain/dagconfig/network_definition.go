@@ -0,0 +1,315 @@
+package dagconfig
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/blockheader"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/merkle"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/subnetworks"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/transactionhelper"
+	"github.com/karlsend/PYVERT/testfork/karlsend/util"
+	"github.com/karlsend/PYVERT/testfork/karlsend/util/difficulty"
+	"github.com/kaspanet/go-muhash"
+	"github.com/pkg/errors"
+)
+
+// NetworkDefinition is the JSON definition of a custom network, for running private
+// networks that don't share the genesis, name, ports, address prefix or seeds of any
+// of the standard networks.
+// Consensus parameters that are omitted take their devnet values.
+type NetworkDefinition struct {
+	Name        string            `json:"name"`
+	Net         uint32            `json:"net"`
+	Prefix      string            `json:"prefix"`
+	RPCPort     string            `json:"rpcPort,omitempty"`
+	DefaultPort string            `json:"defaultPort,omitempty"`
+	DNSSeeds    []string          `json:"dnsSeeds,omitempty"`
+	GRPCSeeds   []string          `json:"grpcSeeds,omitempty"`
+	Genesis     GenesisDefinition `json:"genesis"`
+
+	K                                       *externalapi.KType `json:"k,omitempty"`
+	MaxBlockParents                         *externalapi.KType `json:"maxBlockParents,omitempty"`
+	MergeSetSizeLimit                       *uint64            `json:"mergeSetSizeLimit,omitempty"`
+	MaxBlockMass                            *uint64            `json:"maxBlockMass,omitempty"`
+	MaxCoinbasePayloadLength                *uint64            `json:"maxCoinbasePayloadLength,omitempty"`
+	MassPerTxByte                           *uint64            `json:"massPerTxByte,omitempty"`
+	MassPerScriptPubKeyByte                 *uint64            `json:"massPerScriptPubKeyByte,omitempty"`
+	MassPerSigOp                            *uint64            `json:"massPerSigOp,omitempty"`
+	CoinbasePayloadScriptPublicKeyMaxLength *uint8             `json:"coinbasePayloadScriptPublicKeyMaxLength,omitempty"`
+	PowMax                                  *string            `json:"powMax,omitempty"`
+	BlockCoinbaseMaturity                   *uint64            `json:"blockCoinbaseMaturity,omitempty"`
+	SubsidyGenesisReward                    *uint64            `json:"subsidyGenesisReward,omitempty"`
+	PreDeflationaryPhaseBaseSubsidy         *uint64            `json:"preDeflationaryPhaseBaseSubsidy,omitempty"`
+	DeflationaryPhaseBaseSubsidy            *uint64            `json:"deflationaryPhaseBaseSubsidy,omitempty"`
+	DeflationaryPhaseDAAScore               *uint64            `json:"deflationaryPhaseDaaScore,omitempty"`
+	TargetTimePerBlockInMilliSeconds        *int64             `json:"targetTimePerBlockInMilliSeconds,omitempty"`
+	FinalityDuration                        *int64             `json:"finalityDuration,omitempty"`
+	TimestampDeviationTolerance             *int               `json:"timestampDeviationTolerance,omitempty"`
+	DifficultyAdjustmentWindowSize          *int               `json:"difficultyAdjustmentWindowSize,omitempty"`
+	RuleChangeActivationThreshold           *uint64            `json:"ruleChangeActivationThreshold,omitempty"`
+	MinerConfirmationWindow                 *uint64            `json:"minerConfirmationWindow,omitempty"`
+	RelayNonStdTxs                          *bool              `json:"relayNonStdTxs,omitempty"`
+	AcceptUnroutable                        *bool              `json:"acceptUnroutable,omitempty"`
+	PrivateKeyID                            *byte              `json:"privateKeyId,omitempty"`
+	EnableNonNativeSubnetworks              *bool              `json:"enableNonNativeSubnetworks,omitempty"`
+	DisableDifficultyAdjustment             *bool              `json:"disableDifficultyAdjustment,omitempty"`
+	SkipProofOfWork                         *bool              `json:"skipProofOfWork,omitempty"`
+	PruningProofM                           *uint64            `json:"pruningProofM,omitempty"`
+	DisallowDirectBlocksOnTopOfGenesis      *bool              `json:"disallowDirectBlocksOnTopOfGenesis,omitempty"`
+	MaxBlockLevel                           *int               `json:"maxBlockLevel,omitempty"`
+	MergeDepth                              *uint64            `json:"mergeDepth,omitempty"`
+	FishHashActivationDAAScore              *uint64            `json:"fishHashActivationDaaScore,omitempty"`
+	FishHashActivationBits                  *uint32            `json:"fishHashActivationBits,omitempty"`
+}
+
+// GenesisDefinition defines the genesis block of a custom network
+type GenesisDefinition struct {
+	// CoinbasePayload is the hex encoded payload of the genesis coinbase transaction
+	CoinbasePayload    string `json:"coinbasePayload"`
+	TimeInMilliseconds int64  `json:"timeInMilliseconds"`
+	// Bits defaults to the bits of the devnet genesis if it's omitted
+	Bits  uint32 `json:"bits,omitempty"`
+	Nonce uint64 `json:"nonce"`
+	// Hash is the expected hash of the genesis block. Nodes that load a definition
+	// whose genesis block doesn't hash to it refuse to start, so that all the nodes
+	// that share a definition are guaranteed to agree on the genesis hash.
+	Hash string `json:"hash,omitempty"`
+}
+
+// ReadNetworkDefinition reads a network definition from the given JSON file
+func ReadNetworkDefinition(path string) (*NetworkDefinition, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	// Misspelled parameters would otherwise silently take their devnet values
+	decoder.DisallowUnknownFields()
+	definition := &NetworkDefinition{}
+	err = decoder.Decode(definition)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing the network definition in %s", path)
+	}
+	return definition, nil
+}
+
+// NewGenesisBlock returns a genesis block with a coinbase transaction with the given payload
+func NewGenesisBlock(coinbasePayload []byte, timeInMilliseconds int64, bits uint32, nonce uint64) *externalapi.DomainBlock {
+	coinbaseTransaction := transactionhelper.NewSubnetworkTransaction(0,
+		[]*externalapi.DomainTransactionInput{}, []*externalapi.DomainTransactionOutput{},
+		&subnetworks.SubnetworkIDCoinbase, 0, coinbasePayload)
+	transactions := []*externalapi.DomainTransaction{coinbaseTransaction}
+
+	return &externalapi.DomainBlock{
+		Header: blockheader.NewImmutableBlockHeader(
+			0,
+			[]externalapi.BlockLevelParents{},
+			merkle.CalculateHashMerkleRoot(transactions),
+			&externalapi.DomainHash{},
+			externalapi.NewDomainHashFromByteArray(muhash.EmptyMuHashHash.AsArray()),
+			timeInMilliseconds,
+			bits,
+			nonce,
+			0,
+			0,
+			big.NewInt(0),
+			&externalapi.DomainHash{},
+		),
+		Transactions: transactions,
+	}
+}
+
+// Params returns the network parameters of the definition. If the definition specifies
+// a genesis hash, it's verified to be the hash of the defined genesis block.
+// The address prefix of the network is registered by this function, but the network
+// itself has to be registered with Register by the caller.
+func (definition *NetworkDefinition) Params() (*Params, error) {
+	if definition.Name == "" {
+		return nil, errors.New("the network definition is missing a name")
+	}
+	if definition.Net == 0 {
+		return nil, errors.New("the network definition is missing a net")
+	}
+	prefix, err := util.RegisterPrefix(definition.Prefix)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid address prefix in the network definition")
+	}
+
+	params := DevnetParams
+	params.Name = definition.Name
+	params.Net = appmessage.KaspaNet(definition.Net)
+	params.Prefix = prefix
+	params.DNSSeeds = definition.DNSSeeds
+	params.GRPCSeeds = definition.GRPCSeeds
+	params.Checkpoints = nil
+	if definition.RPCPort != "" {
+		params.RPCPort = definition.RPCPort
+	}
+	if definition.DefaultPort != "" {
+		params.DefaultPort = definition.DefaultPort
+	}
+
+	err = definition.applyConsensusParams(&params)
+	if err != nil {
+		return nil, err
+	}
+
+	coinbasePayload, err := hex.DecodeString(definition.Genesis.CoinbasePayload)
+	if err != nil {
+		return nil, errors.Wrap(err, "the genesis coinbase payload is not valid hex")
+	}
+	if uint64(len(coinbasePayload)) > params.MaxCoinbasePayloadLength {
+		return nil, errors.Errorf("the genesis coinbase payload is %d bytes long, which is longer than "+
+			"the max of %d", len(coinbasePayload), params.MaxCoinbasePayloadLength)
+	}
+	bits := definition.Genesis.Bits
+	if bits == 0 {
+		bits = devnetGenesisBlock.Header.Bits()
+	}
+	genesisTarget := difficulty.CompactToBig(bits)
+	if genesisTarget.Cmp(params.PowMax) > 0 {
+		return nil, errors.Errorf("genesis's target (%s) is larger than powMax (%s)", genesisTarget.Text(16),
+			params.PowMax.Text(16))
+	}
+	if definition.FishHashActivationBits == nil {
+		params.FishHashActivationBits = bits
+	}
+
+	params.GenesisBlock = NewGenesisBlock(coinbasePayload, definition.Genesis.TimeInMilliseconds, bits,
+		definition.Genesis.Nonce)
+	params.GenesisHash = consensushashing.BlockHash(params.GenesisBlock)
+
+	if definition.Genesis.Hash != "" {
+		expectedGenesisHash, err := externalapi.NewDomainHashFromString(definition.Genesis.Hash)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid genesis hash in the network definition")
+		}
+		if !params.GenesisHash.Equal(expectedGenesisHash) {
+			return nil, errors.Errorf("the genesis block of the network definition hashes to %s instead "+
+				"of the expected %s", params.GenesisHash, expectedGenesisHash)
+		}
+	}
+
+	return &params, nil
+}
+
+func (definition *NetworkDefinition) applyConsensusParams(params *Params) error {
+	if definition.K != nil {
+		params.K = *definition.K
+	}
+	if definition.MaxBlockParents != nil {
+		params.MaxBlockParents = *definition.MaxBlockParents
+	}
+	if definition.MergeSetSizeLimit != nil {
+		params.MergeSetSizeLimit = *definition.MergeSetSizeLimit
+	}
+	if definition.MaxBlockMass != nil {
+		params.MaxBlockMass = *definition.MaxBlockMass
+	}
+	if definition.MaxCoinbasePayloadLength != nil {
+		params.MaxCoinbasePayloadLength = *definition.MaxCoinbasePayloadLength
+	}
+	if definition.MassPerTxByte != nil {
+		params.MassPerTxByte = *definition.MassPerTxByte
+	}
+	if definition.MassPerScriptPubKeyByte != nil {
+		params.MassPerScriptPubKeyByte = *definition.MassPerScriptPubKeyByte
+	}
+	if definition.MassPerSigOp != nil {
+		params.MassPerSigOp = *definition.MassPerSigOp
+	}
+	if definition.CoinbasePayloadScriptPublicKeyMaxLength != nil {
+		params.CoinbasePayloadScriptPublicKeyMaxLength = *definition.CoinbasePayloadScriptPublicKeyMaxLength
+	}
+	if definition.PowMax != nil {
+		powMax, ok := big.NewInt(0).SetString(*definition.PowMax, 16)
+		if !ok {
+			return errors.Errorf("couldn't convert %s to big int", *definition.PowMax)
+		}
+		params.PowMax = powMax
+	}
+	if definition.BlockCoinbaseMaturity != nil {
+		params.BlockCoinbaseMaturity = *definition.BlockCoinbaseMaturity
+	}
+	if definition.SubsidyGenesisReward != nil {
+		params.SubsidyGenesisReward = *definition.SubsidyGenesisReward
+	}
+	if definition.PreDeflationaryPhaseBaseSubsidy != nil {
+		params.PreDeflationaryPhaseBaseSubsidy = *definition.PreDeflationaryPhaseBaseSubsidy
+	}
+	if definition.DeflationaryPhaseBaseSubsidy != nil {
+		params.DeflationaryPhaseBaseSubsidy = *definition.DeflationaryPhaseBaseSubsidy
+	}
+	if definition.DeflationaryPhaseDAAScore != nil {
+		params.DeflationaryPhaseDaaScore = *definition.DeflationaryPhaseDAAScore
+	}
+	if definition.TargetTimePerBlockInMilliSeconds != nil {
+		params.TargetTimePerBlock = time.Duration(*definition.TargetTimePerBlockInMilliSeconds) * time.Millisecond
+	}
+	if definition.FinalityDuration != nil {
+		params.FinalityDuration = time.Duration(*definition.FinalityDuration) * time.Millisecond
+	}
+	if params.TargetTimePerBlock <= 0 || params.FinalityDuration < params.TargetTimePerBlock {
+		return errors.Errorf("the finality duration (%s) must be at least the target time per block (%s), "+
+			"which must be positive", params.FinalityDuration, params.TargetTimePerBlock)
+	}
+	if definition.TimestampDeviationTolerance != nil {
+		params.TimestampDeviationTolerance = *definition.TimestampDeviationTolerance
+	}
+	if definition.DifficultyAdjustmentWindowSize != nil {
+		params.DifficultyAdjustmentWindowSize = *definition.DifficultyAdjustmentWindowSize
+	}
+	if definition.RuleChangeActivationThreshold != nil {
+		params.RuleChangeActivationThreshold = *definition.RuleChangeActivationThreshold
+	}
+	if definition.MinerConfirmationWindow != nil {
+		params.MinerConfirmationWindow = *definition.MinerConfirmationWindow
+	}
+	if definition.RelayNonStdTxs != nil {
+		params.RelayNonStdTxs = *definition.RelayNonStdTxs
+	}
+	if definition.AcceptUnroutable != nil {
+		params.AcceptUnroutable = *definition.AcceptUnroutable
+	}
+	if definition.PrivateKeyID != nil {
+		params.PrivateKeyID = *definition.PrivateKeyID
+	}
+	if definition.EnableNonNativeSubnetworks != nil {
+		params.EnableNonNativeSubnetworks = *definition.EnableNonNativeSubnetworks
+	}
+	if definition.DisableDifficultyAdjustment != nil {
+		params.DisableDifficultyAdjustment = *definition.DisableDifficultyAdjustment
+	}
+	if definition.SkipProofOfWork != nil {
+		params.SkipProofOfWork = *definition.SkipProofOfWork
+	}
+	if definition.PruningProofM != nil {
+		params.PruningProofM = *definition.PruningProofM
+	}
+	if definition.DisallowDirectBlocksOnTopOfGenesis != nil {
+		params.DisallowDirectBlocksOnTopOfGenesis = *definition.DisallowDirectBlocksOnTopOfGenesis
+	}
+	if definition.MaxBlockLevel != nil {
+		params.MaxBlockLevel = *definition.MaxBlockLevel
+	}
+	if definition.MergeDepth != nil {
+		params.MergeDepth = *definition.MergeDepth
+	}
+	if definition.FishHashActivationDAAScore != nil {
+		params.FishHashActivationDAAScore = *definition.FishHashActivationDAAScore
+	}
+	if definition.FishHashActivationBits != nil {
+		params.FishHashActivationBits = *definition.FishHashActivationBits
+	}
+	return nil
+}
+
@@ -0,0 +1,157 @@
+package dagconfig
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
+)
+
+func TestNewGenesisBlock(t *testing.T) {
+	devnetHeader := devnetGenesisBlock.Header
+	genesis := NewGenesisBlock(devnetGenesisTxPayload, devnetHeader.TimeInMilliseconds(), devnetHeader.Bits(),
+		devnetHeader.Nonce())
+	hash := consensushashing.BlockHash(genesis)
+	if !hash.Equal(DevnetParams.GenesisHash) {
+		t.Fatalf("NewGenesisBlock: got hash %s, want the devnet genesis hash %s", hash, DevnetParams.GenesisHash)
+	}
+}
+
+func TestNetworkDefinition(t *testing.T) {
+	devnetHeader := devnetGenesisBlock.Header
+	definitionJSON := `{
+		"name": "karlsen-definitiontest",
+		"net": 4000000001,
+		"prefix": "karlsendeftest",
+		"rpcPort": "52110",
+		"defaultPort": "52111",
+		"genesis": {
+			"coinbasePayload": "` + hex.EncodeToString(devnetGenesisTxPayload) + `",
+			"timeInMilliseconds": ` + formatInt(devnetHeader.TimeInMilliseconds()) + `,
+			"nonce": ` + formatInt(int64(devnetHeader.Nonce())) + `,
+			"hash": "` + DevnetParams.GenesisHash.String() + `"
+		},
+		"k": 10,
+		"targetTimePerBlockInMilliSeconds": 500,
+		"blockCoinbaseMaturity": 10
+	}`
+	definition, err := ReadNetworkDefinition(writeDefinition(t, definitionJSON))
+	if err != nil {
+		t.Fatalf("ReadNetworkDefinition: %+v", err)
+	}
+	params, err := definition.Params()
+	if err != nil {
+		t.Fatalf("Params: %+v", err)
+	}
+	if params.Name != "karlsen-definitiontest" || params.Net != 4000000001 ||
+		params.Prefix.String() != "karlsendeftest" {
+		t.Fatalf("Params: unexpected network identity %s/%d/%s", params.Name, params.Net, params.Prefix)
+	}
+	if params.RPCPort != "52110" || params.DefaultPort != "52111" {
+		t.Fatalf("Params: unexpected ports %s/%s", params.RPCPort, params.DefaultPort)
+	}
+	if params.K != 10 || params.TargetTimePerBlock.Milliseconds() != 500 || params.BlockCoinbaseMaturity != 10 {
+		t.Fatalf("Params: the consensus parameters of the definition weren't applied")
+	}
+	if params.MaxBlockMass != DevnetParams.MaxBlockMass {
+		t.Fatalf("Params: omitted consensus parameters don't take their devnet values")
+	}
+	if !params.GenesisHash.Equal(DevnetParams.GenesisHash) {
+		t.Fatalf("Params: got genesis hash %s, want %s", params.GenesisHash, DevnetParams.GenesisHash)
+	}
+	if params.FishHashActivationBits != devnetHeader.Bits() {
+		t.Fatalf("Params: FishHashActivationBits doesn't default to the genesis bits")
+	}
+
+	// A different nonce must fail the pinned genesis hash
+	definition.Genesis.Nonce++
+	_, err = definition.Params()
+	if err == nil || !strings.Contains(err.Error(), "instead of the expected") {
+		t.Fatalf("Params: expected a genesis hash mismatch error, got: %v", err)
+	}
+
+	// Without a pinned hash, the genesis is accepted and gets a different hash
+	definition.Genesis.Hash = ""
+	params, err = definition.Params()
+	if err != nil {
+		t.Fatalf("Params: %+v", err)
+	}
+	if params.GenesisHash.Equal(DevnetParams.GenesisHash) {
+		t.Fatalf("Params: a different nonce resulted in the same genesis hash")
+	}
+}
+
+func TestNetworkDefinitionErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		definitionJSON string
+		expectedError  string
+	}{
+		{
+			name:           "unknown field",
+			definitionJSON: `{"name": "karlsen-test", "net": 1, "prefix": "karlsentest", "kk": 10}`,
+			expectedError:  "unknown field",
+		},
+		{
+			name:           "missing name",
+			definitionJSON: `{"net": 1, "prefix": "karlsentest"}`,
+			expectedError:  "missing a name",
+		},
+		{
+			name:           "missing net",
+			definitionJSON: `{"name": "karlsen-test", "prefix": "karlsentest"}`,
+			expectedError:  "missing a net",
+		},
+		{
+			name:           "invalid prefix",
+			definitionJSON: `{"name": "karlsen-test", "net": 1, "prefix": "Karlsen:Test"}`,
+			expectedError:  "invalid address prefix",
+		},
+		{
+			name: "invalid coinbase payload",
+			definitionJSON: `{"name": "karlsen-test", "net": 1, "prefix": "karlsentest",
+				"genesis": {"coinbasePayload": "xyz"}}`,
+			expectedError: "not valid hex",
+		},
+		{
+			name: "genesis target above powMax",
+			definitionJSON: `{"name": "karlsen-test", "net": 1, "prefix": "karlsentest",
+				"genesis": {"bits": 545259519}, "powMax": "ff"}`,
+			expectedError: "larger than powMax",
+		},
+		{
+			name: "finality shorter than block time",
+			definitionJSON: `{"name": "karlsen-test", "net": 1, "prefix": "karlsentest",
+				"targetTimePerBlockInMilliSeconds": 1000, "finalityDuration": 10}`,
+			expectedError: "finality duration",
+		},
+	}
+
+	for _, test := range tests {
+		definition, err := ReadNetworkDefinition(writeDefinition(t, test.definitionJSON))
+		if err == nil {
+			_, err = definition.Params()
+		}
+		if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+			t.Errorf("%s: expected an error containing %q, got: %v", test.name, test.expectedError, err)
+		}
+	}
+}
+
+func writeDefinition(t *testing.T, definitionJSON string) string {
+	path := filepath.Join(t.TempDir(), "network.json")
+	err := os.WriteFile(path, []byte(definitionJSON), 0600)
+	if err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+	return path
+}
+
+func formatInt(value int64) string {
+	return strconv.FormatInt(value, 10)
+}
+
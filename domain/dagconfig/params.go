@@ -188,6 +188,18 @@ type Params struct {
 	MaxBlockLevel int
 
 	MergeDepth uint64
+
+	// Checkpoints are blocks that must be in the selected parent chain of the network.
+	// See Checkpoint for further details.
+	Checkpoints []Checkpoint
+
+	// FishHashActivationDAAScore is the DAA score from which blocks use KarlsenHashv2
+	// (FishHash) as their proof of work instead of kHeavyHash
+	FishHashActivationDAAScore uint64
+
+	// FishHashActivationBits is the difficulty required from FishHash blocks until the
+	// difficulty adjustment window consists of FishHash blocks only
+	FishHashActivationBits uint32
 }
 
 // NormalizeRPCServerAddress returns addr with the current network default
@@ -272,6 +284,10 @@ var MainnetParams = Params{
 	// This means that any block that has a level lower or equal to genesis will be level 0.
 	MaxBlockLevel: 225,
 	MergeDepth:    defaultMergeDepth,
+	Checkpoints:   mainnetCheckpoints,
+
+	FishHashActivationDAAScore: defaultFishHashActivationDAAScore,
+	FishHashActivationBits:     genesisBlock.Header.Bits(),
 }
 
 // TestnetParams defines the network parameters for the test Kaspa network.
@@ -336,6 +352,10 @@ var TestnetParams = Params{
 
 	MaxBlockLevel: 250,
 	MergeDepth:    defaultMergeDepth,
+	Checkpoints:   testnetCheckpoints,
+
+	FishHashActivationDAAScore: defaultFishHashActivationDAAScore,
+	FishHashActivationBits:     testnetGenesisBlock.Header.Bits(),
 }
 
 // SimnetParams defines the network parameters for the simulation test Kaspa
@@ -402,6 +422,9 @@ var SimnetParams = Params{
 
 	MaxBlockLevel: 250,
 	MergeDepth:    defaultMergeDepth,
+
+	FishHashActivationDAAScore: defaultFishHashActivationDAAScore,
+	FishHashActivationBits:     simnetGenesisBlock.Header.Bits(),
 }
 
 // DevnetParams defines the network parameters for the development Kaspa network.
@@ -464,6 +487,9 @@ var DevnetParams = Params{
 
 	MaxBlockLevel: 250,
 	MergeDepth:    defaultMergeDepth,
+
+	FishHashActivationDAAScore: defaultFishHashActivationDAAScore,
+	FishHashActivationBits:     devnetGenesisBlock.Header.Bits(),
 }
 
 // ErrDuplicateNet describes an error where the parameters for a Kaspa
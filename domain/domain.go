@@ -10,6 +10,7 @@ import (
 	"github.com/PYVERT/testfork/domain/consensus"
 	"github.com/PYVERT/testfork/domain/consensus/model/externalapi"
 	"github.com/PYVERT/testfork/domain/miningmanager"
+	"github.com/PYVERT/testfork/domain/miningmanager/blocktemplatebuilder"
 	"github.com/PYVERT/testfork/domain/miningmanager/mempool"
 	"github.com/PYVERT/testfork/domain/prefixmanager"
 	"github.com/PYVERT/testfork/domain/prefixmanager/prefix"
@@ -178,7 +179,9 @@ func (d *domain) DeleteStagingConsensus() error {
 }
 
 // New instantiates a new instance of a Domain object
-func New(consensusConfig *consensus.Config, mempoolConfig *mempool.Config, db infrastructuredatabase.Database) (Domain, error) {
+func New(consensusConfig *consensus.Config, mempoolConfig *mempool.Config, blockTemplatePolicy *blocktemplatebuilder.Policy,
+	db infrastructuredatabase.Database) (Domain, error) {
+
 	err := prefixmanager.DeleteInactivePrefix(db)
 	if err != nil {
 		return nil, err
@@ -222,7 +225,7 @@ func New(consensusConfig *consensus.Config, mempoolConfig *mempool.Config, db in
 
 	// We create a consensus wrapper because the actual consensus might change
 	consensusReference := consensusreference.NewConsensusReference(&domainInstance.consensus)
-	domainInstance.miningManager = miningManagerFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+	domainInstance.miningManager = miningManagerFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig, blockTemplatePolicy)
 	return domainInstance, nil
 }
 
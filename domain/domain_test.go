@@ -15,6 +15,7 @@ import (
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/testutils"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/miningmanager/blocktemplatebuilder"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/miningmanager/mempool"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/db/database/ldb"
 )
@@ -32,7 +33,7 @@ func TestCreateStagingConsensus(t *testing.T) {
 			t.Fatalf("NewLevelDB: %+v", err)
 		}
 
-		domainInstance, err := domain.New(consensusConfig, mempool.DefaultConfig(&consensusConfig.Params), db)
+		domainInstance, err := domain.New(consensusConfig, mempool.DefaultConfig(&consensusConfig.Params), blocktemplatebuilder.DefaultPolicy(&consensusConfig.Params), db)
 		if err != nil {
 			t.Fatalf("New: %+v", err)
 		}
@@ -126,7 +127,7 @@ func TestCreateStagingConsensus(t *testing.T) {
 			t.Fatalf("ValidateAndInsertBlock: %+v", err)
 		}
 
-		domainInstance2, err := domain.New(consensusConfig, mempool.DefaultConfig(&consensusConfig.Params), db)
+		domainInstance2, err := domain.New(consensusConfig, mempool.DefaultConfig(&consensusConfig.Params), blocktemplatebuilder.DefaultPolicy(&consensusConfig.Params), db)
 		if err != nil {
 			t.Fatalf("New: %+v", err)
 		}
@@ -5,8 +5,10 @@ import (
 	"sort"
 
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/processes/coinbasemanager"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/merkle"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/transactionhelper"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/txscript"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensusreference"
 	"github.com/karlsend/PYVERT/testfork/karlsend/util/mstime"
 
@@ -21,8 +23,12 @@ import (
 
 type candidateTx struct {
 	*consensusexternalapi.DomainTransaction
-	txValue  float64
-	gasLimit uint64
+	// virtualFee is the fee of the transaction plus its fee delta, which is
+	// used instead of its fee for selecting it, but not in the block itself
+	virtualFee uint64
+	txValue    float64
+	gasLimit   uint64
+	isPriority bool
 
 	p     float64
 	start float64
@@ -35,18 +41,18 @@ type candidateTx struct {
 type blockTemplateBuilder struct {
 	consensusReference consensusreference.ConsensusReference
 	mempool            miningmanagerapi.Mempool
-	policy             policy
+	policy             *Policy
 
 	coinbasePayloadScriptPublicKeyMaxLength uint8
 }
 
 // New creates a new blockTemplateBuilder
 func New(consensusReference consensusreference.ConsensusReference, mempool miningmanagerapi.Mempool,
-	blockMaxMass uint64, coinbasePayloadScriptPublicKeyMaxLength uint8) miningmanagerapi.BlockTemplateBuilder {
+	policy *Policy, coinbasePayloadScriptPublicKeyMaxLength uint8) miningmanagerapi.BlockTemplateBuilder {
 	return &blockTemplateBuilder{
 		consensusReference: consensusReference,
 		mempool:            mempool,
-		policy:             policy{BlockMaxMass: blockMaxMass},
+		policy:             policy,
 
 		coinbasePayloadScriptPublicKeyMaxLength: coinbasePayloadScriptPublicKeyMaxLength,
 	}
@@ -93,6 +99,13 @@ func New(consensusReference consensusreference.ConsensusReference, mempool minin
 // policy setting, exceed the maximum allowed signature operations per block, or
 // otherwise cause the block to be invalid are skipped.
 //
+// Transactions with an output of one of the ExcludedScriptClasses are never
+// selected. Before the rest of the block is filled, the transactions from the
+// PriorityScriptPublicKeys are selected, and then the transactions with the
+// highest fee rates, until HighFeeMassRatio of the BlockMaxMass is used by them.
+// The fee deltas of prioritised mempool transactions are added to their fees
+// for the selection only.
+//
 // Given the above, a block generated by this function is of the following form:
 //
 //   -----------------------------------  --  --
@@ -120,17 +133,29 @@ func (btb *blockTemplateBuilder) BuildBlockTemplate(
 	coinbaseData *consensusexternalapi.DomainCoinbaseData) (*consensusexternalapi.DomainBlockTemplate, error) {
 
 	mempoolTransactions := btb.mempool.BlockCandidateTransactions()
+	feeDeltas := btb.mempool.TransactionFeeDeltas()
 	candidateTxs := make([]*candidateTx, 0, len(mempoolTransactions))
 	for _, tx := range mempoolTransactions {
+		if btb.isExcluded(tx) {
+			log.Tracef("Tx %s is of an excluded script class", consensushashing.TransactionID(tx))
+			continue
+		}
+
 		// Calculate the tx value
 		gasLimit := uint64(0)
 		if !subnetworks.IsBuiltInOrNative(tx.SubnetworkID) {
 			panic("We currently don't support non native subnetworks")
 		}
+		virtualFee := tx.Fee
+		if feeDelta, ok := feeDeltas[*consensushashing.TransactionID(tx)]; ok {
+			virtualFee = applyFeeDelta(tx.Fee, feeDelta)
+		}
 		candidateTxs = append(candidateTxs, &candidateTx{
 			DomainTransaction: tx,
-			txValue:           btb.calcTxValue(tx),
+			virtualFee:        virtualFee,
+			txValue:           btb.calcTxValue(tx, virtualFee),
 			gasLimit:          gasLimit,
+			isPriority:        btb.isPriority(tx),
 		})
 	}
 
@@ -205,11 +230,10 @@ func (btb *blockTemplateBuilder) ModifyBlockTemplate(newCoinbaseData *consensuse
 // calcTxValue calculates a value to be used in transaction selection.
 // The higher the number the more likely it is that the transaction will be
 // included in the block.
-func (btb *blockTemplateBuilder) calcTxValue(tx *consensusexternalapi.DomainTransaction) float64 {
+func (btb *blockTemplateBuilder) calcTxValue(tx *consensusexternalapi.DomainTransaction, fee uint64) float64 {
 	massLimit := btb.policy.BlockMaxMass
 
 	mass := tx.Mass
-	fee := tx.Fee
 	if subnetworks.IsBuiltInOrNative(tx.SubnetworkID) {
 		return float64(fee) / (float64(mass) / float64(massLimit))
 	}
@@ -218,3 +242,53 @@ func (btb *blockTemplateBuilder) calcTxValue(tx *consensusexternalapi.DomainTran
 	return float64(fee) / (float64(mass)/float64(massLimit) + float64(tx.Gas)/float64(gasLimit))
 }
 
+// isExcluded returns whether any of the outputs of the given transaction is
+// of one of the ExcludedScriptClasses of the policy
+func (btb *blockTemplateBuilder) isExcluded(tx *consensusexternalapi.DomainTransaction) bool {
+	if len(btb.policy.ExcludedScriptClasses) == 0 {
+		return false
+	}
+	for _, output := range tx.Outputs {
+		scriptClass := txscript.GetScriptClass(output.ScriptPublicKey.Script)
+		for _, excludedScriptClass := range btb.policy.ExcludedScriptClasses {
+			if scriptClass == excludedScriptClass {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isPriority returns whether any of the inputs of the given transaction spends
+// an output that pays to one of the PriorityScriptPublicKeys of the policy
+func (btb *blockTemplateBuilder) isPriority(tx *consensusexternalapi.DomainTransaction) bool {
+	if len(btb.policy.PriorityScriptPublicKeys) == 0 {
+		return false
+	}
+	for _, input := range tx.Inputs {
+		if input.UTXOEntry == nil {
+			continue
+		}
+		for _, priorityScriptPublicKey := range btb.policy.PriorityScriptPublicKeys {
+			if input.UTXOEntry.ScriptPublicKey().Equal(priorityScriptPublicKey) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// applyFeeDelta returns the given fee plus the given delta, clamped to the range of uint64
+func applyFeeDelta(fee uint64, feeDelta int64) uint64 {
+	if feeDelta < 0 {
+		if uint64(-feeDelta) > fee {
+			return 0
+		}
+		return fee - uint64(-feeDelta)
+	}
+	if fee+uint64(feeDelta) < fee {
+		return math.MaxUint64
+	}
+	return fee + uint64(feeDelta)
+}
+
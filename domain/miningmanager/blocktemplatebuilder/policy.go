@@ -4,12 +4,41 @@
 
 package blocktemplatebuilder
 
-// policy houses the policy (configuration parameters) which is used to control
+import (
+	consensusexternalapi "github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/txscript"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/dagconfig"
+)
+
+// Policy houses the policy (configuration parameters) which is used to control
 // the generation of block templates. See the documentation for
-// NewBlockTemplate for more details on each of these parameters are used.
-type policy struct {
+// BuildBlockTemplate for more details on each of these parameters are used.
+type Policy struct {
 	// BlockMaxMass is the maximum block mass to be used when generating a
 	// block template.
 	BlockMaxMass uint64
+
+	// HighFeeMassRatio is the fraction of BlockMaxMass that is reserved for
+	// the transactions with the highest fee rates.
+	HighFeeMassRatio float64
+
+	// PriorityScriptPublicKeys are the script public keys whose transactions
+	// are selected before any other transaction, such as the payout addresses
+	// of a pool. A transaction is from a script public key if any of its inputs
+	// spends an output that pays to it.
+	PriorityScriptPublicKeys []*consensusexternalapi.ScriptPublicKey
+
+	// ExcludedScriptClasses are the script classes whose transactions are
+	// never selected. A transaction is of a script class if any of its
+	// outputs is.
+	ExcludedScriptClasses []txscript.ScriptClass
+}
+
+// DefaultPolicy returns the default block template policy, which selects
+// transactions by their fee rates alone
+func DefaultPolicy(dagParams *dagconfig.Params) *Policy {
+	return &Policy{
+		BlockMaxMass: dagParams.MaxBlockMass,
+	}
 }
 
@@ -64,9 +64,14 @@ func (btb *blockTemplateBuilder) selectTransactions(candidateTxs []*candidateTx)
 		totalMass:   0,
 		totalFees:   0,
 	}
+	gasUsageMap := make(map[consensusexternalapi.DomainSubnetworkID]uint64)
+
+	// The transactions that are selected by the policy are marked for
+	// deletion, so the rebalancing below removes them from the candidates
+	selectedTxs := btb.selectPolicyTransactions(candidateTxs, &txsForBlockTemplate, gasUsageMap)
+
 	usedCount, usedP := 0, 0.0
 	candidateTxs, totalP := rebalanceCandidates(candidateTxs, true)
-	gasUsageMap := make(map[consensusexternalapi.DomainSubnetworkID]uint64)
 
 	markCandidateTxForDeletion := func(candidateTx *candidateTx) {
 		candidateTx.isMarkedForDeletion = true
@@ -74,7 +79,6 @@ func (btb *blockTemplateBuilder) selectTransactions(candidateTxs []*candidateTx)
 		usedP += candidateTx.p
 	}
 
-	selectedTxs := make([]*candidateTx, 0)
 	for len(candidateTxs)-usedCount > 0 {
 		// Rebalance the candidates if it's required
 		if usedP >= rebalanceThreshold*totalP {
@@ -163,6 +167,76 @@ func (btb *blockTemplateBuilder) selectTransactions(candidateTxs []*candidateTx)
 	return txsForBlockTemplate
 }
 
+// selectPolicyTransactions selects the transactions that the policy prioritizes, in the
+// order of their fee rates, before the rest of the block is filled by selectTransactions.
+// First, the transactions from the PriorityScriptPublicKeys are selected as long as they
+// fit in the block. Then, the transactions with the highest fee rates are selected as long
+// as they fit in HighFeeMassRatio of the BlockMaxMass on top of the priority transactions.
+// Transactions that would exceed the gas limit of their subnetwork are skipped.
+// The selected transactions are added to txsForBlockTemplate's totals and to gasUsageMap,
+// and marked for deletion.
+func (btb *blockTemplateBuilder) selectPolicyTransactions(candidateTxs []*candidateTx,
+	txsForBlockTemplate *selectedTransactions,
+	gasUsageMap map[consensusexternalapi.DomainSubnetworkID]uint64) []*candidateTx {
+
+	selectedTxs := make([]*candidateTx, 0)
+	if len(btb.policy.PriorityScriptPublicKeys) == 0 && btb.policy.HighFeeMassRatio == 0 {
+		return selectedTxs
+	}
+
+	candidateTxsByFeeRate := make([]*candidateTx, len(candidateTxs))
+	copy(candidateTxsByFeeRate, candidateTxs)
+	sort.SliceStable(candidateTxsByFeeRate, func(i, j int) bool {
+		return candidateTxsByFeeRate[i].feeRate() > candidateTxsByFeeRate[j].feeRate()
+	})
+
+	selectWithinMass := func(massLimit uint64, isSelectable func(candidateTx *candidateTx) bool) {
+		for _, candidateTx := range candidateTxsByFeeRate {
+			if candidateTx.isMarkedForDeletion || !isSelectable(candidateTx) ||
+				txsForBlockTemplate.totalMass+candidateTx.Mass > massLimit {
+				continue
+			}
+			// Enforce maximum gas per subnetwork per block. Also check
+			// for overflow.
+			if !subnetworks.IsBuiltInOrNative(candidateTx.SubnetworkID) {
+				gasUsage := gasUsageMap[candidateTx.SubnetworkID]
+				if gasUsage+candidateTx.Gas < gasUsage || gasUsage+candidateTx.Gas > candidateTx.gasLimit {
+					log.Tracef("Tx %s would exceed the gas limit in subnetwork %s. Skipping it.",
+						consensushashing.TransactionID(candidateTx.DomainTransaction), candidateTx.SubnetworkID)
+					continue
+				}
+				gasUsageMap[candidateTx.SubnetworkID] = gasUsage + candidateTx.Gas
+			}
+			log.Tracef("Adding tx %s by the block template policy (feePerMegaGram %d)",
+				consensushashing.TransactionID(candidateTx.DomainTransaction), candidateTx.virtualFee*1e6/candidateTx.Mass)
+
+			selectedTxs = append(selectedTxs, candidateTx)
+			txsForBlockTemplate.totalMass += candidateTx.Mass
+			txsForBlockTemplate.totalFees += candidateTx.Fee
+			candidateTx.isMarkedForDeletion = true
+		}
+	}
+
+	selectWithinMass(btb.policy.BlockMaxMass, func(candidateTx *candidateTx) bool {
+		return candidateTx.isPriority
+	})
+
+	highFeeMassLimit := txsForBlockTemplate.totalMass + uint64(btb.policy.HighFeeMassRatio*float64(btb.policy.BlockMaxMass))
+	if highFeeMassLimit > btb.policy.BlockMaxMass {
+		highFeeMassLimit = btb.policy.BlockMaxMass
+	}
+	selectWithinMass(highFeeMassLimit, func(*candidateTx) bool {
+		return true
+	})
+
+	return selectedTxs
+}
+
+// feeRate returns the virtual fee of the transaction per gram of its mass
+func (candidateTx *candidateTx) feeRate() float64 {
+	return float64(candidateTx.virtualFee) / float64(candidateTx.Mass)
+}
+
 func rebalanceCandidates(oldCandidateTxs []*candidateTx, isFirstRun bool) (
 	candidateTxs []*candidateTx, totalP float64) {
 
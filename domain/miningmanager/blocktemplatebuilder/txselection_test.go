@@ -0,0 +1,222 @@
+package blocktemplatebuilder
+
+import (
+	"math"
+	"testing"
+
+	consensusexternalapi "github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/subnetworks"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/txscript"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/utxo"
+)
+
+func newTestCandidateTx(btb *blockTemplateBuilder, index uint32, mass uint64, fee uint64, feeDelta int64,
+	inputScriptPublicKey *consensusexternalapi.ScriptPublicKey) *candidateTx {
+
+	tx := &consensusexternalapi.DomainTransaction{
+		Inputs: []*consensusexternalapi.DomainTransactionInput{{
+			PreviousOutpoint: consensusexternalapi.DomainOutpoint{Index: index},
+			UTXOEntry:        utxo.NewUTXOEntry(fee+1, inputScriptPublicKey, false, 0),
+		}},
+		SubnetworkID: subnetworks.SubnetworkIDNative,
+		Fee:          fee,
+		Mass:         mass,
+	}
+	virtualFee := applyFeeDelta(fee, feeDelta)
+	return &candidateTx{
+		DomainTransaction: tx,
+		virtualFee:        virtualFee,
+		txValue:           btb.calcTxValue(tx, virtualFee),
+		isPriority:        btb.isPriority(tx),
+	}
+}
+
+func isSelected(selected selectedTransactions, tx *candidateTx) bool {
+	for _, selectedTx := range selected.selectedTxs {
+		if selectedTx == tx.DomainTransaction {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSelectTransactionsPolicy(t *testing.T) {
+	priorityScriptPublicKey := &consensusexternalapi.ScriptPublicKey{Script: []byte{txscript.OpTrue}, Version: 0}
+	otherScriptPublicKey := &consensusexternalapi.ScriptPublicKey{Script: []byte{txscript.OpFalse}, Version: 0}
+	btb := &blockTemplateBuilder{
+		policy: &Policy{
+			BlockMaxMass:             1000,
+			HighFeeMassRatio:         0.3,
+			PriorityScriptPublicKeys: []*consensusexternalapi.ScriptPublicKey{priorityScriptPublicKey},
+		},
+	}
+
+	// The selection is probabilistic, so it's repeated to make sure that
+	// the transactions that the policy selects are selected every time
+	for i := 0; i < 100; i++ {
+		var candidateTxs []*candidateTx
+		for j := uint32(0); j < 20; j++ {
+			candidateTxs = append(candidateTxs, newTestCandidateTx(btb, j, 100, 1000+uint64(j), 0, otherScriptPublicKey))
+		}
+		// A priority transaction with the lowest fee
+		priorityTx := newTestCandidateTx(btb, 100, 100, 1, 0, priorityScriptPublicKey)
+		// A transaction with a low fee, whose fee delta makes it the transaction with the highest fee rate
+		prioritisedTx := newTestCandidateTx(btb, 101, 100, 1, 1_000_000, otherScriptPublicKey)
+		// A transaction with the highest fee, whose negative fee delta makes it the transaction
+		// with the lowest fee rate
+		deprioritisedTx := newTestCandidateTx(btb, 102, 100, 1_000_000, -1_000_000, otherScriptPublicKey)
+		candidateTxs = append(candidateTxs, priorityTx, prioritisedTx, deprioritisedTx)
+		highestFeeTxs := []*candidateTx{candidateTxs[19], candidateTxs[18]}
+
+		selected := btb.selectTransactions(candidateTxs)
+
+		if selected.totalMass > btb.policy.BlockMaxMass {
+			t.Fatalf("selected transactions of total mass %d, which is above the max of %d",
+				selected.totalMass, btb.policy.BlockMaxMass)
+		}
+		if len(selected.selectedTxs) != 10 {
+			t.Fatalf("expected 10 transactions to fill the block, got %d", len(selected.selectedTxs))
+		}
+		if !isSelected(selected, priorityTx) {
+			t.Fatalf("the priority transaction wasn't selected")
+		}
+		// The high fee reserve is 300 grams on top of the priority transaction, which
+		// fits the prioritised transaction and the two transactions with the highest fees
+		if !isSelected(selected, prioritisedTx) {
+			t.Fatalf("the prioritised transaction wasn't selected")
+		}
+		for _, highestFeeTx := range highestFeeTxs {
+			if !isSelected(selected, highestFeeTx) {
+				t.Fatalf("a transaction with one of the highest fees wasn't selected")
+			}
+		}
+
+		var expectedTotalFees uint64
+		for i, tx := range selected.selectedTxs {
+			expectedTotalFees += tx.Fee
+			if selected.txFees[i] != tx.Fee {
+				t.Fatalf("the fee of a selected transaction is %d instead of its real fee %d",
+					selected.txFees[i], tx.Fee)
+			}
+		}
+		if selected.totalFees != expectedTotalFees {
+			t.Fatalf("the total fees of the selected transactions are %d instead of their real fees %d",
+				selected.totalFees, expectedTotalFees)
+		}
+	}
+}
+
+func TestSelectTransactionsPolicyGasLimit(t *testing.T) {
+	priorityScriptPublicKey := &consensusexternalapi.ScriptPublicKey{Script: []byte{txscript.OpTrue}, Version: 0}
+	otherScriptPublicKey := &consensusexternalapi.ScriptPublicKey{Script: []byte{txscript.OpFalse}, Version: 0}
+	btb := &blockTemplateBuilder{
+		policy: &Policy{
+			BlockMaxMass:             1000,
+			HighFeeMassRatio:         1,
+			PriorityScriptPublicKeys: []*consensusexternalapi.ScriptPublicKey{priorityScriptPublicKey},
+		},
+	}
+	subnetworkID := consensusexternalapi.DomainSubnetworkID{123}
+	const gasLimit = 100
+
+	// The selection is probabilistic, so it's repeated to make sure that
+	// the gas limit is never exceeded
+	for i := 0; i < 100; i++ {
+		var candidateTxs []*candidateTx
+		var subnetworkTxs []*candidateTx
+		for j := uint32(0); j < 6; j++ {
+			inputScriptPublicKey := otherScriptPublicKey
+			if j < 3 {
+				inputScriptPublicKey = priorityScriptPublicKey
+			}
+			subnetworkTx := newTestCandidateTx(btb, j, 100, 1000+uint64(j), 0, inputScriptPublicKey)
+			subnetworkTx.SubnetworkID = subnetworkID
+			subnetworkTx.Gas = 40
+			subnetworkTx.gasLimit = gasLimit
+			subnetworkTxs = append(subnetworkTxs, subnetworkTx)
+		}
+		candidateTxs = append(candidateTxs, subnetworkTxs...)
+		for j := uint32(100); j < 104; j++ {
+			candidateTxs = append(candidateTxs, newTestCandidateTx(btb, j, 100, 1, 0, otherScriptPublicKey))
+		}
+
+		selected := btb.selectTransactions(candidateTxs)
+
+		var totalGas uint64
+		selectedSubnetworkTxCount := 0
+		for _, subnetworkTx := range subnetworkTxs {
+			if isSelected(selected, subnetworkTx) {
+				totalGas += subnetworkTx.Gas
+				selectedSubnetworkTxCount++
+			}
+		}
+		if totalGas > gasLimit {
+			t.Fatalf("selected transactions of total gas %d in subnetwork %s, which is above its limit of %d",
+				totalGas, subnetworkID, gasLimit)
+		}
+		// The two priority transactions with the highest fees use up the gas limit
+		if selectedSubnetworkTxCount != 2 || !isSelected(selected, subnetworkTxs[2]) ||
+			!isSelected(selected, subnetworkTxs[1]) {
+			t.Fatalf("expected the two priority transactions with the highest fees in subnetwork %s to "+
+				"be selected, but %d transactions of it were selected", subnetworkID, selectedSubnetworkTxCount)
+		}
+		// The native transactions don't use gas, so they fill the rest of the block
+		if len(selected.selectedTxs) != 6 {
+			t.Fatalf("expected 6 transactions to be selected, got %d", len(selected.selectedTxs))
+		}
+	}
+}
+
+func TestIsExcluded(t *testing.T) {
+	btb := &blockTemplateBuilder{
+		policy: &Policy{
+			ExcludedScriptClasses: []txscript.ScriptClass{txscript.NonStandardTy},
+		},
+	}
+
+	payToPubKeyScript := append([]byte{txscript.OpData32}, make([]byte, 32)...)
+	payToPubKeyScript = append(payToPubKeyScript, txscript.OpCheckSig)
+	standardOutput := &consensusexternalapi.DomainTransactionOutput{
+		ScriptPublicKey: &consensusexternalapi.ScriptPublicKey{Script: payToPubKeyScript},
+	}
+	nonStandardOutput := &consensusexternalapi.DomainTransactionOutput{
+		ScriptPublicKey: &consensusexternalapi.ScriptPublicKey{Script: []byte{txscript.OpTrue}},
+	}
+
+	standardTx := &consensusexternalapi.DomainTransaction{
+		Outputs: []*consensusexternalapi.DomainTransactionOutput{standardOutput},
+	}
+	if btb.isExcluded(standardTx) {
+		t.Fatalf("a transaction with a pay-to-pubkey output was excluded")
+	}
+	nonStandardTx := &consensusexternalapi.DomainTransaction{
+		Outputs: []*consensusexternalapi.DomainTransactionOutput{standardOutput, nonStandardOutput},
+	}
+	if !btb.isExcluded(nonStandardTx) {
+		t.Fatalf("a transaction with a non-standard output wasn't excluded")
+	}
+}
+
+func TestApplyFeeDelta(t *testing.T) {
+	tests := []struct {
+		fee         uint64
+		feeDelta    int64
+		expectedFee uint64
+	}{
+		{fee: 100, feeDelta: 0, expectedFee: 100},
+		{fee: 100, feeDelta: 50, expectedFee: 150},
+		{fee: 100, feeDelta: -50, expectedFee: 50},
+		{fee: 100, feeDelta: -100, expectedFee: 0},
+		{fee: 100, feeDelta: -101, expectedFee: 0},
+		{fee: 100, feeDelta: math.MinInt64, expectedFee: 0},
+		{fee: math.MaxUint64 - 10, feeDelta: 20, expectedFee: math.MaxUint64},
+	}
+
+	for _, test := range tests {
+		fee := applyFeeDelta(test.fee, test.feeDelta)
+		if fee != test.expectedFee {
+			t.Errorf("applyFeeDelta(%d, %d): got %d, want %d", test.fee, test.feeDelta, fee, test.expectedFee)
+		}
+	}
+}
+
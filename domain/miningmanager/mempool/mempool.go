@@ -117,6 +117,17 @@ func (mp *mempool) AllTransactions(includeTransactionPool bool, includeOrphanPoo
 	return transactionPoolTransactions, orphanPoolTransactions
 }
 
+// FindTransactions returns the transactions in the transaction pool whose IDs match isMatch.
+// isMatch is called while the mempool is locked, so that only the matching transactions are cloned.
+func (mp *mempool) FindTransactions(
+	isMatch func(transactionID *externalapi.DomainTransactionID) bool) []*externalapi.DomainTransaction {
+
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	return mp.transactionsPool.findTransactions(isMatch)
+}
+
 func (mp *mempool) TransactionCount(includeTransactionPool bool, includeOrphanPool bool) int {
 	mp.mtx.RLock()
 	defer mp.mtx.RUnlock()
@@ -142,6 +153,22 @@ func (mp *mempool) HandleNewBlockTransactions(transactions []*externalapi.Domain
 	return mp.handleNewBlockTransactions(transactions)
 }
 
+func (mp *mempool) PrioritiseTransaction(transactionID *externalapi.DomainTransactionID, feeDelta int64) (
+	totalFeeDelta int64, err error) {
+
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+
+	return mp.transactionsPool.prioritiseTransaction(transactionID, feeDelta)
+}
+
+func (mp *mempool) TransactionFeeDeltas() map[externalapi.DomainTransactionID]int64 {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	return mp.transactionsPool.transactionFeeDeltas()
+}
+
 func (mp *mempool) BlockCandidateTransactions() []*externalapi.DomainTransaction {
 	mp.mtx.RLock()
 	defer mp.mtx.RUnlock()
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"math"
+
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
 )
@@ -11,6 +13,7 @@ type MempoolTransaction struct {
 	parentTransactionsInPool IDToTransactionMap
 	isHighPriority           bool
 	addedAtDAAScore          uint64
+	feeDelta                 int64
 }
 
 // NewMempoolTransaction constructs a new MempoolTransaction
@@ -58,3 +61,22 @@ func (mt *MempoolTransaction) AddedAtDAAScore() uint64 {
 	return mt.addedAtDAAScore
 }
 
+// FeeDelta returns the virtual fee that is added to the fee of this MempoolTransaction
+// when it's considered for block templates
+func (mt *MempoolTransaction) FeeDelta() int64 {
+	return mt.feeDelta
+}
+
+// AddFeeDelta adds the given delta to the FeeDelta of this MempoolTransaction, clamped to
+// the range of int64, and returns the result
+func (mt *MempoolTransaction) AddFeeDelta(feeDelta int64) int64 {
+	sum := mt.feeDelta + feeDelta
+	if feeDelta > 0 && sum < mt.feeDelta {
+		sum = math.MaxInt64
+	} else if feeDelta < 0 && sum > mt.feeDelta {
+		sum = math.MinInt64
+	}
+	mt.feeDelta = sum
+	return mt.feeDelta
+}
+
@@ -14,6 +14,7 @@ type transactionsPool struct {
 	mempool                       *mempool
 	allTransactions               model.IDToTransactionMap
 	highPriorityTransactions      model.IDToTransactionMap
+	prioritisedTransactions       model.IDToTransactionMap
 	chainedTransactionsByParentID model.IDToTransactionsSliceMap
 	transactionsOrderedByFeeRate  model.TransactionsOrderedByFeeRate
 	lastExpireScanDAAScore        uint64
@@ -25,6 +26,7 @@ func newTransactionsPool(mp *mempool) *transactionsPool {
 		mempool:                       mp,
 		allTransactions:               model.IDToTransactionMap{},
 		highPriorityTransactions:      model.IDToTransactionMap{},
+		prioritisedTransactions:       model.IDToTransactionMap{},
 		chainedTransactionsByParentID: model.IDToTransactionsSliceMap{},
 		transactionsOrderedByFeeRate:  model.TransactionsOrderedByFeeRate{},
 		lastExpireScanDAAScore:        0,
@@ -92,6 +94,8 @@ func (tp *transactionsPool) removeTransaction(transaction *model.MempoolTransact
 
 	delete(tp.highPriorityTransactions, *transaction.TransactionID())
 
+	delete(tp.prioritisedTransactions, *transaction.TransactionID())
+
 	delete(tp.chainedTransactionsByParentID, *transaction.TransactionID())
 
 	return nil
@@ -143,6 +147,33 @@ func (tp *transactionsPool) allReadyTransactions() []*externalapi.DomainTransact
 	return result
 }
 
+// prioritiseTransaction adds the given fee delta to the transaction with the given ID,
+// and returns its total fee delta
+func (tp *transactionsPool) prioritiseTransaction(transactionID *externalapi.DomainTransactionID,
+	feeDelta int64) (int64, error) {
+
+	mempoolTransaction, ok := tp.allTransactions[*transactionID]
+	if !ok {
+		return 0, errors.Errorf("transaction %s is not in the transaction pool", transactionID)
+	}
+
+	totalFeeDelta := mempoolTransaction.AddFeeDelta(feeDelta)
+	if totalFeeDelta == 0 {
+		delete(tp.prioritisedTransactions, *transactionID)
+	} else {
+		tp.prioritisedTransactions[*transactionID] = mempoolTransaction
+	}
+	return totalFeeDelta, nil
+}
+
+func (tp *transactionsPool) transactionFeeDeltas() map[externalapi.DomainTransactionID]int64 {
+	feeDeltas := make(map[externalapi.DomainTransactionID]int64, len(tp.prioritisedTransactions))
+	for transactionID, mempoolTransaction := range tp.prioritisedTransactions {
+		feeDeltas[transactionID] = mempoolTransaction.FeeDelta()
+	}
+	return feeDeltas
+}
+
 func (tp *transactionsPool) getParentTransactionsInPool(
 	transaction *externalapi.DomainTransaction) model.IDToTransactionMap {
 
@@ -247,6 +278,19 @@ func (tp *transactionsPool) getAllTransactions() []*externalapi.DomainTransactio
 	return allTransactions
 }
 
+func (tp *transactionsPool) findTransactions(
+	isMatch func(transactionID *externalapi.DomainTransactionID) bool) []*externalapi.DomainTransaction {
+
+	var transactions []*externalapi.DomainTransaction
+	for transactionID, mempoolTransaction := range tp.allTransactions {
+		transactionID := transactionID
+		if isMatch(&transactionID) {
+			transactions = append(transactions, mempoolTransaction.Transaction().Clone()) //this pointer leaves the mempool, hence we clone.
+		}
+	}
+	return transactions
+}
+
 func (tp *transactionsPool) transactionCount() int {
 	return len(tp.allTransactions)
 }
@@ -14,6 +14,7 @@ import (
 type MiningManager interface {
 	GetBlockTemplate(coinbaseData *externalapi.DomainCoinbaseData) (block *externalapi.DomainBlock, isNearlySynced bool, err error)
 	ClearBlockTemplate()
+	LastBlockTemplateTime() time.Time
 	GetBlockTemplateBuilder() miningmanagermodel.BlockTemplateBuilder
 	GetTransaction(transactionID *externalapi.DomainTransactionID, includeTransactionPool bool, includeOrphanPool bool) (
 		transactionPoolTransaction *externalapi.DomainTransaction,
@@ -28,11 +29,13 @@ type MiningManager interface {
 	AllTransactions(includeTransactionPool bool, includeOrphanPool bool) (
 		transactionPoolTransactions []*externalapi.DomainTransaction,
 		orphanPoolTransactions []*externalapi.DomainTransaction)
+	FindTransactions(isMatch func(transactionID *externalapi.DomainTransactionID) bool) []*externalapi.DomainTransaction
 	TransactionCount(includeTransactionPool bool, includeOrphanPool bool) int
 	HandleNewBlockTransactions(txs []*externalapi.DomainTransaction) ([]*externalapi.DomainTransaction, error)
 	ValidateAndInsertTransaction(transaction *externalapi.DomainTransaction, isHighPriority bool, allowOrphan bool) (
 		acceptedTransactions []*externalapi.DomainTransaction, err error)
 	RevalidateHighPriorityTransactions() (validTransactions []*externalapi.DomainTransaction, err error)
+	PrioritiseTransaction(transactionID *externalapi.DomainTransactionID, feeDelta int64) (totalFeeDelta int64, err error)
 }
 
 type miningManager struct {
@@ -42,6 +45,10 @@ type miningManager struct {
 	cachedBlockTemplate  *externalapi.DomainBlockTemplate
 	cachingTime          time.Time
 	cacheLock            *sync.Mutex
+
+	// lastBlockTemplateTime is the time a block template was last built, and unlike
+	// cachingTime it's not reset when the cached template is cleared
+	lastBlockTemplateTime time.Time
 }
 
 // GetBlockTemplate obtains a block template for a miner to consume
@@ -83,6 +90,15 @@ func (mm *miningManager) ClearBlockTemplate() {
 	mm.cacheLock.Unlock()
 }
 
+// LastBlockTemplateTime returns the time a block template was last built,
+// or the zero time if none was built yet
+func (mm *miningManager) LastBlockTemplateTime() time.Time {
+	mm.cacheLock.Lock()
+	defer mm.cacheLock.Unlock()
+
+	return mm.lastBlockTemplateTime
+}
+
 func (mm *miningManager) getImmutableCachedTemplate() *externalapi.DomainBlockTemplate {
 	if time.Since(mm.cachingTime) > time.Second {
 		// No point in cache optimizations if queries are more than a second apart -- we prefer rechecking the mempool.
@@ -97,6 +113,7 @@ func (mm *miningManager) getImmutableCachedTemplate() *externalapi.DomainBlockTe
 func (mm *miningManager) setImmutableCachedTemplate(blockTemplate *externalapi.DomainBlockTemplate) {
 	mm.cachingTime = time.Now()
 	mm.cachedBlockTemplate = blockTemplate
+	mm.lastBlockTemplateTime = mm.cachingTime
 }
 
 func (mm *miningManager) GetBlockTemplateBuilder() miningmanagermodel.BlockTemplateBuilder {
@@ -135,6 +152,12 @@ func (mm *miningManager) AllTransactions(includeTransactionPool bool, includeOrp
 	return mm.mempool.AllTransactions(includeTransactionPool, includeOrphanPool)
 }
 
+func (mm *miningManager) FindTransactions(
+	isMatch func(transactionID *externalapi.DomainTransactionID) bool) []*externalapi.DomainTransaction {
+
+	return mm.mempool.FindTransactions(isMatch)
+}
+
 func (mm *miningManager) GetTransactionsByAddresses(includeTransactionPool bool, includeOrphanPool bool) (
 	sendingInTransactionPool map[string]*externalapi.DomainTransaction,
 	receivingInTransactionPool map[string]*externalapi.DomainTransaction,
@@ -155,3 +178,20 @@ func (mm *miningManager) RevalidateHighPriorityTransactions() (
 	return mm.mempool.RevalidateHighPriorityTransactions()
 }
 
+// PrioritiseTransaction adds the given fee delta to the virtual fee of the given transaction
+// pool transaction, which is used instead of its fee when selecting transactions for block
+// templates, and returns its total fee delta. The fee delta is discarded when the transaction
+// leaves the mempool.
+func (mm *miningManager) PrioritiseTransaction(transactionID *externalapi.DomainTransactionID, feeDelta int64) (
+	totalFeeDelta int64, err error) {
+
+	totalFeeDelta, err = mm.mempool.PrioritiseTransaction(transactionID, feeDelta)
+	if err != nil {
+		return 0, err
+	}
+
+	// The cached template might not include the transaction because of its old fee delta
+	mm.ClearBlockTemplate()
+	return totalFeeDelta, nil
+}
+
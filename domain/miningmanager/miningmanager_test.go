@@ -1,6 +1,7 @@
 package miningmanager_test
 
 import (
+	"math"
 	"reflect"
 	"strings"
 	"testing"
@@ -11,6 +12,7 @@ import (
 	"github.com/karlsend/PYVERT/testfork/karlsend/util"
 	"github.com/karlsend/PYVERT/testfork/karlsend/version"
 
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/miningmanager/blocktemplatebuilder"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/miningmanager/mempool"
 
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus"
@@ -42,7 +44,8 @@ func TestValidateAndInsertTransaction(t *testing.T) {
 		tcAsConsensus := tc.(externalapi.Consensus)
 		tcAsConsensusPointer := &tcAsConsensus
 		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
-		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params))
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params),
+			blocktemplatebuilder.DefaultPolicy(&consensusConfig.Params))
 		transactionsToInsert := make([]*externalapi.DomainTransaction, 10)
 		for i := range transactionsToInsert {
 			transactionsToInsert[i] = createTransactionWithUTXOEntry(t, i, 0)
@@ -93,7 +96,8 @@ func TestImmatureSpend(t *testing.T) {
 		tcAsConsensus := tc.(externalapi.Consensus)
 		tcAsConsensusPointer := &tcAsConsensus
 		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
-		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params))
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params),
+			blocktemplatebuilder.DefaultPolicy(&consensusConfig.Params))
 		tx := createTransactionWithUTXOEntry(t, 0, consensusConfig.GenesisBlock.Header.DAAScore())
 		_, err = miningManager.ValidateAndInsertTransaction(tx, false, false)
 		txRuleError := &mempool.TxRuleError{}
@@ -123,7 +127,8 @@ func TestInsertDoubleTransactionsToMempool(t *testing.T) {
 		tcAsConsensus := tc.(externalapi.Consensus)
 		tcAsConsensusPointer := &tcAsConsensus
 		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
-		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params))
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params),
+			blocktemplatebuilder.DefaultPolicy(&consensusConfig.Params))
 		transaction := createTransactionWithUTXOEntry(t, 0, 0)
 		_, err = miningManager.ValidateAndInsertTransaction(transaction, false, true)
 		if err != nil {
@@ -152,7 +157,8 @@ func TestDoubleSpendInMempool(t *testing.T) {
 		tcAsConsensus := tc.(externalapi.Consensus)
 		tcAsConsensusPointer := &tcAsConsensus
 		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
-		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params))
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params),
+			blocktemplatebuilder.DefaultPolicy(&consensusConfig.Params))
 		transaction, err := createChildAndParentTxsAndAddParentToConsensus(tc)
 		if err != nil {
 			t.Fatalf("Error creating transaction: %+v", err)
@@ -188,7 +194,8 @@ func TestHandleNewBlockTransactions(t *testing.T) {
 		tcAsConsensus := tc.(externalapi.Consensus)
 		tcAsConsensusPointer := &tcAsConsensus
 		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
-		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params))
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params),
+			blocktemplatebuilder.DefaultPolicy(&consensusConfig.Params))
 		transactionsToInsert := make([]*externalapi.DomainTransaction, 10)
 		for i := range transactionsToInsert {
 			transaction := createTransactionWithUTXOEntry(t, i, 0)
@@ -234,6 +241,95 @@ func TestHandleNewBlockTransactions(t *testing.T) {
 	})
 }
 
+// TestPrioritiseTransaction verifies that the fee deltas of mempool transactions accumulate,
+// don't change their fees, and are discarded with the transactions.
+func TestPrioritiseTransaction(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.BlockCoinbaseMaturity = 0
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestPrioritiseTransaction")
+		if err != nil {
+			t.Fatalf("Error setting up TestConsensus: %+v", err)
+		}
+		defer teardown(false)
+
+		miningFactory := miningmanager.NewFactory()
+		tcAsConsensus := tc.(externalapi.Consensus)
+		tcAsConsensusPointer := &tcAsConsensus
+		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params),
+			blocktemplatebuilder.DefaultPolicy(&consensusConfig.Params))
+		transaction := createTransactionWithUTXOEntry(t, 0, 0)
+		_, err = miningManager.ValidateAndInsertTransaction(transaction, false, true)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %v", err)
+		}
+		transactionID := consensushashing.TransactionID(transaction)
+
+		totalFeeDelta, err := miningManager.PrioritiseTransaction(transactionID, 1000)
+		if err != nil {
+			t.Fatalf("PrioritiseTransaction: %v", err)
+		}
+		if totalFeeDelta != 1000 {
+			t.Fatalf("Unexpected total fee delta: expected: %d, got: %d", 1000, totalFeeDelta)
+		}
+		totalFeeDelta, err = miningManager.PrioritiseTransaction(transactionID, -1300)
+		if err != nil {
+			t.Fatalf("PrioritiseTransaction: %v", err)
+		}
+		if totalFeeDelta != -300 {
+			t.Fatalf("Unexpected total fee delta: expected: %d, got: %d", -300, totalFeeDelta)
+		}
+
+		// The total fee delta saturates rather than overflows
+		for _, test := range []struct {
+			feeDelta              int64
+			expectedTotalFeeDelta int64
+		}{
+			{feeDelta: math.MaxInt64, expectedTotalFeeDelta: math.MaxInt64 - 300},
+			{feeDelta: math.MaxInt64, expectedTotalFeeDelta: math.MaxInt64},
+			{feeDelta: math.MinInt64, expectedTotalFeeDelta: -1},
+			{feeDelta: math.MinInt64, expectedTotalFeeDelta: math.MinInt64},
+			{feeDelta: -1, expectedTotalFeeDelta: math.MinInt64},
+			{feeDelta: math.MaxInt64, expectedTotalFeeDelta: -1},
+			{feeDelta: -299, expectedTotalFeeDelta: -300},
+		} {
+			totalFeeDelta, err = miningManager.PrioritiseTransaction(transactionID, test.feeDelta)
+			if err != nil {
+				t.Fatalf("PrioritiseTransaction: %v", err)
+			}
+			if totalFeeDelta != test.expectedTotalFeeDelta {
+				t.Fatalf("Unexpected total fee delta after adding %d: expected: %d, got: %d",
+					test.feeDelta, test.expectedTotalFeeDelta, totalFeeDelta)
+			}
+		}
+
+		mempoolTransaction, _, found := miningManager.GetTransaction(transactionID, true, false)
+		if !found {
+			t.Fatalf("Missing transaction %s in the mempool", transactionID)
+		}
+		if mempoolTransaction.Fee != transaction.Fee {
+			t.Fatalf("PrioritiseTransaction changed the fee of the transaction from %d to %d",
+				transaction.Fee, mempoolTransaction.Fee)
+		}
+
+		notInMempoolTransactionID := consensushashing.TransactionID(createTransactionWithUTXOEntry(t, 1, 0))
+		_, err = miningManager.PrioritiseTransaction(notInMempoolTransactionID, 1000)
+		if err == nil {
+			t.Fatalf("PrioritiseTransaction: expected an error for a transaction that is not in the mempool")
+		}
+
+		_, err = miningManager.HandleNewBlockTransactions([]*externalapi.DomainTransaction{nil, transaction})
+		if err != nil {
+			t.Fatalf("HandleNewBlockTransactions: %v", err)
+		}
+		_, err = miningManager.PrioritiseTransaction(transactionID, 1000)
+		if err == nil {
+			t.Fatalf("PrioritiseTransaction: expected an error for a transaction that was removed from the mempool")
+		}
+	})
+}
+
 func domainBlocksToBlockIds(blocks []*externalapi.DomainTransaction) []*externalapi.DomainTransactionID {
 	blockIDs := make([]*externalapi.DomainTransactionID, len(blocks))
 	for i := range blockIDs {
@@ -258,7 +354,8 @@ func TestDoubleSpendWithBlock(t *testing.T) {
 		tcAsConsensus := tc.(externalapi.Consensus)
 		tcAsConsensusPointer := &tcAsConsensus
 		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
-		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params))
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params),
+			blocktemplatebuilder.DefaultPolicy(&consensusConfig.Params))
 		transactionInTheMempool := createTransactionWithUTXOEntry(t, 0, 0)
 		_, err = miningManager.ValidateAndInsertTransaction(transactionInTheMempool, false, true)
 		if err != nil {
@@ -294,7 +391,8 @@ func TestOrphanTransactions(t *testing.T) {
 		tcAsConsensus := tc.(externalapi.Consensus)
 		tcAsConsensusPointer := &tcAsConsensus
 		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
-		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params))
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params),
+			blocktemplatebuilder.DefaultPolicy(&consensusConfig.Params))
 		// Before each parent transaction, We will add two blocks by consensus in order to fund the parent transactions.
 		parentTransactions, childTransactions, err := createArraysOfParentAndChildrenTransactions(tc)
 		if err != nil {
@@ -397,7 +495,8 @@ func TestHighPriorityTransactions(t *testing.T) {
 		tcAsConsensus := tc.(externalapi.Consensus)
 		tcAsConsensusPointer := &tcAsConsensus
 		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
-		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig,
+			blocktemplatebuilder.DefaultPolicy(&consensusConfig.Params))
 
 		// Create 3 pairs of transaction parent-and-child pairs: 1 low priority and 2 high priority
 		lowPriorityParentTransaction, lowPriorityChildTransaction, err := createParentAndChildrenTransactions(tc)
@@ -490,7 +589,8 @@ func TestRevalidateHighPriorityTransactions(t *testing.T) {
 		tcAsConsensus := tc.(externalapi.Consensus)
 		tcAsConsensusPointer := &tcAsConsensus
 		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
-		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig,
+			blocktemplatebuilder.DefaultPolicy(&consensusConfig.Params))
 
 		// Create two valid transactions that double-spend each other (childTransaction1, childTransaction2)
 		parentTransaction, childTransaction1, err := createParentAndChildrenTransactions(tc)
@@ -593,7 +693,8 @@ func TestRevalidateHighPriorityTransactionsWithChain(t *testing.T) {
 		tcAsConsensus := tc.(externalapi.Consensus)
 		tcAsConsensusPointer := &tcAsConsensus
 		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
-		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig,
+			blocktemplatebuilder.DefaultPolicy(&consensusConfig.Params))
 
 		const chainSize = 10
 		chain, err := createTxChain(tc, chainSize)
@@ -659,7 +760,8 @@ func TestModifyBlockTemplate(t *testing.T) {
 		tcAsConsensus := tc.(externalapi.Consensus)
 		tcAsConsensusPointer := &tcAsConsensus
 		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
-		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params))
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params),
+			blocktemplatebuilder.DefaultPolicy(&consensusConfig.Params))
 
 		// Create some complex transactions. Logic taken from TestOrphanTransactions
 
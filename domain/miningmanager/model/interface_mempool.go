@@ -10,6 +10,8 @@ import (
 type Mempool interface {
 	HandleNewBlockTransactions(txs []*externalapi.DomainTransaction) ([]*externalapi.DomainTransaction, error)
 	BlockCandidateTransactions() []*externalapi.DomainTransaction
+	PrioritiseTransaction(transactionID *externalapi.DomainTransactionID, feeDelta int64) (totalFeeDelta int64, err error)
+	TransactionFeeDeltas() map[externalapi.DomainTransactionID]int64
 	ValidateAndInsertTransaction(transaction *externalapi.DomainTransaction, isHighPriority bool, allowOrphan bool) (
 		acceptedTransactions []*externalapi.DomainTransaction, err error)
 	RemoveInvalidTransactions(err *ruleerrors.ErrInvalidTransactionsInNewBlock) error
@@ -35,6 +37,7 @@ type Mempool interface {
 	) (
 		transactionPoolTransactions []*externalapi.DomainTransaction,
 		orphanPoolTransactions []*externalapi.DomainTransaction)
+	FindTransactions(isMatch func(transactionID *externalapi.DomainTransactionID) bool) []*externalapi.DomainTransaction
 	TransactionCount(
 		includeTransactionPool bool,
 		includeOrphanPool bool) int
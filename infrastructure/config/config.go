@@ -5,8 +5,10 @@
 package config
 
 import (
+	"crypto/ed25519"
 	// _ "embed" is necessary for the go:embed feature.
 	_ "embed"
+	"encoding/hex"
 	"fmt"
 	"net"
 	"os"
@@ -19,7 +21,10 @@ import (
 	"github.com/btcsuite/go-socks/socks"
 	"github.com/jessevdk/go-flags"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/processes/coinbasemanager"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/txscript"
 	"github.com/karlsend/PYVERT/testfork/karlsend/domain/dagconfig"
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/db/database/ldb"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/logger"
 	"github.com/karlsend/PYVERT/testfork/karlsend/util"
 	"github.com/karlsend/PYVERT/testfork/karlsend/util/network"
@@ -53,7 +58,12 @@ const (
 	defaultSigCacheMaxSize  = 100_000
 	sampleConfigFilename    = "sample-karlsend.conf"
 	defaultMaxUTXOCacheSize = 5_000_000_000
-	defaultProtocolVersion  = 5
+	defaultProtocolVersion  = 6
+	defaultP2PKeyFilename   = "p2p.key"
+	defaultDBCacheSizeMiB   = 256
+	// maxPayAddressScriptPublicKeyLength is the length of the longest script
+	// public key that an address pays to
+	maxPayAddressScriptPublicKeyLength = 35
 )
 
 var (
@@ -113,6 +123,10 @@ type Flags struct {
 	MinRelayTxFee                   float64       `long:"minrelaytxfee" description:"The minimum transaction fee in KAS/kB to be considered a non-zero fee."`
 	MaxOrphanTxs                    uint64        `long:"maxorphantx" description:"Max number of orphan transactions to keep in memory"`
 	BlockMaxMass                    uint64        `long:"blockmaxmass" description:"Maximum transaction mass to be used when creating a block"`
+	TemplateHighFeeRatio            float64       `long:"template-high-fee-ratio" description:"Fraction of the max block mass that is reserved in block templates for the transactions with the highest fee rates (between 0 and 1)"`
+	TemplatePriorityAddresses       []string      `long:"template-priority-address" description:"Select the transactions that spend from this address, such as the payouts of a pool, before any other transaction in block templates. May be given multiple times"`
+	TemplateExcludedScriptClasses   []string      `long:"template-exclude-script-class" description:"Never select transactions with an output of this script class {nonstandard, pubkey, pubkeyecdsa, scripthash} in block templates. May be given multiple times"`
+	MinerTag                        string        `long:"miner-tag" description:"Tag to add to the coinbase payload of block templates whose request has no extra data, so that the mined blocks can be attributed to this node"`
 	UserAgentComments               []string      `long:"uacomment" description:"Comment to add to the user agent -- See BIP 14 for more information."`
 	NoPeerBloomFilters              bool          `long:"nopeerbloomfilters" description:"Disable bloom filtering support"`
 	SigCacheMaxSize                 uint          `long:"sigcachemaxsize" description:"The maximum number of entries in the signature verification cache"`
@@ -126,6 +140,23 @@ type Flags struct {
 	AllowSubmitBlockWhenNotSynced   bool          `long:"allow-submit-block-when-not-synced" hidden:"true" description:"Allow the node to accept blocks from RPC while not synced (this flag is mainly used for testing)"`
 	EnableSanityCheckPruningUTXOSet bool          `long:"enable-sanity-check-pruning-utxo" hidden:"true" description:"When moving the pruning point - check that the utxo set matches the utxo commitment"`
 	ProtocolVersion                 uint32        `long:"protocol-version" description:"Use non default p2p protocol version"`
+	P2PEncryption                   bool          `long:"p2p-encryption" description:"Encrypt P2P connections with TLS, falling back to plaintext for peers that don't support it"`
+	P2PEncryptionRequired           bool          `long:"p2p-encryption-required" description:"Refuse unencrypted P2P connections (implies --p2p-encryption)"`
+	P2PKey                          string        `long:"p2pkey" description:"File containing this node's P2P identity key. A new key is generated if the file doesn't exist (default: p2p.key in the network's data directory)"`
+	P2PAllowedPeerKeys              []string      `long:"p2p-allowed-peer-key" description:"Only allow P2P connections with peers that authenticate with this hex encoded public key. May be given multiple times (implies --p2p-encryption-required)"`
+	UploadLimit                     uint64        `long:"upload-limit" description:"Maximum rate in KB/s at which IBD blocks and pruning point UTXO sets are served to all peers combined (0 for unlimited)"`
+	PeerUploadLimit                 uint64        `long:"peer-upload-limit" description:"Maximum rate in KB/s at which IBD blocks and pruning point UTXO sets are served to a single peer (0 for unlimited)"`
+	Dandelion                       bool          `long:"dandelion" description:"Relay transactions through a random path of peers (stem phase) before broadcasting them to all peers (fluff phase) to hide their origin"`
+	ExportBootstrap                 string        `long:"export-bootstrap" description:"Export the pruning point, its UTXO set and all the blocks above it to a bootstrap file, and exit"`
+	ImportBootstrap                 string        `long:"import-bootstrap" description:"Import a bootstrap file that was created with --export-bootstrap before starting the node"`
+	VerifyDatabase                  bool          `long:"verify-db" description:"Verify the integrity of the consensus database, print a report and exit"`
+	RestoreSnapshot                 string        `long:"restore-snapshot" description:"Replace the database with a snapshot that was created by the CreateSnapshot RPC command before starting the node"`
+	DBCacheSizeMiB                  int           `long:"db-cache-mb" description:"Size of the database block cache in MiB"`
+	DBWriteBufferSizeMiB            int           `long:"db-write-buffer-mb" description:"Size of the database write buffer in MiB (default: half of the cache size)"`
+	DBBlockSizeKiB                  int           `long:"db-block-size-kb" description:"Size of the database blocks in KiB"`
+	DBCompression                   string        `long:"db-compression" description:"Compression of the database blocks {none, snappy}"`
+	DBSync                          string        `long:"db-sync" description:"When database writes are synced to disk {never, always, periodic} -- never is the fastest, but recent writes might be lost on a crash"`
+	Checkpoints                     []string      `long:"checkpoint" description:"Add a checkpoint in the form <DAA score>:<block hash>. Headers and pruning point proofs that conflict with it are rejected. May be given multiple times"`
 	NetworkFlags
 	ServiceOptions *ServiceOptions
 }
@@ -135,12 +166,16 @@ type Flags struct {
 // See loadConfig for details on the configuration load process.
 type Config struct {
 	*Flags
-	Lookup        func(string) ([]net.IP, error)
-	Dial          func(string, string, time.Duration) (net.Conn, error)
-	MiningAddrs   []util.Address
-	MinRelayTxFee util.Amount
-	Whitelists    []*net.IPNet
-	SubnetworkID  *externalapi.DomainSubnetworkID // nil in full nodes
+	Lookup                   func(string) ([]net.IP, error)
+	Dial                     func(string, string, time.Duration) (net.Conn, error)
+	MiningAddrs              []util.Address
+	MinRelayTxFee            util.Amount
+	Whitelists               []*net.IPNet
+	SubnetworkID             *externalapi.DomainSubnetworkID // nil in full nodes
+	P2PAllowedPeerPublicKeys []ed25519.PublicKey
+
+	TemplatePriorityScriptPublicKeys []*externalapi.ScriptPublicKey
+	TemplateExcludedScriptClasses    []txscript.ScriptClass
 }
 
 // ServiceOptions defines the configuration options for the daemon as a service on
@@ -173,6 +208,7 @@ func newConfigParser(cfgFlags *Flags, options flags.Options) *flags.Parser {
 }
 
 func defaultFlags() *Flags {
+	defaultDBConfig := ldb.DefaultConfig(defaultDBCacheSizeMiB)
 	return &Flags{
 		ConfigFile:           defaultConfigFile,
 		LogLevel:             defaultLogLevel,
@@ -193,6 +229,10 @@ func defaultFlags() *Flags {
 		MaxUTXOCacheSize:     defaultMaxUTXOCacheSize,
 		ServiceOptions:       &ServiceOptions{},
 		ProtocolVersion:      defaultProtocolVersion,
+		DBCacheSizeMiB:       defaultDBCacheSizeMiB,
+		DBBlockSizeKiB:       defaultDBConfig.BlockSizeKiB,
+		DBCompression:        string(defaultDBConfig.Compression),
+		DBSync:               string(defaultDBConfig.SyncMode),
 	}
 }
 
@@ -203,6 +243,22 @@ func DefaultConfig() *Config {
 	return config
 }
 
+// DBConfig returns the LevelDB config that is defined by the database flags.
+// Unless it's set explicitly, the write buffer size is derived from the cache size.
+func (cfg *Config) DBConfig() *ldb.Config {
+	writeBufferSizeMiB := cfg.DBWriteBufferSizeMiB
+	if writeBufferSizeMiB == 0 {
+		writeBufferSizeMiB = ldb.DefaultConfig(cfg.DBCacheSizeMiB).WriteBufferSizeMiB
+	}
+	return &ldb.Config{
+		CacheSizeMiB:       cfg.DBCacheSizeMiB,
+		WriteBufferSizeMiB: writeBufferSizeMiB,
+		BlockSizeKiB:       cfg.DBBlockSizeKiB,
+		Compression:        ldb.Compression(cfg.DBCompression),
+		SyncMode:           ldb.SyncMode(cfg.DBSync),
+	}
+}
+
 // LoadConfig initializes and parses the config using a config file and command
 // line options.
 //
@@ -328,12 +384,44 @@ func LoadConfig() (*Config, error) {
 	// worry about changing names per network and such.
 	cfg.AppDir = filepath.Join(cfg.AppDir, cfg.NetParams().Name)
 
+	// The P2P key is usually under the network's data directory, unless otherwise specified
+	if cfg.P2PKey == "" {
+		cfg.P2PKey = filepath.Join(cfg.AppDir, defaultP2PKeyFilename)
+	}
+	cfg.P2PKey = cleanAndExpandPath(cfg.P2PKey)
+
 	// Logs directory is usually under the home directory, unless otherwise specified
 	if cfg.LogDir == "" {
 		cfg.LogDir = filepath.Join(cfg.AppDir, defaultLogDirname)
 	}
 	cfg.LogDir = cleanAndExpandPath(cfg.LogDir)
 
+	err = cfg.DBConfig().Validate()
+	if err != nil {
+		err := errors.Errorf("%s: invalid database options: %s", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// --export-bootstrap and --import-bootstrap do not mix.
+	if cfg.ExportBootstrap != "" && cfg.ImportBootstrap != "" {
+		str := "%s: the --export-bootstrap and --import-bootstrap options can not be mixed"
+		err := errors.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.ExportBootstrap != "" {
+		cfg.ExportBootstrap = cleanAndExpandPath(cfg.ExportBootstrap)
+	}
+	if cfg.ImportBootstrap != "" {
+		cfg.ImportBootstrap = cleanAndExpandPath(cfg.ImportBootstrap)
+	}
+	if cfg.RestoreSnapshot != "" {
+		cfg.RestoreSnapshot = cleanAndExpandPath(cfg.RestoreSnapshot)
+	}
+
 	// Special show command to list supported subsystems and exit.
 	if cfg.LogLevel == "show" {
 		fmt.Println("Supported subsystems", logger.SupportedSubsystems())
@@ -405,6 +493,46 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	// Validate the allowed P2P peer keys. Restricting peers by key is only
+	// possible over encrypted connections, so it implies that encryption is required.
+	if len(cfg.P2PAllowedPeerKeys) > 0 {
+		cfg.P2PAllowedPeerPublicKeys = make([]ed25519.PublicKey, 0, len(cfg.P2PAllowedPeerKeys))
+		for _, keyString := range cfg.P2PAllowedPeerKeys {
+			key, err := hex.DecodeString(keyString)
+			if err != nil || len(key) != ed25519.PublicKeySize {
+				str := "%s: The p2p-allowed-peer-key value of '%s' is not a hex encoded %d-byte public key"
+				err := errors.Errorf(str, funcName, keyString, ed25519.PublicKeySize)
+				fmt.Fprintln(os.Stderr, err)
+				fmt.Fprintln(os.Stderr, usageMessage)
+				return nil, err
+			}
+			cfg.P2PAllowedPeerPublicKeys = append(cfg.P2PAllowedPeerPublicKeys, key)
+		}
+		cfg.P2PEncryptionRequired = true
+	}
+	if cfg.P2PEncryptionRequired {
+		cfg.P2PEncryption = true
+	}
+
+	// Add the given checkpoints to the ones of the network. The network parameters are
+	// copied so that the checkpoints don't leak into the parameters of the network itself.
+	if len(cfg.Flags.Checkpoints) > 0 {
+		netParams := *cfg.ActiveNetParams
+		netParams.Checkpoints = append([]dagconfig.Checkpoint{}, netParams.Checkpoints...)
+		for _, checkpointString := range cfg.Flags.Checkpoints {
+			checkpoint, err := dagconfig.ParseCheckpoint(checkpointString)
+			if err != nil {
+				str := "%s: %s"
+				err := errors.Errorf(str, funcName, err)
+				fmt.Fprintln(os.Stderr, err)
+				fmt.Fprintln(os.Stderr, usageMessage)
+				return nil, err
+			}
+			netParams.Checkpoints = append(netParams.Checkpoints, checkpoint)
+		}
+		cfg.ActiveNetParams = &netParams
+	}
+
 	// --addPeer and --connect do not mix.
 	if len(cfg.AddPeers) > 0 && len(cfg.ConnectPeers) > 0 {
 		str := "%s: the --addpeer and --connect options can not be " +
@@ -490,6 +618,56 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	// Validate the block template policy.
+	if cfg.TemplateHighFeeRatio < 0 || cfg.TemplateHighFeeRatio > 1 {
+		str := "%s: The template-high-fee-ratio option must be between 0 and 1 -- parsed [%f]"
+		err := errors.Errorf(str, funcName, cfg.TemplateHighFeeRatio)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	for _, addressString := range cfg.TemplatePriorityAddresses {
+		address, err := util.DecodeAddress(addressString, cfg.ActiveNetParams.Prefix)
+		if err != nil {
+			str := "%s: The template-priority-address value of '%s' is invalid: %s"
+			err := errors.Errorf(str, funcName, addressString, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, err
+		}
+		scriptPublicKey, err := txscript.PayToAddrScript(address)
+		if err != nil {
+			return nil, err
+		}
+		cfg.TemplatePriorityScriptPublicKeys = append(cfg.TemplatePriorityScriptPublicKeys, scriptPublicKey)
+	}
+	for _, scriptClassName := range cfg.Flags.TemplateExcludedScriptClasses {
+		scriptClass, ok := txscript.ScriptClassFromName(scriptClassName)
+		if !ok {
+			str := "%s: The template-exclude-script-class value of '%s' is not a script class"
+			err := errors.Errorf(str, funcName, scriptClassName)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, err
+		}
+		cfg.TemplateExcludedScriptClasses = append(cfg.TemplateExcludedScriptClasses, scriptClass)
+	}
+
+	// Make sure that the miner tag fits in the coinbase payload along with the
+	// node version that GetBlockTemplate prefixes it with, whatever the mining
+	// address is.
+	minerTagCoinbaseData := &externalapi.DomainCoinbaseData{
+		ScriptPublicKey: &externalapi.ScriptPublicKey{Script: make([]byte, maxPayAddressScriptPublicKeyLength)},
+		ExtraData:       []byte(version.Version() + "/" + cfg.MinerTag),
+	}
+	if uint64(coinbasemanager.CoinbasePayloadLength(minerTagCoinbaseData)) > cfg.ActiveNetParams.MaxCoinbasePayloadLength {
+		str := "%s: The miner-tag option is too long to fit in the coinbase payload -- parsed [%s]"
+		err := errors.Errorf(str, funcName, cfg.MinerTag)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
 	// Look for illegal characters in the user agent comments.
 	for _, uaComment := range cfg.UserAgentComments {
 		if strings.ContainsAny(uaComment, "/:()") {
@@ -71,3 +71,24 @@ func TestConstants(t *testing.T) {
 	}
 }
 
+func TestDBConfigWriteBufferSize(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.DBConfig().WriteBufferSizeMiB != defaultDBCacheSizeMiB/2 {
+		t.Fatalf("Expected the default write buffer size to be %d MiB, but got %d MiB",
+			defaultDBCacheSizeMiB/2, cfg.DBConfig().WriteBufferSizeMiB)
+	}
+
+	// The default write buffer size follows the configured cache size
+	cfg.DBCacheSizeMiB = 1024
+	if cfg.DBConfig().WriteBufferSizeMiB != 512 {
+		t.Fatalf("Expected the write buffer size to be half of a cache of 1024 MiB, but got %d MiB",
+			cfg.DBConfig().WriteBufferSizeMiB)
+	}
+
+	cfg.DBWriteBufferSizeMiB = 64
+	if cfg.DBConfig().WriteBufferSizeMiB != 64 {
+		t.Fatalf("Expected the explicitly set write buffer size of 64 MiB, but got %d MiB",
+			cfg.DBConfig().WriteBufferSizeMiB)
+	}
+}
+
@@ -21,6 +21,7 @@ type NetworkFlags struct {
 	Simnet                bool   `long:"simnet" description:"Use the simulation test network"`
 	Devnet                bool   `long:"devnet" description:"Use the development test network"`
 	OverrideDAGParamsFile string `long:"override-dag-params-file" description:"Overrides DAG params (allowed only on devnet)"`
+	NetworkDefinitionFile string `long:"network-definition-file" description:"Use the custom network defined in the given JSON file"`
 
 	ActiveNetParams *dagconfig.Params
 }
@@ -50,6 +51,8 @@ type overrideDAGParamsConfig struct {
 	DisableDifficultyAdjustment             *bool              `json:"disableDifficultyAdjustment"`
 	SkipProofOfWork                         *bool              `json:"skipProofOfWork"`
 	HardForkOmitGenesisFromParentsDAAScore  *uint64            `json:"hardForkOmitGenesisFromParentsDaaScore"`
+	FishHashActivationDAAScore              *uint64            `json:"fishHashActivationDaaScore"`
+	FishHashActivationBits                  *uint32            `json:"fishHashActivationBits"`
 }
 
 // ResolveNetwork parses the network command line argument and sets NetParams accordingly.
@@ -74,6 +77,14 @@ func (networkFlags *NetworkFlags) ResolveNetwork(parser *flags.Parser) error {
 		numNets++
 		networkFlags.ActiveNetParams = &dagconfig.DevnetParams
 	}
+	if networkFlags.NetworkDefinitionFile != "" {
+		numNets++
+		params, err := loadNetworkDefinition(networkFlags.NetworkDefinitionFile)
+		if err != nil {
+			return err
+		}
+		networkFlags.ActiveNetParams = params
+	}
 	if numNets > 1 {
 		message := "Multiple networks parameters (testnet, simnet, devnet, etc.) cannot be used" +
 			"together. Please choose only one network"
@@ -91,6 +102,29 @@ func (networkFlags *NetworkFlags) ResolveNetwork(parser *flags.Parser) error {
 	return nil
 }
 
+// loadNetworkDefinition returns the network parameters of the given network definition file,
+// and registers the network. The definition must pin its genesis hash, so that nodes that
+// don't agree on the genesis block fail to start instead of forming separate networks.
+func loadNetworkDefinition(path string) (*dagconfig.Params, error) {
+	definition, err := dagconfig.ReadNetworkDefinition(path)
+	if err != nil {
+		return nil, err
+	}
+	if definition.Genesis.Hash == "" {
+		return nil, errors.Errorf("the network definition in %s doesn't specify the genesis hash. "+
+			"Use `karlsennetwork genesis` to mine the genesis block and get its hash", path)
+	}
+	params, err := definition.Params()
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid network definition in %s", path)
+	}
+	err = dagconfig.Register(params)
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't register the network defined in %s", path)
+	}
+	return params, nil
+}
+
 // NetParams returns the ActiveNetParams
 func (networkFlags *NetworkFlags) NetParams() *dagconfig.Params {
 	return networkFlags.ActiveNetParams
@@ -214,6 +248,14 @@ func (networkFlags *NetworkFlags) overrideDAGParams() error {
 		networkFlags.ActiveNetParams.SkipProofOfWork = *config.SkipProofOfWork
 	}
 
+	if config.FishHashActivationDAAScore != nil {
+		networkFlags.ActiveNetParams.FishHashActivationDAAScore = *config.FishHashActivationDAAScore
+	}
+
+	if config.FishHashActivationBits != nil {
+		networkFlags.ActiveNetParams.FishHashActivationBits = *config.FishHashActivationBits
+	}
+
 	return nil
 }
 
@@ -0,0 +1,89 @@
+package ldb
+
+import (
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+// backupBatchSize is the size in bytes of the batches that are
+// written to the backup database
+const backupBatchSize = 16 * opt.MiB
+
+// backupCacheSizeMiB is the cache size of the backup database
+const backupCacheSizeMiB = 8
+
+// Snapshot is a consistent read-only view of the database at
+// the time it was taken. It must be released once it's no longer
+// needed.
+type Snapshot struct {
+	snapshot *leveldb.Snapshot
+}
+
+// Snapshot takes a snapshot of the database. Writes that are done after
+// the snapshot was taken are not visible through it.
+func (db *LevelDB) Snapshot() (*Snapshot, error) {
+	snapshot, err := db.ldb.GetSnapshot()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &Snapshot{snapshot: snapshot}, nil
+}
+
+// Release releases the snapshot
+func (s *Snapshot) Release() {
+	s.snapshot.Release()
+}
+
+// Backup copies all the data in the snapshot into a new LevelDB database
+// in the given path. The database is written while the original database
+// keeps being used, and every batch that's written to it is synced to disk.
+func (s *Snapshot) Backup(path string) (keyCount uint64, err error) {
+	config := DefaultConfig(backupCacheSizeMiB)
+	config.SyncMode = SyncAlways
+	options := optionsFromConfig(config)
+	options.ErrorIfExist = true
+	backup, err := leveldb.OpenFile(path, &options)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	defer func() {
+		closeErr := backup.Close()
+		if err == nil {
+			err = errors.WithStack(closeErr)
+		}
+	}()
+
+	writeOptions := &opt.WriteOptions{Sync: true}
+	iterator := s.snapshot.NewIterator(nil, nil)
+	defer iterator.Release()
+
+	batch := new(leveldb.Batch)
+	batchSize := 0
+	for iterator.Next() {
+		// The iterator reuses its buffers, and Batch.Put copies them
+		batch.Put(iterator.Key(), iterator.Value())
+		batchSize += len(iterator.Key()) + len(iterator.Value())
+		keyCount++
+
+		if batchSize >= backupBatchSize {
+			err := backup.Write(batch, writeOptions)
+			if err != nil {
+				return 0, errors.WithStack(err)
+			}
+			batch.Reset()
+			batchSize = 0
+		}
+	}
+	err = iterator.Error()
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	err = backup.Write(batch, writeOptions)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return keyCount, nil
+}
+
@@ -1,6 +1,9 @@
 package ldb
 
 import (
+	"sync/atomic"
+	"time"
+
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/db/database"
 	"github.com/pkg/errors"
 	"github.com/syndtr/goleveldb/leveldb"
@@ -11,15 +14,30 @@ import (
 
 // LevelDB defines a thin wrapper around leveldb.
 type LevelDB struct {
-	ldb *leveldb.DB
+	ldb      *leveldb.DB
+	syncMode SyncMode
+
+	// lastSyncNanoseconds is the unix time of the last
+	// sync. It's only used in SyncPeriodic mode
+	lastSyncNanoseconds int64
 }
 
-// NewLevelDB opens a leveldb instance defined by the given path.
+// NewLevelDB opens a leveldb instance defined by the given path,
+// with the default config for the given cache size.
 func NewLevelDB(path string, cacheSizeMiB int) (*LevelDB, error) {
+	return NewLevelDBWithConfig(path, DefaultConfig(cacheSizeMiB))
+}
+
+// NewLevelDBWithConfig opens a leveldb instance defined by the given path,
+// with the given config.
+func NewLevelDBWithConfig(path string, config *Config) (*LevelDB, error) {
+	err := config.Validate()
+	if err != nil {
+		return nil, err
+	}
+
 	// Open leveldb. If it doesn't exist, create it.
-	options := Options()
-	options.BlockCacheCapacity = cacheSizeMiB * opt.MiB
-	options.WriteBuffer = (cacheSizeMiB * opt.MiB) / 2
+	options := optionsFromConfig(config)
 	ldb, err := leveldb.OpenFile(path, &options)
 
 	// If the database is corrupted, attempt to recover.
@@ -27,7 +45,7 @@ func NewLevelDB(path string, cacheSizeMiB int) (*LevelDB, error) {
 		log.Warnf("LevelDB corruption detected for path %s: %s",
 			path, err)
 		var recoverErr error
-		ldb, recoverErr = leveldb.RecoverFile(path, nil)
+		ldb, recoverErr = leveldb.RecoverFile(path, &options)
 		if recoverErr != nil {
 			return nil, errors.Wrapf(err, "failed recovering from "+
 				"database corruption: %s", recoverErr)
@@ -43,11 +61,31 @@ func NewLevelDB(path string, cacheSizeMiB int) (*LevelDB, error) {
 	}
 
 	db := &LevelDB{
-		ldb: ldb,
+		ldb:                 ldb,
+		syncMode:            config.SyncMode,
+		lastSyncNanoseconds: time.Now().UnixNano(),
 	}
 	return db, nil
 }
 
+// writeOptions returns the write options of the next write,
+// according to the sync mode of the database.
+func (db *LevelDB) writeOptions() *opt.WriteOptions {
+	switch db.syncMode {
+	case SyncAlways:
+		return &opt.WriteOptions{Sync: true}
+	case SyncPeriodic:
+		now := time.Now().UnixNano()
+		lastSync := atomic.LoadInt64(&db.lastSyncNanoseconds)
+		if now-lastSync >= int64(periodicSyncInterval) &&
+			atomic.CompareAndSwapInt64(&db.lastSyncNanoseconds, lastSync, now) {
+
+			return &opt.WriteOptions{Sync: true}
+		}
+	}
+	return nil
+}
+
 // Compact compacts the leveldb instance.
 func (db *LevelDB) Compact() error {
 	err := db.ldb.CompactRange(util.Range{Start: nil, Limit: nil})
@@ -63,7 +101,7 @@ func (db *LevelDB) Close() error {
 // Put sets the value for the given key. It overwrites
 // any previous value for that key.
 func (db *LevelDB) Put(key *database.Key, value []byte) error {
-	err := db.ldb.Put(key.Bytes(), value, nil)
+	err := db.ldb.Put(key.Bytes(), value, db.writeOptions())
 	return errors.WithStack(err)
 }
 
@@ -94,7 +132,7 @@ func (db *LevelDB) Has(key *database.Key) (bool, error) {
 // Delete deletes the value for the given key. Will not
 // return an error if the key doesn't exist.
 func (db *LevelDB) Delete(key *database.Key) error {
-	err := db.ldb.Delete(key.Bytes(), nil)
+	err := db.ldb.Delete(key.Bytes(), db.writeOptions())
 	return errors.WithStack(err)
 }
 
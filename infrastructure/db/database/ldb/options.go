@@ -1,6 +1,97 @@
 package ldb
 
-import "github.com/syndtr/goleveldb/leveldb/opt"
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+// Compression is the compression algorithm of the LevelDB blocks
+type Compression string
+
+// The supported compression algorithms
+const (
+	CompressionNone   Compression = "none"
+	CompressionSnappy Compression = "snappy"
+)
+
+// SyncMode defines when writes to LevelDB are synced to disk
+type SyncMode string
+
+// The supported sync modes
+const (
+	// SyncNever leaves syncing to the operating system. This is the fastest mode,
+	// but writes that weren't flushed yet might be lost on a crash
+	SyncNever SyncMode = "never"
+
+	// SyncAlways syncs every write to disk before it returns
+	SyncAlways SyncMode = "always"
+
+	// SyncPeriodic syncs the first write after every periodicSyncInterval,
+	// which also syncs all the writes that preceded it
+	SyncPeriodic SyncMode = "periodic"
+)
+
+// periodicSyncInterval is the minimal time between syncs in SyncPeriodic mode
+const periodicSyncInterval = time.Second
+
+// Config defines the tuning and durability options of a LevelDB instance
+type Config struct {
+	CacheSizeMiB       int
+	WriteBufferSizeMiB int
+	BlockSizeKiB       int
+	Compression        Compression
+	SyncMode           SyncMode
+}
+
+// DefaultConfig returns the config with the given cache size, a write
+// buffer of half the cache size but at least 1 MiB, no compression, and no syncing
+func DefaultConfig(cacheSizeMiB int) *Config {
+	writeBufferSizeMiB := cacheSizeMiB / 2
+	if writeBufferSizeMiB < 1 {
+		writeBufferSizeMiB = 1
+	}
+	return &Config{
+		CacheSizeMiB:       cacheSizeMiB,
+		WriteBufferSizeMiB: writeBufferSizeMiB,
+		BlockSizeKiB:       opt.DefaultBlockSize / opt.KiB,
+		Compression:        CompressionNone,
+		SyncMode:           SyncNever,
+	}
+}
+
+// Validate returns an error if the config contains invalid values
+func (config *Config) Validate() error {
+	if config.CacheSizeMiB <= 0 {
+		return errors.Errorf("the cache size must be positive, got %d MiB", config.CacheSizeMiB)
+	}
+	if config.WriteBufferSizeMiB <= 0 {
+		return errors.Errorf("the write buffer size must be positive, got %d MiB", config.WriteBufferSizeMiB)
+	}
+	if config.BlockSizeKiB <= 0 {
+		return errors.Errorf("the block size must be positive, got %d KiB", config.BlockSizeKiB)
+	}
+	switch config.Compression {
+	case CompressionNone, CompressionSnappy:
+	default:
+		return errors.Errorf("unknown compression %s, expected one of: %s, %s",
+			config.Compression, CompressionNone, CompressionSnappy)
+	}
+	switch config.SyncMode {
+	case SyncNever, SyncAlways, SyncPeriodic:
+	default:
+		return errors.Errorf("unknown sync mode %s, expected one of: %s, %s, %s",
+			config.SyncMode, SyncNever, SyncAlways, SyncPeriodic)
+	}
+	return nil
+}
+
+func (config *Config) String() string {
+	return fmt.Sprintf("cache: %d MiB, write buffer: %d MiB, block size: %d KiB, compression: %s, sync: %s",
+		config.CacheSizeMiB, config.WriteBufferSizeMiB, config.BlockSizeKiB, config.Compression, config.SyncMode)
+}
 
 // Options is a function that returns a leveldb
 // opt.Options struct for opening a database.
@@ -12,3 +103,17 @@ func Options() opt.Options {
 	}
 }
 
+// optionsFromConfig returns the opt.Options that correspond to the given config
+func optionsFromConfig(config *Config) opt.Options {
+	options := Options()
+	options.BlockCacheCapacity = config.CacheSizeMiB * opt.MiB
+	options.WriteBuffer = config.WriteBufferSizeMiB * opt.MiB
+	options.BlockSize = config.BlockSizeKiB * opt.KiB
+	if config.Compression == CompressionSnappy {
+		options.Compression = opt.SnappyCompression
+	}
+	// LevelDB ignores the Sync write option if NoSync is set
+	options.NoSync = config.SyncMode == SyncNever
+	return options
+}
+
@@ -0,0 +1,147 @@
+package ldb
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/db/database"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name          string
+		modify        func(config *Config)
+		expectedValid bool
+	}{
+		{
+			name:          "default",
+			modify:        func(config *Config) {},
+			expectedValid: true,
+		},
+		{
+			name:          "snappy and periodic",
+			modify:        func(config *Config) { config.Compression, config.SyncMode = CompressionSnappy, SyncPeriodic },
+			expectedValid: true,
+		},
+		{
+			name:          "zero cache size",
+			modify:        func(config *Config) { config.CacheSizeMiB = 0 },
+			expectedValid: false,
+		},
+		{
+			name:          "negative write buffer size",
+			modify:        func(config *Config) { config.WriteBufferSizeMiB = -1 },
+			expectedValid: false,
+		},
+		{
+			name:          "zero block size",
+			modify:        func(config *Config) { config.BlockSizeKiB = 0 },
+			expectedValid: false,
+		},
+		{
+			name:          "unknown compression",
+			modify:        func(config *Config) { config.Compression = "zstd" },
+			expectedValid: false,
+		},
+		{
+			name:          "unknown sync mode",
+			modify:        func(config *Config) { config.SyncMode = "sometimes" },
+			expectedValid: false,
+		},
+	}
+
+	for _, test := range tests {
+		config := DefaultConfig(8)
+		test.modify(config)
+		err := config.Validate()
+		if test.expectedValid && err != nil {
+			t.Errorf("%s: expected the config to be valid, but got: %s", test.name, err)
+		}
+		if !test.expectedValid && err == nil {
+			t.Errorf("%s: expected the config to be invalid", test.name)
+		}
+	}
+}
+
+func TestNewLevelDBWithConfig(t *testing.T) {
+	for _, compression := range []Compression{CompressionNone, CompressionSnappy} {
+		for _, syncMode := range []SyncMode{SyncNever, SyncAlways, SyncPeriodic} {
+			config := DefaultConfig(8)
+			config.Compression = compression
+			config.SyncMode = syncMode
+			testName := "TestNewLevelDBWithConfig-" + string(compression) + "-" + string(syncMode)
+
+			path, err := ioutil.TempDir("", testName)
+			if err != nil {
+				t.Fatalf("%s: TempDir unexpectedly failed: %s", testName, err)
+			}
+			defer os.RemoveAll(path)
+
+			ldb, err := NewLevelDBWithConfig(path, config)
+			if err != nil {
+				t.Fatalf("%s: NewLevelDBWithConfig unexpectedly failed: %s", testName, err)
+			}
+
+			key := database.MakeBucket(nil).Key([]byte("key"))
+			putData := []byte("Hello world!")
+			err = ldb.Put(key, putData)
+			if err != nil {
+				t.Fatalf("%s: Put unexpectedly failed: %s", testName, err)
+			}
+			err = ldb.Close()
+			if err != nil {
+				t.Fatalf("%s: Close unexpectedly failed: %s", testName, err)
+			}
+
+			// The data should be there after the database is reopened
+			ldb, err = NewLevelDBWithConfig(path, config)
+			if err != nil {
+				t.Fatalf("%s: NewLevelDBWithConfig unexpectedly failed: %s", testName, err)
+			}
+			getData, err := ldb.Get(key)
+			if err != nil {
+				t.Fatalf("%s: Get unexpectedly failed: %s", testName, err)
+			}
+			if !reflect.DeepEqual(getData, putData) {
+				t.Fatalf("%s: get data and put data are not equal. Put: %s, got: %s",
+					testName, string(putData), string(getData))
+			}
+			err = ldb.Close()
+			if err != nil {
+				t.Fatalf("%s: Close unexpectedly failed: %s", testName, err)
+			}
+		}
+	}
+}
+
+func TestPeriodicSyncWriteOptions(t *testing.T) {
+	config := DefaultConfig(8)
+	config.SyncMode = SyncPeriodic
+	path, err := ioutil.TempDir("", "TestPeriodicSyncWriteOptions")
+	if err != nil {
+		t.Fatalf("TempDir unexpectedly failed: %s", err)
+	}
+	defer os.RemoveAll(path)
+
+	ldb, err := NewLevelDBWithConfig(path, config)
+	if err != nil {
+		t.Fatalf("NewLevelDBWithConfig unexpectedly failed: %s", err)
+	}
+	defer ldb.Close()
+
+	if ldb.writeOptions().GetSync() {
+		t.Fatalf("Expected no sync right after the database was opened")
+	}
+
+	// Pretend that the last sync was an interval ago
+	ldb.lastSyncNanoseconds -= int64(periodicSyncInterval)
+	if !ldb.writeOptions().GetSync() {
+		t.Fatalf("Expected a sync once the interval had passed")
+	}
+	if ldb.writeOptions().GetSync() {
+		t.Fatalf("Expected no sync right after a sync")
+	}
+}
+
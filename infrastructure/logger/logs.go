@@ -38,6 +38,7 @@ import (
 	"os"
 	"runtime"
 	"sync/atomic"
+	"time"
 
 	"github.com/karlsend/PYVERT/testfork/karlsend/util/mstime"
 )
@@ -165,7 +166,7 @@ func (l *Logger) Backend() *Backend {
 // function and formatting the provided arguments according to the given format
 // specifier.
 func (l *Logger) printf(lvl Level, tag string, format string, args ...interface{}) {
-	t := mstime.Now() // get as early as possible
+	t := mstime.ToMSTime(time.Now()) // get as early as possible, and regardless of the mock time
 
 	var file string
 	var line int
@@ -195,7 +196,7 @@ func (l *Logger) print(lvl Level, tag string, args ...interface{}) {
 	if atomic.LoadUint32(&l.b.isRunning) == 0 {
 		panic("printing log without initializing")
 	}
-	t := mstime.Now() // get as early as possible
+	t := mstime.ToMSTime(time.Now()) // get as early as possible, and regardless of the mock time
 
 	var file string
 	var line int
@@ -43,6 +43,9 @@ func (i ipv6) equal(other ipv6) bool {
 	return i == other
 }
 
+// maxBanTime is the duration after which banned addresses are unbanned
+const maxBanTime = 24 * time.Hour
+
 // ErrAddressNotFound is an error returned from some functions when a
 // given address is not found in the address manager
 var ErrAddressNotFound = errors.New("address not found")
@@ -204,6 +207,33 @@ func (am *AddressManager) BannedAddresses() []*appmessage.NetAddress {
 	return am.store.getAllBannedNetAddresses()
 }
 
+// BannedAddress is a banned address along with the time its ban expires
+type BannedAddress struct {
+	NetAddress *appmessage.NetAddress
+	BanExpiry  mstime.Time
+}
+
+// BannedAddressesWithExpiry returns all addresses whose ban hasn't
+// expired yet, along with the time their ban expires
+func (am *AddressManager) BannedAddressesWithExpiry() []*BannedAddress {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	bannedNetAddresses := am.store.getAllBannedNetAddresses()
+	bannedAddresses := make([]*BannedAddress, 0, len(bannedNetAddresses))
+	for _, netAddress := range bannedNetAddresses {
+		banExpiry := netAddress.Timestamp.Add(maxBanTime)
+		if banExpiry.Before(mstime.Now()) {
+			continue
+		}
+		bannedAddresses = append(bannedAddresses, &BannedAddress{
+			NetAddress: netAddress,
+			BanExpiry:  banExpiry,
+		})
+	}
+	return bannedAddresses
+}
+
 // notBannedAddressesWithException returns all not banned addresses with excpetion
 func (am *AddressManager) notBannedAddressesWithException(exceptions []*appmessage.NetAddress) []*address {
 	am.mutex.Lock()
@@ -289,7 +319,6 @@ func (am *AddressManager) unbanIfOldEnough(key addressKey) error {
 		return nil
 	}
 
-	const maxBanTime = 24 * time.Hour
 	if mstime.Since(address.netAddress.Timestamp) > maxBanTime {
 		err := am.store.removeBanned(key)
 		if err != nil {
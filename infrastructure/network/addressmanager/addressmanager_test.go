@@ -214,6 +214,20 @@ func TestAddressManager(t *testing.T) {
 		t.Fatalf("Banned address %s not returned from BannedAddresses()", addressToBan.IP)
 	}
 
+	// Check that BannedAddressesWithExpiry() returns the banned address along with its ban expiry
+	bannedAddressesWithExpiry := addressManager.BannedAddressesWithExpiry()
+	if len(bannedAddressesWithExpiry) != 1 {
+		t.Fatalf("Unexpected amount of addresses returned from BannedAddressesWithExpiry(). "+
+			"Want: %d, got: %d", 1, len(bannedAddressesWithExpiry))
+	}
+	if !reflect.DeepEqual(addressToBan, bannedAddressesWithExpiry[0].NetAddress) {
+		t.Fatalf("Banned address %s not returned from BannedAddressesWithExpiry()", addressToBan.IP)
+	}
+	expectedBanExpiry := addressToBan.Timestamp.Add(maxBanTime)
+	if bannedAddressesWithExpiry[0].BanExpiry != expectedBanExpiry {
+		t.Fatalf("Unexpected ban expiry. Want: %s, got: %s", expectedBanExpiry, bannedAddressesWithExpiry[0].BanExpiry)
+	}
+
 	// Unban the address
 	err = addressManager.Unban(addressToBan)
 	if err != nil {
@@ -1,7 +1,10 @@
 package connmanager
 
 import (
+	"sort"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 const (
@@ -108,9 +111,68 @@ func (c *ConnectionManager) addConnectionRequest(address string, isPermanent boo
 	}
 }
 
+// ErrConnectionRequestNotFound is the error returned when trying to remove
+// a connection request that doesn't exist.
+var ErrConnectionRequestNotFound = errors.New("ErrConnectionRequestNotFound")
+
 // RemoveConnection disconnects the connection for the given address
 // and removes it entirely from the connection manager.
-func (c *ConnectionManager) RemoveConnection(address string) {
-	panic("unimplemented")
+func (c *ConnectionManager) RemoveConnection(address string) error {
+	c.connectionRequestsLock.Lock()
+	_, isActive := c.activeRequested[address]
+	_, isPending := c.pendingRequested[address]
+	delete(c.activeRequested, address)
+	delete(c.pendingRequested, address)
+	c.connectionRequestsLock.Unlock()
+
+	if !isActive && !isPending {
+		return errors.Wrapf(ErrConnectionRequestNotFound, "there is no connection request for %s", address)
+	}
+
+	for _, connection := range c.netAdapter.P2PConnections() {
+		if connection.Address() == address {
+			connection.Disconnect()
+		}
+	}
+	return nil
+}
+
+// ConnectionRequestInfo describes a connection request that was
+// made through the CLI or RPC
+type ConnectionRequestInfo struct {
+	Address     string
+	IsPermanent bool
+	IsConnected bool
+
+	// NextAttempt is the time of the next connection attempt
+	// for connection requests that aren't connected
+	NextAttempt time.Time
+}
+
+// ConnectionRequests returns all the connection requests, both
+// connected and pending, sorted by address
+func (c *ConnectionManager) ConnectionRequests() []*ConnectionRequestInfo {
+	c.connectionRequestsLock.RLock()
+	defer c.connectionRequestsLock.RUnlock()
+
+	connectionRequests := make([]*ConnectionRequestInfo, 0, len(c.activeRequested)+len(c.pendingRequested))
+	for address, connReq := range c.activeRequested {
+		connectionRequests = append(connectionRequests, &ConnectionRequestInfo{
+			Address:     address,
+			IsPermanent: connReq.isPermanent,
+			IsConnected: true,
+		})
+	}
+	for address, connReq := range c.pendingRequested {
+		connectionRequests = append(connectionRequests, &ConnectionRequestInfo{
+			Address:     address,
+			IsPermanent: connReq.isPermanent,
+			NextAttempt: connReq.nextAttempt,
+		})
+	}
+	sort.Slice(connectionRequests, func(i, j int) bool {
+		return connectionRequests[i].Address < connectionRequests[j].Address
+	})
+	return connectionRequests
 }
 
@@ -166,6 +166,26 @@ func (c *ConnectionManager) BanByIP(ip net.IP) error {
 	return c.addressManager.Ban(appmessage.NewNetAddressIPPort(ip, 0))
 }
 
+// ErrPeerNotFound is the error returned when trying to disconnect a peer that isn't connected.
+var ErrPeerNotFound = errors.New("ErrPeerNotFound")
+
+// DisconnectPeer disconnects the connection with the given address.
+// Note that permanent connection requests are reconnected later on. Use
+// RemoveConnection to prevent that.
+func (c *ConnectionManager) DisconnectPeer(address string) error {
+	isFound := false
+	for _, connection := range c.netAdapter.P2PConnections() {
+		if connection.Address() == address {
+			connection.Disconnect()
+			isFound = true
+		}
+	}
+	if !isFound {
+		return errors.Wrapf(ErrPeerNotFound, "there is no connected peer with address %s", address)
+	}
+	return nil
+}
+
 // IsBanned returns whether the given netConnection is banned
 func (c *ConnectionManager) IsBanned(netConnection *netadapter.NetConnection) (bool, error) {
 	if c.isPermanent(netConnection.Address()) {
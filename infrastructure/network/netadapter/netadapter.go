@@ -1,6 +1,7 @@
 package netadapter
 
 import (
+	"crypto/ed25519"
 	"sync"
 	"sync/atomic"
 
@@ -31,6 +32,10 @@ type NetAdapter struct {
 	rpcRouterInitializer RouterInitializer
 	stop                 uint32
 
+	// uploadLimiter is shared by all p2p connections, and is nil
+	// if the total upload rate is unlimited
+	uploadLimiter *routerpkg.UploadLimiter
+
 	p2pConnections     map[*NetConnection]struct{}
 	p2pConnectionsLock sync.RWMutex
 }
@@ -42,7 +47,11 @@ func NewNetAdapter(cfg *config.Config) (*NetAdapter, error) {
 	if err != nil {
 		return nil, err
 	}
-	p2pServer, err := grpcserver.NewP2PServer(cfg.Listeners)
+	p2pEncryptionOptions, err := p2pEncryptionOptionsFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	p2pServer, err := grpcserver.NewP2PServer(cfg.Listeners, p2pEncryptionOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -58,6 +67,9 @@ func NewNetAdapter(cfg *config.Config) (*NetAdapter, error) {
 
 		p2pConnections: make(map[*NetConnection]struct{}),
 	}
+	if cfg.UploadLimit > 0 {
+		adapter.uploadLimiter = routerpkg.NewUploadLimiter(cfg.UploadLimit * 1024)
+	}
 
 	adapter.p2pServer.SetOnConnectedHandler(adapter.onP2PConnectedHandler)
 	adapter.rpcServer.SetOnConnectedHandler(adapter.onRPCConnectedHandler)
@@ -65,6 +77,28 @@ func NewNetAdapter(cfg *config.Config) (*NetAdapter, error) {
 	return &adapter, nil
 }
 
+// p2pEncryptionOptionsFromConfig returns the P2P encryption options
+// according to cfg, or nil if P2P encryption is disabled
+func p2pEncryptionOptionsFromConfig(cfg *config.Config) (*grpcserver.P2PEncryptionOptions, error) {
+	if !cfg.P2PEncryption {
+		return nil, nil
+	}
+	privateKey, err := grpcserver.LoadOrCreateP2PKey(cfg.P2PKey)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("P2P encryption is enabled. This node's P2P public key is %s",
+		grpcserver.P2PPublicKeyString(privateKey.Public().(ed25519.PublicKey)))
+	if len(cfg.P2PAllowedPeerPublicKeys) > 0 {
+		log.Infof("P2P connections are restricted to %d allowed peer keys", len(cfg.P2PAllowedPeerPublicKeys))
+	}
+	return &grpcserver.P2PEncryptionOptions{
+		PrivateKey:      privateKey,
+		Required:        cfg.P2PEncryptionRequired,
+		AllowedPeerKeys: cfg.P2PAllowedPeerPublicKeys,
+	}, nil
+}
+
 // Start begins the operation of the NetAdapter
 func (na *NetAdapter) Start() error {
 	if na.p2pRouterInitializer == nil {
@@ -129,6 +163,7 @@ func (na *NetAdapter) P2PConnectionCount() int {
 
 func (na *NetAdapter) onP2PConnectedHandler(connection server.Connection) error {
 	netConnection := newNetConnection(connection, na.p2pRouterInitializer, "on P2P connected")
+	netConnection.router.SetUploadLimiters(na.p2pUploadLimiters()...)
 
 	na.p2pConnectionsLock.Lock()
 	defer na.p2pConnectionsLock.Unlock()
@@ -147,6 +182,19 @@ func (na *NetAdapter) onP2PConnectedHandler(connection server.Connection) error
 	return nil
 }
 
+// p2pUploadLimiters returns the upload limiters that should
+// be charged for the bytes sent to a new p2p connection
+func (na *NetAdapter) p2pUploadLimiters() []*routerpkg.UploadLimiter {
+	var uploadLimiters []*routerpkg.UploadLimiter
+	if na.uploadLimiter != nil {
+		uploadLimiters = append(uploadLimiters, na.uploadLimiter)
+	}
+	if na.cfg.PeerUploadLimit > 0 {
+		uploadLimiters = append(uploadLimiters, routerpkg.NewUploadLimiter(na.cfg.PeerUploadLimit*1024))
+	}
+	return uploadLimiters
+}
+
 func (na *NetAdapter) onRPCConnectedHandler(connection server.Connection) error {
 	netConnection := newNetConnection(connection, na.rpcRouterInitializer, "on RPC connected")
 	netConnection.setOnDisconnectedHandler(func() {})
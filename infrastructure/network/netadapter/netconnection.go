@@ -1,6 +1,7 @@
 package netadapter
 
 import (
+	"crypto/ed25519"
 	"fmt"
 	"sync/atomic"
 
@@ -76,11 +77,33 @@ func (c *NetConnection) IsOutbound() bool {
 	return c.connection.IsOutbound()
 }
 
+// IsEncrypted returns whether the connection runs over the encrypted P2P transport
+func (c *NetConnection) IsEncrypted() bool {
+	return c.connection.IsEncrypted()
+}
+
+// PeerPublicKey returns the P2P public key the remote peer authenticated
+// with, or nil if the connection isn't encrypted
+func (c *NetConnection) PeerPublicKey() ed25519.PublicKey {
+	return c.connection.PeerPublicKey()
+}
+
 // NetAddress returns the NetAddress associated with this connection
 func (c *NetConnection) NetAddress() *appmessage.NetAddress {
 	return appmessage.NewNetAddress(c.connection.Address())
 }
 
+// Bandwidth returns the amount of bytes sent and received through this connection
+func (c *NetConnection) Bandwidth() *routerpkg.Bandwidth {
+	return c.router.Bandwidth()
+}
+
+// WaitForUploadQuota blocks until this connection may upload more data
+// without exceeding the configured upload limits, or until it's disconnected
+func (c *NetConnection) WaitForUploadQuota() error {
+	return c.router.WaitForUploadQuota()
+}
+
 func (c *NetConnection) setOnDisconnectedHandler(onDisconnectedHandler server.OnDisconnectedHandler) {
 	c.onDisconnectedHandler = onDisconnectedHandler
 }
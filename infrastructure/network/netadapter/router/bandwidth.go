@@ -0,0 +1,75 @@
+package router
+
+import (
+	"sync"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+)
+
+// MessageBandwidth holds the amount of bytes sent and received
+// for a single message type
+type MessageBandwidth struct {
+	BytesSent     uint64
+	BytesReceived uint64
+}
+
+// Bandwidth counts the bytes sent and received through a router,
+// both in total and per message type
+type Bandwidth struct {
+	lock          sync.RWMutex
+	total         MessageBandwidth
+	byMessageType map[appmessage.MessageCommand]*MessageBandwidth
+}
+
+func newBandwidth() *Bandwidth {
+	return &Bandwidth{
+		byMessageType: make(map[appmessage.MessageCommand]*MessageBandwidth),
+	}
+}
+
+func (b *Bandwidth) messageBandwidth(command appmessage.MessageCommand) *MessageBandwidth {
+	messageBandwidth, ok := b.byMessageType[command]
+	if !ok {
+		messageBandwidth = &MessageBandwidth{}
+		b.byMessageType[command] = messageBandwidth
+	}
+	return messageBandwidth
+}
+
+func (b *Bandwidth) addSent(command appmessage.MessageCommand, bytes int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.total.BytesSent += uint64(bytes)
+	b.messageBandwidth(command).BytesSent += uint64(bytes)
+}
+
+func (b *Bandwidth) addReceived(command appmessage.MessageCommand, bytes int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.total.BytesReceived += uint64(bytes)
+	b.messageBandwidth(command).BytesReceived += uint64(bytes)
+}
+
+// Total returns the total amount of bytes sent and received
+func (b *Bandwidth) Total() MessageBandwidth {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	return b.total
+}
+
+// ByMessageType returns a copy of the amount of bytes sent and
+// received for every message type that was seen so far
+func (b *Bandwidth) ByMessageType() map[appmessage.MessageCommand]MessageBandwidth {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	byMessageType := make(map[appmessage.MessageCommand]MessageBandwidth, len(b.byMessageType))
+	for command, messageBandwidth := range b.byMessageType {
+		byMessageType[command] = *messageBandwidth
+	}
+	return byMessageType
+}
+
@@ -0,0 +1,96 @@
+package router
+
+import (
+	"testing"
+	"time"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func TestRouterBandwidth(t *testing.T) {
+	router := NewRouter("test")
+	router.OnMessageSent(appmessage.CmdIBDBlock, 100)
+	router.OnMessageSent(appmessage.CmdIBDBlock, 50)
+	router.OnMessageSent(appmessage.CmdPing, 10)
+	router.OnMessageReceived(appmessage.CmdPong, 20)
+
+	total := router.Bandwidth().Total()
+	if total.BytesSent != 160 || total.BytesReceived != 20 {
+		t.Fatalf("unexpected total bandwidth %+v", total)
+	}
+
+	expected := map[appmessage.MessageCommand]MessageBandwidth{
+		appmessage.CmdIBDBlock: {BytesSent: 150},
+		appmessage.CmdPing:     {BytesSent: 10},
+		appmessage.CmdPong:     {BytesReceived: 20},
+	}
+	byMessageType := router.Bandwidth().ByMessageType()
+	if len(byMessageType) != len(expected) {
+		t.Fatalf("expected %d message types but got %d", len(expected), len(byMessageType))
+	}
+	for command, messageBandwidth := range expected {
+		if byMessageType[command] != messageBandwidth {
+			t.Fatalf("unexpected bandwidth for %s: %+v", command, byMessageType[command])
+		}
+	}
+}
+
+func TestUploadLimiter(t *testing.T) {
+	const bytesPerSecond = 1000
+	limiter := NewUploadLimiter(bytesPerSecond)
+
+	// A full second worth of bytes is allowed as a burst
+	limiter.Consume(bytesPerSecond)
+	if delay := limiter.Delay(); delay != 0 {
+		t.Fatalf("expected no delay after a burst within the limit but got %s", delay)
+	}
+
+	// Going over the limit puts the limiter into debt for about as
+	// long as it takes to upload the excess bytes
+	limiter.Consume(bytesPerSecond / 2)
+	delay := limiter.Delay()
+	if delay <= 400*time.Millisecond || delay > 500*time.Millisecond {
+		t.Fatalf("expected a delay of about 500ms but got %s", delay)
+	}
+
+	router := NewRouter("test")
+	router.SetUploadLimiters(limiter)
+	start := time.Now()
+	err := router.WaitForUploadQuota()
+	if err != nil {
+		t.Fatalf("WaitForUploadQuota: %+v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Fatalf("expected WaitForUploadQuota to wait for the debt to be repaid, but it returned after %s", elapsed)
+	}
+	if delay := limiter.Delay(); delay != 0 {
+		t.Fatalf("expected no delay after waiting for the upload quota but got %s", delay)
+	}
+}
+
+func TestWaitForUploadQuotaStopsOnClose(t *testing.T) {
+	const bytesPerSecond = 1000
+	limiter := NewUploadLimiter(bytesPerSecond)
+	// Put the limiter into debt for about a minute
+	limiter.Consume(61 * bytesPerSecond)
+
+	router := NewRouter("test")
+	router.SetUploadLimiters(limiter)
+	errChan := make(chan error)
+	go func() {
+		errChan <- router.WaitForUploadQuota()
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	router.Close()
+	select {
+	case err := <-errChan:
+		if !errors.Is(err, ErrRouteClosed) {
+			t.Fatalf("expected WaitForUploadQuota to return ErrRouteClosed but got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected WaitForUploadQuota to return once the router is closed")
+	}
+}
+
@@ -3,6 +3,7 @@ package router
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
 	"github.com/pkg/errors"
@@ -21,6 +22,12 @@ type Router struct {
 	incomingRoutesLock sync.RWMutex
 
 	outgoingRoute *Route
+
+	bandwidth      *Bandwidth
+	uploadLimiters []*UploadLimiter
+
+	// closeChan is closed when the router is closed, to stop waiting for upload quota
+	closeChan chan struct{}
 }
 
 // NewRouter creates a new empty router
@@ -28,6 +35,8 @@ func NewRouter(name string) *Router {
 	router := Router{
 		incomingRoutes: make(map[appmessage.MessageCommand]*Route),
 		outgoingRoute:  newRouteWithCapacity(fmt.Sprintf("%s - outgoing", name), outgoingRouteMaxMessages),
+		bandwidth:      newBandwidth(),
+		closeChan:      make(chan struct{}),
 	}
 	return &router
 }
@@ -91,6 +100,54 @@ func (r *Router) OutgoingRoute() *Route {
 	return r.outgoingRoute
 }
 
+// Bandwidth returns the amount of bytes sent and received through this router
+func (r *Router) Bandwidth() *Bandwidth {
+	return r.bandwidth
+}
+
+// SetUploadLimiters sets the limiters that are charged for the bytes sent
+// through this router. It must be called before the router is used.
+func (r *Router) SetUploadLimiters(uploadLimiters ...*UploadLimiter) {
+	r.uploadLimiters = uploadLimiters
+}
+
+// OnMessageSent records that a message of the given type and size
+// was sent through this router
+func (r *Router) OnMessageSent(command appmessage.MessageCommand, bytes int) {
+	r.bandwidth.addSent(command, bytes)
+	for _, uploadLimiter := range r.uploadLimiters {
+		uploadLimiter.Consume(bytes)
+	}
+}
+
+// OnMessageReceived records that a message of the given type and size
+// was received through this router
+func (r *Router) OnMessageReceived(command appmessage.MessageCommand, bytes int) {
+	r.bandwidth.addReceived(command, bytes)
+}
+
+// WaitForUploadQuota blocks until none of this router's upload
+// limiters are in debt. It returns ErrRouteClosed if the router is
+// closed while waiting.
+func (r *Router) WaitForUploadQuota() error {
+	for _, uploadLimiter := range r.uploadLimiters {
+		for {
+			delay := uploadLimiter.Delay()
+			if delay == 0 {
+				break
+			}
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-r.closeChan:
+				timer.Stop()
+				return errors.WithStack(ErrRouteClosed)
+			}
+		}
+	}
+	return nil
+}
+
 // Close shuts down the router by closing all registered
 // incoming routes and the outgoing route
 func (r *Router) Close() {
@@ -105,6 +162,7 @@ func (r *Router) Close() {
 		route.Close()
 	}
 	r.outgoingRoute.Close()
+	close(r.closeChan)
 }
 
 func (r *Router) incomingRoute(messageType appmessage.MessageCommand) (*Route, bool) {
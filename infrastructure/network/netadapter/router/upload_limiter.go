@@ -0,0 +1,64 @@
+package router
+
+import (
+	"sync"
+	"time"
+)
+
+// UploadLimiter is a token bucket that limits the rate in which
+// bytes are uploaded. Uploads are never blocked by the limiter
+// itself. Instead, they are allowed to put it into debt, and
+// callers that wish to respect the limit wait for the debt to be
+// repaid before uploading more.
+type UploadLimiter struct {
+	bytesPerSecond uint64
+
+	lock       sync.Mutex
+	tokens     float64
+	lastRefill time.Time
+}
+
+// NewUploadLimiter creates a new UploadLimiter that allows
+// bytesPerSecond bytes to be uploaded every second, with bursts
+// of up to one second worth of bytes
+func NewUploadLimiter(bytesPerSecond uint64) *UploadLimiter {
+	return &UploadLimiter{
+		bytesPerSecond: bytesPerSecond,
+		tokens:         float64(bytesPerSecond),
+		lastRefill:     time.Now(),
+	}
+}
+
+func (l *UploadLimiter) refill(now time.Time) {
+	elapsed := now.Sub(l.lastRefill).Seconds()
+	l.lastRefill = now
+
+	l.tokens += elapsed * float64(l.bytesPerSecond)
+	if l.tokens > float64(l.bytesPerSecond) {
+		l.tokens = float64(l.bytesPerSecond)
+	}
+}
+
+// Consume charges the limiter for the upload of the given
+// amount of bytes
+func (l *UploadLimiter) Consume(bytes int) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.refill(time.Now())
+	l.tokens -= float64(bytes)
+}
+
+// Delay returns how long a caller should wait before uploading
+// more bytes, so that the limiter is out of debt
+func (l *UploadLimiter) Delay() time.Duration {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.refill(time.Now())
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / float64(l.bytesPerSecond) * float64(time.Second))
+}
+
@@ -13,6 +13,7 @@ import (
 
 	routerpkg "github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter/router"
 	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter/server/grpcserver/protowire"
 )
@@ -72,6 +73,7 @@ func (c *gRPCConnection) sendLoop() error {
 		if err != nil {
 			return err
 		}
+		c.router.OnMessageSent(message.Command(), proto.Size(messageProto))
 	}
 	return nil
 }
@@ -94,6 +96,8 @@ func (c *gRPCConnection) receiveLoop() error {
 			return err
 		}
 
+		c.router.OnMessageReceived(message.Command(), proto.Size(protoMessage))
+
 		messageNumber++
 		message.SetMessageNumber(messageNumber)
 		message.SetReceivedAt(time.Now())
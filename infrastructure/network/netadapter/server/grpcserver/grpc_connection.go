@@ -1,6 +1,7 @@
 package grpcserver
 
 import (
+	"crypto/ed25519"
 	"net"
 	"sync"
 	"sync/atomic"
@@ -22,6 +23,9 @@ type gRPCConnection struct {
 	router                   *router.Router
 	lowLevelClientConnection *grpc.ClientConn
 
+	isEncrypted   bool
+	peerPublicKey ed25519.PublicKey
+
 	// streamLock protects concurrent access to stream.
 	// Note that it's an RWMutex. Despite what the name
 	// implies, we use it to RLock() send() and receive() because
@@ -100,6 +104,21 @@ func (c *gRPCConnection) IsOutbound() bool {
 	return c.lowLevelClientConnection != nil
 }
 
+// IsEncrypted returns whether the connection runs over the encrypted P2P transport
+//
+// This is part of the Connection interface
+func (c *gRPCConnection) IsEncrypted() bool {
+	return c.isEncrypted
+}
+
+// PeerPublicKey returns the P2P public key the remote peer authenticated with,
+// or nil if the connection isn't encrypted
+//
+// This is part of the Connection interface
+func (c *gRPCConnection) PeerPublicKey() ed25519.PublicKey {
+	return c.peerPublicKey
+}
+
 // Disconnect disconnects the connection
 // Calling this function a second time doesn't do anything
 //
@@ -148,13 +167,17 @@ func (c *gRPCConnection) send(message *protowire.KarlsendMessage) error {
 }
 
 func (c *gRPCConnection) closeSend() {
+	// Close the low level connection before taking streamLock. This cancels
+	// the stream, so that a receive() that's blocked waiting for the next
+	// message from the peer releases streamLock instead of holding it until
+	// the peer happens to send something.
+	// ignore error because we don't really know what's the status of the connection
+	_ = c.lowLevelClientConnection.Close()
+
 	c.streamLock.Lock()
 	defer c.streamLock.Unlock()
 
 	clientStream := c.stream.(grpc.ClientStream)
-
-	// ignore error because we don't really know what's the status of the connection
 	_ = clientStream.CloseSend()
-	_ = c.lowLevelClientConnection.Close()
 }
 
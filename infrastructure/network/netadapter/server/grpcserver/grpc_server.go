@@ -26,10 +26,14 @@ type gRPCServer struct {
 }
 
 // newGRPCServer creates a gRPC server
-func newGRPCServer(listeningAddresses []string, maxMessageSize int, maxInboundConnections int, name string) *gRPCServer {
+func newGRPCServer(listeningAddresses []string, maxMessageSize int, maxInboundConnections int, name string,
+	extraServerOptions ...grpc.ServerOption) *gRPCServer {
+
 	log.Debugf("Created new %s GRPC server with maxMessageSize %d and maxInboundConnections %d", name, maxMessageSize, maxInboundConnections)
+	serverOptions := append([]grpc.ServerOption{grpc.MaxRecvMsgSize(maxMessageSize), grpc.MaxSendMsgSize(maxMessageSize)},
+		extraServerOptions...)
 	return &gRPCServer{
-		server:                     grpc.NewServer(grpc.MaxRecvMsgSize(maxMessageSize), grpc.MaxSendMsgSize(maxMessageSize)),
+		server:                     grpc.NewServer(serverOptions...),
 		listeningAddresses:         listeningAddresses,
 		name:                       name,
 		maxInboundConnections:      maxInboundConnections,
@@ -111,13 +115,19 @@ func (s *gRPCServer) handleInboundConnection(ctx context.Context, stream grpcStr
 	}
 
 	connection := newConnection(s, tcpAddress, stream, nil)
+	connection.isEncrypted, connection.peerPublicKey = connectionSecurity(peerInfo.AuthInfo)
 
 	err = s.onConnectedHandler(connection)
 	if err != nil {
 		return err
 	}
 
-	log.Infof("%s Incoming connection from %s #%d", s.name, peerInfo.Addr, connectionCount)
+	if connection.isEncrypted {
+		log.Infof("%s Incoming encrypted connection from %s #%d (peer key %s)", s.name, peerInfo.Addr, connectionCount,
+			P2PPublicKeyString(connection.peerPublicKey))
+	} else {
+		log.Infof("%s Incoming connection from %s #%d", s.name, peerInfo.Addr, connectionCount)
+	}
 
 	<-connection.stopChan
 	return nil
@@ -0,0 +1,272 @@
+package grpcserver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/credentials"
+)
+
+// P2PEncryptionOptions configures the encrypted P2P transport
+type P2PEncryptionOptions struct {
+	// PrivateKey is this node's P2P identity key. Encryption is
+	// disabled when it's nil
+	PrivateKey ed25519.PrivateKey
+
+	// Required makes the node refuse plaintext connections instead
+	// of falling back to them for peers that don't support encryption
+	Required bool
+
+	// AllowedPeerKeys restricts P2P connections to peers that present
+	// one of the given keys. An empty list allows any key
+	AllowedPeerKeys []ed25519.PublicKey
+}
+
+func (o *P2PEncryptionOptions) isEnabled() bool {
+	return o != nil && o.PrivateKey != nil
+}
+
+func (o *P2PEncryptionOptions) isRequired() bool {
+	return o.isEnabled() && (o.Required || len(o.AllowedPeerKeys) > 0)
+}
+
+// tlsRecordTypeHandshake is the first byte of every TLS connection. It lets
+// the server tell encrypted connections apart from plaintext ones, which
+// always start with the HTTP/2 client preface.
+const tlsRecordTypeHandshake = 0x16
+
+const p2pAuthType = "karlsen-p2p"
+
+// p2pAuthInfo is the credentials.AuthInfo attached to P2P connections
+type p2pAuthInfo struct {
+	credentials.CommonAuthInfo
+
+	// peerPublicKey is nil for plaintext connections
+	peerPublicKey ed25519.PublicKey
+}
+
+func (p2pAuthInfo) AuthType() string {
+	return p2pAuthType
+}
+
+// handshakeError is returned from failed TLS handshakes. It's marked as
+// non-temporary so that a blocking gRPC dial fails immediately and the
+// caller may fall back to a plaintext connection.
+type handshakeError struct {
+	error
+}
+
+func (handshakeError) Temporary() bool {
+	return false
+}
+
+// p2pTransportCredentials implements credentials.TransportCredentials for
+// the P2P server. Incoming connections are served encrypted or plaintext
+// according to what the remote peer initiated, while outgoing connections
+// are always encrypted.
+type p2pTransportCredentials struct {
+	options   *P2PEncryptionOptions
+	tlsConfig *tls.Config
+}
+
+func newP2PTransportCredentials(options *P2PEncryptionOptions) (*p2pTransportCredentials, error) {
+	certificate, err := selfSignedCertificate(options.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	c := &p2pTransportCredentials{options: options}
+	c.tlsConfig = &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS13,
+		NextProtos:   []string{"h2"},
+
+		// Peers use self-signed certificates and are identified by their
+		// public keys rather than by a certificate chain, so the default
+		// verification is replaced by verifyPeerCertificate.
+		ClientAuth:            tls.RequireAnyClientCert,
+		InsecureSkipVerify:    true,
+		VerifyPeerCertificate: c.verifyPeerCertificate,
+	}
+	return c, nil
+}
+
+func selfSignedCertificate(privateKey ed25519.PrivateKey) (tls.Certificate, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		NotBefore:    now.Add(-24 * time.Hour),
+		NotAfter:     now.Add(10 * 365 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	publicKey := privateKey.Public()
+	certificateDER, err := x509.CreateCertificate(rand.Reader, template, template, publicKey, privateKey)
+	if err != nil {
+		return tls.Certificate{}, errors.Wrap(err, "error creating P2P certificate")
+	}
+	return tls.Certificate{
+		Certificate: [][]byte{certificateDER},
+		PrivateKey:  privateKey,
+	}, nil
+}
+
+func (c *p2pTransportCredentials) verifyPeerCertificate(rawCertificates [][]byte, _ [][]*x509.Certificate) error {
+	publicKey, err := publicKeyFromRawCertificates(rawCertificates)
+	if err != nil {
+		return err
+	}
+	if !c.isPeerKeyAllowed(publicKey) {
+		return errors.Errorf("peer key %s is not in the list of allowed peer keys", P2PPublicKeyString(publicKey))
+	}
+	return nil
+}
+
+func (c *p2pTransportCredentials) isPeerKeyAllowed(publicKey ed25519.PublicKey) bool {
+	if len(c.options.AllowedPeerKeys) == 0 {
+		return true
+	}
+	for _, allowedKey := range c.options.AllowedPeerKeys {
+		if bytes.Equal(allowedKey, publicKey) {
+			return true
+		}
+	}
+	return false
+}
+
+func publicKeyFromRawCertificates(rawCertificates [][]byte) (ed25519.PublicKey, error) {
+	if len(rawCertificates) == 0 {
+		return nil, errors.New("peer did not present a certificate")
+	}
+	certificate, err := x509.ParseCertificate(rawCertificates[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing peer certificate")
+	}
+	publicKey, ok := certificate.PublicKey.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.Errorf("peer certificate has an unsupported key type %T", certificate.PublicKey)
+	}
+	return publicKey, nil
+}
+
+func (c *p2pTransportCredentials) encryptedAuthInfo(conn *tls.Conn) (credentials.AuthInfo, error) {
+	state := conn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return nil, errors.New("peer did not present a certificate")
+	}
+	publicKey, ok := state.PeerCertificates[0].PublicKey.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.Errorf("peer certificate has an unsupported key type %T", state.PeerCertificates[0].PublicKey)
+	}
+	return p2pAuthInfo{
+		CommonAuthInfo: credentials.CommonAuthInfo{SecurityLevel: credentials.PrivacyAndIntegrity},
+		peerPublicKey:  publicKey,
+	}, nil
+}
+
+// ClientHandshake performs the TLS handshake for outgoing connections.
+// This is part of the credentials.TransportCredentials interface
+func (c *p2pTransportCredentials) ClientHandshake(ctx context.Context, _ string, rawConn net.Conn) (
+	net.Conn, credentials.AuthInfo, error) {
+
+	conn := tls.Client(rawConn, c.tlsConfig)
+	err := conn.HandshakeContext(ctx)
+	if err != nil {
+		_ = rawConn.Close()
+		return nil, nil, handshakeError{errors.Wrap(err, "P2P TLS handshake failed")}
+	}
+	authInfo, err := c.encryptedAuthInfo(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, handshakeError{err}
+	}
+	return conn, authInfo, nil
+}
+
+// ServerHandshake serves incoming connections encrypted if they start with a
+// TLS handshake, and as plaintext otherwise (unless encryption is required).
+// This is part of the credentials.TransportCredentials interface
+func (c *p2pTransportCredentials) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	reader := bufio.NewReader(rawConn)
+	firstBytes, err := reader.Peek(1)
+	if err != nil {
+		return nil, nil, err
+	}
+	conn := &peekedConn{Conn: rawConn, reader: reader}
+
+	if firstBytes[0] != tlsRecordTypeHandshake {
+		if c.options.isRequired() {
+			return nil, nil, errors.Errorf("refusing unencrypted P2P connection from %s", rawConn.RemoteAddr())
+		}
+		return conn, p2pAuthInfo{CommonAuthInfo: credentials.CommonAuthInfo{SecurityLevel: credentials.NoSecurity}}, nil
+	}
+
+	tlsConn := tls.Server(conn, c.tlsConfig)
+	err = tlsConn.Handshake()
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "P2P TLS handshake with %s failed", rawConn.RemoteAddr())
+	}
+	authInfo, err := c.encryptedAuthInfo(tlsConn)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tlsConn, authInfo, nil
+}
+
+// Info returns the protocol info of the credentials.
+// This is part of the credentials.TransportCredentials interface
+func (c *p2pTransportCredentials) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{
+		SecurityProtocol: "tls",
+		SecurityVersion:  "1.3",
+	}
+}
+
+// Clone returns a copy of the credentials.
+// This is part of the credentials.TransportCredentials interface
+func (c *p2pTransportCredentials) Clone() credentials.TransportCredentials {
+	clone := &p2pTransportCredentials{options: c.options}
+	clone.tlsConfig = c.tlsConfig.Clone()
+	clone.tlsConfig.VerifyPeerCertificate = clone.verifyPeerCertificate
+	return clone
+}
+
+// OverrideServerName is a no-op since peers are not identified by name.
+// This is part of the credentials.TransportCredentials interface
+func (c *p2pTransportCredentials) OverrideServerName(string) error {
+	return nil
+}
+
+// peekedConn is a net.Conn whose first bytes were already
+// read into reader by ServerHandshake
+type peekedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *peekedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// connectionSecurity extracts whether a connection is encrypted, and the
+// public key of its remote peer, from its gRPC auth info
+func connectionSecurity(authInfo credentials.AuthInfo) (isEncrypted bool, peerPublicKey ed25519.PublicKey) {
+	p2pAuthInfo, ok := authInfo.(p2pAuthInfo)
+	if !ok || p2pAuthInfo.peerPublicKey == nil {
+		return false, nil
+	}
+	return true, p2pAuthInfo.peerPublicKey
+}
+
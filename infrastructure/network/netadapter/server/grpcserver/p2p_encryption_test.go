@@ -0,0 +1,178 @@
+package grpcserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+)
+
+func generateTestP2PKey(t *testing.T) ed25519.PrivateKey {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %s", err)
+	}
+	return privateKey
+}
+
+type handshakeResult struct {
+	conn          net.Conn
+	isEncrypted   bool
+	peerPublicKey ed25519.PublicKey
+	err           error
+}
+
+func runTestHandshake(t *testing.T, serverOptions, clientOptions *P2PEncryptionOptions) (server, client handshakeResult) {
+	serverCredentials, err := newP2PTransportCredentials(serverOptions)
+	if err != nil {
+		t.Fatalf("newP2PTransportCredentials: %s", err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %s", err)
+	}
+	defer listener.Close()
+
+	serverResultChan := make(chan handshakeResult)
+	go func() {
+		serverConn, err := listener.Accept()
+		if err != nil {
+			serverResultChan <- handshakeResult{err: err}
+			return
+		}
+		conn, authInfo, err := serverCredentials.ServerHandshake(serverConn)
+		isEncrypted, peerPublicKey := connectionSecurity(authInfo)
+		if err != nil {
+			_ = serverConn.Close()
+		}
+		serverResultChan <- handshakeResult{conn, isEncrypted, peerPublicKey, err}
+	}()
+
+	clientConn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial: %s", err)
+	}
+
+	if clientOptions.isEnabled() {
+		clientCredentials, err := newP2PTransportCredentials(clientOptions)
+		if err != nil {
+			t.Fatalf("newP2PTransportCredentials: %s", err)
+		}
+		conn, authInfo, err := clientCredentials.ClientHandshake(context.Background(), "", clientConn)
+		isEncrypted, peerPublicKey := connectionSecurity(authInfo)
+		client = handshakeResult{conn, isEncrypted, peerPublicKey, err}
+	} else {
+		// Emulate a plaintext peer by sending the first bytes of the HTTP/2 client preface
+		_, err := clientConn.Write([]byte("PRI * HTTP/2.0"))
+		if err != nil {
+			t.Fatalf("Write: %s", err)
+		}
+		client = handshakeResult{conn: clientConn}
+	}
+
+	server = <-serverResultChan
+	return server, client
+}
+
+func TestP2PEncryptionHandshake(t *testing.T) {
+	serverKey := generateTestP2PKey(t)
+	clientKey := generateTestP2PKey(t)
+
+	server, client := runTestHandshake(t,
+		&P2PEncryptionOptions{PrivateKey: serverKey},
+		&P2PEncryptionOptions{PrivateKey: clientKey})
+	if server.err != nil {
+		t.Fatalf("ServerHandshake: %s", server.err)
+	}
+	if client.err != nil {
+		t.Fatalf("ClientHandshake: %s", client.err)
+	}
+	if !server.isEncrypted || !client.isEncrypted {
+		t.Fatalf("expected both sides of the connection to be encrypted")
+	}
+	if !bytes.Equal(server.peerPublicKey, clientKey.Public().(ed25519.PublicKey)) {
+		t.Errorf("server got unexpected peer key %s", P2PPublicKeyString(server.peerPublicKey))
+	}
+	if !bytes.Equal(client.peerPublicKey, serverKey.Public().(ed25519.PublicKey)) {
+		t.Errorf("client got unexpected peer key %s", P2PPublicKeyString(client.peerPublicKey))
+	}
+
+	message := []byte("hello")
+	_, err := client.conn.Write(message)
+	if err != nil {
+		t.Fatalf("Write: %s", err)
+	}
+	received := make([]byte, len(message))
+	_, err = io.ReadFull(server.conn, received)
+	if err != nil {
+		t.Fatalf("Read: %s", err)
+	}
+	if !bytes.Equal(received, message) {
+		t.Fatalf("expected to receive %q but got %q", message, received)
+	}
+}
+
+func TestP2PEncryptionPlaintextFallback(t *testing.T) {
+	server, _ := runTestHandshake(t, &P2PEncryptionOptions{PrivateKey: generateTestP2PKey(t)}, nil)
+	if server.err != nil {
+		t.Fatalf("ServerHandshake: %s", server.err)
+	}
+	if server.isEncrypted {
+		t.Fatalf("expected the connection to be unencrypted")
+	}
+
+	// The peeked bytes must still be readable from the connection
+	received := make([]byte, 3)
+	_, err := io.ReadFull(server.conn, received)
+	if err != nil {
+		t.Fatalf("Read: %s", err)
+	}
+	if string(received) != "PRI" {
+		t.Fatalf("expected to read the beginning of the preface but got %q", received)
+	}
+}
+
+func TestP2PEncryptionRequired(t *testing.T) {
+	server, _ := runTestHandshake(t, &P2PEncryptionOptions{PrivateKey: generateTestP2PKey(t), Required: true}, nil)
+	if server.err == nil {
+		t.Fatalf("expected a plaintext connection to be refused")
+	}
+}
+
+func TestP2PEncryptionAllowedPeerKeys(t *testing.T) {
+	allowedKey := generateTestP2PKey(t)
+	serverOptions := &P2PEncryptionOptions{
+		PrivateKey:      generateTestP2PKey(t),
+		AllowedPeerKeys: []ed25519.PublicKey{allowedKey.Public().(ed25519.PublicKey)},
+	}
+
+	server, client := runTestHandshake(t, serverOptions, &P2PEncryptionOptions{PrivateKey: allowedKey})
+	if server.err != nil || client.err != nil {
+		t.Fatalf("expected an allowed peer to connect, got server error: %v, client error: %v", server.err, client.err)
+	}
+
+	server, _ = runTestHandshake(t, serverOptions, &P2PEncryptionOptions{PrivateKey: generateTestP2PKey(t)})
+	if server.err == nil {
+		t.Fatalf("expected a peer with a key that isn't allowed to be refused")
+	}
+}
+
+func TestLoadOrCreateP2PKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "p2p.key")
+	createdKey, err := LoadOrCreateP2PKey(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreateP2PKey: %s", err)
+	}
+	loadedKey, err := LoadOrCreateP2PKey(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreateP2PKey: %s", err)
+	}
+	if !createdKey.Equal(loadedKey) {
+		t.Fatalf("expected the loaded key to equal the created one")
+	}
+}
+
@@ -0,0 +1,70 @@
+package grpcserver
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+const p2pKeyPEMType = "PRIVATE KEY"
+
+// LoadOrCreateP2PKey loads this node's P2P identity key from the given path.
+// If the file does not exist, a new key is generated and saved there.
+func LoadOrCreateP2PKey(path string) (ed25519.PrivateKey, error) {
+	keyPEM, err := os.ReadFile(path)
+	if err == nil {
+		return parseP2PKey(keyPEM)
+	}
+	if !os.IsNotExist(err) {
+		return nil, errors.Wrapf(err, "error reading P2P key from %s", path)
+	}
+
+	log.Infof("Generating a new P2P key at %s", path)
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: p2pKeyPEMType, Bytes: serializedKey})
+	err = os.WriteFile(path, keyPEM, 0600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error writing P2P key to %s", path)
+	}
+	return privateKey, nil
+}
+
+func parseP2PKey(keyPEM []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil || block.Type != p2pKeyPEMType {
+		return nil, errors.New("P2P key file does not contain a PEM encoded private key")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing P2P key")
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.Errorf("P2P key must be an ed25519 key, got %T", key)
+	}
+	return privateKey, nil
+}
+
+// P2PPublicKeyString returns the hex representation of a P2P public key,
+// as used by the --p2p-allowed-peer-key option
+func P2PPublicKeyString(publicKey ed25519.PublicKey) string {
+	return hex.EncodeToString(publicKey)
+}
+
@@ -17,6 +17,9 @@ import (
 type p2pServer struct {
 	protowire.UnimplementedP2PServer
 	gRPCServer
+
+	encryptionOptions *P2PEncryptionOptions
+	credentials       *p2pTransportCredentials
 }
 
 const p2pMaxMessageSize = 1024 * 1024 * 1024 // 1GB
@@ -27,10 +30,26 @@ const p2pMaxMessageSize = 1024 * 1024 * 1024 // 1GB
 // is handled in the ConnectionManager instead.
 const p2pMaxInboundConnections = 0
 
-// NewP2PServer creates a new P2PServer
-func NewP2PServer(listeningAddresses []string) (server.P2PServer, error) {
-	gRPCServer := newGRPCServer(listeningAddresses, p2pMaxMessageSize, p2pMaxInboundConnections, "P2P")
-	p2pServer := &p2pServer{gRPCServer: *gRPCServer}
+// NewP2PServer creates a new P2PServer.
+// encryptionOptions may be nil, in which case all connections are plaintext
+func NewP2PServer(listeningAddresses []string, encryptionOptions *P2PEncryptionOptions) (server.P2PServer, error) {
+	var serverOptions []grpc.ServerOption
+	var credentials *p2pTransportCredentials
+	if encryptionOptions.isEnabled() {
+		var err error
+		credentials, err = newP2PTransportCredentials(encryptionOptions)
+		if err != nil {
+			return nil, err
+		}
+		serverOptions = append(serverOptions, grpc.Creds(credentials))
+	}
+
+	gRPCServer := newGRPCServer(listeningAddresses, p2pMaxMessageSize, p2pMaxInboundConnections, "P2P", serverOptions...)
+	p2pServer := &p2pServer{
+		gRPCServer:        *gRPCServer,
+		encryptionOptions: encryptionOptions,
+		credentials:       credentials,
+	}
 	protowire.RegisterP2PServer(gRPCServer.server, p2pServer)
 	return p2pServer, nil
 }
@@ -46,13 +65,9 @@ func (p *p2pServer) MessageStream(stream protowire.P2P_MessageStreamServer) erro
 func (p *p2pServer) Connect(address string) (server.Connection, error) {
 	log.Debugf("%s Dialing to %s", p.name, address)
 
-	const dialTimeout = 1 * time.Second
-	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
-	defer cancel()
-
-	gRPCClientConnection, err := grpc.DialContext(ctx, address, grpc.WithInsecure(), grpc.WithBlock())
+	gRPCClientConnection, err := p.dial(address)
 	if err != nil {
-		return nil, errors.Wrapf(err, "%s error connecting to %s", p.name, address)
+		return nil, err
 	}
 
 	client := protowire.NewP2PClient(gRPCClientConnection)
@@ -72,14 +87,52 @@ func (p *p2pServer) Connect(address string) (server.Connection, error) {
 	}
 
 	connection := newConnection(&p.gRPCServer, tcpAddress, stream, gRPCClientConnection)
+	connection.isEncrypted, connection.peerPublicKey = connectionSecurity(peerInfo.AuthInfo)
 
 	err = p.onConnectedHandler(connection)
 	if err != nil {
 		return nil, err
 	}
 
-	log.Infof("%s Connected to %s", p.name, address)
+	if connection.isEncrypted {
+		log.Infof("%s Connected to %s over an encrypted connection (peer key %s)", p.name, address,
+			P2PPublicKeyString(connection.peerPublicKey))
+	} else {
+		log.Infof("%s Connected to %s", p.name, address)
+	}
 
 	return connection, nil
 }
 
+// dial opens a gRPC client connection to the given address. If encryption
+// is enabled, an encrypted connection is attempted first, falling back to
+// plaintext for peers that don't support it unless encryption is required
+func (p *p2pServer) dial(address string) (*grpc.ClientConn, error) {
+	const dialTimeout = 1 * time.Second
+
+	if p.encryptionOptions.isEnabled() {
+		ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+		defer cancel()
+
+		gRPCClientConnection, err := grpc.DialContext(ctx, address, grpc.WithTransportCredentials(p.credentials),
+			grpc.WithBlock(), grpc.FailOnNonTempDialError(true))
+		if err == nil {
+			return gRPCClientConnection, nil
+		}
+		if p.encryptionOptions.isRequired() {
+			return nil, errors.Wrapf(err, "%s error connecting to %s over an encrypted connection", p.name, address)
+		}
+		log.Debugf("%s Could not connect to %s over an encrypted connection, falling back to plaintext: %s",
+			p.name, address, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+
+	gRPCClientConnection, err := grpc.DialContext(ctx, address, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s error connecting to %s", p.name, address)
+	}
+	return gRPCClientConnection, nil
+}
+
@@ -25,7 +25,7 @@ func (x *InvTransactionsMessage) toAppMessage() (appmessage.Message, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &appmessage.MsgInvTransaction{TxIDs: ids}, nil
+	return &appmessage.MsgInvTransaction{TxIDs: ids, IsStem: x.IsStem}, nil
 
 }
 
@@ -36,7 +36,8 @@ func (x *KarlsendMessage_InvTransactions) fromAppMessage(msgInvTransaction *appm
 	}
 
 	x.InvTransactions = &InvTransactionsMessage{
-		Ids: wireTransactionIDsToProto(msgInvTransaction.TxIDs),
+		Ids:    wireTransactionIDsToProto(msgInvTransaction.TxIDs),
+		IsStem: msgInvTransaction.IsStem,
 	}
 	return nil
 }
@@ -0,0 +1,66 @@
+package protowire
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KarlsendMessage_CreateSnapshotRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KarlsendMessage_CreateSnapshotRequest is nil")
+	}
+	return x.CreateSnapshotRequest.toAppMessage()
+}
+
+func (x *KarlsendMessage_CreateSnapshotRequest) fromAppMessage(message *appmessage.CreateSnapshotRequestMessage) error {
+	x.CreateSnapshotRequest = &CreateSnapshotRequestMessage{TargetDirectory: message.TargetDirectory}
+	return nil
+}
+
+func (x *CreateSnapshotRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "CreateSnapshotRequestMessage is nil")
+	}
+	return &appmessage.CreateSnapshotRequestMessage{
+		TargetDirectory: x.TargetDirectory,
+	}, nil
+}
+
+func (x *KarlsendMessage_CreateSnapshotResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KarlsendMessage_CreateSnapshotResponse is nil")
+	}
+	return x.CreateSnapshotResponse.toAppMessage()
+}
+
+func (x *KarlsendMessage_CreateSnapshotResponse) fromAppMessage(message *appmessage.CreateSnapshotResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	x.CreateSnapshotResponse = &CreateSnapshotResponseMessage{
+		PruningPointHash:          message.PruningPointHash,
+		VirtualSelectedParentHash: message.VirtualSelectedParentHash,
+		KeyCount:                  message.KeyCount,
+		Error:                     err,
+	}
+	return nil
+}
+
+func (x *CreateSnapshotResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "CreateSnapshotResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	return &appmessage.CreateSnapshotResponseMessage{
+		PruningPointHash:          x.PruningPointHash,
+		VirtualSelectedParentHash: x.VirtualSelectedParentHash,
+		KeyCount:                  x.KeyCount,
+		Error:                     rpcErr,
+	}, nil
+}
+
@@ -0,0 +1,60 @@
+package protowire
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KarlsendMessage_DisconnectPeerRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KarlsendMessage_DisconnectPeerRequest is nil")
+	}
+	return x.DisconnectPeerRequest.toAppMessage()
+}
+
+func (x *KarlsendMessage_DisconnectPeerRequest) fromAppMessage(message *appmessage.DisconnectPeerRequestMessage) error {
+	x.DisconnectPeerRequest = &DisconnectPeerRequestMessage{Address: message.Address}
+	return nil
+}
+
+func (x *DisconnectPeerRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "DisconnectPeerRequestMessage is nil")
+	}
+	return &appmessage.DisconnectPeerRequestMessage{
+		Address: x.Address,
+	}, nil
+}
+
+func (x *KarlsendMessage_DisconnectPeerResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KarlsendMessage_DisconnectPeerResponse is nil")
+	}
+	return x.DisconnectPeerResponse.toAppMessage()
+}
+
+func (x *KarlsendMessage_DisconnectPeerResponse) fromAppMessage(message *appmessage.DisconnectPeerResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	x.DisconnectPeerResponse = &DisconnectPeerResponseMessage{
+		Error: err,
+	}
+	return nil
+}
+
+func (x *DisconnectPeerResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "DisconnectPeerResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	return &appmessage.DisconnectPeerResponseMessage{
+		Error: rpcErr,
+	}, nil
+}
+
@@ -0,0 +1,79 @@
+package protowire
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KarlsendMessage_GetBannedPeersRequest) toAppMessage() (appmessage.Message, error) {
+	return &appmessage.GetBannedPeersRequestMessage{}, nil
+}
+
+func (x *KarlsendMessage_GetBannedPeersRequest) fromAppMessage(_ *appmessage.GetBannedPeersRequestMessage) error {
+	x.GetBannedPeersRequest = &GetBannedPeersRequestMessage{}
+	return nil
+}
+
+func (x *KarlsendMessage_GetBannedPeersResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KarlsendMessage_GetBannedPeersResponse is nil")
+	}
+	return x.GetBannedPeersResponse.toAppMessage()
+}
+
+func (x *KarlsendMessage_GetBannedPeersResponse) fromAppMessage(message *appmessage.GetBannedPeersResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	bannedPeers := make([]*BannedPeerInfo, len(message.BannedPeers))
+	for i, bannedPeer := range message.BannedPeers {
+		bannedPeers[i] = &BannedPeerInfo{
+			Address:   bannedPeer.Address,
+			BanExpiry: bannedPeer.BanExpiry,
+		}
+	}
+	x.GetBannedPeersResponse = &GetBannedPeersResponseMessage{
+		BannedPeers: bannedPeers,
+		Error:       err,
+	}
+	return nil
+}
+
+func (x *GetBannedPeersResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetBannedPeersResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	if rpcErr != nil && len(x.BannedPeers) != 0 {
+		return nil, errors.New("GetBannedPeersResponseMessage contains both an error and a response")
+	}
+	bannedPeers := make([]*appmessage.BannedPeerInfo, len(x.BannedPeers))
+	for i, bannedPeer := range x.BannedPeers {
+		appBannedPeer, err := bannedPeer.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+		bannedPeers[i] = appBannedPeer
+	}
+
+	return &appmessage.GetBannedPeersResponseMessage{
+		BannedPeers: bannedPeers,
+		Error:       rpcErr,
+	}, nil
+}
+
+func (x *BannedPeerInfo) toAppMessage() (*appmessage.BannedPeerInfo, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "BannedPeerInfo is nil")
+	}
+	return &appmessage.BannedPeerInfo{
+		Address:   x.Address,
+		BanExpiry: x.BanExpiry,
+	}, nil
+}
+
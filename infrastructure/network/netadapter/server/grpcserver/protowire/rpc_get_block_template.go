@@ -14,8 +14,10 @@ func (x *KarlsendMessage_GetBlockTemplateRequest) toAppMessage() (appmessage.Mes
 
 func (x *KarlsendMessage_GetBlockTemplateRequest) fromAppMessage(message *appmessage.GetBlockTemplateRequestMessage) error {
 	x.GetBlockTemplateRequest = &GetBlockTemplateRequestMessage{
-		PayAddress: message.PayAddress,
-		ExtraData:  message.ExtraData,
+		PayAddress:         message.PayAddress,
+		ExtraData:          message.ExtraData,
+		LongPollTemplateId: message.LongPollTemplateID,
+		HeaderOnly:         message.HeaderOnly,
 	}
 	return nil
 }
@@ -25,8 +27,10 @@ func (x *GetBlockTemplateRequestMessage) toAppMessage() (appmessage.Message, err
 		return nil, errors.Wrapf(errorNil, "GetBlockTemplateRequestMessage is nil")
 	}
 	return &appmessage.GetBlockTemplateRequestMessage{
-		PayAddress: x.PayAddress,
-		ExtraData:  x.ExtraData,
+		PayAddress:         x.PayAddress,
+		ExtraData:          x.ExtraData,
+		LongPollTemplateID: x.LongPollTemplateId,
+		HeaderOnly:         x.HeaderOnly,
 	}, nil
 }
 
@@ -54,9 +58,10 @@ func (x *KarlsendMessage_GetBlockTemplateResponse) fromAppMessage(message *appme
 	}
 
 	x.GetBlockTemplateResponse = &GetBlockTemplateResponseMessage{
-		Block:    block,
-		IsSynced: message.IsSynced,
-		Error:    err,
+		Block:      block,
+		IsSynced:   message.IsSynced,
+		TemplateId: message.TemplateID,
+		Error:      err,
 	}
 	return nil
 }
@@ -82,9 +87,10 @@ func (x *GetBlockTemplateResponseMessage) toAppMessage() (appmessage.Message, er
 		}
 	}
 	return &appmessage.GetBlockTemplateResponseMessage{
-		Block:    msgBlock,
-		IsSynced: x.IsSynced,
-		Error:    rpcError,
+		Block:      msgBlock,
+		IsSynced:   x.IsSynced,
+		TemplateID: x.TemplateId,
+		Error:      rpcError,
 	}, nil
 }
 
@@ -27,6 +27,14 @@ func (x *KarlsendMessage_GetConnectedPeerInfoResponse) fromAppMessage(message *a
 	}
 	infos := make([]*GetConnectedPeerInfoMessage, len(message.Infos))
 	for i, info := range message.Infos {
+		bandwidthByMessageType := make([]*PeerMessageTypeBandwidth, len(info.BandwidthByMessageType))
+		for j, bandwidth := range info.BandwidthByMessageType {
+			bandwidthByMessageType[j] = &PeerMessageTypeBandwidth{
+				MessageType:   bandwidth.MessageType,
+				BytesSent:     bandwidth.BytesSent,
+				BytesReceived: bandwidth.BytesReceived,
+			}
+		}
 		infos[i] = &GetConnectedPeerInfoMessage{
 			Id:                        info.ID,
 			Address:                   info.Address,
@@ -37,6 +45,11 @@ func (x *KarlsendMessage_GetConnectedPeerInfoResponse) fromAppMessage(message *a
 			AdvertisedProtocolVersion: info.AdvertisedProtocolVersion,
 			TimeConnected:             info.TimeConnected,
 			IsIbdPeer:                 info.IsIBDPeer,
+			IsEncrypted:               info.IsEncrypted,
+			PeerPublicKey:             info.PeerPublicKey,
+			BytesSent:                 info.BytesSent,
+			BytesReceived:             info.BytesReceived,
+			BandwidthByMessageType:    bandwidthByMessageType,
 		}
 	}
 	x.GetConnectedPeerInfoResponse = &GetConnectedPeerInfoResponseMessage{
@@ -78,6 +91,14 @@ func (x *GetConnectedPeerInfoMessage) toAppMessage() (*appmessage.GetConnectedPe
 	if x == nil {
 		return nil, errors.Wrapf(errorNil, "GetConnectedPeerInfoMessage is nil")
 	}
+	bandwidthByMessageType := make([]*appmessage.PeerMessageTypeBandwidth, len(x.BandwidthByMessageType))
+	for i, bandwidth := range x.BandwidthByMessageType {
+		appBandwidth, err := bandwidth.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+		bandwidthByMessageType[i] = appBandwidth
+	}
 	return &appmessage.GetConnectedPeerInfoMessage{
 		ID:                        x.Id,
 		Address:                   x.Address,
@@ -88,6 +109,22 @@ func (x *GetConnectedPeerInfoMessage) toAppMessage() (*appmessage.GetConnectedPe
 		AdvertisedProtocolVersion: x.AdvertisedProtocolVersion,
 		TimeConnected:             x.TimeOffset,
 		IsIBDPeer:                 x.IsIbdPeer,
+		IsEncrypted:               x.IsEncrypted,
+		PeerPublicKey:             x.PeerPublicKey,
+		BytesSent:                 x.BytesSent,
+		BytesReceived:             x.BytesReceived,
+		BandwidthByMessageType:    bandwidthByMessageType,
+	}, nil
+}
+
+func (x *PeerMessageTypeBandwidth) toAppMessage() (*appmessage.PeerMessageTypeBandwidth, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "PeerMessageTypeBandwidth is nil")
+	}
+	return &appmessage.PeerMessageTypeBandwidth{
+		MessageType:   x.MessageType,
+		BytesSent:     x.BytesSent,
+		BytesReceived: x.BytesReceived,
 	}, nil
 }
 
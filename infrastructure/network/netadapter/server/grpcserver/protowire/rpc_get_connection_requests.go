@@ -0,0 +1,83 @@
+package protowire
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KarlsendMessage_GetConnectionRequestsRequest) toAppMessage() (appmessage.Message, error) {
+	return &appmessage.GetConnectionRequestsRequestMessage{}, nil
+}
+
+func (x *KarlsendMessage_GetConnectionRequestsRequest) fromAppMessage(_ *appmessage.GetConnectionRequestsRequestMessage) error {
+	x.GetConnectionRequestsRequest = &GetConnectionRequestsRequestMessage{}
+	return nil
+}
+
+func (x *KarlsendMessage_GetConnectionRequestsResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KarlsendMessage_GetConnectionRequestsResponse is nil")
+	}
+	return x.GetConnectionRequestsResponse.toAppMessage()
+}
+
+func (x *KarlsendMessage_GetConnectionRequestsResponse) fromAppMessage(message *appmessage.GetConnectionRequestsResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	connectionRequests := make([]*ConnectionRequestInfo, len(message.ConnectionRequests))
+	for i, connectionRequest := range message.ConnectionRequests {
+		connectionRequests[i] = &ConnectionRequestInfo{
+			Address:     connectionRequest.Address,
+			IsPermanent: connectionRequest.IsPermanent,
+			IsConnected: connectionRequest.IsConnected,
+			NextAttempt: connectionRequest.NextAttempt,
+		}
+	}
+	x.GetConnectionRequestsResponse = &GetConnectionRequestsResponseMessage{
+		ConnectionRequests: connectionRequests,
+		Error:              err,
+	}
+	return nil
+}
+
+func (x *GetConnectionRequestsResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetConnectionRequestsResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	if rpcErr != nil && len(x.ConnectionRequests) != 0 {
+		return nil, errors.New("GetConnectionRequestsResponseMessage contains both an error and a response")
+	}
+	connectionRequests := make([]*appmessage.ConnectionRequestInfo, len(x.ConnectionRequests))
+	for i, connectionRequest := range x.ConnectionRequests {
+		appConnectionRequest, err := connectionRequest.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+		connectionRequests[i] = appConnectionRequest
+	}
+
+	return &appmessage.GetConnectionRequestsResponseMessage{
+		ConnectionRequests: connectionRequests,
+		Error:              rpcErr,
+	}, nil
+}
+
+func (x *ConnectionRequestInfo) toAppMessage() (*appmessage.ConnectionRequestInfo, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "ConnectionRequestInfo is nil")
+	}
+	return &appmessage.ConnectionRequestInfo{
+		Address:     x.Address,
+		IsPermanent: x.IsPermanent,
+		IsConnected: x.IsConnected,
+		NextAttempt: x.NextAttempt,
+	}, nil
+}
+
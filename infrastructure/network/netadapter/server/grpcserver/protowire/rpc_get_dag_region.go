@@ -0,0 +1,121 @@
+package protowire
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KarlsendMessage_GetDAGRegionRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KarlsendMessage_GetDAGRegionRequest is nil")
+	}
+	return x.GetDAGRegionRequest.toAppMessage()
+}
+
+func (x *KarlsendMessage_GetDAGRegionRequest) fromAppMessage(message *appmessage.GetDAGRegionRequestMessage) error {
+	x.GetDAGRegionRequest = &GetDAGRegionRequestMessage{
+		LowDaaScore:    message.LowDAAScore,
+		HighDaaScore:   message.HighDAAScore,
+		AroundHash:     message.AroundHash,
+		DaaScoreRadius: message.DAAScoreRadius,
+	}
+	return nil
+}
+
+func (x *GetDAGRegionRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetDAGRegionRequestMessage is nil")
+	}
+	return &appmessage.GetDAGRegionRequestMessage{
+		LowDAAScore:    x.LowDaaScore,
+		HighDAAScore:   x.HighDaaScore,
+		AroundHash:     x.AroundHash,
+		DAAScoreRadius: x.DaaScoreRadius,
+	}, nil
+}
+
+func (x *KarlsendMessage_GetDAGRegionResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KarlsendMessage_GetDAGRegionResponse is nil")
+	}
+	return x.GetDAGRegionResponse.toAppMessage()
+}
+
+func (x *KarlsendMessage_GetDAGRegionResponse) fromAppMessage(message *appmessage.GetDAGRegionResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	blocks := make([]*DAGRegionBlock, len(message.Blocks))
+	for i, block := range message.Blocks {
+		blocks[i] = &DAGRegionBlock{
+			Hash:                block.Hash,
+			ParentHashes:        block.ParentHashes,
+			SelectedParentHash:  block.SelectedParentHash,
+			DaaScore:            block.DAAScore,
+			BlueScore:           block.BlueScore,
+			BlueWork:            block.BlueWork,
+			IsChainBlock:        block.IsChainBlock,
+			Color:               block.Color,
+			MergingBlockHash:    block.MergingBlockHash,
+			MergeSetBluesHashes: block.MergeSetBluesHashes,
+			MergeSetRedsHashes:  block.MergeSetRedsHashes,
+		}
+	}
+	x.GetDAGRegionResponse = &GetDAGRegionResponseMessage{
+		LowDaaScore:  message.LowDAAScore,
+		HighDaaScore: message.HighDAAScore,
+		Blocks:       blocks,
+		Error:        err,
+	}
+	return nil
+}
+
+func (x *GetDAGRegionResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetDAGRegionResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	if rpcErr != nil && len(x.Blocks) != 0 {
+		return nil, errors.New("GetDAGRegionResponseMessage contains both an error and a response")
+	}
+	blocks := make([]*appmessage.DAGRegionBlock, len(x.Blocks))
+	for i, block := range x.Blocks {
+		appBlock, err := block.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+		blocks[i] = appBlock
+	}
+
+	return &appmessage.GetDAGRegionResponseMessage{
+		LowDAAScore:  x.LowDaaScore,
+		HighDAAScore: x.HighDaaScore,
+		Blocks:       blocks,
+		Error:        rpcErr,
+	}, nil
+}
+
+func (x *DAGRegionBlock) toAppMessage() (*appmessage.DAGRegionBlock, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "DAGRegionBlock is nil")
+	}
+	return &appmessage.DAGRegionBlock{
+		Hash:                x.Hash,
+		ParentHashes:        x.ParentHashes,
+		SelectedParentHash:  x.SelectedParentHash,
+		DAAScore:            x.DaaScore,
+		BlueScore:           x.BlueScore,
+		BlueWork:            x.BlueWork,
+		IsChainBlock:        x.IsChainBlock,
+		Color:               x.Color,
+		MergingBlockHash:    x.MergingBlockHash,
+		MergeSetBluesHashes: x.MergeSetBluesHashes,
+		MergeSetRedsHashes:  x.MergeSetRedsHashes,
+	}, nil
+}
+
@@ -0,0 +1,82 @@
+package protowire
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KarlsendMessage_GetMiningInfoRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KarlsendMessage_GetMiningInfoRequest is nil")
+	}
+	return x.GetMiningInfoRequest.toAppMessage()
+}
+
+func (x *KarlsendMessage_GetMiningInfoRequest) fromAppMessage(message *appmessage.GetMiningInfoRequestMessage) error {
+	x.GetMiningInfoRequest = &GetMiningInfoRequestMessage{
+		PayAddress: message.PayAddress,
+		WindowSize: message.WindowSize,
+	}
+	return nil
+}
+
+func (x *GetMiningInfoRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetMiningInfoRequestMessage is nil")
+	}
+	return &appmessage.GetMiningInfoRequestMessage{
+		PayAddress: x.PayAddress,
+		WindowSize: x.WindowSize,
+	}, nil
+}
+
+func (x *KarlsendMessage_GetMiningInfoResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KarlsendMessage_GetMiningInfoResponse is nil")
+	}
+	return x.GetMiningInfoResponse.toAppMessage()
+}
+
+func (x *KarlsendMessage_GetMiningInfoResponse) fromAppMessage(message *appmessage.GetMiningInfoResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	x.GetMiningInfoResponse = &GetMiningInfoResponseMessage{
+		WindowStartDaaScore:          message.WindowStartDAAScore,
+		AcceptedBlockCount:           message.AcceptedBlockCount,
+		BlueBlockCount:               message.BlueBlockCount,
+		RedBlockCount:                message.RedBlockCount,
+		PendingBlockCount:            message.PendingBlockCount,
+		MaturedRewards:               message.MaturedRewards,
+		PendingRewards:               message.PendingRewards,
+		NetworkHashesPerSecond:       message.NetworkHashesPerSecond,
+		BlockTemplateAgeMilliseconds: message.BlockTemplateAgeMilliseconds,
+		Error:                        err,
+	}
+	return nil
+}
+
+func (x *GetMiningInfoResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetMiningInfoResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	return &appmessage.GetMiningInfoResponseMessage{
+		WindowStartDAAScore:          x.WindowStartDaaScore,
+		AcceptedBlockCount:           x.AcceptedBlockCount,
+		BlueBlockCount:               x.BlueBlockCount,
+		RedBlockCount:                x.RedBlockCount,
+		PendingBlockCount:            x.PendingBlockCount,
+		MaturedRewards:               x.MaturedRewards,
+		PendingRewards:               x.PendingRewards,
+		NetworkHashesPerSecond:       x.NetworkHashesPerSecond,
+		BlockTemplateAgeMilliseconds: x.BlockTemplateAgeMilliseconds,
+		Error:                        rpcErr,
+	}, nil
+}
+
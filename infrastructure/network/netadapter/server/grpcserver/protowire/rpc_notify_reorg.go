@@ -0,0 +1,82 @@
+package protowire
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KarlsendMessage_NotifyReorgRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KarlsendMessage_NotifyReorgRequest is nil")
+	}
+	return &appmessage.NotifyReorgRequestMessage{}, nil
+}
+
+func (x *KarlsendMessage_NotifyReorgRequest) fromAppMessage(_ *appmessage.NotifyReorgRequestMessage) error {
+	x.NotifyReorgRequest = &NotifyReorgRequestMessage{}
+	return nil
+}
+
+func (x *KarlsendMessage_NotifyReorgResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KarlsendMessage_NotifyReorgResponse is nil")
+	}
+	return x.NotifyReorgResponse.toAppMessage()
+}
+
+func (x *KarlsendMessage_NotifyReorgResponse) fromAppMessage(message *appmessage.NotifyReorgResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	x.NotifyReorgResponse = &NotifyReorgResponseMessage{
+		Error: err,
+	}
+	return nil
+}
+
+func (x *NotifyReorgResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "NotifyReorgResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	return &appmessage.NotifyReorgResponseMessage{
+		Error: rpcErr,
+	}, nil
+}
+
+func (x *KarlsendMessage_ReorgNotification) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KarlsendMessage_ReorgNotification is nil")
+	}
+	return x.ReorgNotification.toAppMessage()
+}
+
+func (x *KarlsendMessage_ReorgNotification) fromAppMessage(message *appmessage.ReorgNotificationMessage) error {
+	x.ReorgNotification = &ReorgNotificationMessage{
+		CommonAncestorHash:      message.CommonAncestorHash,
+		Depth:                   message.Depth,
+		RemovedChainBlockHashes: message.RemovedChainBlockHashes,
+		AddedChainBlockHashes:   message.AddedChainBlockHashes,
+		RemovedTransactionIds:   message.RemovedTransactionIDs,
+	}
+	return nil
+}
+
+func (x *ReorgNotificationMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "ReorgNotificationMessage is nil")
+	}
+	return &appmessage.ReorgNotificationMessage{
+		CommonAncestorHash:      x.CommonAncestorHash,
+		Depth:                   x.Depth,
+		RemovedChainBlockHashes: x.RemovedChainBlockHashes,
+		AddedChainBlockHashes:   x.AddedChainBlockHashes,
+		RemovedTransactionIDs:   x.RemovedTransactionIds,
+	}, nil
+}
+
@@ -0,0 +1,66 @@
+package protowire
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KarlsendMessage_PrioritiseTransactionRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KarlsendMessage_PrioritiseTransactionRequest is nil")
+	}
+	return x.PrioritiseTransactionRequest.toAppMessage()
+}
+
+func (x *PrioritiseTransactionRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "PrioritiseTransactionRequestMessage is nil")
+	}
+	return &appmessage.PrioritiseTransactionRequestMessage{
+		TransactionID: x.TransactionId,
+		FeeDelta:      x.FeeDelta,
+	}, nil
+}
+
+func (x *KarlsendMessage_PrioritiseTransactionRequest) fromAppMessage(message *appmessage.PrioritiseTransactionRequestMessage) error {
+	x.PrioritiseTransactionRequest = &PrioritiseTransactionRequestMessage{
+		TransactionId: message.TransactionID,
+		FeeDelta:      message.FeeDelta,
+	}
+	return nil
+}
+
+func (x *KarlsendMessage_PrioritiseTransactionResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KarlsendMessage_PrioritiseTransactionResponse is nil")
+	}
+	return x.PrioritiseTransactionResponse.toAppMessage()
+}
+
+func (x *PrioritiseTransactionResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "PrioritiseTransactionResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	return &appmessage.PrioritiseTransactionResponseMessage{
+		TotalFeeDelta: x.TotalFeeDelta,
+		Error:         rpcErr,
+	}, nil
+}
+
+func (x *KarlsendMessage_PrioritiseTransactionResponse) fromAppMessage(message *appmessage.PrioritiseTransactionResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	x.PrioritiseTransactionResponse = &PrioritiseTransactionResponseMessage{
+		TotalFeeDelta: message.TotalFeeDelta,
+		Error:         err,
+	}
+	return nil
+}
+
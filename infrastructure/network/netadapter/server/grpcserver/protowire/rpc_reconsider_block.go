@@ -0,0 +1,60 @@
+package protowire
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KarlsendMessage_ReconsiderBlockRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KarlsendMessage_ReconsiderBlockRequest is nil")
+	}
+	return x.ReconsiderBlockRequest.toAppMessage()
+}
+
+func (x *KarlsendMessage_ReconsiderBlockRequest) fromAppMessage(message *appmessage.ReconsiderBlockRequestMessage) error {
+	x.ReconsiderBlockRequest = &ReconsiderBlockRequestMessage{BlockHash: message.BlockHash}
+	return nil
+}
+
+func (x *ReconsiderBlockRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "ReconsiderBlockRequestMessage is nil")
+	}
+	return &appmessage.ReconsiderBlockRequestMessage{
+		BlockHash: x.BlockHash,
+	}, nil
+}
+
+func (x *KarlsendMessage_ReconsiderBlockResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KarlsendMessage_ReconsiderBlockResponse is nil")
+	}
+	return x.ReconsiderBlockResponse.toAppMessage()
+}
+
+func (x *KarlsendMessage_ReconsiderBlockResponse) fromAppMessage(message *appmessage.ReconsiderBlockResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	x.ReconsiderBlockResponse = &ReconsiderBlockResponseMessage{
+		Error: err,
+	}
+	return nil
+}
+
+func (x *ReconsiderBlockResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "ReconsiderBlockResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	return &appmessage.ReconsiderBlockResponseMessage{
+		Error: rpcErr,
+	}, nil
+}
+
@@ -0,0 +1,60 @@
+package protowire
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KarlsendMessage_RemovePeerRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KarlsendMessage_RemovePeerRequest is nil")
+	}
+	return x.RemovePeerRequest.toAppMessage()
+}
+
+func (x *KarlsendMessage_RemovePeerRequest) fromAppMessage(message *appmessage.RemovePeerRequestMessage) error {
+	x.RemovePeerRequest = &RemovePeerRequestMessage{Address: message.Address}
+	return nil
+}
+
+func (x *RemovePeerRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "RemovePeerRequestMessage is nil")
+	}
+	return &appmessage.RemovePeerRequestMessage{
+		Address: x.Address,
+	}, nil
+}
+
+func (x *KarlsendMessage_RemovePeerResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KarlsendMessage_RemovePeerResponse is nil")
+	}
+	return x.RemovePeerResponse.toAppMessage()
+}
+
+func (x *KarlsendMessage_RemovePeerResponse) fromAppMessage(message *appmessage.RemovePeerResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	x.RemovePeerResponse = &RemovePeerResponseMessage{
+		Error: err,
+	}
+	return nil
+}
+
+func (x *RemovePeerResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "RemovePeerResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	return &appmessage.RemovePeerResponseMessage{
+		Error: rpcErr,
+	}, nil
+}
+
@@ -0,0 +1,62 @@
+package protowire
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KarlsendMessage_SetMockTimeRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KarlsendMessage_SetMockTimeRequest is nil")
+	}
+	return x.SetMockTimeRequest.toAppMessage()
+}
+
+func (x *SetMockTimeRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "SetMockTimeRequestMessage is nil")
+	}
+	return &appmessage.SetMockTimeRequestMessage{
+		MockTime: x.MockTime,
+	}, nil
+}
+
+func (x *KarlsendMessage_SetMockTimeRequest) fromAppMessage(message *appmessage.SetMockTimeRequestMessage) error {
+	x.SetMockTimeRequest = &SetMockTimeRequestMessage{
+		MockTime: message.MockTime,
+	}
+	return nil
+}
+
+func (x *KarlsendMessage_SetMockTimeResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KarlsendMessage_SetMockTimeResponse is nil")
+	}
+	return x.SetMockTimeResponse.toAppMessage()
+}
+
+func (x *SetMockTimeResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "SetMockTimeResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	return &appmessage.SetMockTimeResponseMessage{
+		Error: rpcErr,
+	}, nil
+}
+
+func (x *KarlsendMessage_SetMockTimeResponse) fromAppMessage(message *appmessage.SetMockTimeResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	x.SetMockTimeResponse = &SetMockTimeResponseMessage{
+		Error: err,
+	}
+	return nil
+}
+
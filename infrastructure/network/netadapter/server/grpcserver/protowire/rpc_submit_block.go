@@ -193,6 +193,14 @@ func (x *RpcBlockVerboseData) toAppMessage() (*appmessage.RPCBlockVerboseData, e
 	if x == nil {
 		return nil, errors.Wrapf(errorNil, "RpcBlockVerboseData is nil")
 	}
+	var coinbaseData *appmessage.RPCCoinbaseData
+	if x.CoinbaseData != nil {
+		var err error
+		coinbaseData, err = x.CoinbaseData.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+	}
 	return &appmessage.RPCBlockVerboseData{
 		Hash:                x.Hash,
 		Difficulty:          x.Difficulty,
@@ -204,10 +212,16 @@ func (x *RpcBlockVerboseData) toAppMessage() (*appmessage.RPCBlockVerboseData, e
 		MergeSetBluesHashes: x.MergeSetBluesHashes,
 		MergeSetRedsHashes:  x.MergeSetRedsHashes,
 		IsChainBlock:        x.IsChainBlock,
+		CoinbaseData:        coinbaseData,
 	}, nil
 }
 
 func (x *RpcBlockVerboseData) fromAppMessage(message *appmessage.RPCBlockVerboseData) {
+	var coinbaseData *RpcCoinbaseData
+	if message.CoinbaseData != nil {
+		coinbaseData = &RpcCoinbaseData{}
+		coinbaseData.fromAppMessage(message.CoinbaseData)
+	}
 	*x = RpcBlockVerboseData{
 		Hash:                message.Hash,
 		Difficulty:          message.Difficulty,
@@ -219,6 +233,36 @@ func (x *RpcBlockVerboseData) fromAppMessage(message *appmessage.RPCBlockVerbose
 		MergeSetBluesHashes: message.MergeSetBluesHashes,
 		MergeSetRedsHashes:  message.MergeSetRedsHashes,
 		IsChainBlock:        message.IsChainBlock,
+		CoinbaseData:        coinbaseData,
+	}
+}
+
+func (x *RpcCoinbaseData) toAppMessage() (*appmessage.RPCCoinbaseData, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "RpcCoinbaseData is nil")
+	}
+	scriptPublicKey, err := x.ScriptPublicKey.toAppMessage()
+	if err != nil {
+		return nil, err
+	}
+	return &appmessage.RPCCoinbaseData{
+		ScriptPublicKey:        scriptPublicKey,
+		ScriptPublicKeyAddress: x.ScriptPublicKeyAddress,
+		ExtraData:              x.ExtraData,
+		Version:                x.Version,
+		MinerTag:               x.MinerTag,
+	}, nil
+}
+
+func (x *RpcCoinbaseData) fromAppMessage(message *appmessage.RPCCoinbaseData) {
+	scriptPublicKey := &RpcScriptPublicKey{}
+	scriptPublicKey.fromAppMessage(message.ScriptPublicKey)
+	*x = RpcCoinbaseData{
+		ScriptPublicKey:        scriptPublicKey,
+		ScriptPublicKeyAddress: message.ScriptPublicKeyAddress,
+		ExtraData:              message.ExtraData,
+		Version:                message.Version,
+		MinerTag:               message.MinerTag,
 	}
 }
 
@@ -359,6 +359,34 @@ func toP2PPayload(message appmessage.Message) (isKarlsendMessage_Payload, error)
 			return nil, err
 		}
 		return payload, nil
+	case *appmessage.MsgRequestCompactBlock:
+		payload := new(KarlsendMessage_RequestCompactBlock)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.MsgCompactBlock:
+		payload := new(KarlsendMessage_CompactBlock)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.MsgRequestBlockTransactions:
+		payload := new(KarlsendMessage_RequestBlockTransactions)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.MsgBlockTransactions:
+		payload := new(KarlsendMessage_BlockTransactions)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
 	default:
 		return nil, nil
 	}
@@ -968,6 +996,195 @@ func toRPCPayload(message appmessage.Message) (isKarlsendMessage_Payload, error)
 			return nil, err
 		}
 		return payload, nil
+	case *appmessage.DisconnectPeerRequestMessage:
+		payload := new(KarlsendMessage_DisconnectPeerRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.DisconnectPeerResponseMessage:
+		payload := new(KarlsendMessage_DisconnectPeerResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.RemovePeerRequestMessage:
+		payload := new(KarlsendMessage_RemovePeerRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.RemovePeerResponseMessage:
+		payload := new(KarlsendMessage_RemovePeerResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetBannedPeersRequestMessage:
+		payload := new(KarlsendMessage_GetBannedPeersRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetBannedPeersResponseMessage:
+		payload := new(KarlsendMessage_GetBannedPeersResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetConnectionRequestsRequestMessage:
+		payload := new(KarlsendMessage_GetConnectionRequestsRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetConnectionRequestsResponseMessage:
+		payload := new(KarlsendMessage_GetConnectionRequestsResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.InvalidateBlockRequestMessage:
+		payload := new(KarlsendMessage_InvalidateBlockRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.InvalidateBlockResponseMessage:
+		payload := new(KarlsendMessage_InvalidateBlockResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.ReconsiderBlockRequestMessage:
+		payload := new(KarlsendMessage_ReconsiderBlockRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.ReconsiderBlockResponseMessage:
+		payload := new(KarlsendMessage_ReconsiderBlockResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.CreateSnapshotRequestMessage:
+		payload := new(KarlsendMessage_CreateSnapshotRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.CreateSnapshotResponseMessage:
+		payload := new(KarlsendMessage_CreateSnapshotResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetDAGRegionRequestMessage:
+		payload := new(KarlsendMessage_GetDAGRegionRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetDAGRegionResponseMessage:
+		payload := new(KarlsendMessage_GetDAGRegionResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.NotifyReorgRequestMessage:
+		payload := new(KarlsendMessage_NotifyReorgRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.NotifyReorgResponseMessage:
+		payload := new(KarlsendMessage_NotifyReorgResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.ReorgNotificationMessage:
+		payload := new(KarlsendMessage_ReorgNotification)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetMiningInfoRequestMessage:
+		payload := new(KarlsendMessage_GetMiningInfoRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetMiningInfoResponseMessage:
+		payload := new(KarlsendMessage_GetMiningInfoResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.PrioritiseTransactionRequestMessage:
+		payload := new(KarlsendMessage_PrioritiseTransactionRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.PrioritiseTransactionResponseMessage:
+		payload := new(KarlsendMessage_PrioritiseTransactionResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GenerateBlocksRequestMessage:
+		payload := new(KarlsendMessage_GenerateBlocksRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GenerateBlocksResponseMessage:
+		payload := new(KarlsendMessage_GenerateBlocksResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.SetMockTimeRequestMessage:
+		payload := new(KarlsendMessage_SetMockTimeRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.SetMockTimeResponseMessage:
+		payload := new(KarlsendMessage_SetMockTimeResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
 	default:
 		return nil, nil
 	}
@@ -0,0 +1,21 @@
+package rpcclient
+
+import "github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+
+// DisconnectPeer sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) DisconnectPeer(address string) (*appmessage.DisconnectPeerResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewDisconnectPeerRequestMessage(address))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdDisconnectPeerResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	disconnectPeerResponse := response.(*appmessage.DisconnectPeerResponseMessage)
+	if disconnectPeerResponse.Error != nil {
+		return nil, c.convertRPCError(disconnectPeerResponse.Error)
+	}
+	return disconnectPeerResponse, nil
+}
+
@@ -4,7 +4,17 @@ import "github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
 
 // GetBlockTemplate sends an RPC request respective to the function's name and returns the RPC server's response
 func (c *RPCClient) GetBlockTemplate(miningAddress, extraData string) (*appmessage.GetBlockTemplateResponseMessage, error) {
-	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetBlockTemplateRequestMessage(miningAddress, extraData))
+	return c.GetBlockTemplateWithOptions(miningAddress, extraData, "", false)
+}
+
+// GetBlockTemplateWithOptions sends a GetBlockTemplate RPC request that long-polls until the template
+// differs from the template with the given ID if it's not empty, and that asks for a template without
+// transactions if headerOnly is set
+func (c *RPCClient) GetBlockTemplateWithOptions(miningAddress, extraData, longPollTemplateID string,
+	headerOnly bool) (*appmessage.GetBlockTemplateResponseMessage, error) {
+
+	err := c.rpcRouter.outgoingRoute().Enqueue(
+		appmessage.NewGetBlockTemplateRequestMessage(miningAddress, extraData, longPollTemplateID, headerOnly))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,21 @@
+package rpcclient
+
+import "github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+
+// GetConnectionRequests sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetConnectionRequests() (*appmessage.GetConnectionRequestsResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetConnectionRequestsRequestMessage())
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdGetConnectionRequestsResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	getConnectionRequestsResponse := response.(*appmessage.GetConnectionRequestsResponseMessage)
+	if getConnectionRequestsResponse.Error != nil {
+		return nil, c.convertRPCError(getConnectionRequestsResponse.Error)
+	}
+	return getConnectionRequestsResponse, nil
+}
+
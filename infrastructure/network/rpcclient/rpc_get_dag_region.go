@@ -0,0 +1,31 @@
+package rpcclient
+
+import "github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+
+// GetDAGRegion sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetDAGRegion(lowDAAScore uint64, highDAAScore uint64) (*appmessage.GetDAGRegionResponseMessage, error) {
+	return c.getDAGRegion(appmessage.NewGetDAGRegionRequestMessage(lowDAAScore, highDAAScore, "", 0))
+}
+
+// GetDAGRegionAroundHash sends a GetDAGRegion RPC request for the region of
+// daaScoreRadius around the given block, and returns the RPC server's response
+func (c *RPCClient) GetDAGRegionAroundHash(hash string, daaScoreRadius uint64) (*appmessage.GetDAGRegionResponseMessage, error) {
+	return c.getDAGRegion(appmessage.NewGetDAGRegionRequestMessage(0, 0, hash, daaScoreRadius))
+}
+
+func (c *RPCClient) getDAGRegion(request *appmessage.GetDAGRegionRequestMessage) (*appmessage.GetDAGRegionResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(request)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdGetDAGRegionResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	getDAGRegionResponse := response.(*appmessage.GetDAGRegionResponseMessage)
+	if getDAGRegionResponse.Error != nil {
+		return nil, c.convertRPCError(getDAGRegionResponse.Error)
+	}
+	return getDAGRegionResponse, nil
+}
+
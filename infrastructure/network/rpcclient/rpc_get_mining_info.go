@@ -0,0 +1,21 @@
+package rpcclient
+
+import "github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+
+// GetMiningInfo sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetMiningInfo(payAddress string, windowSize uint64) (*appmessage.GetMiningInfoResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetMiningInfoRequestMessage(payAddress, windowSize))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdGetMiningInfoResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	getMiningInfoResponse := response.(*appmessage.GetMiningInfoResponseMessage)
+	if getMiningInfoResponse.Error != nil {
+		return nil, c.convertRPCError(getMiningInfoResponse.Error)
+	}
+	return getMiningInfoResponse, nil
+}
+
@@ -0,0 +1,40 @@
+package rpcclient
+
+import (
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	routerpkg "github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/network/netadapter/router"
+	"github.com/pkg/errors"
+)
+
+// RegisterForReorgNotifications sends an RPC request respective to the function's
+// name and returns the RPC server's response. Additionally, it starts listening for the appropriate notification
+// using the given handler function
+func (c *RPCClient) RegisterForReorgNotifications(onReorg func(notification *appmessage.ReorgNotificationMessage)) error {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewNotifyReorgRequestMessage())
+	if err != nil {
+		return err
+	}
+	response, err := c.route(appmessage.CmdNotifyReorgResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return err
+	}
+	notifyReorgResponse := response.(*appmessage.NotifyReorgResponseMessage)
+	if notifyReorgResponse.Error != nil {
+		return c.convertRPCError(notifyReorgResponse.Error)
+	}
+	spawn("RegisterForReorgNotifications", func() {
+		for {
+			notification, err := c.route(appmessage.CmdReorgNotificationMessage).Dequeue()
+			if err != nil {
+				if errors.Is(err, routerpkg.ErrRouteClosed) {
+					break
+				}
+				panic(err)
+			}
+			reorgNotification := notification.(*appmessage.ReorgNotificationMessage)
+			onReorg(reorgNotification)
+		}
+	})
+	return nil
+}
+
@@ -0,0 +1,23 @@
+package rpcclient
+
+import "github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+
+// PrioritiseTransaction sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) PrioritiseTransaction(transactionID string, feeDelta int64) (
+	*appmessage.PrioritiseTransactionResponseMessage, error) {
+
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewPrioritiseTransactionRequestMessage(transactionID, feeDelta))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdPrioritiseTransactionResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	prioritiseTransactionResponse := response.(*appmessage.PrioritiseTransactionResponseMessage)
+	if prioritiseTransactionResponse.Error != nil {
+		return nil, c.convertRPCError(prioritiseTransactionResponse.Error)
+	}
+	return prioritiseTransactionResponse, nil
+}
+
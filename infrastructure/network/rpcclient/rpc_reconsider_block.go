@@ -0,0 +1,21 @@
+package rpcclient
+
+import "github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+
+// ReconsiderBlock sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) ReconsiderBlock(blockHash string) (*appmessage.ReconsiderBlockResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewReconsiderBlockRequestMessage(blockHash))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdReconsiderBlockResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	reconsiderBlockResponse := response.(*appmessage.ReconsiderBlockResponseMessage)
+	if reconsiderBlockResponse.Error != nil {
+		return nil, c.convertRPCError(reconsiderBlockResponse.Error)
+	}
+	return reconsiderBlockResponse, nil
+}
+
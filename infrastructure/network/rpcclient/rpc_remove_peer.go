@@ -0,0 +1,21 @@
+package rpcclient
+
+import "github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+
+// RemovePeer sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) RemovePeer(address string) (*appmessage.RemovePeerResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewRemovePeerRequestMessage(address))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdRemovePeerResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	removePeerResponse := response.(*appmessage.RemovePeerResponseMessage)
+	if removePeerResponse.Error != nil {
+		return nil, c.convertRPCError(removePeerResponse.Error)
+	}
+	return removePeerResponse, nil
+}
+
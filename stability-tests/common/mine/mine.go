@@ -128,7 +128,7 @@ func mineOrFetchBlock(blockData JSONBlock, mdb *miningDB, testConsensus testapi.
 	}
 
 	if !testConsensus.DAGParams().SkipProofOfWork {
-		SolveBlock(block)
+		SolveBlock(block, testConsensus.DAGParams().FishHashActivationDAAScore)
 	}
 
 	err = testConsensus.ValidateAndInsertBlock(block, true)
@@ -153,7 +153,7 @@ func mineOrFetchBlock(blockData JSONBlock, mdb *miningDB, testConsensus testapi.
 var random = rand.New(rand.NewSource(time.Now().UnixNano()))
 
 // SolveBlock increments the given block's nonce until it matches the difficulty requirements in its bits field
-func SolveBlock(block *externalapi.DomainBlock) {
-	mining.SolveBlock(block, random)
+func SolveBlock(block *externalapi.DomainBlock, fishHashActivationDAAScore uint64) {
+	mining.SolveBlock(block, random, fishHashActivationDAAScore)
 }
 
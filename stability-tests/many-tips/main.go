@@ -77,7 +77,8 @@ func realMain() error {
 	genesisTimestamp := activeConfig().NetParams().GenesisBlock.Header.TimeInMilliseconds()
 	mutableHeader.SetTimeInMilliseconds(genesisTimestamp + 1000)
 	block.Header = mutableHeader.ToImmutable()
-	mining.SolveBlock(block, rand.New(rand.NewSource(time.Now().UnixNano())))
+	mining.SolveBlock(block, rand.New(rand.NewSource(time.Now().UnixNano())),
+		activeConfig().NetParams().FishHashActivationDAAScore)
 	_, err = rpcClient.SubmitBlockAlsoIfNonDAA(block)
 	if err != nil {
 		return err
@@ -183,7 +184,8 @@ func mineBlock(rpcClient *rpc.Client, miningAddress util.Address) error {
 	if err != nil {
 		return err
 	}
-	mining.SolveBlock(block, rand.New(rand.NewSource(time.Now().UnixNano())))
+	mining.SolveBlock(block, rand.New(rand.NewSource(time.Now().UnixNano())),
+		activeConfig().NetParams().FishHashActivationDAAScore)
 	_, err = rpcClient.SubmitBlockAlsoIfNonDAA(block)
 	if err != nil {
 		return err
@@ -202,7 +204,7 @@ func mineTips(numOfTips int, miningAddress util.Address, rpcClient *rpc.Client)
 	}
 	rd := rand.New(rand.NewSource(time.Now().UnixNano()))
 	for i := 0; i < numOfTips; i++ {
-		mining.SolveBlock(block, rd)
+		mining.SolveBlock(block, rd, activeConfig().NetParams().FishHashActivationDAAScore)
 		_, err = rpcClient.SubmitBlockAlsoIfNonDAA(block)
 		if err != nil {
 			return err
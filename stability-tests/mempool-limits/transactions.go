@@ -106,7 +106,7 @@ func mineBlockAndGetCoinbaseTransaction(t *testing.T, rpcClient *rpcclient.RPCCl
 	if err != nil {
 		t.Fatalf("RPCBlockToDomainBlock: %+v", err)
 	}
-	mine.SolveBlock(templateBlock)
+	mine.SolveBlock(templateBlock, dagconfig.SimnetParams.FishHashActivationDAAScore)
 	_, err = rpcClient.SubmitBlockAlsoIfNonDAA(templateBlock)
 	if err != nil {
 		t.Fatalf("SubmitBlock: %+v", err)
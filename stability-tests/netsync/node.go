@@ -197,7 +197,7 @@ func mineOnTips(client *rpc.Client) (appmessage.RejectReason, error) {
 	}
 
 	if !activeConfig().NetParams().SkipProofOfWork {
-		mine.SolveBlock(domainBlock)
+		mine.SolveBlock(domainBlock, activeConfig().NetParams().FishHashActivationDAAScore)
 	}
 
 	return client.SubmitBlockAlsoIfNonDAA(domainBlock)
@@ -48,7 +48,7 @@ func prepareBlocks() (blocks []*externalapi.DomainBlock, topBlock *externalapi.D
 			return nil, nil, errors.Wrap(err, "error in BuildBlockWithParents")
 		}
 
-		mine.SolveBlock(block)
+		mine.SolveBlock(block, config.ActiveNetParams.FishHashActivationDAAScore)
 		err = testConsensus.ValidateAndInsertBlock(block, true)
 		if err != nil {
 			return nil, nil, errors.Wrap(err, "error in ValidateAndInsertBlock")
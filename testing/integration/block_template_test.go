@@ -0,0 +1,135 @@
+package integration
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/mining"
+)
+
+func TestHeaderOnlyBlockTemplate(t *testing.T) {
+	harness, teardown := setupHarness(t, &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+	})
+	defer teardown()
+
+	headerOnlyTemplate, err := harness.rpcClient.GetBlockTemplateWithOptions(harness.miningAddress, "integration", "", true)
+	if err != nil {
+		t.Fatalf("Error getting header-only block template: %+v", err)
+	}
+	if len(headerOnlyTemplate.Block.Transactions) != 0 {
+		t.Fatalf("Expected a header-only block template, but it has %d transactions",
+			len(headerOnlyTemplate.Block.Transactions))
+	}
+
+	// The template is rebuilt after a second, with a newer timestamp that doesn't change its ID
+	time.Sleep(1100 * time.Millisecond)
+	fullTemplate, err := harness.rpcClient.GetBlockTemplate(harness.miningAddress, "integration")
+	if err != nil {
+		t.Fatalf("Error getting block template: %+v", err)
+	}
+	if fullTemplate.TemplateID != headerOnlyTemplate.TemplateID {
+		t.Fatalf("Expected the full and header-only templates to have the same ID, but got %s and %s",
+			fullTemplate.TemplateID, headerOnlyTemplate.TemplateID)
+	}
+
+	block, err := appmessage.RPCBlockToDomainBlock(headerOnlyTemplate.Block)
+	if err != nil {
+		t.Fatalf("Error converting block: %s", err)
+	}
+
+	// A header-only block that isn't of a header-only template is rejected
+	unknownBlock := block.Clone()
+	mutableHeader := unknownBlock.Header.ToMutable()
+	mutableHeader.SetHashMerkleRoot(&externalapi.DomainHash{})
+	unknownBlock.Header = mutableHeader.ToImmutable()
+	_, err = harness.rpcClient.SubmitBlockAlsoIfNonDAA(unknownBlock)
+	if err == nil || !strings.Contains(err.Error(), "header-only block templates") {
+		t.Fatalf("Expected submitting a header-only block of an unknown template to fail, but got: %v", err)
+	}
+
+	rd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	mining.SolveBlock(block, rd, harness.config.ActiveNetParams.FishHashActivationDAAScore)
+	_, err = harness.rpcClient.SubmitBlockAlsoIfNonDAA(block)
+	if err != nil {
+		t.Fatalf("Error submitting header-only block: %s", err)
+	}
+
+	getBlockResponse, err := harness.rpcClient.GetBlock(consensushashing.BlockHash(block).String(), true)
+	if err != nil {
+		t.Fatalf("Error getting block: %+v", err)
+	}
+	if len(getBlockResponse.Block.Transactions) != len(fullTemplate.Block.Transactions) {
+		t.Fatalf("Expected the submitted block to have the %d transactions of its template, but it has %d",
+			len(fullTemplate.Block.Transactions), len(getBlockResponse.Block.Transactions))
+	}
+}
+
+func TestLongPollBlockTemplate(t *testing.T) {
+	harness, teardown := setupHarness(t, &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+	})
+	defer teardown()
+
+	// A template that differs from the given ID is returned right away
+	template, err := harness.rpcClient.GetBlockTemplateWithOptions(harness.miningAddress, "integration", "unknown", false)
+	if err != nil {
+		t.Fatalf("Error getting block template: %+v", err)
+	}
+
+	// A long-polling request blocks its connection, so it's sent over a dedicated one
+	longPollClient, err := newTestRPCClient(harness.rpcAddress)
+	if err != nil {
+		t.Fatalf("Error getting RPC client: %+v", err)
+	}
+	defer longPollClient.Close()
+
+	type longPollResult struct {
+		template *appmessage.GetBlockTemplateResponseMessage
+		err      error
+	}
+	longPollResultChan := make(chan longPollResult)
+	spawn("TestLongPollBlockTemplate-longPoll", func() {
+		newTemplate, err := longPollClient.GetBlockTemplateWithOptions(
+			harness.miningAddress, "integration", template.TemplateID, false)
+		longPollResultChan <- longPollResult{template: newTemplate, err: err}
+	})
+
+	select {
+	case <-longPollResultChan:
+		t.Fatalf("The long-polling request returned before the template changed")
+	case <-time.After(2 * time.Second):
+	}
+
+	block := mineNextBlock(t, harness)
+
+	select {
+	case result := <-longPollResultChan:
+		if result.err != nil {
+			t.Fatalf("Error long-polling block template: %+v", result.err)
+		}
+		if result.template.TemplateID == template.TemplateID {
+			t.Fatalf("Expected the long-polling request to return a new template")
+		}
+		blockHash := consensushashing.BlockHash(block).String()
+		parentHashes := result.template.Block.Header.Parents[0].ParentHashes
+		if len(parentHashes) != 1 || parentHashes[0] != blockHash {
+			t.Fatalf("Expected the new template to point at the mined block %s, but it points at %s",
+				blockHash, parentHashes)
+		}
+	case <-time.After(defaultTimeout):
+		t.Fatalf("Timeout waiting for the long-polling request to return")
+	}
+}
+
@@ -0,0 +1,219 @@
+package integration
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/protocol/flowcontext"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/constants"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/transactionhelper"
+	"github.com/karlsend/PYVERT/testfork/karlsend/util"
+)
+
+const (
+	// dandelionMinEmbargoDuration and dandelionMaxEmbargoDuration mirror the range of
+	// embargo durations in flowcontext
+	dandelionMinEmbargoDuration = 10 * time.Second
+	dandelionMaxEmbargoDuration = 30 * time.Second
+
+	// dandelionStemPhaseTimeout is how long a stem transaction is given to reach a peer.
+	// It's well below dandelionMinEmbargoDuration, so that a transaction that arrives within
+	// it wasn't broadcast because an embargo expired.
+	dandelionStemPhaseTimeout = 5 * time.Second
+)
+
+func TestDandelionStemRelay(t *testing.T) {
+	harnesses, teardown := setupHarnesses(t, []*harnessParams{
+		dandelionHarnessParams(p2pAddress1, rpcAddress1, miningAddress1, miningAddress1PrivateKey, true, 0),
+		dandelionHarnessParams(p2pAddress2, rpcAddress2, miningAddress2, miningAddress2PrivateKey, true, 0),
+		dandelionHarnessParams(p2pAddress3, rpcAddress3, miningAddress3, miningAddress3PrivateKey, true, 0),
+	})
+	defer teardown()
+	payer, stemCandidate1, stemCandidate2 := harnesses[0], harnesses[1], harnesses[2]
+
+	// Connect both stem candidates to the payer only:
+	// stemCandidate1 <--> payer <--> stemCandidate2
+	connect(t, payer, stemCandidate1)
+	connect(t, payer, stemCandidate2)
+
+	txID := submitDandelionTransaction(t, payer, stemCandidate1, stemCandidate1, stemCandidate2)
+
+	// The payer relays the transaction to a single stem peer. That peer has no other
+	// stem peer to relay it to, so it broadcasts it back to the payer, which already
+	// has it, and the other candidate never receives it.
+	if !waitForMempoolEntry(t, dandelionStemPhaseTimeout, txID, stemCandidate1, stemCandidate2) {
+		t.Fatalf("Timeout waiting for the transaction to reach a stem peer")
+	}
+	time.Sleep(time.Second)
+	hasTransaction1 := hasMempoolEntry(t, stemCandidate1, txID)
+	hasTransaction2 := hasMempoolEntry(t, stemCandidate2, txID)
+	if hasTransaction1 == hasTransaction2 {
+		t.Fatalf("Expected the transaction to be relayed to exactly one stem peer, but stemCandidate1: %t, "+
+			"stemCandidate2: %t", hasTransaction1, hasTransaction2)
+	}
+}
+
+func TestDandelionFluff(t *testing.T) {
+	harnesses, teardown := setupHarnesses(t, []*harnessParams{
+		dandelionHarnessParams(p2pAddress1, rpcAddress1, miningAddress1, miningAddress1PrivateKey, true, 0),
+		dandelionHarnessParams(p2pAddress2, rpcAddress2, miningAddress2, miningAddress2PrivateKey, true, 0),
+		dandelionHarnessParams(p2pAddress3, rpcAddress3, miningAddress3, miningAddress3PrivateKey, false, 0),
+	})
+	defer teardown()
+	payer, stemPeer, payee := harnesses[0], harnesses[1], harnesses[2]
+
+	// Connect nodes in chain: payer <--> stemPeer <--> payee
+	// The payee doesn't support Dandelion, so the stem ends at stemPeer
+	connect(t, payer, stemPeer)
+	connect(t, stemPeer, payee)
+
+	txID := submitDandelionTransaction(t, payer, payee, stemPeer, payee)
+
+	// stemPeer has no stem peer of its own, so it switches the transaction to its fluff
+	// phase and broadcasts it to the payee long before the embargo of the payer expires
+	if !waitForMempoolEntry(t, dandelionStemPhaseTimeout, txID, payee) {
+		t.Fatalf("Timeout waiting for the transaction to be fluffed to the payee")
+	}
+}
+
+func TestDandelionEmbargoExpiry(t *testing.T) {
+	harnesses, teardown := setupHarnesses(t, []*harnessParams{
+		dandelionHarnessParams(p2pAddress1, rpcAddress1, miningAddress1, miningAddress1PrivateKey, true, 0),
+		// The stem peer doesn't accept the transaction because of its fee, which breaks off the stem phase
+		dandelionHarnessParams(p2pAddress2, rpcAddress2, miningAddress2, miningAddress2PrivateKey, true,
+			util.Amount(constants.SompiPerKaspa)),
+		dandelionHarnessParams(p2pAddress3, rpcAddress3, miningAddress3, miningAddress3PrivateKey, false, 0),
+	})
+	defer teardown()
+	payer, stemPeer, payee := harnesses[0], harnesses[1], harnesses[2]
+
+	// stemPeer is the only peer of the payer that supports Dandelion:
+	// stemPeer <--> payer <--> payee
+	connect(t, payer, stemPeer)
+	connect(t, payer, payee)
+
+	txID := submitDandelionTransaction(t, payer, payee, stemPeer, payee)
+
+	// Keep the nodes nearly synced while waiting, so that they keep relaying transactions.
+	// The blocks are mined by stemPeer, which doesn't have the transaction in its mempool.
+	stopMiningChan := make(chan struct{})
+	defer close(stopMiningChan)
+	spawn("TestDandelionEmbargoExpiry-mineBlocks", func() {
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopMiningChan:
+				return
+			case <-ticker.C:
+				mineNextBlock(t, stemPeer)
+			}
+		}
+	})
+
+	// Nothing broadcasts the transaction until the embargo of the payer expires
+	if waitForMempoolEntry(t, dandelionStemPhaseTimeout, txID, payee) {
+		t.Fatalf("Expected the transaction not to be broadcast while it's embargoed")
+	}
+
+	if !waitForMempoolEntry(t, dandelionMaxEmbargoDuration+dandelionStemPhaseTimeout, txID, payee) {
+		t.Fatalf("Timeout waiting for the transaction to be broadcast after its embargo expired")
+	}
+	if hasMempoolEntry(t, stemPeer, txID) {
+		t.Fatalf("Expected stemPeer to reject the transaction")
+	}
+}
+
+func dandelionHarnessParams(p2pAddress, rpcAddress, miningAddress, miningAddressPrivateKey string,
+	dandelion bool, minRelayTxFee util.Amount) *harnessParams {
+
+	return &harnessParams{
+		p2pAddress:              p2pAddress,
+		rpcAddress:              rpcAddress,
+		miningAddress:           miningAddress,
+		miningAddressPrivateKey: miningAddressPrivateKey,
+		dandelion:               dandelion,
+		minRelayTxFee:           minRelayTxFee,
+	}
+}
+
+// submitDandelionTransaction mines mature coins for the payer, waits for the given harnesses
+// to receive the blocks, and submits a transaction that pays the payee to the payer
+func submitDandelionTransaction(t *testing.T, payer, payee *appHarness, otherHarnesses ...*appHarness) string {
+	blockAddedChans := make([]chan struct{}, len(otherHarnesses))
+	for i, harness := range otherHarnesses {
+		blockAddedChan := make(chan struct{}, 100)
+		blockAddedChans[i] = blockAddedChan
+		setOnBlockAddedHandler(t, harness, func(_ *appmessage.BlockAddedNotificationMessage) {
+			blockAddedChan <- struct{}{}
+		})
+	}
+	mineAndWait := func() *externalapi.DomainBlock {
+		block := mineNextBlock(t, payer)
+		for _, blockAddedChan := range blockAddedChans {
+			select {
+			case <-blockAddedChan:
+			case <-time.After(defaultTimeout):
+				t.Fatalf("Timeout waiting for block added")
+			}
+		}
+		return block
+	}
+
+	// skip the first block because it's paying to genesis script
+	mineAndWait()
+	// use the second block to get money to pay with
+	secondBlock := mineAndWait()
+	// Mine BlockCoinbaseMaturity more blocks for our money to mature
+	for i := uint64(0); i < payer.config.ActiveNetParams.BlockCoinbaseMaturity; i++ {
+		mineAndWait()
+	}
+
+	// Sleep for `TransactionIDPropagationInterval` to make sure that our transaction will
+	// be propagated
+	time.Sleep(flowcontext.TransactionIDPropagationInterval)
+
+	msgTx := generateTx(t, secondBlock.Transactions[transactionhelper.CoinbaseTransactionIndex], payer, payee)
+	rpcTransaction := appmessage.DomainTransactionToRPCTransaction(appmessage.MsgTxToDomainTransaction(msgTx))
+	response, err := payer.rpcClient.SubmitTransaction(rpcTransaction, false)
+	if err != nil {
+		t.Fatalf("Error submitting transaction: %+v", err)
+	}
+	return response.TransactionID
+}
+
+// waitForMempoolEntry waits until any of the given harnesses has the given transaction
+// in its mempool, and returns false if none of them has it within the given timeout
+func waitForMempoolEntry(t *testing.T, timeout time.Duration, txID string, harnesses ...*appHarness) bool {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	deadline := time.After(timeout)
+
+	for {
+		for _, harness := range harnesses {
+			if hasMempoolEntry(t, harness, txID) {
+				return true
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-deadline:
+			return false
+		}
+	}
+}
+
+func hasMempoolEntry(t *testing.T, harness *appHarness, txID string) bool {
+	_, err := harness.rpcClient.GetMempoolEntry(txID, true, false)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return false
+		}
+		t.Fatalf("Error getting mempool entry: %+v", err)
+	}
+	return true
+}
+
@@ -0,0 +1,72 @@
+package integration
+
+import (
+	"testing"
+	"time"
+)
+
+// TestDisconnectWhileFlowsAreStarting disconnects a peer right after the handshake, while its
+// flows are still starting, and checks that the peer is removed from the peers list rather
+// than staying listed until its slowest flow, the ping flow, notices the disconnection.
+func TestDisconnectWhileFlowsAreStarting(t *testing.T) {
+	incoming, teardownIncoming := setupHarness(t, &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+	})
+	defer teardownIncoming()
+
+	outgoing, teardownOutgoing := setupHarness(t, &harnessParams{
+		p2pAddress:              p2pAddress2,
+		rpcAddress:              rpcAddress2,
+		miningAddress:           miningAddress2,
+		miningAddressPrivateKey: miningAddress2PrivateKey,
+	})
+	isOutgoingTornDown := false
+	defer func() {
+		if !isOutgoingTornDown {
+			teardownOutgoing()
+		}
+	}()
+
+	err := outgoing.rpcClient.AddPeer(incoming.p2pAddress, false)
+	if err != nil {
+		t.Fatalf("AddPeer: %+v", err)
+	}
+
+	// The incoming node lists the outgoing one once the handshake is done, right
+	// before it starts its flows, so the outgoing node is stopped as soon as it's listed
+	outgoingID := outgoing.app.P2PNodeID().String()
+	waitForPeerListing(t, incoming, outgoingID, true)
+	teardownOutgoing()
+	isOutgoingTornDown = true
+
+	waitForPeerListing(t, incoming, outgoingID, false)
+}
+
+func waitForPeerListing(t *testing.T, harness *appHarness, peerID string, shouldBeListed bool) {
+	t.Helper()
+	deadline := time.Now().Add(defaultTimeout)
+	for {
+		connectedPeerInfo, err := harness.rpcClient.GetConnectedPeerInfo()
+		if err != nil {
+			t.Fatalf("GetConnectedPeerInfo: %+v", err)
+		}
+		isListed := false
+		for _, info := range connectedPeerInfo.Infos {
+			if info.ID == peerID {
+				isListed = true
+				break
+			}
+		}
+		if isListed == shouldBeListed {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for peer %s to be listed: %t", peerID, shouldBeListed)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
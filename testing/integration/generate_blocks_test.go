@@ -0,0 +1,200 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/model/externalapi"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/transactionhelper"
+)
+
+func TestGenerateBlocks(t *testing.T) {
+	harness, teardown := setupHarness(t, &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+	})
+	defer teardown()
+
+	// The first block pays to the genesis script, so the following blocks are used to get money to pay with,
+	// which matures in time to be spent in a block that isn't built over the selected tip
+	blockCount := uint32(harness.config.ActiveNetParams.BlockCoinbaseMaturity) + 4
+	generateBlocksResponse, err := harness.rpcClient.GenerateBlocks(harness.miningAddress, blockCount, nil, nil)
+	if err != nil {
+		t.Fatalf("Error generating blocks: %+v", err)
+	}
+	if len(generateBlocksResponse.BlockHashes) != int(blockCount) {
+		t.Fatalf("Expected %d generated blocks, but got %d", blockCount, len(generateBlocksResponse.BlockHashes))
+	}
+	blockHashes := generateBlocksResponse.BlockHashes
+	lastBlockHash := blockHashes[blockCount-1]
+	if selectedTipHash := getSelectedTipHash(t, harness); selectedTipHash != lastBlockHash {
+		t.Fatalf("Expected the last generated block %s to be the selected tip, but it's %s",
+			lastBlockHash, selectedTipHash)
+	}
+
+	// A block over the selected tip that includes a transaction from the mempool
+	transactionID := submitGeneratedBlockCoinbaseSpend(t, harness, blockHashes[1])
+	generateBlocksResponse, err = harness.rpcClient.GenerateBlocks(harness.miningAddress, 1, []string{transactionID}, nil)
+	if err != nil {
+		t.Fatalf("Error generating a block with a transaction: %+v", err)
+	}
+	blockWithTransaction := getGeneratedBlock(t, harness, generateBlocksResponse.BlockHashes[0])
+	if len(blockWithTransaction.Transactions) != 2 ||
+		consensushashing.TransactionID(blockWithTransaction.Transactions[1]).String() != transactionID {
+
+		t.Fatalf("Expected the block to include the coinbase and the given transaction, "+
+			"but it has %d transactions", len(blockWithTransaction.Transactions))
+	}
+	if selectedTipHash := getSelectedTipHash(t, harness); selectedTipHash != generateBlocksResponse.BlockHashes[0] {
+		t.Fatalf("Expected the block with the transaction %s to be the selected tip, but it's %s",
+			generateBlocksResponse.BlockHashes[0], selectedTipHash)
+	}
+	if hasMempoolEntry(t, harness, transactionID) {
+		t.Fatalf("Expected the transaction to be removed from the mempool once it's in a block")
+	}
+	mainChainTipHash := generateBlocksResponse.BlockHashes[0]
+
+	// A block with custom parents that includes a transaction, and a block over it
+	transactionID = submitGeneratedBlockCoinbaseSpend(t, harness, blockHashes[2])
+	parentHash := blockHashes[blockCount-2]
+	generateBlocksResponse, err = harness.rpcClient.GenerateBlocks(
+		harness.miningAddress, 2, []string{transactionID}, []string{parentHash})
+	if err != nil {
+		t.Fatalf("Error generating blocks with custom parents: %+v", err)
+	}
+	firstBlock := getGeneratedBlock(t, harness, generateBlocksResponse.BlockHashes[0])
+	expectedParentHashes := []string{parentHash}
+	for _, blockHash := range generateBlocksResponse.BlockHashes {
+		parentHashes := getGeneratedBlockParentHashes(t, harness, blockHash)
+		if len(parentHashes) != 1 || parentHashes[0] != expectedParentHashes[0] {
+			t.Fatalf("Expected block %s to have the parents %s, but it has %s",
+				blockHash, expectedParentHashes, parentHashes)
+		}
+		expectedParentHashes = []string{blockHash}
+	}
+	if len(firstBlock.Transactions) != 2 {
+		t.Fatalf("Expected the first block to include the coinbase and the given transaction, "+
+			"but it has %d transactions", len(firstBlock.Transactions))
+	}
+	sideChainTipHash := generateBlocksResponse.BlockHashes[1]
+
+	// A block that merges both branches
+	generateBlocksResponse, err = harness.rpcClient.GenerateBlocks(
+		harness.miningAddress, 1, nil, []string{mainChainTipHash, sideChainTipHash})
+	if err != nil {
+		t.Fatalf("Error generating a block with multiple parents: %+v", err)
+	}
+	mergingBlockHash := generateBlocksResponse.BlockHashes[0]
+	parentHashes := getGeneratedBlockParentHashes(t, harness, mergingBlockHash)
+	if len(parentHashes) != 2 ||
+		!(parentHashes[0] == mainChainTipHash && parentHashes[1] == sideChainTipHash ||
+			parentHashes[0] == sideChainTipHash && parentHashes[1] == mainChainTipHash) {
+
+		t.Fatalf("Expected block %s to have the parents %s and %s, but it has %s",
+			mergingBlockHash, mainChainTipHash, sideChainTipHash, parentHashes)
+	}
+	if selectedTipHash := getSelectedTipHash(t, harness); selectedTipHash != mergingBlockHash {
+		t.Fatalf("Expected the merging block %s to be the selected tip, but it's %s", mergingBlockHash, selectedTipHash)
+	}
+
+	_, err = harness.rpcClient.GenerateBlocks(harness.miningAddress, 1, nil,
+		[]string{"0000000000000000000000000000000000000000000000000000000000000001"})
+	if err == nil {
+		t.Fatalf("Expected generating a block over an unknown parent to fail")
+	}
+	_, err = harness.rpcClient.GenerateBlocks(harness.miningAddress, 0, nil, nil)
+	if err == nil {
+		t.Fatalf("Expected generating zero blocks to fail")
+	}
+}
+
+func TestSetMockTime(t *testing.T) {
+	harness, teardown := setupHarness(t, &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+	})
+	defer teardown()
+
+	mockTime := time.Now().Add(time.Hour).UnixNano() / int64(time.Millisecond)
+	_, err := harness.rpcClient.SetMockTime(mockTime)
+	if err != nil {
+		t.Fatalf("Error setting mock time: %+v", err)
+	}
+	defer func() {
+		_, err := harness.rpcClient.SetMockTime(0)
+		if err != nil {
+			t.Fatalf("Error unsetting mock time: %+v", err)
+		}
+	}()
+
+	// Blocks that are an hour in the future are accepted once it's the mock time
+	for i := int64(0); i < 3; i++ {
+		blockTime := mockTime + i*1000
+		_, err := harness.rpcClient.SetMockTime(blockTime)
+		if err != nil {
+			t.Fatalf("Error setting mock time: %+v", err)
+		}
+		generateBlocksResponse, err := harness.rpcClient.GenerateBlocks(harness.miningAddress, 1, nil, nil)
+		if err != nil {
+			t.Fatalf("Error generating block: %+v", err)
+		}
+		block := getGeneratedBlock(t, harness, generateBlocksResponse.BlockHashes[0])
+		if block.Header.TimeInMilliseconds() != blockTime {
+			t.Fatalf("Expected the block timestamp to be the mock time %d, but got %d",
+				blockTime, block.Header.TimeInMilliseconds())
+		}
+	}
+
+	_, err = harness.rpcClient.SetMockTime(-1)
+	if err == nil {
+		t.Fatalf("Expected setting a negative mock time to fail")
+	}
+}
+
+func getGeneratedBlock(t *testing.T, harness *appHarness, blockHash string) *externalapi.DomainBlock {
+	getBlockResponse, err := harness.rpcClient.GetBlock(blockHash, true)
+	if err != nil {
+		t.Fatalf("Error getting block: %+v", err)
+	}
+	block, err := appmessage.RPCBlockToDomainBlock(getBlockResponse.Block)
+	if err != nil {
+		t.Fatalf("Error converting block: %s", err)
+	}
+	return block
+}
+
+// submitGeneratedBlockCoinbaseSpend submits a transaction that spends the coinbase of the given
+// generated block, and returns its ID
+func submitGeneratedBlockCoinbaseSpend(t *testing.T, harness *appHarness, blockHash string) string {
+	block := getGeneratedBlock(t, harness, blockHash)
+	msgTx := generateTx(t, block.Transactions[transactionhelper.CoinbaseTransactionIndex], harness, harness)
+	rpcTransaction := appmessage.DomainTransactionToRPCTransaction(appmessage.MsgTxToDomainTransaction(msgTx))
+	submitTransactionResponse, err := harness.rpcClient.SubmitTransaction(rpcTransaction, false)
+	if err != nil {
+		t.Fatalf("Error submitting transaction: %+v", err)
+	}
+	return submitTransactionResponse.TransactionID
+}
+
+func getGeneratedBlockParentHashes(t *testing.T, harness *appHarness, blockHash string) []string {
+	getBlockResponse, err := harness.rpcClient.GetBlock(blockHash, false)
+	if err != nil {
+		t.Fatalf("Error getting block: %+v", err)
+	}
+	return getBlockResponse.Block.Header.Parents[0].ParentHashes
+}
+
+func getSelectedTipHash(t *testing.T, harness *appHarness) string {
+	getSelectedTipHashResponse, err := harness.rpcClient.GetSelectedTipHash()
+	if err != nil {
+		t.Fatalf("Error getting selected tip hash: %+v", err)
+	}
+	return getSelectedTipHashResponse.SelectedTipHash
+}
+
@@ -230,7 +230,7 @@ func mineNextBlockWithMockTimestamps(t *testing.T, harness *appHarness, rd *rand
 	mutableHeader.SetTimeInMilliseconds(currentMockTimestamp)
 	block.Header = mutableHeader.ToImmutable()
 
-	mining.SolveBlock(block, rd)
+	mining.SolveBlock(block, rd, harness.config.ActiveNetParams.FishHashActivationDAAScore)
 
 	_, err = harness.rpcClient.SubmitBlockAlsoIfNonDAA(block)
 	if err != nil {
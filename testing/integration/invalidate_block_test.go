@@ -0,0 +1,65 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
+)
+
+func TestInvalidateAndReconsiderBlock(t *testing.T) {
+	appHarness1, _, _, teardown := standardSetup(t)
+	defer teardown()
+
+	const chainLength = 4
+	blockHashes := make([]string, chainLength)
+	for i := 0; i < chainLength; i++ {
+		block := mineNextBlock(t, appHarness1)
+		blockHashes[i] = consensushashing.BlockHash(block).String()
+	}
+	requireSelectedTipHash(t, appHarness1, blockHashes[chainLength-1])
+
+	// Invalidating a block should move the selected tip to its selected parent
+	_, err := appHarness1.rpcClient.InvalidateBlock(blockHashes[2])
+	if err != nil {
+		t.Fatalf("InvalidateBlock: %+v", err)
+	}
+	requireSelectedTipHash(t, appHarness1, blockHashes[1])
+
+	_, err = appHarness1.rpcClient.InvalidateBlock(blockHashes[2])
+	if err == nil {
+		t.Fatalf("Expected invalidating an already invalidated block to fail")
+	}
+
+	// New blocks are mined on top of the remaining chain
+	block := mineNextBlock(t, appHarness1)
+	selectedParent := block.Header.DirectParents()[0].String()
+	if selectedParent != blockHashes[1] {
+		t.Fatalf("Expected the new block to be mined on top of %s, but its parent is %s",
+			blockHashes[1], selectedParent)
+	}
+	requireSelectedTipHash(t, appHarness1, consensushashing.BlockHash(block).String())
+
+	// Reconsidering the block should return the selected tip to the longer chain
+	_, err = appHarness1.rpcClient.ReconsiderBlock(blockHashes[2])
+	if err != nil {
+		t.Fatalf("ReconsiderBlock: %+v", err)
+	}
+	requireSelectedTipHash(t, appHarness1, blockHashes[chainLength-1])
+
+	_, err = appHarness1.rpcClient.ReconsiderBlock(blockHashes[2])
+	if err == nil {
+		t.Fatalf("Expected reconsidering a block that isn't invalidated to fail")
+	}
+}
+
+func requireSelectedTipHash(t *testing.T, harness *appHarness, expectedSelectedTipHash string) {
+	selectedTipHashResponse, err := harness.rpcClient.GetSelectedTipHash()
+	if err != nil {
+		t.Fatalf("GetSelectedTipHash: %+v", err)
+	}
+	if selectedTipHashResponse.SelectedTipHash != expectedSelectedTipHash {
+		t.Fatalf("Expected the selected tip to be %s, but got %s",
+			expectedSelectedTipHash, selectedTipHashResponse.SelectedTipHash)
+	}
+}
+
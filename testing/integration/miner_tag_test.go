@@ -0,0 +1,81 @@
+package integration
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
+	"github.com/karlsend/PYVERT/testfork/karlsend/version"
+)
+
+func TestMinerTag(t *testing.T) {
+	const minerTag = "integration-pool"
+	harness, teardown := setupHarness(t, &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+		minerTag:                minerTag,
+	})
+	defer teardown()
+
+	tests := []struct {
+		name             string
+		extraData        string
+		expectedMinerTag string
+	}{
+		{
+			name:             "default miner tag",
+			extraData:        "",
+			expectedMinerTag: minerTag,
+		},
+		{
+			name:             "extra data of the request",
+			extraData:        "custom/tag",
+			expectedMinerTag: "custom/tag",
+		},
+	}
+
+	for _, test := range tests {
+		block := mineNextBlockWithExtraData(t, harness, test.extraData)
+
+		getBlockResponse, err := harness.rpcClient.GetBlock(consensushashing.BlockHash(block).String(), false)
+		if err != nil {
+			t.Fatalf("%s: Error getting block: %+v", test.name, err)
+		}
+		coinbaseData := getBlockResponse.Block.VerboseData.CoinbaseData
+		if coinbaseData == nil {
+			t.Fatalf("%s: The verbose data of the block has no coinbase data", test.name)
+		}
+		if coinbaseData.MinerTag != test.expectedMinerTag {
+			t.Fatalf("%s: Expected miner tag %s but got %s", test.name, test.expectedMinerTag, coinbaseData.MinerTag)
+		}
+		if coinbaseData.Version != version.Version() {
+			t.Fatalf("%s: Expected version %s but got %s", test.name, version.Version(), coinbaseData.Version)
+		}
+		expectedExtraData := hex.EncodeToString([]byte(version.Version() + "/" + test.expectedMinerTag))
+		if coinbaseData.ExtraData != expectedExtraData {
+			t.Fatalf("%s: Expected extra data %s but got %s", test.name, expectedExtraData, coinbaseData.ExtraData)
+		}
+		if coinbaseData.ScriptPublicKeyAddress != harness.miningAddress {
+			t.Fatalf("%s: Expected pay address %s but got %s",
+				test.name, harness.miningAddress, coinbaseData.ScriptPublicKeyAddress)
+		}
+	}
+
+	_, err := harness.rpcClient.GetBlockTemplate(harness.miningAddress, strings.Repeat("a", 300))
+	if err == nil {
+		t.Fatalf("Expected an error getting a block template with extra data that is too long")
+	}
+	if !strings.Contains(err.Error(), "Coinbase payload of") {
+		t.Fatalf("Unexpected error getting a block template with extra data that is too long: %s", err)
+	}
+
+	// The genesis block can be fetched whether or not its coinbase payload can be decoded
+	_, err = harness.rpcClient.GetBlock(harness.config.ActiveNetParams.GenesisHash.String(), true)
+	if err != nil {
+		t.Fatalf("Error getting the genesis block: %+v", err)
+	}
+}
+
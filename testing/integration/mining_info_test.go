@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+)
+
+func TestGetMiningInfo(t *testing.T) {
+	harness, teardown := setupHarness(t, &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+	})
+	defer teardown()
+
+	// Mine enough blocks for some of the rewards to mature
+	const blockAmountToMine = 20
+	for i := 0; i < blockAmountToMine; i++ {
+		mineNextBlock(t, harness)
+	}
+
+	// The stats of the submitted blocks and of the blocks paying to their miner are expected
+	// to be the same, since all the blocks in the DAG were submitted via this node
+	submittedBlocksInfo, err := harness.rpcClient.GetMiningInfo("", 0)
+	if err != nil {
+		t.Fatalf("Error getting mining info: %+v", err)
+	}
+	requireMiningInfoBlockCounts(t, submittedBlocksInfo, blockAmountToMine)
+	if submittedBlocksInfo.MaturedRewards == 0 {
+		t.Fatalf("Expected some of the rewards to be matured")
+	}
+	if submittedBlocksInfo.PendingRewards == 0 {
+		t.Fatalf("Expected some of the rewards to be pending")
+	}
+
+	payAddressInfo, err := harness.rpcClient.GetMiningInfo(miningAddress1, 0)
+	if err != nil {
+		t.Fatalf("Error getting mining info: %+v", err)
+	}
+	requireMiningInfoBlockCounts(t, payAddressInfo, blockAmountToMine)
+	if payAddressInfo.MaturedRewards != submittedBlocksInfo.MaturedRewards ||
+		payAddressInfo.PendingRewards != submittedBlocksInfo.PendingRewards {
+		t.Fatalf("Unexpected rewards. Want: %d matured and %d pending, got: %d matured and %d pending",
+			submittedBlocksInfo.MaturedRewards, submittedBlocksInfo.PendingRewards,
+			payAddressInfo.MaturedRewards, payAddressInfo.PendingRewards)
+	}
+
+	otherAddressInfo, err := harness.rpcClient.GetMiningInfo(miningAddress3, 0)
+	if err != nil {
+		t.Fatalf("Error getting mining info: %+v", err)
+	}
+	requireMiningInfoBlockCounts(t, otherAddressInfo, 0)
+	if otherAddressInfo.MaturedRewards != 0 || otherAddressInfo.PendingRewards != 0 {
+		t.Fatalf("Unexpected rewards for an address that didn't mine")
+	}
+
+	if submittedBlocksInfo.BlockTemplateAgeMilliseconds < 0 {
+		t.Fatalf("Expected a block template to have been built")
+	}
+
+	_, err = harness.rpcClient.GetMiningInfo("not-an-address", 0)
+	if err == nil {
+		t.Fatalf("Expected an error for an invalid pay address")
+	}
+}
+
+func requireMiningInfoBlockCounts(t *testing.T, miningInfo *appmessage.GetMiningInfoResponseMessage,
+	expectedAcceptedBlockCount uint64) {
+
+	if miningInfo.AcceptedBlockCount != expectedAcceptedBlockCount {
+		t.Fatalf("Unexpected accepted block count. Want: %d, got: %d",
+			expectedAcceptedBlockCount, miningInfo.AcceptedBlockCount)
+	}
+	colorsBlockCount := miningInfo.BlueBlockCount + miningInfo.RedBlockCount + miningInfo.PendingBlockCount
+	if colorsBlockCount != expectedAcceptedBlockCount {
+		t.Fatalf("Blue, red and pending block counts don't add up to %d: %d blue, %d red and %d pending",
+			expectedAcceptedBlockCount, miningInfo.BlueBlockCount, miningInfo.RedBlockCount,
+			miningInfo.PendingBlockCount)
+	}
+}
+
@@ -11,7 +11,11 @@ import (
 )
 
 func mineNextBlock(t *testing.T, harness *appHarness) *externalapi.DomainBlock {
-	blockTemplate, err := harness.rpcClient.GetBlockTemplate(harness.miningAddress, "integration")
+	return mineNextBlockWithExtraData(t, harness, "integration")
+}
+
+func mineNextBlockWithExtraData(t *testing.T, harness *appHarness, extraData string) *externalapi.DomainBlock {
+	blockTemplate, err := harness.rpcClient.GetBlockTemplate(harness.miningAddress, extraData)
 	if err != nil {
 		t.Fatalf("Error getting block template: %+v", err)
 	}
@@ -22,7 +26,7 @@ func mineNextBlock(t *testing.T, harness *appHarness) *externalapi.DomainBlock {
 	}
 
 	rd := rand.New(rand.NewSource(time.Now().UnixNano()))
-	mining.SolveBlock(block, rd)
+	mining.SolveBlock(block, rd, harness.config.ActiveNetParams.FishHashActivationDAAScore)
 
 	_, err = harness.rpcClient.SubmitBlockAlsoIfNonDAA(block)
 	if err != nil {
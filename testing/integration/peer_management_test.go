@@ -0,0 +1,105 @@
+package integration
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPeerManagement(t *testing.T) {
+	appHarness1, appHarness2, appHarness3, teardown := standardSetup(t)
+	defer teardown()
+
+	// Add a permanent connection request and wait for it to connect
+	err := appHarness1.rpcClient.AddPeer(appHarness2.p2pAddress, true)
+	if err != nil {
+		t.Fatalf("AddPeer: %+v", err)
+	}
+	waitForConnectionState(t, appHarness1, appHarness2, true)
+
+	connectionRequests, err := appHarness1.rpcClient.GetConnectionRequests()
+	if err != nil {
+		t.Fatalf("GetConnectionRequests: %+v", err)
+	}
+	if len(connectionRequests.ConnectionRequests) != 1 {
+		t.Fatalf("Expected 1 connection request but got %d", len(connectionRequests.ConnectionRequests))
+	}
+	connectionRequest := connectionRequests.ConnectionRequests[0]
+	if connectionRequest.Address != appHarness2.p2pAddress || !connectionRequest.IsPermanent ||
+		!connectionRequest.IsConnected {
+		t.Fatalf("Unexpected connection request %+v", connectionRequest)
+	}
+
+	// Removing the connection request should disconnect the peer
+	_, err = appHarness1.rpcClient.RemovePeer(appHarness2.p2pAddress)
+	if err != nil {
+		t.Fatalf("RemovePeer: %+v", err)
+	}
+	waitForConnectionState(t, appHarness1, appHarness2, false)
+
+	connectionRequests, err = appHarness1.rpcClient.GetConnectionRequests()
+	if err != nil {
+		t.Fatalf("GetConnectionRequests: %+v", err)
+	}
+	if len(connectionRequests.ConnectionRequests) != 0 {
+		t.Fatalf("Expected no connection requests but got %d", len(connectionRequests.ConnectionRequests))
+	}
+
+	_, err = appHarness1.rpcClient.RemovePeer(appHarness2.p2pAddress)
+	if err == nil {
+		t.Fatalf("Expected removing a non-existing connection request to fail")
+	}
+
+	// Disconnect an incoming peer by the address reported in GetConnectedPeerInfo
+	connect(t, appHarness1, appHarness3)
+	connectedPeerInfo, err := appHarness1.rpcClient.GetConnectedPeerInfo()
+	if err != nil {
+		t.Fatalf("GetConnectedPeerInfo: %+v", err)
+	}
+	if len(connectedPeerInfo.Infos) != 1 {
+		t.Fatalf("Expected 1 connected peer but got %d", len(connectedPeerInfo.Infos))
+	}
+	_, err = appHarness1.rpcClient.DisconnectPeer(connectedPeerInfo.Infos[0].Address)
+	if err != nil {
+		t.Fatalf("DisconnectPeer: %+v", err)
+	}
+	waitForConnectionState(t, appHarness1, appHarness3, false)
+
+	_, err = appHarness1.rpcClient.DisconnectPeer(connectedPeerInfo.Infos[0].Address)
+	if err == nil {
+		t.Fatalf("Expected disconnecting a peer that isn't connected to fail")
+	}
+	_, err = appHarness1.rpcClient.DisconnectPeer("[::1")
+	if err == nil || !strings.Contains(err.Error(), "Could not parse address") {
+		t.Fatalf("Expected disconnecting a malformed address to fail parsing it, but got: %v", err)
+	}
+
+	// Banned addresses should be reported along with their ban expiry
+	const bannedIP = "127.0.0.2"
+	_, err = appHarness1.rpcClient.Ban(bannedIP)
+	if err != nil {
+		t.Fatalf("Ban: %+v", err)
+	}
+	bannedPeers, err := appHarness1.rpcClient.GetBannedPeers()
+	if err != nil {
+		t.Fatalf("GetBannedPeers: %+v", err)
+	}
+	if len(bannedPeers.BannedPeers) != 1 || bannedPeers.BannedPeers[0].Address != bannedIP {
+		t.Fatalf("Unexpected banned peers %+v", bannedPeers.BannedPeers)
+	}
+	if bannedPeers.BannedPeers[0].BanExpiry <= time.Now().UnixMilli() {
+		t.Fatalf("Expected the ban of %s to expire in the future", bannedIP)
+	}
+}
+
+func waitForConnectionState(t *testing.T, appHarness1, appHarness2 *appHarness, shouldBeConnected bool) {
+	t.Helper()
+	deadline := time.Now().Add(defaultTimeout)
+	for isConnected(t, appHarness1, appHarness2) != shouldBeConnected {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for the connection state to become %t", shouldBeConnected)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
@@ -0,0 +1,68 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/transactionhelper"
+)
+
+func TestPrioritiseTransaction(t *testing.T) {
+	payer, _, payee, teardown := standardSetup(t)
+	defer teardown()
+
+	// skip the first block because it's paying to genesis script
+	mineNextBlock(t, payer)
+	// use the second block to get money to pay with
+	secondBlock := mineNextBlock(t, payer)
+	// Mine BlockCoinbaseMaturity more blocks for our money to mature
+	for i := uint64(0); i < payer.config.ActiveNetParams.BlockCoinbaseMaturity; i++ {
+		mineNextBlock(t, payer)
+	}
+
+	msgTx := generateTx(t, secondBlock.Transactions[transactionhelper.CoinbaseTransactionIndex], payer, payee)
+	domainTransaction := appmessage.MsgTxToDomainTransaction(msgTx)
+	rpcTransaction := appmessage.DomainTransactionToRPCTransaction(domainTransaction)
+	submitTransactionResponse, err := payer.rpcClient.SubmitTransaction(rpcTransaction, false)
+	if err != nil {
+		t.Fatalf("Error submitting transaction: %+v", err)
+	}
+	transactionID := submitTransactionResponse.TransactionID
+
+	response, err := payer.rpcClient.PrioritiseTransaction(transactionID, 1000)
+	if err != nil {
+		t.Fatalf("Error prioritising transaction: %+v", err)
+	}
+	if response.TotalFeeDelta != 1000 {
+		t.Fatalf("Unexpected total fee delta: expected: %d, got: %d", 1000, response.TotalFeeDelta)
+	}
+	response, err = payer.rpcClient.PrioritiseTransaction(transactionID, 500)
+	if err != nil {
+		t.Fatalf("Error prioritising transaction: %+v", err)
+	}
+	if response.TotalFeeDelta != 1500 {
+		t.Fatalf("Unexpected total fee delta: expected: %d, got: %d", 1500, response.TotalFeeDelta)
+	}
+
+	// The fee delta is for the selection only, so the block that includes the
+	// transaction is still valid
+	block := mineNextBlock(t, payer)
+	isTransactionIncluded := false
+	for _, transaction := range block.Transactions {
+		if consensushashing.TransactionID(transaction).String() == transactionID {
+			isTransactionIncluded = true
+			break
+		}
+	}
+	if !isTransactionIncluded {
+		t.Fatalf("The prioritised transaction wasn't included in the mined block")
+	}
+
+	// The transaction left the mempool with the block, and its fee delta with it
+	_, err = payer.rpcClient.PrioritiseTransaction(transactionID, 1000)
+	if err == nil {
+		t.Fatalf("Expected an error when prioritising a transaction that is not in the mempool")
+	}
+}
+
@@ -0,0 +1,75 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/consensushashing"
+	"github.com/karlsend/PYVERT/testfork/karlsend/domain/consensus/utils/transactionhelper"
+)
+
+func TestReorgNotifications(t *testing.T) {
+	// Setup a couple of karlsend instances
+	karlsend1, karlsend2, _, teardown := standardSetup(t)
+	defer teardown()
+
+	onReorgChan := make(chan *appmessage.ReorgNotificationMessage)
+	err := karlsend1.rpcClient.RegisterForReorgNotifications(func(notification *appmessage.ReorgNotificationMessage) {
+		onReorgChan <- notification
+	})
+	if err != nil {
+		t.Fatalf("Failed to register for reorg notifications: %s", err)
+	}
+
+	// In karlsend1, mine a chain in which a transaction is accepted.
+	// Skip the first block because it's paying to genesis script
+	mineNextBlock(t, karlsend1)
+	secondBlock := mineNextBlock(t, karlsend1)
+	for i := uint64(0); i < karlsend1.config.ActiveNetParams.BlockCoinbaseMaturity; i++ {
+		mineNextBlock(t, karlsend1)
+	}
+	msgTx := generateTx(t, secondBlock.Transactions[transactionhelper.CoinbaseTransactionIndex], karlsend1, karlsend2)
+	rpcTransaction := appmessage.DomainTransactionToRPCTransaction(appmessage.MsgTxToDomainTransaction(msgTx))
+	response, err := karlsend1.rpcClient.SubmitTransaction(rpcTransaction, false)
+	if err != nil {
+		t.Fatalf("Error submitting transaction: %+v", err)
+	}
+	// The transaction is accepted by the chain block that merges the block that includes it
+	mineNextBlock(t, karlsend1)
+	mineNextBlock(t, karlsend1)
+	chain1Length := karlsend1.config.ActiveNetParams.BlockCoinbaseMaturity + 4
+
+	// In karlsend2, mine a longer chain over the genesis
+	for i := uint64(0); i < chain1Length+1; i++ {
+		mineNextBlock(t, karlsend2)
+	}
+
+	// Connecting the two karlsends makes karlsend1 sync the longer chain
+	// and reorg out all of its own chain blocks
+	connect(t, karlsend1, karlsend2)
+	notification := <-onReorgChan
+
+	genesisHashString := consensushashing.BlockHash(karlsend1.config.NetParams().GenesisBlock).String()
+	if notification.CommonAncestorHash != genesisHashString {
+		t.Fatalf("Unexpected common ancestor. Want: %s, got: %s", genesisHashString, notification.CommonAncestorHash)
+	}
+	if notification.Depth != chain1Length {
+		t.Fatalf("Unexpected depth. Want: %d, got: %d", chain1Length, notification.Depth)
+	}
+	if uint64(len(notification.RemovedChainBlockHashes)) != chain1Length {
+		t.Fatalf("Unexpected length of RemovedChainBlockHashes. Want: %d, got: %d",
+			chain1Length, len(notification.RemovedChainBlockHashes))
+	}
+	if len(notification.AddedChainBlockHashes) == 0 {
+		t.Fatalf("AddedChainBlockHashes is unexpectedly empty")
+	}
+	isTransactionRemoved := false
+	for _, transactionID := range notification.RemovedTransactionIDs {
+		isTransactionRemoved = isTransactionRemoved || transactionID == response.TransactionID
+	}
+	if !isTransactionRemoved {
+		t.Fatalf("Expected transaction %s to be in RemovedTransactionIDs, but got: %s",
+			response.TransactionID, notification.RemovedTransactionIDs)
+	}
+}
+
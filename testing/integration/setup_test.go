@@ -12,6 +12,7 @@ import (
 
 	"github.com/karlsend/PYVERT/testfork/karlsend/app"
 	"github.com/karlsend/PYVERT/testfork/karlsend/infrastructure/config"
+	"github.com/karlsend/PYVERT/testfork/karlsend/util"
 )
 
 type appHarness struct {
@@ -24,6 +25,9 @@ type appHarness struct {
 	config                  *config.Config
 	database                database.Database
 	utxoIndex               bool
+	minerTag                string
+	dandelion               bool
+	minRelayTxFee           util.Amount
 	overrideDAGParams       *dagconfig.Params
 }
 
@@ -33,6 +37,9 @@ type harnessParams struct {
 	miningAddress           string
 	miningAddressPrivateKey string
 	utxoIndex               bool
+	minerTag                string
+	dandelion               bool
+	minRelayTxFee           util.Amount
 	overrideDAGParams       *dagconfig.Params
 	protocolVersion         uint32
 }
@@ -45,6 +52,9 @@ func setupHarness(t *testing.T, params *harnessParams) (harness *appHarness, tea
 		miningAddress:           params.miningAddress,
 		miningAddressPrivateKey: params.miningAddressPrivateKey,
 		utxoIndex:               params.utxoIndex,
+		minerTag:                params.minerTag,
+		dandelion:               params.dandelion,
+		minRelayTxFee:           params.minRelayTxFee,
 		overrideDAGParams:       params.overrideDAGParams,
 	}
 
@@ -0,0 +1,238 @@
+package integration
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/karlsend/PYVERT/testfork/karlsend/app/appmessage"
+	"github.com/karlsend/PYVERT/testfork/karlsend/cmd/karlsenstratum/stratumserver"
+)
+
+const (
+	stratumAddress1 = "127.0.0.1:15555"
+	stratumAddress2 = "127.0.0.1:15556"
+)
+
+func TestStratum(t *testing.T) {
+	harness, teardown := setupHarness(t, &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+	})
+	defer teardown()
+
+	blockAddedChan := make(chan *appmessage.BlockAddedNotificationMessage, 1)
+	setOnBlockAddedHandler(t, harness, func(notification *appmessage.BlockAddedNotificationMessage) {
+		select {
+		case blockAddedChan <- notification:
+		default:
+		}
+	})
+
+	// Every hash meets the share target of this difficulty
+	stopServer := startStratumServer(t, harness, stratumAddress1, 1.0/(1<<32))
+	defer stopServer()
+
+	miner := newFakeStratumMiner(t, stratumAddress1)
+	defer miner.close()
+
+	response := miner.request("mining.submit", "worker", "1", "0000000000000000")
+	miner.requireError(response, 24)
+
+	response = miner.request("mining.subscribe", "fake-miner")
+	miner.requireResult(response, []interface{}{true, "EthereumStratum/1.0.0"})
+	extranonceParams := miner.waitForNotification("mining.set_extranonce")
+	if len(extranonceParams) != 2 || extranonceParams[1] != float64(6) {
+		t.Fatalf("Unexpected mining.set_extranonce params: %v", extranonceParams)
+	}
+
+	response = miner.request("mining.authorize", "worker", "x")
+	miner.requireResult(response, true)
+	miner.waitForNotification("mining.set_difficulty")
+	jobParams := miner.waitForNotification("mining.notify")
+	if len(jobParams) != 3 {
+		t.Fatalf("Unexpected mining.notify params: %v", jobParams)
+	}
+	jobID := jobParams[0].(string)
+
+	// About half of the hashes meet the simnet target, so the node
+	// is expected to get a block out of these shares
+	const numberOfShares = 32
+	for nonce := 0; nonce < numberOfShares; nonce++ {
+		response := miner.request("mining.submit", "worker", jobID, fmt.Sprintf("%016x", nonce))
+		miner.requireResult(response, true)
+	}
+	select {
+	case <-blockAddedChan:
+	case <-time.After(defaultTimeout):
+		t.Fatalf("Timeout waiting for a block mined through the stratum server")
+	}
+
+	response = miner.request("mining.submit", "worker", jobID, fmt.Sprintf("%016x", 0))
+	miner.requireError(response, 22)
+
+	response = miner.request("mining.submit", "worker", "not-a-job", fmt.Sprintf("%016x", 0))
+	miner.requireError(response, 21)
+
+	// Hashes that don't meet the simnet target don't meet the share target
+	// of this difficulty either, and are rejected as low difficulty shares
+	stopHighDifficultyServer := startStratumServer(t, harness, stratumAddress2, 1e12)
+	defer stopHighDifficultyServer()
+
+	highDifficultyMiner := newFakeStratumMiner(t, stratumAddress2)
+	defer highDifficultyMiner.close()
+
+	highDifficultyMiner.requireResult(highDifficultyMiner.request("mining.subscribe", "fake-miner"),
+		[]interface{}{true, "EthereumStratum/1.0.0"})
+	highDifficultyMiner.requireResult(highDifficultyMiner.request("mining.authorize", "worker", "x"), true)
+	jobID = highDifficultyMiner.waitForNotification("mining.notify")[0].(string)
+
+	isLowDifficultyShareRejected := false
+	for nonce := 0; nonce < numberOfShares; nonce++ {
+		response := highDifficultyMiner.request("mining.submit", "worker", jobID, fmt.Sprintf("%016x", nonce))
+		if response.Error != nil {
+			highDifficultyMiner.requireError(response, 23)
+			isLowDifficultyShareRejected = true
+			break
+		}
+	}
+	if !isLowDifficultyShareRejected {
+		t.Fatalf("Expected a low difficulty share to be rejected")
+	}
+}
+
+func startStratumServer(t *testing.T, harness *appHarness, address string, minShareDifficulty float64) func() {
+	server := stratumserver.New(&stratumserver.Config{
+		ListenAddress:              address,
+		MiningAddress:              harness.miningAddress,
+		ExtraData:                  "integration",
+		FishHashActivationDAAScore: harness.config.ActiveNetParams.FishHashActivationDAAScore,
+		MinShareDifficulty:         minShareDifficulty,
+		SharesPerMinute:            20,
+		MineWhenNotSynced:          true,
+	}, harness.rpcClient)
+	err := server.Start()
+	if err != nil {
+		t.Fatalf("Error starting the stratum server: %+v", err)
+	}
+	return func() {
+		err := server.Stop()
+		if err != nil {
+			t.Errorf("Error stopping the stratum server: %+v", err)
+		}
+	}
+}
+
+type fakeStratumMessage struct {
+	ID     *uint64       `json:"id"`
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+	Result interface{}   `json:"result"`
+	Error  []interface{} `json:"error"`
+}
+
+// fakeStratumMiner is a stratum client that doesn't mine, but only sends the
+// requests it's told to
+type fakeStratumMiner struct {
+	t             *testing.T
+	conn          net.Conn
+	reader        *bufio.Reader
+	nextID        uint64
+	notifications []*fakeStratumMessage
+}
+
+func newFakeStratumMiner(t *testing.T, address string) *fakeStratumMiner {
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		t.Fatalf("Error connecting to the stratum server: %s", err)
+	}
+	return &fakeStratumMiner{
+		t:      t,
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+	}
+}
+
+func (m *fakeStratumMiner) close() {
+	m.conn.Close()
+}
+
+func (m *fakeStratumMiner) readMessage() *fakeStratumMessage {
+	err := m.conn.SetReadDeadline(time.Now().Add(defaultTimeout))
+	if err != nil {
+		m.t.Fatalf("SetReadDeadline: %s", err)
+	}
+	line, err := m.reader.ReadBytes('\n')
+	if err != nil {
+		m.t.Fatalf("Error reading from the stratum server: %s", err)
+	}
+	message := &fakeStratumMessage{}
+	err = json.Unmarshal(line, message)
+	if err != nil {
+		m.t.Fatalf("Error parsing %s: %s", line, err)
+	}
+	return message
+}
+
+// request sends a request and returns its response. Notifications that
+// arrive in the meantime are kept for waitForNotification.
+func (m *fakeStratumMiner) request(method string, params ...interface{}) *fakeStratumMessage {
+	m.nextID++
+	id := m.nextID
+	requestBytes, err := json.Marshal(&fakeStratumMessage{ID: &id, Method: method, Params: params})
+	if err != nil {
+		m.t.Fatalf("Marshal: %s", err)
+	}
+	_, err = m.conn.Write(append(requestBytes, '\n'))
+	if err != nil {
+		m.t.Fatalf("Error writing to the stratum server: %s", err)
+	}
+
+	for {
+		message := m.readMessage()
+		if message.ID == nil {
+			m.notifications = append(m.notifications, message)
+			continue
+		}
+		if *message.ID != id {
+			m.t.Fatalf("Got a response to request %d instead of %d", *message.ID, id)
+		}
+		return message
+	}
+}
+
+// waitForNotification returns the params of the next notification with the given method
+func (m *fakeStratumMiner) waitForNotification(method string) []interface{} {
+	for {
+		var message *fakeStratumMessage
+		if len(m.notifications) > 0 {
+			message, m.notifications = m.notifications[0], m.notifications[1:]
+		} else {
+			message = m.readMessage()
+		}
+		if message.Method == method {
+			return message.Params
+		}
+	}
+}
+
+func (m *fakeStratumMiner) requireResult(response *fakeStratumMessage, expectedResult interface{}) {
+	if response.Error != nil {
+		m.t.Fatalf("Unexpected error: %v", response.Error)
+	}
+	if fmt.Sprint(response.Result) != fmt.Sprint(expectedResult) {
+		m.t.Fatalf("Expected result %v, but got %v", expectedResult, response.Result)
+	}
+}
+
+func (m *fakeStratumMiner) requireError(response *fakeStratumMessage, expectedCode int) {
+	if len(response.Error) == 0 || response.Error[0] != float64(expectedCode) {
+		m.t.Fatalf("Expected error code %d, but got %v", expectedCode, response.Error)
+	}
+}
+
@@ -70,6 +70,35 @@ func ParsePrefix(prefixString string) (Bech32Prefix, error) {
 	return prefix, nil
 }
 
+// RegisterPrefix registers the Bech32 address prefix of a custom network, so that addresses
+// of that network can be encoded and parsed, and returns it. Registering a prefix that's
+// already registered returns the existing one.
+// Prefixes should be registered by a main package as early as possible, similarly to
+// dagconfig.Register.
+func RegisterPrefix(prefixString string) (Bech32Prefix, error) {
+	if prefix, ok := stringsToBech32Prefixes[prefixString]; ok {
+		return prefix, nil
+	}
+	if prefixString == "" {
+		return Bech32PrefixUnknown, errors.New("prefix must not be empty")
+	}
+	for _, char := range prefixString {
+		if (char < 'a' || char > 'z') && (char < '0' || char > '9') {
+			return Bech32PrefixUnknown, errors.Errorf("prefix %s may contain only lowercase "+
+				"letters and digits", prefixString)
+		}
+	}
+
+	prefix := Bech32PrefixKaspaSim + 1
+	for _, registeredPrefix := range stringsToBech32Prefixes {
+		if registeredPrefix >= prefix {
+			prefix = registeredPrefix + 1
+		}
+	}
+	stringsToBech32Prefixes[prefixString] = prefix
+	return prefix, nil
+}
+
 // Converts from Bech32 address prefixes to their string values
 func (prefix Bech32Prefix) String() string {
 	for key, value := range stringsToBech32Prefixes {
@@ -458,3 +458,61 @@ func TestPrefixToString(t *testing.T) {
 	}
 }
 
+func TestRegisterPrefix(t *testing.T) {
+	prefix, err := util.RegisterPrefix("karlsenregistered")
+	if err != nil {
+		t.Fatalf("RegisterPrefix: %s", err)
+	}
+	if prefix == util.Bech32PrefixUnknown || prefix == util.Bech32PrefixKaspaSim {
+		t.Fatalf("RegisterPrefix: unexpected prefix %d", prefix)
+	}
+	if prefix.String() != "karlsenregistered" {
+		t.Fatalf("RegisterPrefix: expected string karlsenregistered, but got %s", prefix)
+	}
+
+	parsedPrefix, err := util.ParsePrefix("karlsenregistered")
+	if err != nil {
+		t.Fatalf("ParsePrefix: %s", err)
+	}
+	if parsedPrefix != prefix {
+		t.Fatalf("ParsePrefix: expected prefix %d, but got %d", prefix, parsedPrefix)
+	}
+
+	reregisteredPrefix, err := util.RegisterPrefix("karlsenregistered")
+	if err != nil {
+		t.Fatalf("RegisterPrefix: %s", err)
+	}
+	if reregisteredPrefix != prefix {
+		t.Fatalf("RegisterPrefix: expected the same prefix %d when registering again, but got %d",
+			prefix, reregisteredPrefix)
+	}
+
+	existingPrefix, err := util.RegisterPrefix("karlsendev")
+	if err != nil {
+		t.Fatalf("RegisterPrefix: %s", err)
+	}
+	if existingPrefix != util.Bech32PrefixKaspaDev {
+		t.Fatalf("RegisterPrefix: expected karlsendev to be %d, but got %d", util.Bech32PrefixKaspaDev, existingPrefix)
+	}
+
+	for _, invalidPrefix := range []string{"", "Karlsen", "karlsen:", "karlsen private"} {
+		_, err := util.RegisterPrefix(invalidPrefix)
+		if err == nil {
+			t.Fatalf("RegisterPrefix: expected an error for prefix '%s'", invalidPrefix)
+		}
+	}
+
+	publicKey := make([]byte, 32)
+	address, err := util.NewAddressPublicKey(publicKey, prefix)
+	if err != nil {
+		t.Fatalf("NewAddressPublicKey: %s", err)
+	}
+	decodedAddress, err := util.DecodeAddress(address.String(), prefix)
+	if err != nil {
+		t.Fatalf("DecodeAddress: %s", err)
+	}
+	if !decodedAddress.IsForPrefix(prefix) {
+		t.Fatalf("DecodeAddress: expected the address %s to be for prefix %s", decodedAddress, prefix)
+	}
+}
+
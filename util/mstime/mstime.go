@@ -1,8 +1,10 @@
 package mstime
 
 import (
-	"github.com/pkg/errors"
+	"sync/atomic"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 const (
@@ -86,11 +88,31 @@ func (t Time) ToNativeTime() time.Time {
 	return t.time
 }
 
+// mockTimeInMilliseconds is the time that is returned by Now instead of the current
+// time, or 0 if no mock time is set. It's accessed atomically
+var mockTimeInMilliseconds int64
+
 // Now returns the current local time, with precision of one millisecond.
+// If a mock time is set, Now returns it instead.
 func Now() Time {
+	mockTime := atomic.LoadInt64(&mockTimeInMilliseconds)
+	if mockTime != 0 {
+		return UnixMilliseconds(mockTime)
+	}
 	return ToMSTime(time.Now())
 }
 
+// SetMockTime makes Now return the given time instead of the current time, so
+// that time-dependent behavior can be tested deterministically. A zero time
+// makes Now return the current time again.
+func SetMockTime(t Time) {
+	if t.IsZero() {
+		atomic.StoreInt64(&mockTimeInMilliseconds, 0)
+		return
+	}
+	atomic.StoreInt64(&mockTimeInMilliseconds, t.UnixMilliseconds())
+}
+
 // UnixMilliseconds returns the local Time corresponding to the given Unix time,
 // ms milliseconds since January 1, 1970 UTC.
 func UnixMilliseconds(ms int64) Time {
@@ -23,6 +23,21 @@ func TestNow(t *testing.T) {
 	}
 }
 
+func TestSetMockTime(t *testing.T) {
+	mockTime := UnixMilliseconds(1000000)
+	SetMockTime(mockTime)
+	defer SetMockTime(Time{})
+
+	if !Now().time.Equal(mockTime.time) {
+		t.Fatalf("expected Now() to return the mock time %s but got %s", mockTime, Now())
+	}
+
+	SetMockTime(Time{})
+	if Now().UnixMilliseconds() == mockTime.UnixMilliseconds() {
+		t.Fatalf("expected Now() to return the current time after the mock time was unset")
+	}
+}
+
 func TestAdd(t *testing.T) {
 	tests := []struct {
 		unixMilli         int64